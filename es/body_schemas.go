@@ -0,0 +1,262 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// The JSON schemas below describe the bodies accepted by the APIs, to catch
+// typos and misplaced keys during plan instead of halfway through an apply.
+// They only support the subset of JSON schema checked by bodySchema and are
+// deliberately loose below the top level, so that new cluster versions don't
+// require a provider release.
+
+const indexTemplateBodySchema = `{
+  "type": "object",
+  "properties": {
+    "index_patterns": {"type": ["array", "string"], "items": {"type": "string"}},
+    "template": {"type": "string"},
+    "order": {"type": "number"},
+    "version": {"type": "number"},
+    "settings": {"type": "object"},
+    "mappings": {"type": "object"},
+    "aliases": {"type": "object"}
+  },
+  "additionalProperties": false
+}`
+
+const composableIndexTemplateBodySchema = `{
+  "type": "object",
+  "required": ["index_patterns"],
+  "properties": {
+    "index_patterns": {"type": ["array", "string"], "items": {"type": "string"}},
+    "composed_of": {"type": "array", "items": {"type": "string"}},
+    "template": {"$ref": "template"},
+    "priority": {"type": "number"},
+    "version": {"type": "number"},
+    "_meta": {"type": "object"},
+    "data_stream": {"type": "object"},
+    "allow_auto_create": {"type": "boolean"},
+    "ignore_missing_component_templates": {"type": "array", "items": {"type": "string"}},
+    "deprecated": {"type": "boolean"}
+  },
+  "additionalProperties": false
+}`
+
+const componentTemplateBodySchema = `{
+  "type": "object",
+  "required": ["template"],
+  "properties": {
+    "template": {"$ref": "template"},
+    "version": {"type": "number"},
+    "_meta": {"type": "object"},
+    "allow_auto_create": {"type": "boolean"},
+    "deprecated": {"type": "boolean"}
+  },
+  "additionalProperties": false
+}`
+
+// templateBodySchema is the template block shared by composable and component
+// templates.
+const templateBodySchema = `{
+  "type": "object",
+  "properties": {
+    "settings": {"type": "object"},
+    "mappings": {"type": "object"},
+    "aliases": {"type": "object"},
+    "lifecycle": {"type": "object"},
+    "data_stream_options": {"type": "object"}
+  },
+  "additionalProperties": false
+}`
+
+const ingestPipelineBodySchema = `{
+  "type": "object",
+  "required": ["processors"],
+  "properties": {
+    "description": {"type": "string"},
+    "processors": {"type": "array", "items": {"type": "object"}},
+    "on_failure": {"type": "array", "items": {"type": "object"}},
+    "version": {"type": "number"},
+    "_meta": {"type": "object"},
+    "deprecated": {"type": "boolean"}
+  },
+  "additionalProperties": false
+}`
+
+const indexLifecyclePolicyBodySchema = `{
+  "type": "object",
+  "required": ["policy"],
+  "properties": {
+    "policy": {
+      "type": "object",
+      "properties": {
+        "phases": {
+          "type": "object",
+          "properties": {
+            "hot": {"type": "object"},
+            "warm": {"type": "object"},
+            "cold": {"type": "object"},
+            "frozen": {"type": "object"},
+            "delete": {"type": "object"}
+          },
+          "additionalProperties": true
+        },
+        "_meta": {"type": "object"}
+      },
+      "additionalProperties": true
+    }
+  },
+  "additionalProperties": false
+}`
+
+// watchBodySchema accepts unknown keys at the top level too, so that the keys
+// of newer cluster versions don't fail the plan.
+const watchBodySchema = `{
+  "type": "object",
+  "required": ["trigger"],
+  "properties": {
+    "trigger": {"type": "object"},
+    "input": {"type": "object"},
+    "condition": {"type": "object"},
+    "transform": {"type": "object"},
+    "actions": {"type": "object"},
+    "metadata": {"type": "object"},
+    "throttle_period": {"type": "string"},
+    "throttle_period_in_millis": {"type": "number"}
+  },
+  "additionalProperties": true
+}`
+
+// bodySchemaRefs are the schemas that can be referenced with $ref.
+var bodySchemaRefs = map[string]*bodySchema{
+	"template": mustParseBodySchema(templateBodySchema),
+}
+
+// bodySchema is the subset of JSON schema used to validate bodies: type,
+// required, properties, additionalProperties, items and $ref.
+type bodySchema struct {
+	Type                 interface{}            `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*bodySchema `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *bodySchema            `json:"items"`
+	Ref                  string                 `json:"$ref"`
+}
+
+func mustParseBodySchema(s string) *bodySchema {
+	var schema bodySchema
+	if err := json.Unmarshal([]byte(s), &schema); err != nil {
+		panic(fmt.Sprintf("invalid body schema: %+v", err))
+	}
+	return &schema
+}
+
+// validate checks v, decoded from JSON, returning an error mentioning the
+// path of the first invalid value.
+func (s *bodySchema) validate(v interface{}, path string) error {
+	if s.Ref != "" {
+		return bodySchemaRefs[s.Ref].validate(v, path)
+	}
+
+	if types := s.types(); len(types) > 0 && !containsString(types, jsonType(v)) {
+		return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonType(v))
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, k := range s.Required {
+			if _, ok := value[k]; !ok {
+				return fmt.Errorf("%s: missing required key %q", path, k)
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if property, ok := s.Properties[k]; ok {
+				if err := property.validate(value[k], path+"."+k); err != nil {
+					return err
+				}
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return fmt.Errorf("%s: unknown key %q", path, k)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *bodySchema) types() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			types = append(types, fmt.Sprintf("%v", v))
+		}
+		return types
+	}
+	return nil
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// customizeDiffValidateBody validates the JSON in the key attribute against
+// the JSON schema bodySchemaJson during plan. Values that aren't known yet
+// are checked once they are.
+func customizeDiffValidateBody(key string, bodySchemaJson string) schema.CustomizeDiffFunc {
+	s := mustParseBodySchema(bodySchemaJson)
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(d.Get(key).(string)), &v); err != nil {
+			// reported by the attribute's ValidateFunc
+			return nil
+		}
+		if err := s.validate(v, key); err != nil {
+			return fmt.Errorf("invalid %s", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+package es
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBodySchemas(t *testing.T) {
+	cases := []struct {
+		schema string
+		body   string
+		err    string
+	}{
+		{indexTemplateBodySchema, `{"index_patterns": ["te*"], "settings": {"index": {"number_of_shards": 1}}}`, ""},
+		{indexTemplateBodySchema, `{"index_patterns": "te*", "setings": {}}`, `body: unknown key "setings"`},
+		{composableIndexTemplateBodySchema, `{"index_patterns": ["te*"], "template": {"settings": {}}, "priority": 1}`, ""},
+		{composableIndexTemplateBodySchema, `{"template": {"settings": {}}}`, `body: missing required key "index_patterns"`},
+		{composableIndexTemplateBodySchema, `{"index_patterns": ["te*"], "template": {"mapping": {}}}`, `body.template: unknown key "mapping"`},
+		{componentTemplateBodySchema, `{"template": {"mappings": {}}, "version": 2}`, ""},
+		{ingestPipelineBodySchema, `{"description": "test", "processors": [{"set": {"field": "foo", "value": "bar"}}]}`, ""},
+		{ingestPipelineBodySchema, `{"processors": {"set": {}}}`, `body.processors: expected array, got object`},
+		{ingestPipelineBodySchema, `{"processors": ["set"]}`, `body.processors[0]: expected object, got string`},
+		{indexLifecyclePolicyBodySchema, `{"policy": {"phases": {"hot": {"actions": {}}, "delete": {}}}}`, ""},
+		{indexLifecyclePolicyBodySchema, `{"policy": {"phases": {"hot": {}, "archive": {"min_age": "90d"}}, "deprecated": false}}`, ""},
+		{indexLifecyclePolicyBodySchema, `{"policy": {"phases": {"hot": []}}}`, `body.policy.phases.hot: expected object, got array`},
+		{indexLifecyclePolicyBodySchema, `{"policy": {}, "polcy": {}}`, `body: unknown key "polcy"`},
+		{watchBodySchema, `{"trigger": {"schedule": {"cron": "0 0 * * * ?"}}, "actions": {}}`, ""},
+		{watchBodySchema, `{"input": {}}`, `body: missing required key "trigger"`},
+		{watchBodySchema, `{"trigger": {}, "added_in_a_later_version": {}}`, ""},
+	}
+
+	for _, c := range cases {
+		var v interface{}
+		if err := json.Unmarshal([]byte(c.body), &v); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		err := mustParseBodySchema(c.schema).validate(v, "body")
+		if c.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %s", c.body, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s: expected error %q, got %v", c.body, c.err, err)
+		}
+	}
+}
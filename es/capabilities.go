@@ -0,0 +1,294 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// capability is an API provided from a minimum version of each flavor. A
+// flavor without a minimum version doesn't provide the API at all.
+type capability struct {
+	name          string
+	elasticsearch string
+	opensearch    string
+}
+
+var (
+	capabilityIndices                   = capability{"indices", "5.0.0", "1.0.0"}
+	capabilityShardSettings             = capability{"shard and replica settings", "5.0.0", "1.0.0"}
+	capabilityClusterHealth             = capability{"cluster health", "5.0.0", "1.0.0"}
+	capabilityClusterSettings           = capability{"cluster settings", "5.0.0", "1.0.0"}
+	capabilityForceMerge                = capability{"force merge", "5.0.0", "1.0.0"}
+	capabilityForceMergeTasks           = capability{"force merge tasks", "7.12.0", "2.7.0"}
+	capabilityRollover                  = capability{"rollover", "5.0.0", "1.0.0"}
+	capabilityReindex                   = capability{"reindex", "5.0.0", "1.0.0"}
+	capabilityIndexTemplates            = capability{"index templates", "5.0.0", "1.0.0"}
+	capabilityDocuments                 = capability{"documents", "6.7.0", "1.0.0"}
+	capabilityByQuery                   = capability{"update and delete by query", "5.1.0", "1.0.0"}
+	capabilityComposableIndexTemplates  = capability{"composable index templates", "7.8.0", "1.0.0"}
+	capabilityComponentTemplates        = capability{"component templates", "7.8.0", "1.0.0"}
+	capabilityIndexTemplateSimulation   = capability{"index template simulation", "7.9.0", "1.0.0"}
+	capabilityResolveIndex              = capability{"index resolution", "7.9.0", "1.0.0"}
+	capabilityWeightedRouting           = capability{"weighted routing", "", "2.4.0"}
+	capabilitySegmentReplication        = capability{"segment replication settings", "", "2.7.0"}
+	capabilityRemoteStore               = capability{"remote store settings", "", "2.10.0"}
+	capabilityDataStreams               = capability{"data streams", "7.9.0", "1.0.0"}
+	capabilityDataStreamFailureStore    = capability{"data stream failure stores", "8.15.0", ""}
+	capabilityDataStreamOptions         = capability{"data stream options", "8.19.0", ""}
+	capabilityIngestPipelines           = capability{"ingest pipelines", "5.0.0", "1.0.0"}
+	capabilityGeoipDownloader           = capability{"GeoIP downloader", "7.14.0", ""}
+	capabilityGeoipDatabases            = capability{"GeoIP database configurations", "8.15.0", ""}
+	capabilityEnrichPolicies            = capability{"enrich policies", "7.5.0", ""}
+	capabilityLogstashPipelines         = capability{"Logstash pipelines", "7.12.0", ""}
+	capabilitySnapshotRepositories      = capability{"snapshot repositories", "5.0.0", "1.0.0"}
+	capabilitySnapshotRepositoryCleanup = capability{"snapshot repository cleanup", "7.4.0", "1.0.0"}
+	capabilityKibanaObjects             = capability{"Kibana objects", "5.0.0", "1.0.0"}
+	capabilityKibanaAlerts              = capability{"Kibana alerts", "7.7.0", ""}
+	capabilityKibanaSavedObjectsImport  = capability{"Kibana saved objects imports", "7.0.0", "1.0.0"}
+	capabilityKibanaSavedObjectsExport  = capability{"Kibana saved objects exports", "7.0.0", "1.0.0"}
+	capabilityKibanaTags                = capability{"Kibana tags", "7.10.0", ""}
+	capabilityKibanaSpaces              = capability{"Kibana spaces", "6.5.0", ""}
+	capabilityDashboardsIndexPatterns   = capability{"OpenSearch Dashboards index patterns", "", "1.0.0"}
+	capabilityXpackSecurity             = capability{"X-Pack users and roles", "5.0.0", ""}
+	capabilityXpackNativeUsers          = capability{"X-Pack native users", "5.0.0", ""}
+	capabilityXpackRoleMappings         = capability{"X-Pack role mappings", "5.5.0", ""}
+	capabilityXpackRoleDescriptions     = capability{"X-Pack role descriptions", "8.15.0", ""}
+	capabilityXpackServiceAccounts      = capability{"X-Pack service accounts", "7.13.0", ""}
+	capabilityXpackBuiltinPrivileges    = capability{"X-Pack builtin privileges", "7.3.0", ""}
+	capabilityXpackSslCertificates      = capability{"X-Pack SSL certificates", "7.0.0", ""}
+	capabilityXpackUsage                = capability{"X-Pack usage", "5.0.0", ""}
+	capabilityCcr                       = capability{"cross-cluster replication", "6.7.0", ""}
+	capabilityDeprecations              = capability{"deprecation info", "7.0.0", ""}
+	capabilityHealthReport              = capability{"health reports", "8.7.0", ""}
+	capabilityReloadSecureSettings      = capability{"reloading secure settings", "6.4.0", "1.0.0"}
+	capabilityXpackMonitoring           = capability{"X-Pack monitoring settings", "6.3.0", ""}
+	capabilityXpackLicense              = capability{"X-Pack licenses", "5.0.0", ""}
+	capabilityWatcher                   = capability{"watches", "6.0.0", ""}
+	capabilityWatchQueries              = capability{"watch queries", "7.11.0", ""}
+	capabilityMlUpgradeMode             = capability{"machine learning upgrade mode", "7.0.0", ""}
+	capabilityTransforms                = capability{"transforms", "7.5.0", ""}
+	capabilityIndexLifecyclePolicies    = capability{"index lifecycle policies", "6.6.0", ""}
+	capabilitySnapshotLifecyclePolicies = capability{"snapshot lifecycle policies", "7.4.0", ""}
+	capabilitySnapshotLifecycleMode     = capability{"snapshot lifecycle management start and stop", "7.6.0", ""}
+	// Open Distro clusters report the version of Elasticsearch they are built on
+	capabilityOpenDistroSecurity = capability{"security plugin APIs", "6.5.0", "1.0.0"}
+	capabilityOpenDistroAlerting = capability{"alerting plugin APIs", "6.5.0", "1.0.0"}
+	capabilityOpenDistroISM      = capability{"index state management policies", "7.1.0", "1.0.0"}
+	capabilityTenancyConfig      = capability{"multi-tenancy configurations", "", "2.7.0"}
+)
+
+// indexSettingsCapabilities are the index settings, without the index prefix,
+// that require a capability of the cluster, which otherwise rejects them as
+// unknown.
+var indexSettingsCapabilities = map[string]capability{
+	"number_of_shards":                      capabilityShardSettings,
+	"number_of_replicas":                    capabilityShardSettings,
+	"auto_expand_replicas":                  capabilityShardSettings,
+	"lifecycle.name":                        capabilityIndexLifecyclePolicies,
+	"replication.type":                      capabilitySegmentReplication,
+	"remote_store.translog.buffer_interval": capabilityRemoteStore,
+}
+
+// serverlessCapabilities are the capabilities provided by Elastic Cloud
+// Serverless projects, whatever the version they report. The others, e.g.
+// index lifecycle policies, snapshots, legacy index templates, native users
+// or the node and cluster level APIs, are managed by Elastic.
+var serverlessCapabilities = map[capability]bool{
+	capabilityIndices:                  true,
+	capabilityRollover:                 true,
+	capabilityReindex:                  true,
+	capabilityDocuments:                true,
+	capabilityByQuery:                  true,
+	capabilityComposableIndexTemplates: true,
+	capabilityComponentTemplates:       true,
+	capabilityIndexTemplateSimulation:  true,
+	capabilityResolveIndex:             true,
+	capabilityDataStreams:              true,
+	capabilityDataStreamFailureStore:   true,
+	capabilityDataStreamOptions:        true,
+	capabilityIngestPipelines:          true,
+	capabilityEnrichPolicies:           true,
+	capabilityLogstashPipelines:        true,
+	capabilityKibanaObjects:            true,
+	capabilityKibanaAlerts:             true,
+	capabilityKibanaSavedObjectsImport: true,
+	capabilityKibanaSavedObjectsExport: true,
+	capabilityKibanaTags:               true,
+	capabilityKibanaSpaces:             true,
+	capabilityXpackSecurity:            true,
+	capabilityXpackRoleDescriptions:    true,
+	capabilityXpackBuiltinPrivileges:   true,
+}
+
+// resourceCapabilities are the APIs required by each resource, checked
+// during plan.
+var resourceCapabilities = map[string]capability{
+	"elasticsearch_blue_green_index":                    capabilityReindex,
+	"elasticsearch_bulk_documents":                      capabilityDocuments,
+	"elasticsearch_ccr_follower":                        capabilityCcr,
+	"elasticsearch_cluster_health_check":                capabilityClusterHealth,
+	"elasticsearch_cluster_routing_weights":             capabilityWeightedRouting,
+	"elasticsearch_cluster_settings":                    capabilityClusterSettings,
+	"elasticsearch_data_stream_failure_store":           capabilityDataStreamOptions,
+	"elasticsearch_delete_by_query":                     capabilityByQuery,
+	"elasticsearch_destination":                         capabilityOpenDistroAlerting,
+	"elasticsearch_document":                            capabilityDocuments,
+	"elasticsearch_force_merge":                         capabilityForceMerge,
+	"elasticsearch_index":                               capabilityIndices,
+	"elasticsearch_index_rollover":                      capabilityRollover,
+	"elasticsearch_index_state":                         capabilityIndices,
+	"elasticsearch_index_lifecycle_policy":              capabilityIndexLifecyclePolicies,
+	"elasticsearch_index_template":                      capabilityIndexTemplates,
+	"elasticsearch_ingest_geoip_database":               capabilityGeoipDatabases,
+	"elasticsearch_ingest_geoip_downloader":             capabilityGeoipDownloader,
+	"elasticsearch_composable_index_template":           capabilityComposableIndexTemplates,
+	"elasticsearch_component_template":                  capabilityComponentTemplates,
+	"elasticsearch_ingest_pipeline":                     capabilityIngestPipelines,
+	"elasticsearch_kibana_alert":                        capabilityKibanaAlerts,
+	"elasticsearch_kibana_object":                       capabilityKibanaObjects,
+	"elasticsearch_kibana_saved_objects_import":         capabilityKibanaSavedObjectsImport,
+	"elasticsearch_kibana_tag":                          capabilityKibanaTags,
+	"elasticsearch_logstash_pipeline":                   capabilityLogstashPipelines,
+	"elasticsearch_monitor":                             capabilityOpenDistroAlerting,
+	"elasticsearch_reload_secure_settings":              capabilityReloadSecureSettings,
+	"elasticsearch_snapshot":                            capabilitySnapshotRepositories,
+	"elasticsearch_snapshot_repository":                 capabilitySnapshotRepositories,
+	"elasticsearch_snapshot_repository_cleanup":         capabilitySnapshotRepositoryCleanup,
+	"elasticsearch_snapshot_repository_verify":          capabilitySnapshotRepositories,
+	"elasticsearch_update_by_query":                     capabilityByQuery,
+	"elasticsearch_watch":                               capabilityWatcher,
+	"elasticsearch_opendistro_destination":              capabilityOpenDistroAlerting,
+	"elasticsearch_opendistro_ism_policy":               capabilityOpenDistroISM,
+	"elasticsearch_opendistro_ism_policy_mapping":       capabilityOpenDistroISM,
+	"elasticsearch_opendistro_monitor":                  capabilityOpenDistroAlerting,
+	"elasticsearch_opendistro_roles_mapping":            capabilityOpenDistroSecurity,
+	"elasticsearch_opendistro_role":                     capabilityOpenDistroSecurity,
+	"elasticsearch_opendistro_user":                     capabilityOpenDistroSecurity,
+	"elasticsearch_opendistro_kibana_tenant":            capabilityOpenDistroSecurity,
+	"elasticsearch_opendistro_dashboards_index_pattern": capabilityDashboardsIndexPatterns,
+	"elasticsearch_opendistro_security_cache_flush":     capabilityOpenDistroSecurity,
+	"elasticsearch_opendistro_tenancy_config":           capabilityTenancyConfig,
+	"elasticsearch_xpack_index_lifecycle_mode":          capabilityIndexLifecyclePolicies,
+	"elasticsearch_xpack_index_lifecycle_policy":        capabilityIndexLifecyclePolicies,
+	"elasticsearch_xpack_license":                       capabilityXpackLicense,
+	"elasticsearch_xpack_ml_upgrade_mode":               capabilityMlUpgradeMode,
+	"elasticsearch_xpack_monitoring_settings":           capabilityXpackMonitoring,
+	"elasticsearch_xpack_role":                          capabilityXpackSecurity,
+	"elasticsearch_xpack_role_mapping":                  capabilityXpackRoleMappings,
+	"elasticsearch_xpack_snapshot_lifecycle_mode":       capabilitySnapshotLifecycleMode,
+	"elasticsearch_xpack_snapshot_lifecycle_policy":     capabilitySnapshotLifecyclePolicies,
+	"elasticsearch_xpack_transform":                     capabilityTransforms,
+	"elasticsearch_xpack_user":                          capabilityXpackNativeUsers,
+	"elasticsearch_xpack_watch":                         capabilityWatcher,
+	"elasticsearch_xpack_watcher_mode":                  capabilityWatcher,
+}
+
+// check returns an error naming the required and detected versions if the
+// cluster doesn't provide the capability, detecting the version first if
+// needed.
+func (c capability) check(conf *ProviderConf) error {
+	if _, err := getClient(conf); err != nil {
+		return err
+	}
+
+	if clusterServerless(conf) {
+		if !serverlessCapabilities[c] {
+			return fmt.Errorf("%s are not available on Elastic Cloud Serverless", c.name)
+		}
+		return nil
+	}
+
+	detected, detectedFlavor := clusterVersion(conf)
+	flavor, minimum := "Elasticsearch", c.elasticsearch
+	if detectedFlavor == flavorOpenSearch {
+		flavor, minimum = "OpenSearch", c.opensearch
+	}
+	if minimum == "" {
+		return fmt.Errorf("%s are not available on %s; detected %s %s", c.name, flavor, flavor, detected)
+	}
+
+	current, err := version.NewVersion(detected)
+	if err != nil {
+		return fmt.Errorf("error parsing the version of the cluster %q: %+v", detected, err)
+	}
+	if current.LessThan(version.Must(version.NewVersion(minimum))) {
+		return fmt.Errorf("%s require %s >= %s; detected %s %s", c.name, flavor, minimum, flavor, detected)
+	}
+
+	return nil
+}
+
+// resourceWithCapability fails the plan of r when the cluster doesn't provide
+// c, instead of failing the apply with whatever error the cluster returns for
+// an unknown endpoint. If the version can't be detected, e.g. because the
+// cluster isn't reachable yet, the check is left to the apply.
+func resourceWithCapability(r *schema.Resource, c capability) *schema.Resource {
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+		conf := meta.(*ProviderConf)
+		if _, err := getClient(conf); err != nil {
+			log.Printf("[WARN] Skipping the version check, the version of the cluster couldn't be detected: %+v", err)
+		} else if err := c.check(conf); err != nil {
+			return err
+		}
+
+		if customizeDiff != nil {
+			return customizeDiff(d, meta)
+		}
+		return nil
+	}
+
+	return r
+}
+
+// checkIndexSettingsCapabilities returns an error if the cluster doesn't
+// accept one of settings, nested or flat, with or without the index prefix.
+// Like resourceWithCapability, the check is left to the apply if the cluster
+// can't be reached.
+func checkIndexSettingsCapabilities(conf *ProviderConf, settings map[string]interface{}) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	if _, err := getClient(conf); err != nil {
+		log.Printf("[WARN] Skipping the version check of the index settings, the version of the cluster couldn't be detected: %+v", err)
+		return nil
+	}
+	keys := []string{}
+	for key := range flattenMap(settings) {
+		keys = append(keys, strings.TrimPrefix(key, "index."))
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if c, ok := indexSettingsCapabilities[key]; ok {
+			if err := c.check(conf); err != nil {
+				return fmt.Errorf("index setting %s can't be set: %+v", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// customizeDiffCheckBodyIndexSettings fails the plan when the cluster doesn't
+// accept the index settings at path in the JSON body of a template.
+func customizeDiffCheckBodyIndexSettings(key string, path ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if !d.HasChange(key) || !d.NewValueKnown(key) {
+			return nil
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(d.Get(key).(string)), &body); err != nil {
+			// reported by the validation of the body
+			return nil
+		}
+		for _, p := range path {
+			body, _ = body[p].(map[string]interface{})
+		}
+		return checkIndexSettingsCapabilities(meta.(*ProviderConf), body)
+	}
+}
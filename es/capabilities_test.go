@@ -0,0 +1,77 @@
+package es
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestCapabilityCheck(t *testing.T) {
+	cases := []struct {
+		flavor     string
+		version    string
+		capability capability
+		err        string
+	}{
+		{"", "7.10.2", capabilityComposableIndexTemplates, ""},
+		{"", "8.11.0", capabilityComposableIndexTemplates, ""},
+		{"", "6.8.23", capabilityComposableIndexTemplates, "composable index templates require Elasticsearch >= 7.8.0; detected Elasticsearch 6.8.23"},
+		{"opensearch", "2.11.0", capabilityComposableIndexTemplates, ""},
+		{"opensearch", "2.11.0", capabilityKibanaAlerts, "Kibana alerts are not available on OpenSearch; detected OpenSearch 2.11.0"},
+	}
+	for _, c := range cases {
+		raw := map[string]interface{}{
+			"url":                   "http://127.0.0.1:1",
+			"skip_version_ping":     true,
+			"elasticsearch_version": c.version,
+			"flavor":                c.flavor,
+		}
+		meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		err = c.capability.check(meta.(*ProviderConf))
+		if c.err == "" && err != nil {
+			t.Errorf("%s %s: unexpected error: %s", c.flavor, c.version, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s %s: expected error %q, got %v", c.flavor, c.version, c.err, err)
+		}
+	}
+}
+
+func TestCapabilityCheckServerless(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":                   "http://127.0.0.1:1",
+		"skip_version_ping":     true,
+		"elasticsearch_version": "8.11.0",
+		"serverless":            true,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	// serverless projects report an older version than the APIs they provide
+	if err := capabilityDataStreamOptions.check(conf); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	expected := "index lifecycle policies are not available on Elastic Cloud Serverless"
+	if err := capabilityIndexLifecyclePolicies.check(conf); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+	if err := checkIndexSettingsCapabilities(conf, map[string]interface{}{"index.number_of_replicas": "2"}); err == nil || !strings.Contains(err.Error(), "shard and replica settings are not available") {
+		t.Errorf("expected the replicas setting to be rejected, got %v", err)
+	}
+}
+
+func TestResourceCapabilities(t *testing.T) {
+	for name := range Provider().(*schema.Provider).ResourcesMap {
+		if _, ok := resourceCapabilities[name]; !ok {
+			t.Errorf("%s doesn't declare the capability it requires in resourceCapabilities", name)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// persistentClusterSettings returns the persistent cluster settings, flat.
+func persistentClusterSettings(ctx context.Context, esClient interface{}) (map[string]interface{}, error) {
+	persistent, _, err := clusterSettings(ctx, esClient)
+	return persistent, err
+}
+
+// clusterSettings returns the persistent and transient cluster settings, flat.
+func clusterSettings(ctx context.Context, esClient interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	params := url.Values{
+		"flat_settings": []string{"true"},
+	}
+	res, err := performRequest(ctx, esClient, "GET", "/_cluster/settings", params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var settings struct {
+		Persistent map[string]interface{} `json:"persistent"`
+		Transient  map[string]interface{} `json:"transient"`
+	}
+	if err := json.Unmarshal(res, &settings); err != nil {
+		return nil, nil, fmt.Errorf("error unmarshalling cluster settings: %+v: %s", err, res)
+	}
+	return settings.Persistent, settings.Transient, nil
+}
+
+// putPersistentClusterSettings updates the persistent cluster settings, a nil
+// value resetting a setting to its default.
+func putPersistentClusterSettings(ctx context.Context, esClient interface{}, settings map[string]interface{}) error {
+	return putClusterSettings(ctx, esClient, settings, nil)
+}
+
+// putClusterSettings updates the persistent and transient cluster settings,
+// a nil value resetting a setting to its default. Empty scopes are left out.
+func putClusterSettings(ctx context.Context, esClient interface{}, persistent, transient map[string]interface{}) error {
+	body := map[string]interface{}{}
+	if len(persistent) > 0 {
+		body["persistent"] = persistent
+	}
+	if len(transient) > 0 {
+		body["transient"] = transient
+	}
+	_, err := performRequest(ctx, esClient, "PUT", "/_cluster/settings", nil, body)
+	return err
+}
+
+// clusterSettingString returns a flat setting as a string, lists joined by
+// commas as the settings API accepts them.
+func clusterSettingString(v interface{}) string {
+	if list, ok := v.([]interface{}); ok {
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			values = append(values, scalarString(item))
+		}
+		return strings.Join(values, ",")
+	}
+	return scalarString(v)
+}
@@ -0,0 +1,115 @@
+package es
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialsCommand runs an external command, e.g. a Vault or STS helper,
+// that prints a short-lived token on stdout. The token is cached until the
+// refresh interval passes or the cluster rejects it.
+type credentialsCommand struct {
+	args     []string
+	interval time.Duration
+
+	mu      sync.Mutex
+	token   string
+	fetched time.Time
+}
+
+func newCredentialsCommand(args []string, interval time.Duration) (*credentialsCommand, error) {
+	if len(args) == 0 || args[0] == "" {
+		return nil, errors.New("credentials_command must contain the command to run")
+	}
+
+	return &credentialsCommand{args: args, interval: interval}, nil
+}
+
+// Token returns the cached token, running the command first if there is none
+// yet, it's older than the refresh interval or refresh is set.
+func (c *credentialsCommand) Token(refresh bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !refresh && c.token != "" && (c.interval == 0 || time.Since(c.fetched) < c.interval) {
+		return c.token, nil
+	}
+
+	out, err := exec.Command(c.args[0], c.args[1:]...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("error running credentials_command: %+v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("error running credentials_command: %+v", err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("credentials_command did not print a token")
+	}
+
+	log.Printf("[INFO] Refreshed token with credentials_command")
+	c.token = token
+	c.fetched = time.Now()
+
+	return c.token, nil
+}
+
+type withCredentialsCommand struct {
+	rt        http.RoundTripper
+	command   *credentialsCommand
+	tokenName string
+}
+
+// WithCredentialsCommand wraps rt so that every request carries an
+// Authorization header with the token from command. A request that is
+// rejected with 401 is sent once more with a freshly fetched token, in case
+// the token expired before the refresh interval.
+func WithCredentialsCommand(rt http.RoundTripper, command *credentialsCommand, tokenName string) *withCredentialsCommand {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &withCredentialsCommand{rt: rt, command: command, tokenName: tokenName}
+}
+
+func (t *withCredentialsCommand) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.command.Token(false)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", t.tokenName, token))
+
+	res, err := t.rt.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return res, err
+	}
+
+	token, err = t.command.Token(true)
+	if err != nil {
+		log.Printf("[WARN] %+v", err)
+		return res, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return res, nil
+		}
+		req.Body = body
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", t.tokenName, token))
+	return t.rt.RoundTrip(req)
+}
@@ -0,0 +1,92 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+func dataSourceElasticsearchAlias() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_alias` resolves the indices an alias points to, and its write index, e.g. to switch an alias from one index to another.",
+		Read:        dataSourceElasticsearchAliasRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the alias.",
+			},
+			"indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The sorted names of the indices the alias points to.",
+			},
+			"write_index": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The index that writes to the alias go to: the one marked with `is_write_index`, or the only index of the alias. Empty if writes are rejected.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchAliasRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("name").(string)
+	path, err := uritemplates.Expand("/_alias/{name}", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for alias: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling alias: %+v: %s", err, body)
+	}
+
+	indices := []string{}
+	var writeIndex string
+	for index, r := range response {
+		alias, ok := r.Aliases[name]
+		if !ok {
+			continue
+		}
+		indices = append(indices, index)
+		if alias.IsWriteIndex != nil && *alias.IsWriteIndex {
+			writeIndex = index
+		}
+	}
+	sort.Strings(indices)
+	if writeIndex == "" && len(indices) == 1 {
+		alias := response[indices[0]].Aliases[name]
+		// an alias of a single index writes to it unless it's explicitly not
+		// the write index
+		if alias.IsWriteIndex == nil {
+			writeIndex = indices[0]
+		}
+	}
+
+	d.SetId(name)
+	ds := &resourceDataSetter{d: d}
+	ds.set("indices", indices)
+	ds.set("write_index", writeIndex)
+	return ds.err
+}
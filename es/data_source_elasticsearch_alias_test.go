@@ -0,0 +1,79 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchAliasRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_alias/app":
+			_, _ = w.Write([]byte(`{
+				"app-blue": {"aliases": {"app": {"is_write_index": false}}},
+				"app-green": {"aliases": {"app": {"is_write_index": true}}}
+			}`))
+		case "/_alias/single":
+			_, _ = w.Write([]byte(`{"app-green": {"aliases": {"single": {}}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchAlias()
+	for alias, expected := range map[string]map[string]interface{}{
+		"app":    {"indices.#": 2, "indices.0": "app-blue", "write_index": "app-green"},
+		"single": {"indices.#": 1, "write_index": "app-green"},
+	} {
+		d := r.TestResourceData()
+		_ = d.Set("name", alias)
+		if err := r.Read(d, conf); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for key, value := range expected {
+			if actual := d.Get(key); actual != value {
+				t.Errorf("%s: expected %s to be %v, got %v", alias, key, value, actual)
+			}
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceAlias_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceAlias,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_alias.test", "indices.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_alias.test", "write_index", "terraform-test-alias-000001"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceAlias = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-alias-000001"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  aliases = jsonencode({
+    "terraform-test-alias" = {}
+  })
+}
+
+data "elasticsearch_alias" "test" {
+  name = "terraform-test-alias"
+
+  depends_on = [elasticsearch_index.test]
+}
+`
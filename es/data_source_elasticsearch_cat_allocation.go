@@ -0,0 +1,137 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// catAllocationUnassigned is the node of the row counting the unassigned
+// shards.
+const catAllocationUnassigned = "UNASSIGNED"
+
+type catAllocation struct {
+	Node        string  `json:"node"`
+	Host        string  `json:"host"`
+	IP          string  `json:"ip"`
+	Shards      *string `json:"shards"`
+	DiskIndices *string `json:"disk.indices"`
+	DiskUsed    *string `json:"disk.used"`
+	DiskAvail   *string `json:"disk.avail"`
+	DiskTotal   *string `json:"disk.total"`
+	DiskPercent *string `json:"disk.percent"`
+}
+
+func dataSourceElasticsearchCatAllocation() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_cat_allocation` exposes the number of shards and the disk usage of each data node, e.g. to size replicas or allocation filters according to the actual usage.",
+		Read:        dataSourceElasticsearchCatAllocationRead,
+
+		Schema: map[string]*schema.Schema{
+			"unassigned_shards": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of shards that aren't allocated to any node.",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The data nodes, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"shards": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk_indices_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The disk space used by the shards of the node.",
+						},
+						"disk_used_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The disk space used on the node, by the shards and anything else.",
+						},
+						"disk_available_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk_total_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk_percent": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The percentage of the disk space used on the node.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchCatAllocationRead(d *schema.ResourceData, m interface{}) error {
+	params := url.Values{
+		"format": []string{"json"},
+		"bytes":  []string{"b"},
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", "/_cat/allocation", params, nil)
+	if err != nil {
+		return err
+	}
+
+	var rows []catAllocation
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return fmt.Errorf("error unmarshalling allocation: %+v: %s", err, body)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Node < rows[j].Node })
+
+	var unassigned int
+	nodes := []map[string]interface{}{}
+	for _, row := range rows {
+		if row.Node == catAllocationUnassigned {
+			unassigned = catInt(row.Shards)
+			continue
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"name":                 row.Node,
+			"host":                 row.Host,
+			"ip":                   row.IP,
+			"shards":               catInt(row.Shards),
+			"disk_indices_bytes":   catInt(row.DiskIndices),
+			"disk_used_bytes":      catInt(row.DiskUsed),
+			"disk_available_bytes": catInt(row.DiskAvail),
+			"disk_total_bytes":     catInt(row.DiskTotal),
+			"disk_percent":         catInt(row.DiskPercent),
+		})
+	}
+
+	d.SetId("_cat/allocation")
+	ds := &resourceDataSetter{d: d}
+	ds.set("unassigned_shards", unassigned)
+	ds.set("nodes", nodes)
+	return ds.err
+}
@@ -0,0 +1,73 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchCatAllocationRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_cat/allocation":
+			if r.URL.Query().Get("bytes") != "b" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[
+				{"shards": "7", "disk.indices": "1000", "disk.used": "5000", "disk.avail": "15000", "disk.total": "20000", "disk.percent": "25", "host": "10.0.0.2", "ip": "10.0.0.2", "node": "node-2"},
+				{"shards": "2", "disk.indices": null, "disk.used": null, "disk.avail": null, "disk.total": null, "disk.percent": null, "host": null, "ip": null, "node": "UNASSIGNED"},
+				{"shards": "5", "disk.indices": "800", "disk.used": "4000", "disk.avail": "16000", "disk.total": "20000", "disk.percent": "20", "host": "10.0.0.1", "ip": "10.0.0.1", "node": "node-1"}
+			]`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchCatAllocation()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"unassigned_shards":            2,
+		"nodes.#":                      2,
+		"nodes.0.name":                 "node-1",
+		"nodes.0.shards":               5,
+		"nodes.0.disk_indices_bytes":   800,
+		"nodes.1.host":                 "10.0.0.2",
+		"nodes.1.disk_available_bytes": 15000,
+		"nodes.1.disk_percent":         25,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceCatAllocation_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceCatAllocation,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_cat_allocation.test", "nodes.0.name"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_cat_allocation.test", "nodes.0.disk_total_bytes"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceCatAllocation = `
+data "elasticsearch_cat_allocation" "test" {}
+`
@@ -0,0 +1,148 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+type catShard struct {
+	Index            string  `json:"index"`
+	Shard            string  `json:"shard"`
+	PriRep           string  `json:"prirep"`
+	State            string  `json:"state"`
+	Docs             *string `json:"docs"`
+	Store            *string `json:"store"`
+	Node             *string `json:"node"`
+	UnassignedReason *string `json:"unassigned.reason"`
+}
+
+func dataSourceElasticsearchCatShards() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_cat_shards` lists the shards of indices, with their state, size and node.",
+		Read:        dataSourceElasticsearchCatShardsRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*",
+				Description: "Only list the shards of the indices matching this pattern, or comma separated list of patterns.",
+			},
+			"shards": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The shards, sorted by index, shard number and primaries first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"shard": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"primary": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`STARTED`, `RELOCATING`, `INITIALIZING` or `UNASSIGNED`.",
+						},
+						"docs": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"store_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"node": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The node of the shard, empty if it's unassigned.",
+						},
+						"unassigned_reason": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Why the shard is unassigned, e.g. `NODE_LEFT`, empty if it's assigned.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchCatShardsRead(d *schema.ResourceData, m interface{}) error {
+	index := d.Get("index").(string)
+	path, err := uritemplates.Expand("/_cat/shards/{index}", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for shards: %+v", err)
+	}
+	params := url.Values{
+		"format": []string{"json"},
+		"bytes":  []string{"b"},
+		"h":      []string{"index,shard,prirep,state,docs,store,node,unassigned.reason"},
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, params, nil)
+	if err != nil {
+		return err
+	}
+
+	var rows []catShard
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return fmt.Errorf("error unmarshalling shards: %+v: %s", err, body)
+	}
+
+	shards := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		shards = append(shards, map[string]interface{}{
+			"index":             row.Index,
+			"shard":             catInt(&row.Shard),
+			"primary":           row.PriRep == "p",
+			"state":             row.State,
+			"docs":              catInt(row.Docs),
+			"store_bytes":       catInt(row.Store),
+			"node":              catString(row.Node),
+			"unassigned_reason": catString(row.UnassignedReason),
+		})
+	}
+	sort.SliceStable(shards, func(i, j int) bool {
+		a, b := shards[i], shards[j]
+		if a["index"] != b["index"] {
+			return a["index"].(string) < b["index"].(string)
+		}
+		if a["shard"] != b["shard"] {
+			return a["shard"].(int) < b["shard"].(int)
+		}
+		return a["primary"].(bool) && !b["primary"].(bool)
+	})
+
+	d.SetId(index)
+	ds := &resourceDataSetter{d: d}
+	ds.set("shards", shards)
+	return ds.err
+}
+
+// catString returns a column of the cat APIs, which can be null.
+func catString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,82 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchCatShardsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_cat/shards/app-*":
+			_, _ = w.Write([]byte(`[
+				{"index": "app-1", "shard": "0", "prirep": "r", "state": "UNASSIGNED", "docs": null, "store": null, "node": null, "unassigned.reason": "NODE_LEFT"},
+				{"index": "app-1", "shard": "1", "prirep": "p", "state": "STARTED", "docs": "10", "store": "2048", "node": "node-1", "unassigned.reason": null},
+				{"index": "app-1", "shard": "0", "prirep": "p", "state": "STARTED", "docs": "12", "store": "4096", "node": "node-2", "unassigned.reason": null}
+			]`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchCatShards()
+	d := r.TestResourceData()
+	_ = d.Set("index", "app-*")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"shards.#":                   3,
+		"shards.0.shard":             0,
+		"shards.0.primary":           true,
+		"shards.0.docs":              12,
+		"shards.0.store_bytes":       4096,
+		"shards.0.node":              "node-2",
+		"shards.1.state":             "UNASSIGNED",
+		"shards.1.node":              "",
+		"shards.1.unassigned_reason": "NODE_LEFT",
+		"shards.2.shard":             1,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceCatShards_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceCatShards,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_cat_shards.test", "shards.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_cat_shards.test", "shards.0.index", "terraform-test-cat-shards"),
+					resource.TestCheckResourceAttr("data.elasticsearch_cat_shards.test", "shards.0.primary", "true"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceCatShards = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-cat-shards"
+  number_of_shards   = 1
+  number_of_replicas = 0
+}
+
+data "elasticsearch_cat_shards" "test" {
+  index = elasticsearch_index.test.name
+}
+`
@@ -0,0 +1,185 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// ccrFollowStats are the stats of a follower index of the CCR stats API.
+type ccrFollowStats struct {
+	Index  string `json:"index"`
+	Shards []struct {
+		LeaderGlobalCheckpoint   int64 `json:"leader_global_checkpoint"`
+		FollowerGlobalCheckpoint int64 `json:"follower_global_checkpoint"`
+		OperationsWritten        int64 `json:"operations_written"`
+		FailedReadRequests       int64 `json:"failed_read_requests"`
+		FailedWriteRequests      int64 `json:"failed_write_requests"`
+		TimeSinceLastReadMillis  int64 `json:"time_since_last_read_millis"`
+		FatalException           *struct {
+			Reason string `json:"reason"`
+		} `json:"fatal_exception"`
+	} `json:"shards"`
+}
+
+func dataSourceElasticsearchCcrStats() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_ccr_stats` reads the lag and failures of the cross-cluster replication of the follower indices, e.g. to check with a postcondition that replication is healthy before further changes.",
+		Read:        dataSourceElasticsearchCcrStatsRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only read the stats of this follower index, or of the follower indices matching this pattern. Defaults to all the follower indices, and the auto-follow stats.",
+			},
+			"indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The follower indices, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"global_checkpoint_lag": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of operations the shards of the follower index are behind the leader index.",
+						},
+						"operations_written": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"failed_read_requests": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"failed_write_requests": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"time_since_last_read_millis": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The longest time since a shard of the follower index last read from the leader.",
+						},
+						"fatal_exceptions": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The reasons the replication of shards stopped, which requires the follower index to be paused and resumed, or recreated.",
+						},
+					},
+				},
+			},
+			"auto_follow_failed_follow_indices": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of indices auto-follow patterns failed to follow, without `index`.",
+			},
+			"auto_follow_failed_remote_cluster_state_requests": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of failed requests of auto-follow patterns to the remote clusters, without `index`.",
+			},
+			"auto_follow_successful_follow_indices": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of indices auto-follow patterns followed, without `index`.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchCcrStatsRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityCcr.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	path := "/_ccr/stats"
+	index := d.Get("index").(string)
+	if index != "" {
+		var err error
+		path, err = uritemplates.Expand("/{index}/_ccr/stats", map[string]string{
+			"index": index,
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for CCR stats: %+v", err)
+		}
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	// the stats of the follower indices are at the top level when reading
+	// those of an index
+	var response struct {
+		Indices         []ccrFollowStats `json:"indices"`
+		AutoFollowStats struct {
+			NumberOfFailedFollowIndices              int `json:"number_of_failed_follow_indices"`
+			NumberOfFailedRemoteClusterStateRequests int `json:"number_of_failed_remote_cluster_state_requests"`
+			NumberOfSuccessfulFollowIndices          int `json:"number_of_successful_follow_indices"`
+		} `json:"auto_follow_stats"`
+		FollowStats struct {
+			Indices []ccrFollowStats `json:"indices"`
+		} `json:"follow_stats"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling CCR stats: %+v: %s", err, body)
+	}
+	followStats := response.FollowStats.Indices
+	if index != "" {
+		followStats = response.Indices
+	}
+
+	indices := []map[string]interface{}{}
+	for _, stats := range followStats {
+		var lag, written, failedReads, failedWrites, sinceLastRead int64
+		fatal := []string{}
+		for _, shard := range stats.Shards {
+			lag += shard.LeaderGlobalCheckpoint - shard.FollowerGlobalCheckpoint
+			written += shard.OperationsWritten
+			failedReads += shard.FailedReadRequests
+			failedWrites += shard.FailedWriteRequests
+			if shard.TimeSinceLastReadMillis > sinceLastRead {
+				sinceLastRead = shard.TimeSinceLastReadMillis
+			}
+			if shard.FatalException != nil {
+				fatal = append(fatal, shard.FatalException.Reason)
+			}
+		}
+		indices = append(indices, map[string]interface{}{
+			"index":                       stats.Index,
+			"global_checkpoint_lag":       lag,
+			"operations_written":          written,
+			"failed_read_requests":        failedReads,
+			"failed_write_requests":       failedWrites,
+			"time_since_last_read_millis": sinceLastRead,
+			"fatal_exceptions":            fatal,
+		})
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i]["index"].(string) < indices[j]["index"].(string) })
+
+	id := index
+	if id == "" {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("indices", indices)
+	ds.set("auto_follow_failed_follow_indices", response.AutoFollowStats.NumberOfFailedFollowIndices)
+	ds.set("auto_follow_failed_remote_cluster_state_requests", response.AutoFollowStats.NumberOfFailedRemoteClusterStateRequests)
+	ds.set("auto_follow_successful_follow_indices", response.AutoFollowStats.NumberOfSuccessfulFollowIndices)
+	return ds.err
+}
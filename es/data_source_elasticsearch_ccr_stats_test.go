@@ -0,0 +1,117 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchCcrStatsRead(t *testing.T) {
+	shards := `[
+		{"remote_cluster": "eu", "leader_index": "logs", "follower_index": "logs-copy", "shard_id": 0, "leader_global_checkpoint": 120, "follower_global_checkpoint": 100,
+		 "operations_written": 100, "failed_read_requests": 1, "failed_write_requests": 0, "time_since_last_read_millis": 40},
+		{"remote_cluster": "eu", "leader_index": "logs", "follower_index": "logs-copy", "shard_id": 1, "leader_global_checkpoint": 50, "follower_global_checkpoint": 45,
+		 "operations_written": 45, "failed_read_requests": 2, "failed_write_requests": 1, "time_since_last_read_millis": 65,
+		 "fatal_exception": {"type": "index_not_found_exception", "reason": "no such index [logs]"}}
+	]`
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.17.0"}}`))
+		case "/_ccr/stats":
+			_, _ = w.Write([]byte(`{
+				"auto_follow_stats": {"number_of_failed_follow_indices": 1, "number_of_failed_remote_cluster_state_requests": 2, "number_of_successful_follow_indices": 3},
+				"follow_stats": {"indices": [
+					{"index": "metrics-copy", "shards": []},
+					{"index": "logs-copy", "shards": ` + shards + `}
+				]}
+			}`))
+		case "/logs-copy/_ccr/stats":
+			_, _ = w.Write([]byte(`{"indices": [{"index": "logs-copy", "shards": ` + shards + `}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchCcrStats()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"auto_follow_failed_follow_indices":                1,
+		"auto_follow_failed_remote_cluster_state_requests": 2,
+		"auto_follow_successful_follow_indices":            3,
+		"indices.#":                                        2,
+		"indices.0.index":                                  "logs-copy",
+		"indices.0.global_checkpoint_lag":                  25,
+		"indices.0.operations_written":                     145,
+		"indices.0.failed_read_requests":                   3,
+		"indices.0.failed_write_requests":                  1,
+		"indices.0.time_since_last_read_millis":            65,
+		"indices.0.fatal_exceptions.#":                     1,
+		"indices.0.fatal_exceptions.0":                     "no such index [logs]",
+		"indices.1.index":                                  "metrics-copy",
+		"indices.1.global_checkpoint_lag":                  0,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"index": "logs-copy"})
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "logs-copy" || d.Get("indices.#") != 1 || d.Get("indices.0.global_checkpoint_lag") != 25 {
+		t.Errorf("unexpected stats of logs-copy: %v", d.Get("indices"))
+	}
+}
+
+func TestDataSourceElasticsearchCcrStatsOpenSearch(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+	})
+	r := dataSourceElasticsearchCcrStats()
+	err := r.Read(r.TestResourceData(), conf)
+	if err == nil || !strings.Contains(err.Error(), "cross-cluster replication are not available on OpenSearch") {
+		t.Errorf("expected a capability error, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceCcrStats_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	if err := provider.Configure(&terraform.ResourceConfig{}); err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityCcr.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceCcrStats,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_ccr_stats.test", "id", "_all"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_ccr_stats.test", "auto_follow_failed_follow_indices"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceCcrStats = `
+data "elasticsearch_ccr_stats" "test" {}
+`
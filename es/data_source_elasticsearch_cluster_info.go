@@ -0,0 +1,134 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+type clusterInfoResponse struct {
+	Name        string `json:"name"`
+	ClusterName string `json:"cluster_name"`
+	ClusterUUID string `json:"cluster_uuid"`
+	Version     struct {
+		Number                           string `json:"number"`
+		Distribution                     string `json:"distribution"`
+		BuildFlavor                      string `json:"build_flavor"`
+		BuildType                        string `json:"build_type"`
+		BuildHash                        string `json:"build_hash"`
+		BuildDate                        string `json:"build_date"`
+		LuceneVersion                    string `json:"lucene_version"`
+		MinimumWireCompatibilityVersion  string `json:"minimum_wire_compatibility_version"`
+		MinimumIndexCompatibilityVersion string `json:"minimum_index_compatibility_version"`
+	} `json:"version"`
+	Tagline string `json:"tagline"`
+}
+
+func dataSourceElasticsearchClusterInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_cluster_info` retrieves the identity and version of the provider's cluster, e.g. to branch on its capabilities or to tag resources with it.",
+		Read:        dataSourceElasticsearchClusterInfoRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the cluster.",
+			},
+			"cluster_uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The UUID of the cluster.",
+			},
+			"node_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the node that answered.",
+			},
+			"distribution": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "`elasticsearch` or `opensearch`.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version number of the cluster, e.g. `7.10.2`.",
+			},
+			"build_flavor": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The build flavor, e.g. `default` or `oss`, empty for OpenSearch.",
+			},
+			"build_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The build type, e.g. `docker`, `tar` or `rpm`.",
+			},
+			"build_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The commit the cluster was built from.",
+			},
+			"build_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date the cluster was built.",
+			},
+			"lucene_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of Lucene.",
+			},
+			"minimum_wire_compatibility_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The oldest version of the nodes that can join the cluster.",
+			},
+			"minimum_index_compatibility_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The oldest version of the indices that the cluster can read.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchClusterInfoRead(d *schema.ResourceData, m interface{}) error {
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", "/", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var info clusterInfoResponse
+	if err := json.Unmarshal(body, &info); err != nil {
+		return fmt.Errorf("error unmarshalling cluster info: %+v: %s", err, body)
+	}
+
+	distribution := flavorElasticsearch
+	if info.Version.Distribution == flavorOpenSearch || strings.Contains(info.Tagline, "OpenSearch") {
+		distribution = flavorOpenSearch
+	}
+
+	d.SetId(info.ClusterUUID)
+	ds := &resourceDataSetter{d: d}
+	ds.set("cluster_name", info.ClusterName)
+	ds.set("cluster_uuid", info.ClusterUUID)
+	ds.set("node_name", info.Name)
+	ds.set("distribution", distribution)
+	ds.set("version", info.Version.Number)
+	ds.set("build_flavor", info.Version.BuildFlavor)
+	ds.set("build_type", info.Version.BuildType)
+	ds.set("build_hash", info.Version.BuildHash)
+	ds.set("build_date", info.Version.BuildDate)
+	ds.set("lucene_version", info.Version.LuceneVersion)
+	ds.set("minimum_wire_compatibility_version", info.Version.MinimumWireCompatibilityVersion)
+	ds.set("minimum_index_compatibility_version", info.Version.MinimumIndexCompatibilityVersion)
+	return ds.err
+}
@@ -0,0 +1,68 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchClusterInfoRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"name": "node-1",
+			"cluster_name": "search",
+			"cluster_uuid": "dHwWrT8tSb2vj5JjDXAyew",
+			"version": {"distribution": "opensearch", "number": "1.2.4", "build_type": "tar", "lucene_version": "8.10.1"},
+			"tagline": "The OpenSearch Project: https://opensearch.org/"
+		}`))
+	})
+
+	r := dataSourceElasticsearchClusterInfo()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "dHwWrT8tSb2vj5JjDXAyew" {
+		t.Errorf("expected the id to be the cluster uuid, got %q", d.Id())
+	}
+	expected := map[string]string{
+		"cluster_name":   "search",
+		"node_name":      "node-1",
+		"distribution":   "opensearch",
+		"version":        "1.2.4",
+		"build_type":     "tar",
+		"lucene_version": "8.10.1",
+		"build_flavor":   "",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key).(string); actual != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceClusterInfo_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceClusterInfo,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_cluster_info.test", "cluster_uuid"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_cluster_info.test", "version"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_cluster_info.test", "distribution"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceClusterInfo = `
+data "elasticsearch_cluster_info" "test" {}
+`
@@ -0,0 +1,173 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+type dataStreamInfo struct {
+	Name           string `json:"name"`
+	TimestampField struct {
+		Name string `json:"name"`
+	} `json:"timestamp_field"`
+	Indices []struct {
+		IndexName string `json:"index_name"`
+		IndexUUID string `json:"index_uuid"`
+	} `json:"indices"`
+	Generation int    `json:"generation"`
+	Status     string `json:"status"`
+	Template   string `json:"template"`
+	IlmPolicy  string `json:"ilm_policy"`
+	Hidden     bool   `json:"hidden"`
+	// FailureStore is a flag before Elasticsearch 8.15
+	FailureStore json.RawMessage `json:"failure_store"`
+}
+
+// failureStore returns whether the failure store of the data stream is
+// enabled, and its indices.
+func (i dataStreamInfo) failureStore() (bool, []string) {
+	var enabled bool
+	if err := json.Unmarshal(i.FailureStore, &enabled); err == nil {
+		return enabled, []string{}
+	}
+	var failureStore struct {
+		Enabled bool `json:"enabled"`
+		Indices []struct {
+			IndexName string `json:"index_name"`
+		} `json:"indices"`
+	}
+	_ = json.Unmarshal(i.FailureStore, &failureStore)
+	indices := []string{}
+	for _, index := range failureStore.Indices {
+		indices = append(indices, index.IndexName)
+	}
+	return failureStore.Enabled, indices
+}
+
+func dataSourceElasticsearchDataStream() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_data_stream` reads a data stream, e.g. to check that it was created by the expected template with the expected lifecycle policy.",
+		Read:        dataSourceElasticsearchDataStreamRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the data stream.",
+			},
+			"backing_indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The backing indices of the data stream, from the oldest to the write index.",
+			},
+			"write_index": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The current write index of the data stream.",
+			},
+			"generation": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of times the data stream was rolled over, plus one.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The health of the backing indices: `GREEN`, `YELLOW` or `RED`.",
+			},
+			"template": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the index template that created the data stream.",
+			},
+			"ilm_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The index lifecycle policy of the data stream, empty if there is none or on OpenSearch.",
+			},
+			"timestamp_field": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"hidden": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"failure_store_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the documents failing ingestion are stored in the failure store of the data stream, always false before Elasticsearch 8.15.",
+			},
+			"failure_store_indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The indices of the failure store, from the oldest to the write index.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchDataStreamRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("name").(string)
+	if err := capabilityDataStreams.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+	path, err := uritemplates.Expand("/_data_stream/{name}", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for data stream: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		DataStreams []dataStreamInfo `json:"data_streams"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling data stream: %+v: %s", err, body)
+	}
+	var dataStream *dataStreamInfo
+	for i := range response.DataStreams {
+		if response.DataStreams[i].Name == name {
+			dataStream = &response.DataStreams[i]
+		}
+	}
+	if dataStream == nil {
+		return fmt.Errorf("data stream %q not found", name)
+	}
+
+	var backingIndices []string
+	var writeIndex string
+	for _, index := range dataStream.Indices {
+		backingIndices = append(backingIndices, index.IndexName)
+		writeIndex = index.IndexName
+	}
+
+	d.SetId(name)
+	ds := &resourceDataSetter{d: d}
+	ds.set("backing_indices", backingIndices)
+	ds.set("write_index", writeIndex)
+	ds.set("generation", dataStream.Generation)
+	ds.set("status", dataStream.Status)
+	ds.set("template", dataStream.Template)
+	ds.set("ilm_policy", dataStream.IlmPolicy)
+	ds.set("timestamp_field", dataStream.TimestampField.Name)
+	ds.set("hidden", dataStream.Hidden)
+	failureStoreEnabled, failureStoreIndices := dataStream.failureStore()
+	ds.set("failure_store_enabled", failureStoreEnabled)
+	ds.set("failure_store_indices", failureStoreIndices)
+	return ds.err
+}
@@ -0,0 +1,124 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchDataStreamRead(t *testing.T) {
+	version := "7.10.2"
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "` + version + `"}}`))
+		case "/_data_stream/logs-app-default":
+			_, _ = w.Write([]byte(`{"data_streams": [{
+				"name": "logs-app-default",
+				"timestamp_field": {"name": "@timestamp"},
+				"indices": [
+					{"index_name": ".ds-logs-app-default-2021.01.01-000001", "index_uuid": "u1"},
+					{"index_name": ".ds-logs-app-default-2021.01.02-000002", "index_uuid": "u2"}
+				],
+				"generation": 2,
+				"status": "GREEN",
+				"template": "logs-app",
+				"ilm_policy": "logs",
+				"hidden": false,
+				"failure_store": {"enabled": true, "indices": [{"index_name": ".fs-logs-app-default-2021.01.02-000003", "index_uuid": "u3"}]}
+			}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchDataStream()
+	d := r.TestResourceData()
+	_ = d.Set("name", "logs-app-default")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"backing_indices.#":       2,
+		"write_index":             ".ds-logs-app-default-2021.01.02-000002",
+		"generation":              2,
+		"status":                  "GREEN",
+		"template":                "logs-app",
+		"ilm_policy":              "logs",
+		"timestamp_field":         "@timestamp",
+		"failure_store_enabled":   true,
+		"failure_store_indices.0": ".fs-logs-app-default-2021.01.02-000003",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	conf = testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "7.8.0"}}`))
+	})
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), "data streams require Elasticsearch >= 7.9.0") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceDataStream_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityDataStreams.check(testAccProvider.Meta().(*ProviderConf)); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccProviders,
+		// the data stream outlives its template, so neither is managed by the
+		// configuration
+		CheckDestroy: func(s *terraform.State) error {
+			if err := testAccDataStreamRequest("DELETE", "/_data_stream/terraform-test-data-stream", nil); err != nil {
+				return err
+			}
+			return testAccDataStreamRequest("DELETE", "/_index_template/terraform-test-data-stream", nil)
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					template := `{"index_patterns": ["terraform-test-data-stream*"], "data_stream": {}, "priority": 500}`
+					if err := testAccDataStreamRequest("PUT", "/_index_template/terraform-test-data-stream", template); err != nil {
+						t.Fatal(err)
+					}
+					if err := testAccDataStreamRequest("PUT", "/_data_stream/terraform-test-data-stream", nil); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccElasticsearchDataSourceDataStream,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_data_stream.test", "template", "terraform-test-data-stream"),
+					resource.TestCheckResourceAttr("data.elasticsearch_data_stream.test", "generation", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_data_stream.test", "backing_indices.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataStreamRequest(method string, path string, body interface{}) error {
+	meta := testAccProvider.Meta()
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	_, err = performRequest(providerContext(meta), esClient, method, path, nil, body)
+	return err
+}
+
+var testAccElasticsearchDataSourceDataStream = `
+data "elasticsearch_data_stream" "test" {
+  name = "terraform-test-data-stream"
+}
+`
@@ -0,0 +1,158 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// deprecation is an issue reported by the deprecation info API.
+type deprecation struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Url     string `json:"url"`
+	Details string `json:"details"`
+}
+
+func dataSourceElasticsearchDeprecations() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_deprecations` lists the uses of deprecated features that the next major version removes, e.g. to fail a plan before an upgrade while critical ones remain.",
+		Read:        dataSourceElasticsearchDeprecationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only check this index, data stream or alias, with wildcards, besides the cluster. Defaults to all the indices.",
+			},
+			"deprecations": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The deprecations, sorted by category, resource and message.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"category": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "What the deprecation is about, e.g. `cluster_settings`, `node_settings`, `index_settings` or `ml_settings`.",
+						},
+						"resource": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The index, data stream, template or policy the deprecation is about, empty for the cluster and nodes.",
+						},
+						"level": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`none`, `info`, `warning` or `critical`, which blocks the upgrade.",
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The documentation of the deprecation.",
+						},
+						"details": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"critical": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether any of the deprecations is critical.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchDeprecationsRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityDeprecations.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	path := "/_migration/deprecations"
+	index := d.Get("index").(string)
+	if index != "" {
+		var err error
+		path, err = uritemplates.Expand("/{index}/_migration/deprecations", map[string]string{
+			"index": index,
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for deprecations: %+v", err)
+		}
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	// each category is a list of deprecations, or the lists of the
+	// deprecations of each index, template or policy
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling deprecations: %+v: %s", err, body)
+	}
+	deprecations := []map[string]interface{}{}
+	critical := false
+	add := func(category, resource string, list []deprecation) {
+		for _, dep := range list {
+			critical = critical || dep.Level == "critical"
+			deprecations = append(deprecations, map[string]interface{}{
+				"category": category,
+				"resource": resource,
+				"level":    dep.Level,
+				"message":  dep.Message,
+				"url":      dep.Url,
+				"details":  dep.Details,
+			})
+		}
+	}
+	for category, raw := range response {
+		var list []deprecation
+		if err := json.Unmarshal(raw, &list); err == nil {
+			add(category, "", list)
+			continue
+		}
+		var byResource map[string][]deprecation
+		if err := json.Unmarshal(raw, &byResource); err != nil {
+			return fmt.Errorf("error unmarshalling deprecations of %s: %+v: %s", category, err, raw)
+		}
+		for resource, list := range byResource {
+			add(category, resource, list)
+		}
+	}
+	sort.Slice(deprecations, func(i, j int) bool {
+		a, b := deprecations[i], deprecations[j]
+		if a["category"] != b["category"] {
+			return a["category"].(string) < b["category"].(string)
+		}
+		if a["resource"] != b["resource"] {
+			return a["resource"].(string) < b["resource"].(string)
+		}
+		return a["message"].(string) < b["message"].(string)
+	})
+
+	id := index
+	if id == "" {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("deprecations", deprecations)
+	ds.set("critical", critical)
+	return ds.err
+}
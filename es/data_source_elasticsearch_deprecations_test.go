@@ -0,0 +1,92 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchDeprecationsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.17.0"}}`))
+		case "/_migration/deprecations":
+			_, _ = w.Write([]byte(`{
+				"cluster_settings": [{"level": "warning", "message": "Realm order will be required", "url": "https://example.com/realms", "details": "ldap1"}],
+				"node_settings": [],
+				"index_settings": {
+					"logs": [{"level": "critical", "message": "Index created before 7.0", "url": "https://example.com/reindex", "details": "created with 6.8.0"}]
+				},
+				"ml_settings": []
+			}`))
+		case "/metrics-*/_migration/deprecations":
+			_, _ = w.Write([]byte(`{"cluster_settings": [], "node_settings": [], "index_settings": {}, "ml_settings": []}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchDeprecations()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"deprecations.#":          2,
+		"deprecations.0.category": "cluster_settings",
+		"deprecations.0.resource": "",
+		"deprecations.0.details":  "ldap1",
+		"deprecations.1.category": "index_settings",
+		"deprecations.1.resource": "logs",
+		"deprecations.1.level":    "critical",
+		"critical":                true,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("index", "metrics-*")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("deprecations.#") != 0 || d.Get("critical") != false {
+		t.Errorf("expected no deprecations for metrics-*, got %v", d.Get("deprecations"))
+	}
+}
+
+func TestAccElasticsearchDataSourceDeprecations_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	if err := provider.Configure(&terraform.ResourceConfig{}); err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityDeprecations.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceDeprecations,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_deprecations.test", "critical"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceDeprecations = `
+data "elasticsearch_deprecations" "test" {}
+`
@@ -0,0 +1,143 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+type enrichPolicy struct {
+	Name         string          `json:"name"`
+	Indices      []string        `json:"indices"`
+	MatchField   string          `json:"match_field"`
+	EnrichFields []string        `json:"enrich_fields"`
+	Query        json.RawMessage `json:"query"`
+}
+
+func dataSourceElasticsearchEnrichPolicies() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_enrich_policies` lists enrich policies, e.g. to check the ones used by the enrich processors of a pipeline exist.",
+		Read:        dataSourceElasticsearchEnrichPoliciesRead,
+
+		Schema: map[string]*schema.Schema{
+			"names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only list these policies, which must all exist. Defaults to all the policies.",
+			},
+			"policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The policies, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`match`, `geo_match` or `range`.",
+						},
+						"indices": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The source indices of the policy.",
+						},
+						"match_field": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enrich_fields": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"query": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The JSON query selecting the source documents, empty if there is none.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchEnrichPoliciesRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityEnrichPolicies.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, name := range d.Get("names").([]interface{}) {
+		names = append(names, name.(string))
+	}
+	path := "/_enrich/policy"
+	if len(names) > 0 {
+		var err error
+		path, err = uritemplates.Expand("/_enrich/policy/{names}", map[string]string{
+			"names": strings.Join(names, ","),
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for enrich policies: %+v", err)
+		}
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Policies []struct {
+			Config map[string]enrichPolicy `json:"config"`
+		} `json:"policies"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling enrich policies: %+v: %s", err, body)
+	}
+
+	policies := []map[string]interface{}{}
+	found := make(map[string]bool)
+	for _, p := range response.Policies {
+		for policyType, policy := range p.Config {
+			found[policy.Name] = true
+			policies = append(policies, map[string]interface{}{
+				"name":          policy.Name,
+				"type":          policyType,
+				"indices":       policy.Indices,
+				"match_field":   policy.MatchField,
+				"enrich_fields": policy.EnrichFields,
+				"query":         compactJson(policy.Query),
+			})
+		}
+	}
+	for _, name := range names {
+		if !found[name] {
+			return fmt.Errorf("enrich policy %q not found", name)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i]["name"].(string) < policies[j]["name"].(string) })
+
+	id := strings.Join(names, ",")
+	if id == "" {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("policies", policies)
+	return ds.err
+}
@@ -0,0 +1,77 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchEnrichPoliciesRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_enrich/policy/users,postal", "/_enrich/policy/users,missing":
+			_, _ = w.Write([]byte(`{"policies": [
+				{"config": {"match": {"name": "users", "indices": ["users"], "match_field": "email", "enrich_fields": ["first_name", "last_name"], "query": {"term": {"active": true}}}}},
+				{"config": {"geo_match": {"name": "postal", "indices": ["postal_codes"], "match_field": "location", "enrich_fields": ["postal_code"]}}}
+			]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchEnrichPolicies()
+	d := r.TestResourceData()
+	_ = d.Set("names", []string{"users", "postal"})
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"policies.#":                 2,
+		"policies.0.name":            "postal",
+		"policies.0.type":            "geo_match",
+		"policies.0.query":           "",
+		"policies.1.match_field":     "email",
+		"policies.1.enrich_fields.1": "last_name",
+		"policies.1.query":           `{"term":{"active":true}}`,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("names", []string{"users", "missing"})
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), `"missing" not found`) {
+		t.Errorf("expected an error for a missing policy, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceEnrichPolicies_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityEnrichPolicies.check(testAccProvider.Meta().(*ProviderConf)); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceEnrichPolicies,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_enrich_policies.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceEnrichPolicies = `
+data "elasticsearch_enrich_policies" "test" {}
+`
@@ -0,0 +1,224 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// healthIndicator is an indicator of the health report API.
+type healthIndicator struct {
+	Status  string          `json:"status"`
+	Symptom string          `json:"symptom"`
+	Details json.RawMessage `json:"details"`
+	Impacts []struct {
+		ID          string   `json:"id"`
+		Severity    int      `json:"severity"`
+		Description string   `json:"description"`
+		ImpactAreas []string `json:"impact_areas"`
+	} `json:"impacts"`
+	Diagnosis []struct {
+		ID                string          `json:"id"`
+		Cause             string          `json:"cause"`
+		Action            string          `json:"action"`
+		HelpURL           string          `json:"help_url"`
+		AffectedResources json.RawMessage `json:"affected_resources"`
+	} `json:"diagnosis"`
+}
+
+func dataSourceElasticsearchHealthReport() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_health_report` reads the health report of the cluster, with the status, impacts and diagnoses of each of its indicators, e.g. the shards availability or the disk, for richer checks than the color of the cluster health.",
+		Read:        dataSourceElasticsearchHealthReportRead,
+
+		Schema: map[string]*schema.Schema{
+			"feature": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only report this indicator, e.g. `shards_availability`. Defaults to all the indicators.",
+			},
+			"verbose": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Report the details and diagnoses of the indicators, which are more expensive to compute.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The worst status of the indicators, `green`, `unknown`, `yellow` or `red`, empty when reporting a single feature.",
+			},
+			"indicators": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The indicators, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`green`, `unknown`, `yellow` or `red`.",
+						},
+						"symptom": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A summary of the status.",
+						},
+						"details": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The JSON details of the indicator, empty if not verbose.",
+						},
+						"impacts": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The impacts of the status on the cluster.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"severity": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "From 1, the most severe, to 5.",
+									},
+									"description": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"impact_areas": {
+										Type:        schema.TypeList,
+										Computed:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Description: "e.g. `search`, `ingest`, `backup` or `deployment_management`.",
+									},
+								},
+							},
+						},
+						"diagnoses": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The causes of the status, and the actions to fix them, if verbose.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"cause": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"action": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"help_url": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"affected_resources": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The JSON of the affected indices, nodes, policies or repositories.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchHealthReportRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityHealthReport.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	path := "/_health_report"
+	feature := d.Get("feature").(string)
+	if feature != "" {
+		var err error
+		path, err = uritemplates.Expand("/_health_report/{feature}", map[string]string{
+			"feature": feature,
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for health report: %+v", err)
+		}
+	}
+	params := url.Values{
+		"verbose": []string{strconv.FormatBool(d.Get("verbose").(bool))},
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, params, nil)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Status     string                     `json:"status"`
+		Indicators map[string]healthIndicator `json:"indicators"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling health report: %+v: %s", err, body)
+	}
+
+	indicators := []map[string]interface{}{}
+	for name, indicator := range response.Indicators {
+		impacts := []map[string]interface{}{}
+		for _, impact := range indicator.Impacts {
+			impacts = append(impacts, map[string]interface{}{
+				"id":           impact.ID,
+				"severity":     impact.Severity,
+				"description":  impact.Description,
+				"impact_areas": impact.ImpactAreas,
+			})
+		}
+		diagnoses := []map[string]interface{}{}
+		for _, diagnosis := range indicator.Diagnosis {
+			diagnoses = append(diagnoses, map[string]interface{}{
+				"id":                 diagnosis.ID,
+				"cause":              diagnosis.Cause,
+				"action":             diagnosis.Action,
+				"help_url":           diagnosis.HelpURL,
+				"affected_resources": compactJson(diagnosis.AffectedResources),
+			})
+		}
+		indicators = append(indicators, map[string]interface{}{
+			"name":      name,
+			"status":    indicator.Status,
+			"symptom":   indicator.Symptom,
+			"details":   compactJson(indicator.Details),
+			"impacts":   impacts,
+			"diagnoses": diagnoses,
+		})
+	}
+	sort.Slice(indicators, func(i, j int) bool { return indicators[i]["name"].(string) < indicators[j]["name"].(string) })
+
+	id := feature
+	if id == "" {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("status", response.Status)
+	ds.set("indicators", indicators)
+	return ds.err
+}
@@ -0,0 +1,114 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestDataSourceElasticsearchHealthReportRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0"}}`))
+		case "/_health_report":
+			if r.URL.Query().Get("verbose") != "true" {
+				t.Errorf("expected a verbose report, got %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{
+				"cluster_name": "prod",
+				"status": "yellow",
+				"indicators": {
+					"shards_availability": {
+						"status": "yellow",
+						"symptom": "This cluster has 1 unavailable replica shard.",
+						"details": {"unassigned_replicas": 1, "started_primaries": 10},
+						"impacts": [{"id": "elasticsearch:health:shards_availability:impact:replica_unassigned", "severity": 2, "description": "Searches might be slower than usual.", "impact_areas": ["search"]}],
+						"diagnosis": [{"id": "elasticsearch:health:shards_availability:diagnosis:increase_tier_capacity_for_allocations:tier:data_hot", "cause": "Not enough nodes in the data tier.", "action": "Increase the number of nodes in the tier.", "help_url": "https://ela.st/tier-capacity", "affected_resources": {"indices": ["logs"]}}]
+					},
+					"disk": {"status": "green", "symptom": "The cluster has enough available disk space.", "details": {}}
+				}
+			}`))
+		case "/_health_report/disk":
+			if r.URL.Query().Get("verbose") != "false" {
+				t.Errorf("expected a report without details, got %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"cluster_name": "prod", "indicators": {"disk": {"status": "green", "symptom": "The cluster has enough available disk space."}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchHealthReport()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"status":                                      "yellow",
+		"indicators.#":                                2,
+		"indicators.0.name":                           "disk",
+		"indicators.0.details":                        "{}",
+		"indicators.1.status":                         "yellow",
+		"indicators.1.details":                        `{"unassigned_replicas":1,"started_primaries":10}`,
+		"indicators.1.impacts.0.severity":             2,
+		"indicators.1.impacts.0.impact_areas.0":       "search",
+		"indicators.1.diagnoses.0.help_url":           "https://ela.st/tier-capacity",
+		"indicators.1.diagnoses.0.affected_resources": `{"indices":["logs"]}`,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("feature", "disk")
+	_ = d.Set("verbose", false)
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "disk" || d.Get("status") != "" || d.Get("indicators.#") != 1 || d.Get("indicators.0.details") != "" {
+		t.Errorf("unexpected report of the disk: %v", d.Get("indicators"))
+	}
+}
+
+func TestDataSourceElasticsearchHealthReportVersion(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "8.6.2"}}`))
+	})
+	r := dataSourceElasticsearchHealthReport()
+	err := r.Read(r.TestResourceData(), conf)
+	if err == nil || !strings.Contains(err.Error(), "health reports require Elasticsearch >= 8.7.0") {
+		t.Errorf("expected a capability error, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceHealthReport_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityHealthReport.check(testAccProvider.Meta().(*ProviderConf)); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceHealthReport,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_health_report.test", "indicators.0.name", "shards_availability"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceHealthReport = `
+data "elasticsearch_health_report" "test" {
+  feature = "shards_availability"
+}
+`
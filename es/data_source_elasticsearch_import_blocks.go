@@ -0,0 +1,213 @@
+package es
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+// importBlockSource lists the objects managed by a resource type, on the
+// clusters providing capability, and of flavor by default.
+type importBlockSource struct {
+	capability capability
+	flavor     string
+	lister     objectLister
+}
+
+var importBlockSources = map[string]importBlockSource{
+	"elasticsearch_xpack_user":                {capabilityXpackNativeUsers, flavorElasticsearch, objectListers["elasticsearch_xpack_user"]},
+	"elasticsearch_xpack_role":                {capabilityXpackSecurity, flavorElasticsearch, objectListers["elasticsearch_xpack_role"]},
+	"elasticsearch_opendistro_user":           {capabilityOpenDistroSecurity, flavorOpenSearch, objectListers["elasticsearch_opendistro_user"]},
+	"elasticsearch_opendistro_role":           {capabilityOpenDistroSecurity, flavorOpenSearch, objectListers["elasticsearch_opendistro_role"]},
+	"elasticsearch_index_template":            {capabilityIndexTemplates, "", objectListers["elasticsearch_index_template"]},
+	"elasticsearch_composable_index_template": {capabilityComposableIndexTemplates, "", objectListers["elasticsearch_composable_index_template"]},
+	"elasticsearch_component_template":        {capabilityComponentTemplates, "", objectListers["elasticsearch_component_template"]},
+	"elasticsearch_ingest_pipeline":           {capabilityIngestPipelines, "", objectListers["elasticsearch_ingest_pipeline"]},
+	// the resources without on_existing have their listers here
+	"elasticsearch_xpack_index_lifecycle_policy": {capabilityIndexLifecyclePolicies, flavorElasticsearch, objectLister{
+		path7:  "/_ilm/policy",
+		path6:  "/_ilm/policy",
+		decode: decodeNestedObjects("policy"),
+	}},
+	"elasticsearch_opendistro_ism_policy": {capabilityOpenDistroISM, flavorOpenSearch, objectListers["elasticsearch_opendistro_ism_policy"]},
+	"elasticsearch_xpack_watch": {capabilityWatchQueries, flavorElasticsearch, objectLister{
+		path7:     "/_watcher/_query/watches",
+		pageTotal: "count",
+		pageBody:  true,
+		decode:    decodeListedObjects("watches", "_id", "watch"),
+	}},
+}
+
+func dataSourceElasticsearchImportBlocks() *schema.Resource {
+	resourceTypes := make([]string, 0, len(importBlockSources))
+	for resourceType := range importBlockSources {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	return &schema.Resource{
+		Description: "`elasticsearch_import_blocks` walks a live cluster and renders an `import` block for each of its users, roles, templates, pipelines, lifecycle policies and watches, to adopt an existing cluster. Write `hcl` to a file, e.g. with `terraform output -raw`, and run `terraform plan -generate-config-out=generated.tf` (Terraform >= 1.5) to generate the configuration of the resources.",
+		Read:        dataSourceElasticsearchImportBlocksRead,
+
+		Schema: map[string]*schema.Schema{
+			"resource_types": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(resourceTypes, false),
+				},
+				Description: fmt.Sprintf("Only import these resource types, which the cluster must all provide: `%s`. Defaults to the ones of the flavor of the cluster that its version provides, skipping the APIs its license forbids, e.g. watches on a basic license.", strings.Join(resourceTypes, "`, `")),
+			},
+			"include_system": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Also import the objects reserved, hidden or managed by the cluster, e.g. the builtin users and roles, the templates and pipelines with `_meta.managed`, and the ones whose name starts with a `.`.",
+			},
+			"imports": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The imported objects, sorted by resource type and ID.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID to import the resource from, e.g. the name of the user.",
+						},
+						"to": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The address of the resource, named after the ID, e.g. `elasticsearch_xpack_user.alice`.",
+						},
+					},
+				},
+			},
+			"hcl": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The `import` blocks of the objects.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchImportBlocksRead(d *schema.ResourceData, m interface{}) error {
+	conf := m.(*ProviderConf)
+	esClient, err := getClient(conf)
+	if err != nil {
+		return err
+	}
+
+	var resourceTypes []string
+	for _, resourceType := range d.Get("resource_types").([]interface{}) {
+		resourceTypes = append(resourceTypes, resourceType.(string))
+	}
+	explicit := len(resourceTypes) > 0
+	if !explicit {
+		flavor := flavorElasticsearch
+		if _, detected := clusterVersion(conf); detected == flavorOpenSearch {
+			flavor = flavorOpenSearch
+		}
+		for resourceType, source := range importBlockSources {
+			if source.flavor != "" && source.flavor != flavor {
+				continue
+			}
+			if err := source.capability.check(conf); err != nil {
+				log.Printf("[INFO] Not importing %s: %+v", resourceType, err)
+				continue
+			}
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	sort.Strings(resourceTypes)
+
+	includeSystem := d.Get("include_system").(bool)
+	imports := []map[string]interface{}{}
+	var blocks []string
+	for _, resourceType := range resourceTypes {
+		source := importBlockSources[resourceType]
+		if explicit {
+			if err := source.capability.check(conf); err != nil {
+				return err
+			}
+		}
+		objects, err := source.lister.list(providerContext(m), esClient, resourceType)
+		if !explicit && (elastic7.IsStatusCode(err, 403) || elastic6.IsStatusCode(err, 403) || elastic5.IsStatusCode(err, 403)) {
+			log.Printf("[WARN] Not importing %s, listing them is forbidden: %+v", resourceType, err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error listing the objects of %s: %+v", resourceType, err)
+		}
+
+		labels := map[string]bool{}
+		for _, id := range filterObjectNames(objects, "*", includeSystem) {
+			label := importBlockLabel(id)
+			for i := 2; labels[label]; i++ {
+				label = fmt.Sprintf("%s_%d", importBlockLabel(id), i)
+			}
+			labels[label] = true
+
+			to := resourceType + "." + label
+			imports = append(imports, map[string]interface{}{
+				"resource_type": resourceType,
+				"id":            id,
+				"to":            to,
+			})
+			blocks = append(blocks, fmt.Sprintf("import {\n  to = %s\n  id = %s\n}\n", to, hclQuote(id)))
+		}
+	}
+
+	id := strings.Join(resourceTypes, ",")
+	if !explicit {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("imports", imports)
+	ds.set("hcl", strings.Join(blocks, "\n"))
+	return ds.err
+}
+
+// importBlockLabel returns the name of a resource imported from id, replacing
+// the characters Terraform doesn't allow in identifiers.
+func importBlockLabel(id string) string {
+	var b strings.Builder
+	for i, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9', r == '-':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// hclQuote returns s as an HCL string literal, escaping the template
+// sequences as well as the quotes.
+func hclQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "${", "$${", "%{", "%%{")
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,140 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchImportBlocksRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0"}}`))
+		case "/_security/user":
+			_, _ = w.Write([]byte(`{
+				"elastic": {"username": "elastic", "metadata": {"_reserved": true}},
+				"alice": {"username": "alice", "metadata": {}},
+				"bob.smith": {"username": "bob.smith", "metadata": {}}
+			}`))
+		case "/_security/role":
+			_, _ = w.Write([]byte(`{"superuser": {"metadata": {"_reserved": true}}, "ops": {"metadata": {}}}`))
+		case "/_template":
+			_, _ = w.Write([]byte(`{".monitoring-es": {}, "legacy": {}}`))
+		case "/_index_template":
+			_, _ = w.Write([]byte(`{"index_templates": [
+				{"name": "logs", "index_template": {"_meta": {"managed": true}}},
+				{"name": "app-logs", "index_template": {}}
+			]}`))
+		case "/_component_template":
+			_, _ = w.Write([]byte(`{"component_templates": []}`))
+		case "/_ingest/pipeline":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{}`))
+		case "/_ilm/policy":
+			_, _ = w.Write([]byte(`{"30-days-default": {"policy": {"_meta": {"managed": true}}}, "hot-warm": {"policy": {}}}`))
+		case "/_watcher/_query/watches":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"type": "security_exception", "reason": "current license is non-compliant for [watcher]"}, "status": 403}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchImportBlocks()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"imports.#":    6,
+		"imports.0.to": "elasticsearch_composable_index_template.app-logs",
+		"imports.1.to": "elasticsearch_index_template.legacy",
+		"imports.2.to": "elasticsearch_xpack_index_lifecycle_policy.hot-warm",
+		"imports.3.to": "elasticsearch_xpack_role.ops",
+		"imports.4.to": "elasticsearch_xpack_user.alice",
+		"imports.5.id": "bob.smith",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("resource_types", []string{"elasticsearch_xpack_user"})
+	_ = d.Set("include_system", true)
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	hcl := `import {
+  to = elasticsearch_xpack_user.alice
+  id = "alice"
+}
+
+import {
+  to = elasticsearch_xpack_user.bob_smith
+  id = "bob.smith"
+}
+
+import {
+  to = elasticsearch_xpack_user.elastic
+  id = "elastic"
+}
+`
+	if actual := d.Get("hcl").(string); actual != hcl {
+		t.Errorf("unexpected import blocks:\n%s", actual)
+	}
+
+	_ = d.Set("resource_types", []string{"elasticsearch_xpack_watch"})
+	if err := r.Read(d, conf); err == nil {
+		t.Errorf("expected listing explicit resource types to fail if it's forbidden")
+	}
+}
+
+func TestImportBlockLabel(t *testing.T) {
+	for id, expected := range map[string]string{
+		"alice":       "alice",
+		"logs-app":    "logs-app",
+		"bob.smith":   "bob_smith",
+		"1st-policy":  "_1st-policy",
+		"-x":          "_-x",
+		"ünïcode":     "_n_code",
+		"":            "_",
+		"a b/c\"d\\e": "a_b_c_d_e",
+	} {
+		if actual := importBlockLabel(id); actual != expected {
+			t.Errorf("expected the label of %q to be %q, got %q", id, expected, actual)
+		}
+	}
+
+	if actual := hclQuote("a\"b\\c ${x} %{y}\n"); actual != `"a\"b\\c $${x} %%{y}\n"` {
+		t.Errorf("unexpected quoting: %s", actual)
+	}
+}
+
+func TestAccElasticsearchDataSourceImportBlocks_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceImportBlocks,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_import_blocks.test", "id", "elasticsearch_index_template,elasticsearch_ingest_pipeline"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_import_blocks.test", "imports.#"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceImportBlocks = `
+data "elasticsearch_import_blocks" "test" {
+  resource_types = ["elasticsearch_index_template", "elasticsearch_ingest_pipeline"]
+}
+`
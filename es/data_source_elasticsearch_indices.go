@@ -0,0 +1,169 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+type catIndex struct {
+	Index     string  `json:"index"`
+	UUID      string  `json:"uuid"`
+	Health    string  `json:"health"`
+	Status    string  `json:"status"`
+	Primaries *string `json:"pri"`
+	Replicas  *string `json:"rep"`
+	DocsCount *string `json:"docs.count"`
+	StoreSize *string `json:"store.size"`
+}
+
+func dataSourceElasticsearchIndices() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_indices` lists the indices matching a pattern, with their health, document count and size.",
+		Read:        dataSourceElasticsearchIndicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*",
+				Description: "Only list the indices matching this pattern, or comma separated list of patterns, e.g. `logs-*,-logs-old-*`.",
+			},
+			"expand_wildcards": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The kinds of indices that the wildcards of `pattern` match: `open`, `closed`, `hidden`, `none` or `all`. Defaults to the cluster default, `all` for Elasticsearch.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"open", "closed", "hidden", "none", "all"}, false),
+				},
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The sorted names of the indices.",
+			},
+			"indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The indices, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"health": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`green`, `yellow` or `red`, empty for closed indices.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`open` or `close`.",
+						},
+						"number_of_shards": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"number_of_replicas": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"docs_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of documents, 0 for closed indices.",
+						},
+						"store_size_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the primaries and replicas, 0 for closed indices.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchIndicesRead(d *schema.ResourceData, m interface{}) error {
+	pattern := d.Get("pattern").(string)
+	path, err := uritemplates.Expand("/_cat/indices/{pattern}", map[string]string{
+		"pattern": pattern,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for indices: %+v", err)
+	}
+	params := url.Values{
+		"format": []string{"json"},
+		"bytes":  []string{"b"},
+		"h":      []string{"index,uuid,health,status,pri,rep,docs.count,store.size"},
+	}
+	var expandWildcards []string
+	for _, w := range d.Get("expand_wildcards").([]interface{}) {
+		expandWildcards = append(expandWildcards, w.(string))
+	}
+	if len(expandWildcards) > 0 {
+		params.Set("expand_wildcards", strings.Join(expandWildcards, ","))
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, params, nil)
+	if err != nil {
+		return err
+	}
+
+	var catIndices []catIndex
+	if err := json.Unmarshal(body, &catIndices); err != nil {
+		return fmt.Errorf("error unmarshalling indices: %+v: %s", err, body)
+	}
+	sort.Slice(catIndices, func(i, j int) bool { return catIndices[i].Index < catIndices[j].Index })
+
+	names := make([]string, 0, len(catIndices))
+	indices := make([]map[string]interface{}, 0, len(catIndices))
+	for _, index := range catIndices {
+		names = append(names, index.Index)
+		indices = append(indices, map[string]interface{}{
+			"name":               index.Index,
+			"uuid":               index.UUID,
+			"health":             index.Health,
+			"status":             index.Status,
+			"number_of_shards":   catInt(index.Primaries),
+			"number_of_replicas": catInt(index.Replicas),
+			"docs_count":         catInt(index.DocsCount),
+			"store_size_bytes":   catInt(index.StoreSize),
+		})
+	}
+
+	d.SetId(pattern)
+	ds := &resourceDataSetter{d: d}
+	ds.set("names", names)
+	ds.set("indices", indices)
+	return ds.err
+}
+
+// catInt parses a number of the cat APIs, which are null for closed indices.
+func catInt(s *string) int {
+	if s == nil {
+		return 0
+	}
+	i, _ := strconv.Atoi(*s)
+	return i
+}
@@ -0,0 +1,85 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchIndicesRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_cat/indices/app-*":
+			if r.URL.Query().Get("expand_wildcards") != "open,closed" {
+				t.Errorf("unexpected expand_wildcards: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[
+				{"index": "app-2", "uuid": "u2", "health": null, "status": "close", "pri": "1", "rep": "1", "docs.count": null, "store.size": null},
+				{"index": "app-1", "uuid": "u1", "health": "green", "status": "open", "pri": "3", "rep": "1", "docs.count": "42", "store.size": "12345"}
+			]`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchIndices()
+	d := r.TestResourceData()
+	_ = d.Set("pattern", "app-*")
+	_ = d.Set("expand_wildcards", []string{"open", "closed"})
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"names.#":                    2,
+		"names.0":                    "app-1",
+		"indices.0.health":           "green",
+		"indices.0.number_of_shards": 3,
+		"indices.0.docs_count":       42,
+		"indices.0.store_size_bytes": 12345,
+		"indices.1.status":           "close",
+		"indices.1.docs_count":       0,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceIndices_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceIndices,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_indices.test", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_indices.test", "indices.0.name", "terraform-test-indices"),
+					resource.TestCheckResourceAttr("data.elasticsearch_indices.test", "indices.0.number_of_shards", "1"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceIndices = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-indices"
+  number_of_shards   = 1
+  number_of_replicas = 0
+}
+
+data "elasticsearch_indices" "test" {
+  pattern = "terraform-test-indices*"
+
+  depends_on = [elasticsearch_index.test]
+}
+`
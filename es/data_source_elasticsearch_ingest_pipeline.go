@@ -0,0 +1,100 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+func dataSourceElasticsearchIngestPipeline() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_ingest_pipeline` reads an existing ingest pipeline, e.g. one installed by Fleet or an integration, to reference it without copying its body.",
+		Read:        dataSourceElasticsearchIngestPipelineRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the pipeline.",
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the pipeline, 0 if it has none.",
+			},
+			"processors": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON array of the processors of the pipeline.",
+			},
+			"on_failure": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON array of the processors run when a processor fails, empty if there are none.",
+			},
+			"metadata": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON object of the `_meta` of the pipeline, empty if there is none.",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON definition of the pipeline, as used by the `elasticsearch_ingest_pipeline` resource.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchIngestPipelineRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("name").(string)
+	path, err := uritemplates.Expand("/_ingest/pipeline/{name}", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for pipeline: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var pipelines map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(body, &pipelines); err != nil {
+		return fmt.Errorf("error unmarshalling pipeline: %+v: %s", err, body)
+	}
+	pipeline, ok := pipelines[name]
+	if !ok {
+		return fmt.Errorf("ingest pipeline %q not found", name)
+	}
+
+	var description string
+	var version int
+	_ = json.Unmarshal(pipeline["description"], &description)
+	_ = json.Unmarshal(pipeline["version"], &version)
+	pipelineJson, err := json.Marshal(pipeline)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(name)
+	ds := &resourceDataSetter{d: d}
+	ds.set("description", description)
+	ds.set("version", version)
+	ds.set("processors", compactJson(pipeline["processors"]))
+	ds.set("on_failure", compactJson(pipeline["on_failure"]))
+	ds.set("metadata", compactJson(pipeline["_meta"]))
+	ds.set("body", string(pipelineJson))
+	return ds.err
+}
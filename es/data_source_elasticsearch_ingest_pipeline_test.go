@@ -0,0 +1,85 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchIngestPipelineRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_ingest/pipeline/logs-nginx.access-1.2.0":
+			_, _ = w.Write([]byte(`{"logs-nginx.access-1.2.0": {
+				"description": "Pipeline for parsing Nginx access logs.",
+				"version": 3,
+				"processors": [ {"set": {"field": "event.ingested", "value": "{{_ingest.timestamp}}"}} ],
+				"_meta": {"managed_by": "fleet"}
+			}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	r := dataSourceElasticsearchIngestPipeline()
+	d := r.TestResourceData()
+	_ = d.Set("name", "logs-nginx.access-1.2.0")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"description": "Pipeline for parsing Nginx access logs.",
+		"version":     3,
+		"processors":  `[{"set":{"field":"event.ingested","value":"{{_ingest.timestamp}}"}}]`,
+		"on_failure":  "",
+		"metadata":    `{"managed_by":"fleet"}`,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("name", "missing")
+	if err := r.Read(d, conf); err == nil {
+		t.Errorf("expected an error for a missing pipeline")
+	}
+}
+
+func TestAccElasticsearchDataSourceIngestPipeline_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceIngestPipeline,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_ingest_pipeline.test", "description", "terraform test"),
+					resource.TestCheckResourceAttr("data.elasticsearch_ingest_pipeline.test", "processors", `[{"set":{"field":"foo","value":"bar"}}]`),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceIngestPipeline = `
+resource "elasticsearch_ingest_pipeline" "test" {
+  name = "terraform-test-data-source"
+  body = jsonencode({
+    description = "terraform test"
+    processors  = [{ set = { field = "foo", value = "bar" } }]
+  })
+}
+
+data "elasticsearch_ingest_pipeline" "test" {
+  name = elasticsearch_ingest_pipeline.test.name
+}
+`
@@ -0,0 +1,153 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+type ingestSimulateResponse struct {
+	Docs []*struct {
+		Doc *struct {
+			Source json.RawMessage `json:"_source"`
+		} `json:"doc"`
+		Error *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"docs"`
+}
+
+func dataSourceElasticsearchIngestSimulate() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_ingest_simulate` runs sample documents through an ingest pipeline, without indexing them, e.g. to assert in a check or a postcondition that a pipeline transforms them as expected.",
+		Read:        dataSourceElasticsearchIngestSimulateRead,
+
+		Schema: map[string]*schema.Schema{
+			"pipeline_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"pipeline"},
+				Description:   "The name of an existing pipeline to run the documents through.",
+			},
+			"pipeline": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"pipeline_name"},
+				ValidateFunc:  validation.StringIsJSON,
+				Description:   "The JSON definition of a pipeline to run the documents through, as used by the `elasticsearch_ingest_pipeline` resource, e.g. before creating it.",
+			},
+			"documents": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The JSON sources of the sample documents.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsJSON,
+				},
+			},
+			"results": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The result of each document, in the order of `documents`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The JSON source of the transformed document, empty if it failed or was dropped.",
+						},
+						"dropped": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether a `drop` processor dropped the document.",
+						},
+						"error": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The error the document failed with, empty if it succeeded.",
+						},
+					},
+				},
+			},
+			"error_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents that failed.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchIngestSimulateRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("pipeline_name").(string)
+	pipeline := d.Get("pipeline").(string)
+	if name == "" && pipeline == "" {
+		return fmt.Errorf("one of pipeline_name or pipeline must be set")
+	}
+
+	sources := expandStringList(d.Get("documents").([]interface{}))
+	var docs []map[string]json.RawMessage
+	for _, source := range sources {
+		docs = append(docs, map[string]json.RawMessage{"_source": json.RawMessage(source)})
+	}
+	body := map[string]interface{}{"docs": docs}
+
+	path := "/_ingest/pipeline/_simulate"
+	if name != "" {
+		var err error
+		path, err = uritemplates.Expand("/_ingest/pipeline/{name}/_simulate", map[string]string{
+			"name": name,
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for pipeline: %+v", err)
+		}
+	} else {
+		body["pipeline"] = json.RawMessage(pipeline)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	res, err := performRequest(providerContext(m), esClient, "POST", path, nil, body)
+	if err != nil {
+		return err
+	}
+
+	var response ingestSimulateResponse
+	if err := json.Unmarshal(res, &response); err != nil {
+		return fmt.Errorf("error unmarshalling simulated pipeline: %+v: %s", err, res)
+	}
+
+	var errorCount int
+	results := make([]map[string]interface{}, 0, len(response.Docs))
+	for _, doc := range response.Docs {
+		result := map[string]interface{}{"source": "", "dropped": false, "error": ""}
+		switch {
+		case doc == nil || (doc.Doc == nil && doc.Error == nil):
+			result["dropped"] = true
+		case doc.Error != nil:
+			errorCount++
+			result["error"] = doc.Error.Type + ": " + doc.Error.Reason
+		default:
+			result["source"] = compactJson(doc.Doc.Source)
+		}
+		results = append(results, result)
+	}
+
+	id := name
+	if id == "" {
+		id = hashSum(pipeline)
+	}
+	d.SetId(id + "/" + hashSum(strings.Join(sources, "\n")))
+	ds := &resourceDataSetter{d: d}
+	ds.set("results", results)
+	ds.set("error_count", errorCount)
+	return ds.err
+}
@@ -0,0 +1,91 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchIngestSimulateRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_ingest/pipeline/_simulate":
+			var body struct {
+				Pipeline map[string]interface{}   `json:"pipeline"`
+				Docs     []map[string]interface{} `json:"docs"`
+			}
+			raw, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(raw, &body); err != nil || body.Pipeline == nil || len(body.Docs) != 3 {
+				t.Errorf("unexpected body: %s", raw)
+			}
+			_, _ = w.Write([]byte(`{"docs": [
+				{"doc": {"_index": "_index", "_id": "_id", "_source": {"message": "hello", "env": "prod"}, "_ingest": {"timestamp": "2021-01-01T00:00:00Z"}}},
+				{"error": {"root_cause": [], "type": "illegal_argument_exception", "reason": "field [message] not present as part of path [message]"}},
+				null
+			]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchIngestSimulate()
+	d := r.TestResourceData()
+	_ = d.Set("pipeline", `{"processors": [{"set": {"field": "env", "value": "prod"}}]}`)
+	_ = d.Set("documents", []string{`{"message": "hello"}`, `{}`, `{"drop": true}`})
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"error_count":       1,
+		"results.#":         3,
+		"results.0.source":  `{"message":"hello","env":"prod"}`,
+		"results.0.dropped": false,
+		"results.1.error":   "illegal_argument_exception: field [message] not present as part of path [message]",
+		"results.1.source":  "",
+		"results.2.dropped": true,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceIngestSimulate_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceIngestSimulate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_ingest_simulate.test", "error_count", "0"),
+					resource.TestCheckResourceAttr("data.elasticsearch_ingest_simulate.test", "results.0.source", `{"message":"hello","env":"prod"}`),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceIngestSimulate = `
+resource "elasticsearch_ingest_pipeline" "test" {
+  name = "terraform-test-ingest-simulate"
+  body = jsonencode({
+    processors = [{ set = { field = "env", value = "prod" } }]
+  })
+}
+
+data "elasticsearch_ingest_simulate" "test" {
+  pipeline_name = elasticsearch_ingest_pipeline.test.name
+  documents     = [jsonencode({ message = "hello" })]
+}
+`
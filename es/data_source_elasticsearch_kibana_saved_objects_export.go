@@ -0,0 +1,161 @@
+package es
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func dataSourceElasticsearchKibanaSavedObjectsExport() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_kibana_saved_objects_export` exports Kibana or OpenSearch Dashboards saved objects as NDJSON, with the saved objects export API, e.g. to promote dashboards between environments with `elasticsearch_kibana_saved_objects_import`, or to detect that they were changed in the UI by their hash.",
+		Read:        dataSourceElasticsearchKibanaSavedObjectsExportRead,
+
+		Schema: map[string]*schema.Schema{
+			"types": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ExactlyOneOf: []string{"types", "objects"},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				Description: "Export all the saved objects of these types, e.g. `dashboard`.",
+			},
+			"objects": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ExactlyOneOf: []string{"types", "objects"},
+				Description:  "Export these saved objects.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+					},
+				},
+			},
+			"space_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Kibana space to export the saved objects from. Defaults to the default space.",
+			},
+			"include_references_deep": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Also export the objects the exported ones reference, e.g. the visualizations and index patterns of a dashboard, recursively.",
+			},
+			"hash_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave `ndjson` empty, only computing `content_hash` and `exported_objects` as the export is read, so that bundles of any size are never held in memory nor stored in the state.",
+			},
+			"ndjson": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The exported saved objects, one per line, without the export summary.",
+			},
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 hash of the NDJSON, which changes with the exported objects, including their `updated_at`.",
+			},
+			"exported_objects": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The exported saved objects, as `type/id`, in the order of the export.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchKibanaSavedObjectsExportRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityKibanaSavedObjectsExport.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	path, err := kibanaSpacePath(d.Get("space_id").(string), "/api/saved_objects/_export")
+	if err != nil {
+		return err
+	}
+	// the summary would change the hash with the number of objects only
+	body := map[string]interface{}{
+		"excludeExportDetails":  true,
+		"includeReferencesDeep": d.Get("include_references_deep").(bool),
+	}
+	if types, ok := d.GetOk("types"); ok {
+		body["type"] = types
+	} else {
+		body["objects"] = d.Get("objects")
+	}
+
+	kibanaClient, err := getKibanaClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	client, ok := kibanaClient.(*elastic7.Client)
+	if !ok {
+		return fmt.Errorf("saved objects exports are only available from Kibana >= 7.0")
+	}
+	res, err := client.PerformRequest(providerContext(m), elastic7.PerformRequestOptions{
+		Method:  "POST",
+		Path:    path,
+		Body:    body,
+		Headers: http.Header{"Osd-Xsrf": []string{"true"}},
+		Stream:  true,
+	})
+	if res != nil && res.BodyReader != nil {
+		defer res.BodyReader.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("error exporting saved objects: %+v", err)
+	}
+
+	hasher := sha256.New()
+	var ndjson bytes.Buffer
+	w := io.Writer(hasher)
+	if !d.Get("hash_only").(bool) {
+		w = io.MultiWriter(hasher, &ndjson)
+	}
+	objects := []string{}
+	dec := json.NewDecoder(io.TeeReader(res.BodyReader, w))
+	for {
+		var object struct {
+			Type string `json:"type"`
+			ID   string `json:"id"`
+		}
+		if err := dec.Decode(&object); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("error parsing the exported saved objects: %+v", err)
+		}
+		objects = append(objects, object.Type+"/"+object.ID)
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	d.SetId(hash)
+	ds := &resourceDataSetter{d: d}
+	ds.set("ndjson", ndjson.String())
+	ds.set("content_hash", hash)
+	ds.set("exported_objects", objects)
+	return ds.err
+}
@@ -0,0 +1,104 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestDataSourceElasticsearchKibanaSavedObjectsExportRead(t *testing.T) {
+	ndjson := `{"type":"index-pattern","id":"logs","attributes":{"title":"logs-*"},"references":[]}
+{"type":"dashboard","id":"overview","attributes":{"title":"Overview"},"references":[{"type":"index-pattern","id":"logs","name":"panel_0"}]}
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/s/ops/api/saved_objects/_export":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			expected := map[string]interface{}{
+				"objects":               []interface{}{map[string]interface{}{"type": "dashboard", "id": "overview"}},
+				"includeReferencesDeep": true,
+				"excludeExportDetails":  true,
+			}
+			if !reflect.DeepEqual(body, expected) {
+				t.Errorf("unexpected export request: %v", body)
+			}
+			w.Header().Set("Content-Type", "application/ndjson")
+			_, _ = w.Write([]byte(ndjson))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"kibana_url":  server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := dataSourceElasticsearchKibanaSavedObjectsExport()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"objects":                 []interface{}{map[string]interface{}{"type": "dashboard", "id": "overview"}},
+		"space_id":                "ops",
+		"include_references_deep": true,
+	})
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("ndjson") != ndjson || d.Get("content_hash") != hashSum(ndjson) || d.Id() != hashSum(ndjson) {
+		t.Errorf("unexpected export %s: %s", d.Get("content_hash"), d.Get("ndjson"))
+	}
+	expected := []interface{}{"index-pattern/logs", "dashboard/overview"}
+	if actual := d.Get("exported_objects"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the objects %v, got %v", expected, actual)
+	}
+
+	_ = d.Set("hash_only", true)
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("ndjson") != "" || d.Get("content_hash") != hashSum(ndjson) {
+		t.Errorf("expected only the hash of the export, got %s: %s", d.Get("content_hash"), d.Get("ndjson"))
+	}
+}
+
+func TestAccElasticsearchDataSourceKibanaSavedObjectsExport_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccKibanaProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceKibanaSavedObjectsExport,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_kibana_saved_objects_export.test", "content_hash"),
+					resource.TestCheckResourceAttr("data.elasticsearch_kibana_saved_objects_export.test", "ndjson", ""),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceKibanaSavedObjectsExport = `
+data "elasticsearch_kibana_saved_objects_export" "test" {
+  types     = ["index-pattern"]
+  hash_only = true
+}
+`
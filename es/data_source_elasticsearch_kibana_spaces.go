@@ -0,0 +1,119 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceElasticsearchKibanaSpaces() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_kibana_spaces` lists the Kibana spaces, e.g. to import saved objects into every space with `for_each`, including the spaces created outside of Terraform.",
+		Read:        dataSourceElasticsearchKibanaSpacesRead,
+
+		Schema: map[string]*schema.Schema{
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the spaces, sorted, e.g. to use with `toset` in `for_each`.",
+			},
+			"spaces": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The spaces, sorted by ID.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"disabled_features": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The features hidden in the space, e.g. `dev_tools`.",
+						},
+						"color": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"initials": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reserved": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the space is the default space, which can't be deleted.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchKibanaSpacesRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityKibanaSpaces.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	kibanaClient, err := getKibanaClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), kibanaClient, "GET", "/api/spaces/space", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response []struct {
+		ID               string   `json:"id"`
+		Name             string   `json:"name"`
+		Description      string   `json:"description"`
+		DisabledFeatures []string `json:"disabledFeatures"`
+		Color            string   `json:"color"`
+		Initials         string   `json:"initials"`
+		Reserved         bool     `json:"_reserved"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling Kibana spaces: %+v: %s", err, body)
+	}
+	sort.Slice(response, func(i, j int) bool { return response[i].ID < response[j].ID })
+
+	ids := []string{}
+	spaces := []map[string]interface{}{}
+	for _, space := range response {
+		disabled := space.DisabledFeatures
+		if disabled == nil {
+			disabled = []string{}
+		}
+		ids = append(ids, space.ID)
+		spaces = append(spaces, map[string]interface{}{
+			"id":                space.ID,
+			"name":              space.Name,
+			"description":       space.Description,
+			"disabled_features": disabled,
+			"color":             space.Color,
+			"initials":          space.Initials,
+			"reserved":          space.Reserved,
+		})
+	}
+
+	d.SetId("_kibana_spaces")
+	ds := &resourceDataSetter{d: d}
+	ds.set("ids", ids)
+	ds.set("spaces", spaces)
+	return ds.err
+}
@@ -0,0 +1,87 @@
+package es
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchKibanaSpacesRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/api/spaces/space":
+			_, _ = w.Write([]byte(`[
+				{"id": "ops", "name": "Operations", "description": "Dashboards of the on-call", "disabledFeatures": ["dev_tools"], "color": "#aabbcc", "initials": "OP"},
+				{"id": "default", "name": "Default", "description": "This is your default space!", "disabledFeatures": [], "color": "#00bfb3", "_reserved": true}
+			]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"kibana_url":  server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := dataSourceElasticsearchKibanaSpaces()
+	d := r.TestResourceData()
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := []interface{}{"default", "ops"}; !reflect.DeepEqual(d.Get("ids"), expected) {
+		t.Errorf("expected the spaces %v, got %v", expected, d.Get("ids"))
+	}
+	if !d.Get("spaces.0.reserved").(bool) || d.Get("spaces.1.name") != "Operations" {
+		t.Errorf("unexpected spaces: %v", d.Get("spaces"))
+	}
+	if expected := []interface{}{"dev_tools"}; !reflect.DeepEqual(d.Get("spaces.1.disabled_features"), expected) {
+		t.Errorf("expected the disabled features %v, got %v", expected, d.Get("spaces.1.disabled_features"))
+	}
+}
+
+func TestAccElasticsearchDataSourceKibanaSpaces_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityKibanaSpaces.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccKibanaProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceKibanaSpaces,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_kibana_spaces.test", "spaces.0.id", "default"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceKibanaSpaces = `
+data "elasticsearch_kibana_spaces" "test" {}
+`
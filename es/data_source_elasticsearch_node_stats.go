@@ -0,0 +1,207 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// nodeStats is the part of the stats of a node read by the
+// elasticsearch_node_stats data source.
+type nodeStats struct {
+	Name  string   `json:"name"`
+	Host  string   `json:"host"`
+	Roles []string `json:"roles"`
+	Jvm   struct {
+		Mem struct {
+			HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+			HeapUsedPercent int   `json:"heap_used_percent"`
+			HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+		} `json:"mem"`
+	} `json:"jvm"`
+	Fs struct {
+		Total struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			FreeInBytes      int64 `json:"free_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+	} `json:"fs"`
+	ThreadPool map[string]struct {
+		Queue    int `json:"queue"`
+		Rejected int `json:"rejected"`
+	} `json:"thread_pool"`
+}
+
+func dataSourceElasticsearchNodeStats() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_node_stats` reads the heap usage, disk space and thread pool rejections of each node, e.g. to size replicas or the shrink target of a lifecycle policy according to the actual capacity of the cluster.",
+		Read:        dataSourceElasticsearchNodeStatsRead,
+
+		Schema: map[string]*schema.Schema{
+			"node_filter": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "_all",
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only read the nodes matching this filter, e.g. `data:true`, `_master`, or comma separated node names or IDs.",
+			},
+			"max_heap_used_percent": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The highest heap usage of the nodes, in percent.",
+			},
+			"min_disk_available_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The lowest disk space available to Elasticsearch on the nodes.",
+			},
+			"thread_pool_rejections": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of tasks rejected by the thread pools of the nodes since they started.",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The nodes, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"roles": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"heap_used_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"heap_max_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"heap_used_percent": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk_total_bytes": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk_free_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The unallocated disk space of the node.",
+						},
+						"disk_available_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The disk space available to Elasticsearch, less than the free space if some of it is reserved.",
+						},
+						"thread_pool_rejections": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+							Description: "The number of tasks rejected by each thread pool of the node since it started, e.g. `write`.",
+						},
+						"thread_pool_queues": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+							Description: "The number of tasks waiting in the queue of each thread pool of the node.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchNodeStatsRead(d *schema.ResourceData, m interface{}) error {
+	filter := d.Get("node_filter").(string)
+	path, err := uritemplates.Expand("/_nodes/{nodes}/stats/jvm,fs,thread_pool", map[string]string{
+		"nodes": filter,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for node stats: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Nodes map[string]nodeStats `json:"nodes"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling node stats: %+v: %s", err, body)
+	}
+
+	maxHeapUsedPercent, minDiskAvailable, rejections := 0, int64(-1), 0
+	nodes := []map[string]interface{}{}
+	for id, node := range response.Nodes {
+		nodeRejections := map[string]interface{}{}
+		queues := map[string]interface{}{}
+		for name, pool := range node.ThreadPool {
+			nodeRejections[name] = pool.Rejected
+			queues[name] = pool.Queue
+			rejections += pool.Rejected
+		}
+		if node.Jvm.Mem.HeapUsedPercent > maxHeapUsedPercent {
+			maxHeapUsedPercent = node.Jvm.Mem.HeapUsedPercent
+		}
+		if available := node.Fs.Total.AvailableInBytes; minDiskAvailable < 0 || available < minDiskAvailable {
+			minDiskAvailable = available
+		}
+		roles := node.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"id":                     id,
+			"name":                   node.Name,
+			"host":                   node.Host,
+			"roles":                  roles,
+			"heap_used_bytes":        node.Jvm.Mem.HeapUsedInBytes,
+			"heap_max_bytes":         node.Jvm.Mem.HeapMaxInBytes,
+			"heap_used_percent":      node.Jvm.Mem.HeapUsedPercent,
+			"disk_total_bytes":       node.Fs.Total.TotalInBytes,
+			"disk_free_bytes":        node.Fs.Total.FreeInBytes,
+			"disk_available_bytes":   node.Fs.Total.AvailableInBytes,
+			"thread_pool_rejections": nodeRejections,
+			"thread_pool_queues":     queues,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i]["name"].(string) < nodes[j]["name"].(string) })
+	if minDiskAvailable < 0 {
+		minDiskAvailable = 0
+	}
+
+	d.SetId("_nodes/" + filter)
+	ds := &resourceDataSetter{d: d}
+	ds.set("max_heap_used_percent", maxHeapUsedPercent)
+	ds.set("min_disk_available_bytes", minDiskAvailable)
+	ds.set("thread_pool_rejections", rejections)
+	ds.set("nodes", nodes)
+	return ds.err
+}
@@ -0,0 +1,85 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchNodeStatsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_nodes/data:true/stats/jvm,fs,thread_pool":
+			_, _ = w.Write([]byte(`{"nodes": {
+				"b2": {
+					"name": "node-2", "host": "10.0.0.2", "roles": ["data", "ingest"],
+					"jvm": {"mem": {"heap_used_in_bytes": 600, "heap_used_percent": 60, "heap_max_in_bytes": 1000}},
+					"fs": {"total": {"total_in_bytes": 20000, "free_in_bytes": 9000, "available_in_bytes": 8000}},
+					"thread_pool": {"write": {"threads": 2, "queue": 3, "rejected": 4}, "search": {"threads": 4, "queue": 0, "rejected": 1}}
+				},
+				"a1": {
+					"name": "node-1", "host": "10.0.0.1", "roles": ["data"],
+					"jvm": {"mem": {"heap_used_in_bytes": 850, "heap_used_percent": 85, "heap_max_in_bytes": 1000}},
+					"fs": {"total": {"total_in_bytes": 20000, "free_in_bytes": 12000, "available_in_bytes": 11000}},
+					"thread_pool": {"write": {"threads": 2, "queue": 0, "rejected": 0}}
+				}
+			}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchNodeStats()
+	d := r.TestResourceData()
+	_ = d.Set("node_filter", "data:true")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"max_heap_used_percent":                85,
+		"min_disk_available_bytes":             8000,
+		"thread_pool_rejections":               5,
+		"nodes.#":                              2,
+		"nodes.0.id":                           "a1",
+		"nodes.0.name":                         "node-1",
+		"nodes.0.heap_used_percent":            85,
+		"nodes.1.roles.1":                      "ingest",
+		"nodes.1.disk_free_bytes":              9000,
+		"nodes.1.disk_available_bytes":         8000,
+		"nodes.1.thread_pool_rejections.write": 4,
+		"nodes.1.thread_pool_queues.write":     3,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceNodeStats_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceNodeStats,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_node_stats.test", "nodes.0.name"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_node_stats.test", "nodes.0.heap_max_bytes"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_node_stats.test", "min_disk_available_bytes"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceNodeStats = `
+data "elasticsearch_node_stats" "test" {}
+`
@@ -0,0 +1,331 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+// objectLister lists the objects managed by a resource type, by name.
+type objectLister struct {
+	// path7 and path6 are the APIs listing the objects with the client of
+	// Elasticsearch 7, and of Elasticsearch 5 and 6, or empty if the objects
+	// can't be listed with that client.
+	path7  string
+	path6  string
+	params url.Values
+	// pageTotal is the key of the total number of objects in the responses
+	// of the APIs listing them by page, with from and size parameters, or in
+	// a POST body if pageBody is set.
+	pageTotal string
+	pageBody  bool
+	decode    func(body json.RawMessage) (map[string]map[string]interface{}, error)
+	// nameAttribute is the attribute of the resource naming the object.
+	nameAttribute string
+}
+
+// objectListerPageSize is the number of objects listed per request by the
+// APIs listing them by page.
+const objectListerPageSize = 100
+
+var objectListers = map[string]objectLister{
+	"elasticsearch_xpack_user": {
+		path7:         "/_security/user",
+		path6:         "/_xpack/security/user",
+		decode:        decodeKeyedObjects,
+		nameAttribute: "username",
+	},
+	"elasticsearch_xpack_role": {
+		path7:         "/_security/role",
+		path6:         "/_xpack/security/role",
+		decode:        decodeKeyedObjects,
+		nameAttribute: "role_name",
+	},
+	"elasticsearch_opendistro_user": {
+		path7:         "/_opendistro/_security/api/internalusers",
+		decode:        decodeKeyedObjects,
+		nameAttribute: "username",
+	},
+	"elasticsearch_opendistro_role": {
+		path7:         "/_opendistro/_security/api/roles",
+		decode:        decodeKeyedObjects,
+		nameAttribute: "role_name",
+	},
+	"elasticsearch_index_template": {
+		path7:         "/_template",
+		path6:         "/_template",
+		decode:        decodeKeyedObjects,
+		nameAttribute: "name",
+	},
+	"elasticsearch_composable_index_template": {
+		path7:         "/_index_template",
+		decode:        decodeListedObjects("index_templates", "name", "index_template"),
+		nameAttribute: "name",
+	},
+	"elasticsearch_component_template": {
+		path7:         "/_component_template",
+		decode:        decodeListedObjects("component_templates", "name", "component_template"),
+		nameAttribute: "name",
+	},
+	"elasticsearch_ingest_pipeline": {
+		path7:         "/_ingest/pipeline",
+		path6:         "/_ingest/pipeline",
+		decode:        decodeKeyedObjects,
+		nameAttribute: "name",
+	},
+	"elasticsearch_opendistro_ism_policy": {
+		path7:         "/_opendistro/_ism/policies",
+		path6:         "/_opendistro/_ism/policies",
+		pageTotal:     "total_policies",
+		decode:        decodeListedObjects("policies", "_id", "policy"),
+		nameAttribute: "policy_id",
+	},
+}
+
+func dataSourceElasticsearchObjects() *schema.Resource {
+	var resourceTypes []string
+	for t := range objectListers {
+		resourceTypes = append(resourceTypes, t)
+	}
+	sort.Strings(resourceTypes)
+
+	return &schema.Resource{
+		Description: "`elasticsearch_objects` lists the objects of the cluster that a resource type manages, with their import IDs, to adopt existing objects with `import` blocks.",
+		Read:        dataSourceElasticsearchObjectsRead,
+
+		Schema: map[string]*schema.Schema{
+			"resource_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The resource type managing the objects to list, one of " + strings.Join(resourceTypes, ", ") + ".",
+				ValidateFunc: validation.StringInSlice(resourceTypes, false),
+			},
+			"name_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*",
+				Description: "Only list the objects with a name matching this wildcard pattern, e.g. `logs-*`.",
+			},
+			"include_reserved": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Also list the built-in, reserved, hidden and managed objects, which usually shouldn't be managed with Terraform.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The sorted names of the objects.",
+			},
+			"import_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The import ID of each object, by name.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchObjectsRead(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
+	resourceType := d.Get("resource_type").(string)
+	pattern := d.Get("name_pattern").(string)
+	lister := objectListers[resourceType]
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid name_pattern %q: %+v", pattern, err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	objects, err := lister.list(ctx, esClient, resourceType)
+	if err != nil {
+		return err
+	}
+	names := filterObjectNames(objects, pattern, d.Get("include_reserved").(bool))
+	importIds := make(map[string]string, len(names))
+	for _, name := range names {
+		importIds[name] = name
+	}
+
+	d.SetId(resourceType + "/" + pattern)
+	ds := &resourceDataSetter{d: d}
+	ds.set("names", names)
+	ds.set("import_ids", importIds)
+	return ds.err
+}
+
+// list returns the objects by name, going through all the pages of the APIs
+// listing them by page.
+func (lister objectLister) list(ctx context.Context, esClient interface{}, resourceType string) (map[string]map[string]interface{}, error) {
+	path := lister.path7
+	if _, ok := esClient.(*elastic7.Client); !ok {
+		path = lister.path6
+	}
+	if path == "" {
+		return nil, fmt.Errorf("listing %s objects is not supported prior to Elastic v7", resourceType)
+	}
+
+	if lister.pageTotal == "" {
+		objects, _, err := lister.listPage(ctx, esClient, resourceType, "GET", path, lister.params, nil)
+		return objects, err
+	}
+	objects := map[string]map[string]interface{}{}
+	for from := 0; ; from += objectListerPageSize {
+		method, params := "GET", url.Values{}
+		for key, values := range lister.params {
+			params[key] = values
+		}
+		var body interface{}
+		if lister.pageBody {
+			method = "POST"
+			body = map[string]interface{}{"from": from, "size": objectListerPageSize}
+		} else {
+			params.Set("from", strconv.Itoa(from))
+			params.Set("size", strconv.Itoa(objectListerPageSize))
+		}
+		page, total, err := lister.listPage(ctx, esClient, resourceType, method, path, params, body)
+		if err != nil {
+			return nil, err
+		}
+		for name, object := range page {
+			objects[name] = object
+		}
+		if len(page) < objectListerPageSize || len(objects) >= total {
+			return objects, nil
+		}
+	}
+}
+
+// listPage returns the objects of a response, and their total number if the
+// API lists them by page.
+func (lister objectLister) listPage(ctx context.Context, esClient interface{}, resourceType, method, path string, params url.Values, body interface{}) (map[string]map[string]interface{}, int, error) {
+	res, err := performRequest(ctx, esClient, method, path, params, body)
+	if err != nil {
+		// listing templates or pipelines when there are none returns a 404
+		if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+			res = json.RawMessage("{}")
+		} else {
+			return nil, 0, err
+		}
+	}
+
+	objects, err := lister.decode(res)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error unmarshalling %s objects: %+v: %s", resourceType, err, res)
+	}
+	var total int
+	if lister.pageTotal != "" {
+		var response map[string]json.RawMessage
+		if err := json.Unmarshal(res, &response); err != nil {
+			return nil, 0, fmt.Errorf("error unmarshalling %s objects: %+v: %s", resourceType, err, res)
+		}
+		if raw, ok := response[lister.pageTotal]; ok {
+			if err := json.Unmarshal(raw, &total); err != nil {
+				return nil, 0, fmt.Errorf("error unmarshalling %s objects: %+v: %s", resourceType, err, res)
+			}
+		}
+	}
+	return objects, total, nil
+}
+
+// decodeKeyedObjects decodes responses with an object per name, e.g.
+// {"my_pipeline": {...}}.
+func decodeKeyedObjects(body json.RawMessage) (map[string]map[string]interface{}, error) {
+	var objects map[string]map[string]interface{}
+	err := json.Unmarshal(body, &objects)
+	return objects, err
+}
+
+// decodeNestedObjects returns a decoder for responses with an object per name
+// nested under objectKey, e.g. {"my_policy": {"policy": {...}}}.
+func decodeNestedObjects(objectKey string) func(json.RawMessage) (map[string]map[string]interface{}, error) {
+	return func(body json.RawMessage) (map[string]map[string]interface{}, error) {
+		var response map[string]map[string]interface{}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+		objects := make(map[string]map[string]interface{}, len(response))
+		for name, item := range response {
+			object, _ := item[objectKey].(map[string]interface{})
+			objects[name] = object
+		}
+		return objects, nil
+	}
+}
+
+// decodeListedObjects returns a decoder for responses with a list of objects,
+// e.g. {"index_templates": [{"name": "my_template", "index_template": {...}}]}.
+func decodeListedObjects(listKey, nameKey, objectKey string) func(json.RawMessage) (map[string]map[string]interface{}, error) {
+	return func(body json.RawMessage) (map[string]map[string]interface{}, error) {
+		var response map[string]json.RawMessage
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+		var list []map[string]interface{}
+		if raw, ok := response[listKey]; ok {
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, err
+			}
+		}
+
+		objects := make(map[string]map[string]interface{}, len(list))
+		for _, item := range list {
+			name, _ := item[nameKey].(string)
+			object, _ := item[objectKey].(map[string]interface{})
+			objects[name] = object
+		}
+		return objects, nil
+	}
+}
+
+func filterObjectNames(objects map[string]map[string]interface{}, pattern string, includeReserved bool) []string {
+	names := []string{}
+	for name, object := range objects {
+		if matched, _ := path.Match(pattern, name); !matched {
+			continue
+		}
+		if !includeReserved && reservedObject(name, object) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// reservedObject returns whether an object is built in or managed by the
+// cluster, according to the flags each API uses for that.
+func reservedObject(name string, object map[string]interface{}) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, key := range []string{"reserved", "static", "hidden"} {
+		if object[key] == true {
+			return true
+		}
+	}
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok && metadata["_reserved"] == true {
+		return true
+	}
+	if meta, ok := object["_meta"].(map[string]interface{}); ok && meta["managed"] == true {
+		return true
+	}
+	return false
+}
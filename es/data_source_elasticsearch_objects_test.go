@@ -0,0 +1,125 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestObjectListers(t *testing.T) {
+	users, err := objectListers["elasticsearch_xpack_user"].decode(json.RawMessage(`{
+		"elastic": {"username": "elastic", "metadata": {"_reserved": true}},
+		"johndoe": {"username": "johndoe", "metadata": {}},
+		"janedoe": {"username": "janedoe", "metadata": {}}
+	}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if names := filterObjectNames(users, "*", false); !reflect.DeepEqual(names, []string{"janedoe", "johndoe"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+	if names := filterObjectNames(users, "*", true); !reflect.DeepEqual(names, []string{"elastic", "janedoe", "johndoe"}) {
+		t.Errorf("unexpected names with reserved objects: %v", names)
+	}
+
+	templates, err := objectListers["elasticsearch_composable_index_template"].decode(json.RawMessage(`{"index_templates": [
+		{"name": "logs", "index_template": {"index_patterns": ["logs-*-*"], "_meta": {"managed": true}}},
+		{"name": "logs-app", "index_template": {"index_patterns": ["logs-app-*"]}},
+		{"name": "metrics-app", "index_template": {"index_patterns": ["metrics-app-*"]}}
+	]}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if names := filterObjectNames(templates, "logs*", false); !reflect.DeepEqual(names, []string{"logs-app"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	policies, err := objectListers["elasticsearch_opendistro_ism_policy"].decode(json.RawMessage(`{"policies": [{"_id": "hot-warm", "policy": {}}], "total_policies": 1}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if names := filterObjectNames(policies, "*", false); !reflect.DeepEqual(names, []string{"hot-warm"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+}
+
+func TestObjectListerPages(t *testing.T) {
+	var pages []string
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_opendistro/_ism/policies":
+			from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+			size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+			pages = append(pages, r.URL.Query().Get("from"))
+			var policies []string
+			for i := from; i < from+size && i < 150; i++ {
+				policies = append(policies, fmt.Sprintf(`{"_id": "policy-%03d", "policy": {}}`, i))
+			}
+			_, _ = fmt.Fprintf(w, `{"policies": [%s], "total_policies": 150}`, strings.Join(policies, ","))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+	esClient, err := getClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	policies, err := objectListers["elasticsearch_opendistro_ism_policy"].list(providerContext(conf), esClient, "elasticsearch_opendistro_ism_policy")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := policies["policy-149"]; len(policies) != 150 || !ok {
+		t.Errorf("expected the 150 policies, got %d", len(policies))
+	}
+	if !reflect.DeepEqual(pages, []string{"0", "100"}) {
+		t.Errorf("expected 2 pages, got %v", pages)
+	}
+}
+
+func TestAccElasticsearchDataSourceObjects_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceObjects,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_objects.test", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_objects.test", "names.0", "terraform-test-objects"),
+					resource.TestCheckResourceAttr("data.elasticsearch_objects.test", "import_ids.terraform-test-objects", "terraform-test-objects"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceObjects = `
+resource "elasticsearch_ingest_pipeline" "test" {
+  name = "terraform-test-objects"
+  body = <<EOF
+{
+  "description": "test",
+  "processors": [{"set": {"field": "foo", "value": "bar"}}]
+}
+EOF
+}
+
+data "elasticsearch_objects" "test" {
+  resource_type = "elasticsearch_ingest_pipeline"
+  name_pattern  = "terraform-test-objects*"
+
+  depends_on = [elasticsearch_ingest_pipeline.test]
+}
+`
@@ -46,6 +46,7 @@ func dataSourceElasticsearchOpenDistroDestination() *schema.Resource {
 }
 
 func dataSourceElasticsearchOpenDistroDestinationRead(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	destinationName := d.Get("name").(string)
 
 	var id string
@@ -62,12 +63,12 @@ func dataSourceElasticsearchOpenDistroDestinationRead(d *schema.ResourceData, m
 		// the index has become a "system index", so it cannot be searched:
 		// https://opendistro.github.io/for-elasticsearch-docs/docs/alerting/settings/#alerting-indices
 		// instead we paginate through all destinations to find the first name match :|
-		id, destination, err = destinationElasticsearch7GetAll(client, destinationName)
+		id, destination, err = destinationElasticsearch7GetAll(ctx, client, destinationName)
 		if err != nil {
-			id, destination, err = destinationElasticsearch7Search(client, DESTINATION_INDEX, destinationName)
+			id, destination, err = destinationElasticsearch7Search(ctx, client, DESTINATION_INDEX, destinationName)
 		}
 	case *elastic6.Client:
-		id, destination, err = destinationElasticsearch6Search(client, DESTINATION_INDEX, destinationName)
+		id, destination, err = destinationElasticsearch6Search(ctx, client, DESTINATION_INDEX, destinationName)
 	default:
 		err = errors.New("destination resource not implemented prior to Elastic v6")
 	}
@@ -96,12 +97,12 @@ func dataSourceElasticsearchOpenDistroDestinationRead(d *schema.ResourceData, m
 	return err
 }
 
-func destinationElasticsearch7Search(client *elastic7.Client, index string, name string) (string, map[string]interface{}, error) {
+func destinationElasticsearch7Search(ctx context.Context, client *elastic7.Client, index string, name string) (string, map[string]interface{}, error) {
 	termQuery := elastic7.NewTermQuery(DESTINATION_NAME_FIELD, name)
 	result, err := client.Search().
 		Index(index).
 		Query(termQuery).
-		Do(context.TODO())
+		Do(ctx)
 
 	destination := make(map[string]interface{})
 	if err != nil {
@@ -120,12 +121,12 @@ func destinationElasticsearch7Search(client *elastic7.Client, index string, name
 	}
 }
 
-func destinationElasticsearch6Search(client *elastic6.Client, index string, name string) (string, map[string]interface{}, error) {
+func destinationElasticsearch6Search(ctx context.Context, client *elastic6.Client, index string, name string) (string, map[string]interface{}, error) {
 	termQuery := elastic6.NewTermQuery(DESTINATION_NAME_FIELD, name)
 	result, err := client.Search().
 		Index(index).
 		Query(termQuery).
-		Do(context.TODO())
+		Do(ctx)
 
 	destination := make(map[string]interface{})
 	if err != nil {
@@ -144,7 +145,7 @@ func destinationElasticsearch6Search(client *elastic6.Client, index string, name
 	}
 }
 
-func destinationElasticsearch7GetAll(client *elastic7.Client, name string) (string, map[string]interface{}, error) {
+func destinationElasticsearch7GetAll(ctx context.Context, client *elastic7.Client, name string) (string, map[string]interface{}, error) {
 	offset := 0
 	pageSize := 1000
 	destination := make(map[string]interface{})
@@ -157,7 +158,7 @@ func destinationElasticsearch7GetAll(client *elastic7.Client, name string) (stri
 			return "", destination, fmt.Errorf("error building URL path for destination: %+v", err)
 		}
 
-		httpResponse, err := client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		httpResponse, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -0,0 +1,199 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// ismExplanation is the state of an index in the explain API of index state
+// management, with an empty policy ID if the index isn't managed.
+type ismExplanation struct {
+	Index    string `json:"index"`
+	PolicyId string `json:"policy_id"`
+	Enabled  *bool  `json:"enabled"`
+	State    struct {
+		Name string `json:"name"`
+	} `json:"state"`
+	Action struct {
+		Name   string `json:"name"`
+		Failed bool   `json:"failed"`
+	} `json:"action"`
+	Step struct {
+		Name       string `json:"name"`
+		StepStatus string `json:"step_status"`
+	} `json:"step"`
+	RetryInfo struct {
+		Failed          bool `json:"failed"`
+		ConsumedRetries int  `json:"consumed_retries"`
+	} `json:"retry_info"`
+	Info struct {
+		Message string `json:"message"`
+		Cause   string `json:"cause"`
+	} `json:"info"`
+}
+
+func dataSourceElasticsearchOpenDistroISMExplain() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_opendistro_ism_explain` reads the index state management policy, state and action of indices, and their failures, e.g. to check that the indices of a pattern picked up a changed policy.",
+		Read:        dataSourceElasticsearchOpenDistroISMExplainRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The index, or index pattern or comma separated list of them, to explain.",
+			},
+			"total_managed_indices": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of indices managed by a policy.",
+			},
+			"failed_indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The indices whose current action failed, sorted.",
+			},
+			"indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The indices, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"managed": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the index is managed by a policy.",
+						},
+						"policy_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the policy is running on the index, false once it failed or its last state was reached.",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The current state of the index in the policy, empty until the policy is initialized.",
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"step": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"step_status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the current step, e.g. `starting`, `condition_not_met`, `completed` or `failed`.",
+						},
+						"failed": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the current action failed.",
+						},
+						"consumed_retries": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"info": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The message of the last step, e.g. the reason of the failure, with its cause if any.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchOpenDistroISMExplainRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityOpenDistroISM.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+	index := d.Get("index").(string)
+	path, err := uritemplates.Expand("/_opendistro/_ism/explain/{index}", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for ISM explain: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	// the indices are keyed by name, next to the total
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling ISM explain: %+v: %s", err, body)
+	}
+
+	var totalManaged int
+	failed := []string{}
+	indices := []map[string]interface{}{}
+	for name, raw := range response {
+		if name == "total_managed_indices" {
+			if err := json.Unmarshal(raw, &totalManaged); err != nil {
+				return fmt.Errorf("error unmarshalling ISM explain: %+v: %s", err, body)
+			}
+			continue
+		}
+		var e ismExplanation
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return fmt.Errorf("error unmarshalling ISM explain of %s: %+v: %s", name, err, raw)
+		}
+
+		isFailed := e.Action.Failed || e.RetryInfo.Failed
+		if isFailed {
+			failed = append(failed, name)
+		}
+		info := e.Info.Message
+		if e.Info.Cause != "" {
+			info = fmt.Sprintf("%s: %s", info, e.Info.Cause)
+		}
+		indices = append(indices, map[string]interface{}{
+			"index":            name,
+			"managed":          e.PolicyId != "",
+			"policy_id":        e.PolicyId,
+			"enabled":          e.Enabled != nil && *e.Enabled,
+			"state":            e.State.Name,
+			"action":           e.Action.Name,
+			"step":             e.Step.Name,
+			"step_status":      e.Step.StepStatus,
+			"failed":           isFailed,
+			"consumed_retries": e.RetryInfo.ConsumedRetries,
+			"info":             info,
+		})
+	}
+	sort.Strings(failed)
+	sort.Slice(indices, func(i, j int) bool { return indices[i]["index"].(string) < indices[j]["index"].(string) })
+
+	d.SetId(index)
+	ds := &resourceDataSetter{d: d}
+	ds.set("total_managed_indices", totalManaged)
+	ds.set("failed_indices", failed)
+	ds.set("indices", indices)
+	return ds.err
+}
@@ -0,0 +1,116 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchOpenDistroISMExplainRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+		case "/_opendistro/_ism/explain/logs-*":
+			_, _ = w.Write([]byte(`{
+				"logs-000002": {
+					"index.plugins.index_state_management.policy_id": "logs",
+					"index": "logs-000002",
+					"policy_id": "logs",
+					"enabled": true,
+					"state": {"name": "hot", "start_time": 1600000000000},
+					"action": {"name": "rollover", "start_time": 1600000000000, "index": 0, "failed": false, "consumed_retries": 0},
+					"step": {"name": "attempt_rollover", "step_status": "condition_not_met"},
+					"retry_info": {"failed": false, "consumed_retries": 0},
+					"info": {"message": "Pending rollover of index [index=logs-000002]"}
+				},
+				"logs-000001": {
+					"index.plugins.index_state_management.policy_id": "logs",
+					"index": "logs-000001",
+					"policy_id": "logs",
+					"enabled": false,
+					"state": {"name": "warm"},
+					"action": {"name": "force_merge", "failed": true, "consumed_retries": 3},
+					"step": {"name": "attempt_call_force_merge", "step_status": "failed"},
+					"retry_info": {"failed": true, "consumed_retries": 3},
+					"info": {"message": "Failed to start force merge", "cause": "index is closed"}
+				},
+				"logs-legacy": {
+					"index.plugins.index_state_management.policy_id": null,
+					"enabled": null
+				},
+				"total_managed_indices": 2
+			}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchOpenDistroISMExplain()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs-*")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"total_managed_indices":      2,
+		"failed_indices.#":           1,
+		"failed_indices.0":           "logs-000001",
+		"indices.#":                  3,
+		"indices.0.index":            "logs-000001",
+		"indices.0.failed":           true,
+		"indices.0.consumed_retries": 3,
+		"indices.0.info":             "Failed to start force merge: index is closed",
+		"indices.1.state":            "hot",
+		"indices.1.action":           "rollover",
+		"indices.1.step_status":      "condition_not_met",
+		"indices.1.enabled":          true,
+		"indices.2.index":            "logs-legacy",
+		"indices.2.managed":          false,
+		"indices.2.policy_id":        "",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceOpenDistroISMExplain_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityOpenDistroISM.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccOpendistroProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceOpenDistroISMExplain,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_opendistro_ism_explain.test", "total_managed_indices", "0"),
+					resource.TestCheckResourceAttr("data.elasticsearch_opendistro_ism_explain.test", "failed_indices.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceOpenDistroISMExplain = `
+data "elasticsearch_opendistro_ism_explain" "test" {
+  index = "terraform-test-ism-explain-*"
+}
+`
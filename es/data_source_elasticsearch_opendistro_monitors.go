@@ -0,0 +1,150 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// monitorsSearchSize is the number of monitors read, the maximum of a search
+// without scrolling.
+const monitorsSearchSize = 10000
+
+func dataSourceElasticsearchOpenDistroMonitors() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_opendistro_monitors` searches the alerting monitors with the monitors search API, e.g. to reference in composite monitors, or to audit the notifications of, the monitors managed by other teams.",
+		Read:        dataSourceElasticsearchOpenDistroMonitorsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the monitors with this name, or whose name matches this pattern, with `*` wildcards, e.g. `payments-*`.",
+			},
+			"query": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "Only return the monitors matching this JSON query, combined with `name`, e.g. a `term` query on `monitor.monitor_type` or on the tags set in `monitor.ui_metadata`.",
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the monitors, in the order of `monitors`.",
+			},
+			"monitors": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The monitors, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"monitor_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "E.g. `query_level_monitor` or `bucket_level_monitor`, empty for monitors created before monitor types.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchOpenDistroMonitorsRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityOpenDistroAlerting.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	// the search also returns the workflows of recent versions
+	filters := []interface{}{
+		map[string]interface{}{"exists": map[string]interface{}{"field": "monitor"}},
+	}
+	name := d.Get("name").(string)
+	if name != "" {
+		filters = append(filters, map[string]interface{}{
+			"wildcard": map[string]interface{}{"monitor.name.keyword": name},
+		})
+	}
+	if raw, ok := d.GetOk("query"); ok {
+		var query map[string]interface{}
+		if err := json.Unmarshal([]byte(raw.(string)), &query); err != nil {
+			return fmt.Errorf("fail to unmarshal: %v", err)
+		}
+		filters = append(filters, query)
+	}
+	body := map[string]interface{}{
+		"size":  monitorsSearchSize,
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filters}},
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	res, err := performRequest(providerContext(m), esClient, "POST", "/_opendistro/_alerting/monitors/_search", nil, body)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				ID     string `json:"_id"`
+				Source struct {
+					Monitor *struct {
+						Name        string `json:"name"`
+						Enabled     bool   `json:"enabled"`
+						MonitorType string `json:"monitor_type"`
+					} `json:"monitor"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return fmt.Errorf("error unmarshalling monitors: %+v: %s", err, res)
+	}
+
+	monitors := []map[string]interface{}{}
+	for _, hit := range response.Hits.Hits {
+		if hit.Source.Monitor == nil {
+			continue
+		}
+		monitors = append(monitors, map[string]interface{}{
+			"id":           hit.ID,
+			"name":         hit.Source.Monitor.Name,
+			"enabled":      hit.Source.Monitor.Enabled,
+			"monitor_type": hit.Source.Monitor.MonitorType,
+		})
+	}
+	sort.SliceStable(monitors, func(i, j int) bool { return monitors[i]["name"].(string) < monitors[j]["name"].(string) })
+	ids := []string{}
+	for _, monitor := range monitors {
+		ids = append(ids, monitor["id"].(string))
+	}
+
+	id := name
+	if id == "" {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("ids", ids)
+	ds.set("monitors", monitors)
+	return ds.err
+}
@@ -0,0 +1,93 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchOpenDistroMonitorsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+		case "/_opendistro/_alerting/monitors/_search":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			filters := body["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"]
+			expected := []interface{}{
+				map[string]interface{}{"exists": map[string]interface{}{"field": "monitor"}},
+				map[string]interface{}{"wildcard": map[string]interface{}{"monitor.name.keyword": "payments-*"}},
+				map[string]interface{}{"term": map[string]interface{}{"monitor.enabled": true}},
+			}
+			if !reflect.DeepEqual(filters, expected) {
+				t.Errorf("unexpected filters: %v", filters)
+			}
+			_, _ = w.Write([]byte(`{"hits": {"hits": [
+				{"_id": "m2", "_source": {"monitor": {"name": "payments-latency", "enabled": true, "monitor_type": "query_level_monitor"}}},
+				{"_id": "w1", "_source": {"workflow": {"name": "payments-workflow"}}},
+				{"_id": "m1", "_source": {"monitor": {"name": "payments-errors", "enabled": false, "monitor_type": "bucket_level_monitor"}}}
+			]}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchOpenDistroMonitors()
+	d := r.TestResourceData()
+	_ = d.Set("name", "payments-*")
+	_ = d.Set("query", `{"term": {"monitor.enabled": true}}`)
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := []interface{}{"m1", "m2"}; !reflect.DeepEqual(d.Get("ids"), expected) {
+		t.Errorf("expected the monitors %v, got %v", expected, d.Get("ids"))
+	}
+	if d.Get("monitors.0.enabled").(bool) || d.Get("monitors.1.monitor_type") != "query_level_monitor" {
+		t.Errorf("unexpected monitors: %v", d.Get("monitors"))
+	}
+}
+
+func TestAccElasticsearchDataSourceOpenDistroMonitors_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityOpenDistroAlerting.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccOpendistroProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchOpenDistroMonitor + testAccElasticsearchDataSourceOpenDistroMonitors,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_opendistro_monitors.test", "monitors.#", "1"),
+					resource.TestCheckResourceAttrPair("data.elasticsearch_opendistro_monitors.test", "ids.0", "elasticsearch_opendistro_monitor.test_monitor", "id"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceOpenDistroMonitors = `
+data "elasticsearch_opendistro_monitors" "test" {
+  name = "test-monitor"
+
+  depends_on = [elasticsearch_opendistro_monitor.test_monitor]
+}
+`
@@ -0,0 +1,100 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func dataSourceElasticsearchScript() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_script` reads a stored script, and fails if it doesn't exist, e.g. to check that the scripts used by watches and transforms exist.",
+		Read:        dataSourceElasticsearchScriptRead,
+
+		Schema: map[string]*schema.Schema{
+			"script_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the stored script.",
+			},
+			"lang": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The language of the script, e.g. `painless` or `mustache`.",
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The source of the script.",
+			},
+			"options": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The options of the script, e.g. `content_type` for search templates.",
+			},
+			"source_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 hash of the source, e.g. to trigger replacements when the script changes.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchScriptRead(d *schema.ResourceData, m interface{}) error {
+	id := d.Get("script_id").(string)
+	path, err := uritemplates.Expand("/_scripts/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for script: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return fmt.Errorf("stored script %q not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Found  bool `json:"found"`
+		Script struct {
+			Lang    string            `json:"lang"`
+			Source  string            `json:"source"`
+			Code    string            `json:"code"`
+			Options map[string]string `json:"options"`
+		} `json:"script"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling script: %+v: %s", err, body)
+	}
+	if !response.Found {
+		return fmt.Errorf("stored script %q not found", id)
+	}
+	// Elasticsearch 5 names the source code
+	source := response.Script.Source
+	if source == "" {
+		source = response.Script.Code
+	}
+
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("lang", response.Script.Lang)
+	ds.set("source", source)
+	ds.set("options", response.Script.Options)
+	ds.set("source_sha256", hashSum(source))
+	return ds.err
+}
@@ -0,0 +1,90 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchScriptRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_scripts/calculate-score":
+			_, _ = w.Write([]byte(`{"_id": "calculate-score", "found": true, "script": {"lang": "painless", "source": "Math.log(_score * 2)"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"_id": "missing", "found": false}`))
+		}
+	})
+
+	r := dataSourceElasticsearchScript()
+	d := r.TestResourceData()
+	_ = d.Set("script_id", "calculate-score")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"lang":          "painless",
+		"source":        "Math.log(_score * 2)",
+		"source_sha256": hashSum("Math.log(_score * 2)"),
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("script_id", "missing")
+	if err := r.Read(d, conf); err == nil || err.Error() != `stored script "missing" not found` {
+		t.Errorf("expected an error for a missing script, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceScript_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			return testAccScriptRequest("DELETE", nil)
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					script := map[string]interface{}{"script": map[string]string{"lang": "painless", "source": "Math.log(_score * 2)"}}
+					if err := testAccScriptRequest("PUT", script); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccElasticsearchDataSourceScript,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_script.test", "lang", "painless"),
+					resource.TestCheckResourceAttr("data.elasticsearch_script.test", "source", "Math.log(_score * 2)"),
+				),
+			},
+		},
+	})
+}
+
+func testAccScriptRequest(method string, body interface{}) error {
+	meta := testAccProvider.Meta()
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	_, err = performRequest(providerContext(meta), esClient, method, "/_scripts/terraform-test-script", nil, body)
+	return err
+}
+
+var testAccElasticsearchDataSourceScript = `
+data "elasticsearch_script" "test" {
+  script_id = "terraform-test-script"
+}
+`
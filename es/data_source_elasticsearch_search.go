@@ -0,0 +1,172 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// maxSearchSize bounds the number of hits of the search data source, which
+// are all stored in the state.
+const maxSearchSize = 1000
+
+func dataSourceElasticsearchSearch() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_search` runs a bounded search, e.g. to look up reference data stored in the cluster during plan.",
+		Read:        dataSourceElasticsearchSearchRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The indices, aliases or data streams to search, comma separated, with wildcards.",
+			},
+			"query": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      `{"match_all":{}}`,
+				Description:  "The JSON query DSL of the search.",
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				Description:  fmt.Sprintf("The maximum number of hits to return, up to %d.", maxSearchSize),
+				ValidateFunc: validation.IntBetween(0, maxSearchSize),
+			},
+			"sort": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The JSON array sorting the hits, e.g. `[{\"@timestamp\": \"desc\"}]`. Defaults to sorting by score.",
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"total": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents matching the query, which can be larger than the number of hits.",
+			},
+			"hits": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The documents found.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"score": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "The score of the document, 0 when sorting by other fields.",
+						},
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The JSON source of the document, e.g. to use with `jsondecode`.",
+						},
+						"fields": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The scalar values of the source, with the keys of nested objects joined with dots, e.g. `owner.name`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchSearchRead(d *schema.ResourceData, m interface{}) error {
+	index := d.Get("index").(string)
+	path, err := uritemplates.Expand("/{index}/_search", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for search: %+v", err)
+	}
+
+	search := map[string]interface{}{
+		"query": json.RawMessage(d.Get("query").(string)),
+		"size":  d.Get("size").(int),
+	}
+	if sort, ok := d.GetOk("sort"); ok {
+		search["sort"] = json.RawMessage(sort.(string))
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "POST", path, nil, search)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Hits struct {
+			// a number before Elasticsearch 7, an object since
+			Total json.RawMessage `json:"total"`
+			Hits  []struct {
+				Index  string                 `json:"_index"`
+				ID     string                 `json:"_id"`
+				Score  *float64               `json:"_score"`
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling search response: %+v: %s", err, body)
+	}
+
+	var total int
+	if err := json.Unmarshal(response.Hits.Total, &total); err != nil {
+		var totalObject struct {
+			Value int `json:"value"`
+		}
+		_ = json.Unmarshal(response.Hits.Total, &totalObject)
+		total = totalObject.Value
+	}
+
+	hits := make([]map[string]interface{}, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return err
+		}
+		fields := make(map[string]string)
+		for k, v := range flattenMap(hit.Source) {
+			switch v.(type) {
+			case nil, []interface{}:
+			default:
+				fields[k] = scalarString(v)
+			}
+		}
+		var score float64
+		if hit.Score != nil {
+			score = *hit.Score
+		}
+		hits = append(hits, map[string]interface{}{
+			"index":  hit.Index,
+			"id":     hit.ID,
+			"score":  score,
+			"source": string(source),
+			"fields": fields,
+		})
+	}
+
+	d.SetId(index)
+	ds := &resourceDataSetter{d: d}
+	ds.set("total", total)
+	ds.set("hits", hits)
+	return ds.err
+}
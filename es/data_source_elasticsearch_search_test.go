@@ -0,0 +1,94 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchSearchRead(t *testing.T) {
+	for version, total := range map[string]string{"7.10.2": `{"value": 2, "relation": "eq"}`, "6.8.0": `2`} {
+		conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/":
+				_, _ = w.Write([]byte(`{"version": {"number": "` + version + `"}}`))
+			case "/registry/_search":
+				var search map[string]interface{}
+				body, _ := ioutil.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &search)
+				if search["size"] != float64(1) || search["sort"] == nil {
+					t.Errorf("unexpected search: %s", body)
+				}
+				_, _ = w.Write([]byte(`{"hits": {"total": ` + total + `, "hits": [
+					{"_index": "registry", "_id": "prod", "_score": null, "_source": {"name": "prod", "owner": {"team": "sre"}, "replicas": 3, "zones": ["a", "b"]}}
+				]}}`))
+			default:
+				t.Errorf("unexpected request: %s", r.URL)
+			}
+		})
+
+		r := dataSourceElasticsearchSearch()
+		d := r.TestResourceData()
+		_ = d.Set("index", "registry")
+		_ = d.Set("query", `{"term": {"name": "prod"}}`)
+		_ = d.Set("size", 1)
+		_ = d.Set("sort", `[{"name": "asc"}]`)
+		if err := r.Read(d, conf); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		expected := map[string]interface{}{
+			"total":                  2,
+			"hits.#":                 1,
+			"hits.0.id":              "prod",
+			"hits.0.score":           float64(0),
+			"hits.0.source":          `{"name":"prod","owner":{"team":"sre"},"replicas":3,"zones":["a","b"]}`,
+			"hits.0.fields.replicas": "3",
+		}
+		for key, value := range expected {
+			if actual := d.Get(key); actual != value {
+				t.Errorf("%s: expected %s to be %v, got %v", version, key, value, actual)
+			}
+		}
+		fields := d.Get("hits.0.fields").(map[string]interface{})
+		if len(fields) != 3 || fields["owner.team"] != "sre" {
+			t.Errorf("%s: expected the scalar fields, got %v", version, fields)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceSearch_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceSearch,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_search.test", "total", "0"),
+					resource.TestCheckResourceAttr("data.elasticsearch_search.test", "hits.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceSearch = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-search"
+  number_of_shards   = 1
+  number_of_replicas = 0
+}
+
+data "elasticsearch_search" "test" {
+  index = elasticsearch_index.test.name
+  query = jsonencode({ term = { name = "prod" } })
+  size  = 1
+}
+`
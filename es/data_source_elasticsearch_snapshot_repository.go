@@ -0,0 +1,72 @@
+package es
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func dataSourceElasticsearchSnapshotRepository() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_snapshot_repository` reads a registered snapshot repository, and fails if it doesn't exist, e.g. to check that a repository exists before referencing it in a snapshot lifecycle policy.",
+		Read:        dataSourceElasticsearchSnapshotRepositoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the repository.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the repository, e.g. `fs` or `s3`.",
+			},
+			"settings": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The settings of the repository, with nested settings flattened, e.g. `bucket`.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
+	name := d.Get("name").(string)
+
+	var repositoryType string
+	var settings map[string]interface{}
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	switch client := esClient.(type) {
+	case *elastic7.Client:
+		repositoryType, settings, err = elastic7SnapshotGetRepository(ctx, client, name)
+	case *elastic6.Client:
+		repositoryType, settings, err = elastic6SnapshotGetRepository(ctx, client, name)
+	default:
+		elastic5Client := client.(*elastic5.Client)
+		repositoryType, settings, err = elastic5SnapshotGetRepository(ctx, elastic5Client, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	flatSettings := make(map[string]string)
+	for k, v := range flattenMap(settings) {
+		if v != nil {
+			flatSettings[k] = scalarString(v)
+		}
+	}
+
+	d.SetId(name)
+	ds := &resourceDataSetter{d: d}
+	ds.set("type", repositoryType)
+	ds.set("settings", flatSettings)
+	return ds.err
+}
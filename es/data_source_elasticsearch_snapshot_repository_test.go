@@ -0,0 +1,79 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchSnapshotRepositoryRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/dr":
+			_, _ = w.Write([]byte(`{"dr": {"type": "s3", "settings": {"bucket": "backups", "compress": true, "max_restore_bytes_per_sec": 40960000}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"type": "repository_missing_exception", "reason": "[missing] missing"}, "status": 404}`))
+		}
+	})
+
+	r := dataSourceElasticsearchSnapshotRepository()
+	d := r.TestResourceData()
+	_ = d.Set("name", "dr")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"type":                               "s3",
+		"settings.bucket":                    "backups",
+		"settings.compress":                  "true",
+		"settings.max_restore_bytes_per_sec": "40960000",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("name", "missing")
+	if err := r.Read(d, conf); err == nil {
+		t.Errorf("expected an error for a missing repository")
+	}
+}
+
+func TestAccElasticsearchDataSourceSnapshotRepository_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceSnapshotRepository,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_snapshot_repository.test", "type", "fs"),
+					resource.TestCheckResourceAttr("data.elasticsearch_snapshot_repository.test", "settings.location", "/tmp/elasticsearch"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceSnapshotRepository = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name = "terraform-test-data-source"
+  type = "fs"
+  settings = {
+    location = "/tmp/elasticsearch"
+  }
+}
+
+data "elasticsearch_snapshot_repository" "test" {
+  name = elasticsearch_snapshot_repository.test.name
+}
+`
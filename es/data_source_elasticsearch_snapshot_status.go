@@ -0,0 +1,220 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// snapshotFinishedStates are the states of the snapshots that aren't running
+// anymore, the others being IN_PROGRESS, STARTED and ABORTED.
+var snapshotFinishedStates = []string{"SUCCESS", "PARTIAL", "FAILED"}
+
+type snapshotStatus struct {
+	Snapshot           string `json:"snapshot"`
+	Repository         string `json:"repository"`
+	UUID               string `json:"uuid"`
+	State              string `json:"state"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	ShardsStats        struct {
+		Done   int `json:"done"`
+		Failed int `json:"failed"`
+		Total  int `json:"total"`
+	} `json:"shards_stats"`
+	Stats struct {
+		StartTimeInMillis int64 `json:"start_time_in_millis"`
+		TimeInMillis      int64 `json:"time_in_millis"`
+	} `json:"stats"`
+}
+
+func dataSourceElasticsearchSnapshotStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_snapshot_status` reports the snapshots in progress, or the progress of given snapshots, e.g. to avoid changes overlapping with a running snapshot, or to wait for a snapshot to complete.",
+		Read:        dataSourceElasticsearchSnapshotStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only report the snapshots of this repository. Defaults to all the repositories.",
+			},
+			"names": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"repository"},
+				Description:  "Report these snapshots of `repository`, whether they're in progress or not, instead of the snapshots in progress.",
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for the snapshots to complete, up to the read timeout.",
+			},
+			"in_progress": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether any of the snapshots is still running.",
+			},
+			"snapshots": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The status of the snapshots.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"repository": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`IN_PROGRESS`, `STARTED`, `ABORTED`, `SUCCESS`, `PARTIAL` or `FAILED`.",
+						},
+						"include_global_state": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"shards_total": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"shards_done": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"shards_failed": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"start_time_in_millis": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"time_in_millis": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "How long the snapshot has been running, or took.",
+						},
+					},
+				},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultOperationTimeout),
+		},
+	}
+}
+
+func dataSourceElasticsearchSnapshotStatusRead(d *schema.ResourceData, m interface{}) error {
+	repository := d.Get("repository").(string)
+	var names []string
+	for _, name := range d.Get("names").([]interface{}) {
+		names = append(names, name.(string))
+	}
+
+	var path string
+	var err error
+	switch {
+	case len(names) > 0:
+		path, err = uritemplates.Expand("/_snapshot/{repository}/{names}/_status", map[string]string{
+			"repository": repository,
+			"names":      strings.Join(names, ","),
+		})
+	case repository != "":
+		path, err = uritemplates.Expand("/_snapshot/{repository}/_status", map[string]string{
+			"repository": repository,
+		})
+	default:
+		path = "/_snapshot/_status"
+	}
+	if err != nil {
+		return fmt.Errorf("error building URL path for snapshot status: %+v", err)
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+
+	var statuses []snapshotStatus
+	read := func() error {
+		body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+		if err != nil {
+			return err
+		}
+		var response struct {
+			Snapshots []snapshotStatus `json:"snapshots"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return fmt.Errorf("error unmarshalling snapshot status: %+v: %s", err, body)
+		}
+		statuses = response.Snapshots
+		return nil
+	}
+
+	if d.Get("wait_for_completion").(bool) {
+		err = resource.Retry(d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+			if err := read(); err != nil {
+				return resource.NonRetryableError(err)
+			}
+			for _, s := range statuses {
+				if snapshotRunning(s.State) {
+					return resource.RetryableError(fmt.Errorf("snapshot %s/%s is %s", s.Repository, s.Snapshot, s.State))
+				}
+			}
+			return nil
+		})
+	} else {
+		err = read()
+	}
+	if err != nil {
+		return err
+	}
+
+	inProgress := false
+	snapshots := make([]map[string]interface{}, 0, len(statuses))
+	for _, s := range statuses {
+		inProgress = inProgress || snapshotRunning(s.State)
+		snapshots = append(snapshots, map[string]interface{}{
+			"name":                 s.Snapshot,
+			"repository":           s.Repository,
+			"uuid":                 s.UUID,
+			"state":                s.State,
+			"include_global_state": s.IncludeGlobalState,
+			"shards_total":         s.ShardsStats.Total,
+			"shards_done":          s.ShardsStats.Done,
+			"shards_failed":        s.ShardsStats.Failed,
+			"start_time_in_millis": s.Stats.StartTimeInMillis,
+			"time_in_millis":       s.Stats.TimeInMillis,
+		})
+	}
+
+	d.SetId(strings.TrimPrefix(path, "/"))
+	ds := &resourceDataSetter{d: d}
+	ds.set("in_progress", inProgress)
+	ds.set("snapshots", snapshots)
+	return ds.err
+}
+
+func snapshotRunning(state string) bool {
+	for _, finished := range snapshotFinishedStates {
+		if state == finished {
+			return false
+		}
+	}
+	return true
+}
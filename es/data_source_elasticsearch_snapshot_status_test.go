@@ -0,0 +1,122 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchSnapshotStatusRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/_status":
+			_, _ = w.Write([]byte(`{"snapshots": [
+				{"snapshot": "nightly-1", "repository": "dr", "uuid": "u1", "state": "STARTED", "include_global_state": true,
+				 "shards_stats": {"initializing": 0, "started": 1, "finalizing": 0, "done": 4, "failed": 0, "total": 5},
+				 "stats": {"start_time_in_millis": 1000, "time_in_millis": 250}}
+			]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchSnapshotStatus()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"in_progress":                      true,
+		"snapshots.#":                      1,
+		"snapshots.0.name":                 "nightly-1",
+		"snapshots.0.repository":           "dr",
+		"snapshots.0.include_global_state": true,
+		"snapshots.0.shards_done":          4,
+		"snapshots.0.shards_total":         5,
+		"snapshots.0.time_in_millis":       250,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestDataSourceElasticsearchSnapshotStatusReadWaitForCompletion(t *testing.T) {
+	requests := 0
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/dr/nightly-1,nightly-2/_status":
+			requests++
+			state := "STARTED"
+			if requests > 1 {
+				state = "SUCCESS"
+			}
+			_, _ = w.Write([]byte(`{"snapshots": [
+				{"snapshot": "nightly-1", "repository": "dr", "state": "SUCCESS"},
+				{"snapshot": "nightly-2", "repository": "dr", "state": "` + state + `"}
+			]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchSnapshotStatus()
+	d := r.TestResourceData()
+	_ = d.Set("repository", "dr")
+	_ = d.Set("names", []string{"nightly-1", "nightly-2"})
+	_ = d.Set("wait_for_completion", true)
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 status requests, got %d", requests)
+	}
+	if d.Get("in_progress").(bool) {
+		t.Errorf("expected the snapshots to be completed")
+	}
+	if state := d.Get("snapshots.1.state"); state != "SUCCESS" {
+		t.Errorf("expected snapshots.1.state to be SUCCESS, got %v", state)
+	}
+}
+
+func TestAccElasticsearchDataSourceSnapshotStatus_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceSnapshotStatus,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_snapshot_status.test", "in_progress", "false"),
+					resource.TestCheckResourceAttr("data.elasticsearch_snapshot_status.test", "snapshots.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceSnapshotStatus = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name = "terraform-test-snapshot-status"
+  type = "fs"
+  settings = {
+    location = "/tmp/elasticsearch"
+  }
+}
+
+data "elasticsearch_snapshot_status" "test" {
+  repository = elasticsearch_snapshot_repository.test.name
+}
+`
@@ -0,0 +1,174 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+type snapshotInfo struct {
+	Snapshot          string   `json:"snapshot"`
+	UUID              string   `json:"uuid"`
+	State             string   `json:"state"`
+	Indices           []string `json:"indices"`
+	StartTime         string   `json:"start_time"`
+	StartTimeInMillis int64    `json:"start_time_in_millis"`
+	EndTime           string   `json:"end_time"`
+	EndTimeInMillis   int64    `json:"end_time_in_millis"`
+	Reason            string   `json:"reason"`
+	Failures          []struct {
+		Index  string `json:"index"`
+		Reason string `json:"reason"`
+	} `json:"failures"`
+}
+
+func dataSourceElasticsearchSnapshots() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_snapshots` lists the snapshots of a repository, e.g. to select the latest successful snapshot to restore.",
+		Read:        dataSourceElasticsearchSnapshotsRead,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the repository.",
+			},
+			"name_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*",
+				Description: "Only list the snapshots with a name matching this pattern, or comma separated list of patterns, e.g. `nightly-*`.",
+			},
+			"state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only list the snapshots in this state: `SUCCESS`, `PARTIAL`, `FAILED`, `IN_PROGRESS` or `INCOMPATIBLE`.",
+				ValidateFunc: validation.StringInSlice([]string{"SUCCESS", "PARTIAL", "FAILED", "IN_PROGRESS", "INCOMPATIBLE"}, false),
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the snapshots, from the oldest to the latest.",
+			},
+			"latest": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the latest snapshot, empty if there is none.",
+			},
+			"snapshots": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The snapshots, from the oldest to the latest.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"indices": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"start_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time the snapshot started, in RFC 3339 format.",
+						},
+						"start_time_in_millis": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"end_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time the snapshot ended, in RFC 3339 format, empty while it's in progress.",
+						},
+						"end_time_in_millis": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchSnapshotsRead(d *schema.ResourceData, m interface{}) error {
+	repository := d.Get("repository").(string)
+	pattern := d.Get("name_pattern").(string)
+	state := d.Get("state").(string)
+
+	path, err := uritemplates.Expand("/_snapshot/{repository}/{pattern}", map[string]string{
+		"repository": repository,
+		"pattern":    pattern,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for snapshots: %+v", err)
+	}
+	params := url.Values{"ignore_unavailable": []string{"true"}}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, params, nil)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Snapshots []snapshotInfo `json:"snapshots"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling snapshots: %+v: %s", err, body)
+	}
+	sort.SliceStable(response.Snapshots, func(i, j int) bool {
+		return response.Snapshots[i].StartTimeInMillis < response.Snapshots[j].StartTimeInMillis
+	})
+
+	names := []string{}
+	snapshots := []map[string]interface{}{}
+	for _, s := range response.Snapshots {
+		if state != "" && s.State != state {
+			continue
+		}
+		names = append(names, s.Snapshot)
+		snapshots = append(snapshots, map[string]interface{}{
+			"name":                 s.Snapshot,
+			"uuid":                 s.UUID,
+			"state":                s.State,
+			"indices":              s.Indices,
+			"start_time":           s.StartTime,
+			"start_time_in_millis": s.StartTimeInMillis,
+			"end_time":             s.EndTime,
+			"end_time_in_millis":   s.EndTimeInMillis,
+		})
+	}
+	var latest string
+	if len(names) > 0 {
+		latest = names[len(names)-1]
+	}
+
+	d.SetId(repository + "/" + pattern)
+	ds := &resourceDataSetter{d: d}
+	ds.set("names", names)
+	ds.set("latest", latest)
+	ds.set("snapshots", snapshots)
+	return ds.err
+}
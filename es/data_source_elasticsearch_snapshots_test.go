@@ -0,0 +1,84 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchSnapshotsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/dr/nightly-*":
+			_, _ = w.Write([]byte(`{"snapshots": [
+				{"snapshot": "nightly-3", "uuid": "u3", "state": "FAILED", "indices": [], "start_time_in_millis": 3000},
+				{"snapshot": "nightly-2", "uuid": "u2", "state": "SUCCESS", "indices": ["logs"], "start_time": "2021-01-02T01:30:00.000Z", "start_time_in_millis": 2000, "end_time_in_millis": 2500},
+				{"snapshot": "nightly-1", "uuid": "u1", "state": "SUCCESS", "indices": ["logs"], "start_time_in_millis": 1000, "end_time_in_millis": 1500}
+			]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchSnapshots()
+	d := r.TestResourceData()
+	_ = d.Set("repository", "dr")
+	_ = d.Set("name_pattern", "nightly-*")
+	_ = d.Set("state", "SUCCESS")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"names.#":                          2,
+		"names.0":                          "nightly-1",
+		"latest":                           "nightly-2",
+		"snapshots.1.uuid":                 "u2",
+		"snapshots.1.indices.0":            "logs",
+		"snapshots.1.start_time":           "2021-01-02T01:30:00.000Z",
+		"snapshots.1.end_time_in_millis":   2500,
+		"snapshots.0.start_time_in_millis": 1000,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceSnapshots_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceSnapshots,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_snapshots.test", "names.#", "0"),
+					resource.TestCheckResourceAttr("data.elasticsearch_snapshots.test", "latest", ""),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceSnapshots = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name = "terraform-test-snapshots"
+  type = "fs"
+  settings = {
+    location = "/tmp/elasticsearch"
+  }
+}
+
+data "elasticsearch_snapshots" "test" {
+  repository = elasticsearch_snapshot_repository.test.name
+  state      = "SUCCESS"
+}
+`
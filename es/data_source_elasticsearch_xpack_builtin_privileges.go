@@ -0,0 +1,71 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceElasticsearchXpackBuiltinPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_builtin_privileges` lists the names of the privileges the cluster supports, e.g. to validate the privileges of roles or to grant only the ones the version of the cluster knows.",
+		Read:        dataSourceElasticsearchXpackBuiltinPrivilegesRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The cluster privileges, sorted.",
+			},
+			"index": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The index privileges, sorted.",
+			},
+			"remote_cluster": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The remote cluster privileges, sorted, empty before Elasticsearch 8.15.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackBuiltinPrivilegesRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityXpackBuiltinPrivileges.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", "/_security/privilege/_builtin", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var privileges struct {
+		Cluster       []string `json:"cluster"`
+		Index         []string `json:"index"`
+		RemoteCluster []string `json:"remote_cluster"`
+	}
+	if err := json.Unmarshal(body, &privileges); err != nil {
+		return fmt.Errorf("error unmarshalling builtin privileges: %+v: %s", err, body)
+	}
+	sort.Strings(privileges.Cluster)
+	sort.Strings(privileges.Index)
+	sort.Strings(privileges.RemoteCluster)
+
+	d.SetId("_builtin")
+	ds := &resourceDataSetter{d: d}
+	ds.set("cluster", privileges.Cluster)
+	ds.set("index", privileges.Index)
+	ds.set("remote_cluster", privileges.RemoteCluster)
+	return ds.err
+}
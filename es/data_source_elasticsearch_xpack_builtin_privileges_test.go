@@ -0,0 +1,75 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchXpackBuiltinPrivilegesRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_security/privilege/_builtin":
+			_, _ = w.Write([]byte(`{"cluster": ["monitor", "all", "manage"], "index": ["write", "read", "all"]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackBuiltinPrivileges()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"cluster.#":        3,
+		"cluster.0":        "all",
+		"cluster.2":        "monitor",
+		"index.#":          3,
+		"index.1":          "read",
+		"remote_cluster.#": 0,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceXpackBuiltinPrivileges_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	if err := provider.Configure(&terraform.ResourceConfig{}); err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityXpackBuiltinPrivileges.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceXpackBuiltinPrivileges,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_xpack_builtin_privileges.test", "cluster.0"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_xpack_builtin_privileges.test", "index.0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceXpackBuiltinPrivileges = `
+data "elasticsearch_xpack_builtin_privileges" "test" {}
+`
@@ -0,0 +1,108 @@
+package es
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// licenseLevels orders the license types by the features they provide, a
+// trial providing all of them.
+var licenseLevels = map[string]int{
+	"basic":      0,
+	"standard":   1,
+	"gold":       2,
+	"platinum":   3,
+	"enterprise": 4,
+	"trial":      4,
+}
+
+func dataSourceElasticsearchXpackLicense() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_license` reads the license of the cluster, e.g. to only create cross-cluster replication or machine learning resources on clusters whose license provides them, or to fail the plan with `minimum_type` otherwise.",
+		Read:        dataSourceElasticsearchXpackLicenseRead,
+
+		Schema: map[string]*schema.Schema{
+			"minimum_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"basic", "standard", "gold", "platinum", "enterprise"}, false),
+				Description:  "Fail unless the license is active and at least of this type, e.g. `platinum`, a trial license providing all the features.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "`basic`, `standard`, `gold`, `platinum`, `enterprise` or `trial`.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "`active`, `valid`, `invalid` or `expired`.",
+			},
+			"uid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issued_to": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issue_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expiry_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the license expires, empty for basic licenses, which don't.",
+			},
+			"expiry_date_in_millis": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the license expires, in milliseconds since the epoch, `0` for basic licenses.",
+			},
+			"max_nodes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackLicenseRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityXpackLicense.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	l, err := resourceElasticsearchGetXpackLicense(m)
+	if err != nil {
+		return err
+	}
+
+	if minimum := d.Get("minimum_type").(string); minimum != "" {
+		if l.Status != "active" {
+			return fmt.Errorf("the %s license of the cluster is %s, an active %s license is required", l.Type, l.Status, minimum)
+		}
+		if level, ok := licenseLevels[l.Type]; !ok || level < licenseLevels[minimum] {
+			return fmt.Errorf("the license of the cluster is %s, a %s license is required", l.Type, minimum)
+		}
+	}
+
+	d.SetId(l.Uid)
+	ds := &resourceDataSetter{d: d}
+	ds.set("type", l.Type)
+	ds.set("status", l.Status)
+	ds.set("uid", l.Uid)
+	ds.set("issued_to", l.IssuedTo)
+	ds.set("issuer", l.Issuer)
+	ds.set("issue_date", l.IssueDate)
+	ds.set("expiry_date", l.ExpiryDate)
+	ds.set("expiry_date_in_millis", l.ExpiryDateInMillis)
+	ds.set("max_nodes", l.MaxNodes)
+	return ds.err
+}
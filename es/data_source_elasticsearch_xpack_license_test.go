@@ -0,0 +1,86 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchXpackLicenseRead(t *testing.T) {
+	license := `{"license": {"status": "active", "uid": "a1b2", "type": "basic", "issue_date": "2023-01-01T00:00:00.000Z", "issue_date_in_millis": 1672531200000, "max_nodes": 1000, "issued_to": "prod", "issuer": "elasticsearch", "start_date_in_millis": -1}}`
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0"}}`))
+		case "/_license":
+			_, _ = w.Write([]byte(license))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackLicense()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"type":                  "basic",
+		"status":                "active",
+		"expiry_date":           "",
+		"expiry_date_in_millis": 0,
+		"max_nodes":             1000,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+	if d.Id() != "a1b2" {
+		t.Errorf("expected the uid as ID, got %s", d.Id())
+	}
+
+	_ = d.Set("minimum_type", "platinum")
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), "the license of the cluster is basic, a platinum license is required") {
+		t.Errorf("expected a license error, got %v", err)
+	}
+
+	license = `{"license": {"status": "active", "uid": "c3d4", "type": "trial", "expiry_date": "2023-02-01T00:00:00.000Z", "expiry_date_in_millis": 1675209600000}}`
+	if err := r.Read(d, conf); err != nil {
+		t.Errorf("expected a trial to provide platinum features, got %v", err)
+	}
+
+	license = `{"license": {"status": "expired", "uid": "e5f6", "type": "platinum"}}`
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), "the platinum license of the cluster is expired") {
+		t.Errorf("expected an expired license error, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceXpackLicense_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityXpackLicense.check(testAccProvider.Meta().(*ProviderConf)); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceXpackLicense,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.elasticsearch_xpack_license.test", "type"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceXpackLicense = `
+data "elasticsearch_xpack_license" "test" {
+  minimum_type = "basic"
+}
+`
@@ -0,0 +1,130 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceElasticsearchXpackServiceAccounts() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_service_accounts` lists the service accounts and their privileges, e.g. to check one exists before creating tokens for it.",
+		Read:        dataSourceElasticsearchXpackServiceAccountsRead,
+
+		Schema: map[string]*schema.Schema{
+			"names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only list these service accounts, as `namespace/service`, e.g. `elastic/fleet-server`, which must all exist. Defaults to all the service accounts.",
+			},
+			"service_accounts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The service accounts, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the service account, as `namespace/service`.",
+						},
+						"namespace": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"service": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cluster": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The cluster privileges of the service account.",
+						},
+						"role_descriptor": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The JSON role descriptor of the service account, with all its privileges.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackServiceAccountsRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityXpackServiceAccounts.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, name := range d.Get("names").([]interface{}) {
+		names = append(names, name.(string))
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", "/_security/service", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response map[string]struct {
+		RoleDescriptor json.RawMessage `json:"role_descriptor"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling service accounts: %+v: %s", err, body)
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range names {
+		if _, ok := response[name]; !ok {
+			return fmt.Errorf("service account %q not found", name)
+		}
+		selected[name] = true
+	}
+
+	accounts := []map[string]interface{}{}
+	for name, account := range response {
+		if len(selected) > 0 && !selected[name] {
+			continue
+		}
+		var descriptor struct {
+			Cluster []string `json:"cluster"`
+		}
+		if len(account.RoleDescriptor) > 0 {
+			if err := json.Unmarshal(account.RoleDescriptor, &descriptor); err != nil {
+				return fmt.Errorf("error unmarshalling the role descriptor of service account %s: %+v: %s", name, err, account.RoleDescriptor)
+			}
+		}
+		namespace, service := name, ""
+		if i := strings.Index(name, "/"); i >= 0 {
+			namespace, service = name[:i], name[i+1:]
+		}
+		accounts = append(accounts, map[string]interface{}{
+			"name":            name,
+			"namespace":       namespace,
+			"service":         service,
+			"cluster":         descriptor.Cluster,
+			"role_descriptor": compactJson(account.RoleDescriptor),
+		})
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i]["name"].(string) < accounts[j]["name"].(string) })
+
+	id := strings.Join(names, ",")
+	if id == "" {
+		id = "_all"
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("service_accounts", accounts)
+	return ds.err
+}
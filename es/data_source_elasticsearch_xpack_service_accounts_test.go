@@ -0,0 +1,94 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceElasticsearchXpackServiceAccountsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.5.0"}}`))
+		case "/_security/service":
+			_, _ = w.Write([]byte(`{
+				"elastic/fleet-server": {"role_descriptor": {"cluster": ["monitor", "manage_own_api_key"], "indices": [{"names": ["logs-*"], "privileges": ["write"]}]}},
+				"elastic/kibana": {"role_descriptor": {"cluster": ["all"]}}
+			}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackServiceAccounts()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"service_accounts.#":                 2,
+		"service_accounts.0.name":            "elastic/fleet-server",
+		"service_accounts.0.namespace":       "elastic",
+		"service_accounts.0.service":         "fleet-server",
+		"service_accounts.0.cluster.1":       "manage_own_api_key",
+		"service_accounts.0.role_descriptor": `{"cluster":["monitor","manage_own_api_key"],"indices":[{"names":["logs-*"],"privileges":["write"]}]}`,
+		"service_accounts.1.name":            "elastic/kibana",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("names", []string{"elastic/kibana"})
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Get("service_accounts.#"); actual != 1 {
+		t.Errorf("expected only the named service account, got %v", actual)
+	}
+
+	_ = d.Set("names", []string{"elastic/kibana", "elastic/missing"})
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), `"elastic/missing" not found`) {
+		t.Errorf("expected an error for a missing service account, got %v", err)
+	}
+}
+
+func TestAccElasticsearchDataSourceXpackServiceAccounts_basic(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	if err := provider.Configure(&terraform.ResourceConfig{}); err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityXpackServiceAccounts.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceXpackServiceAccounts,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_xpack_service_accounts.test", "service_accounts.0.name", "elastic/fleet-server"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceXpackServiceAccounts = `
+data "elasticsearch_xpack_service_accounts" "test" {
+  names = ["elastic/fleet-server"]
+}
+`
@@ -0,0 +1,138 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceElasticsearchXpackSslCertificates() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_ssl_certificates` lists the certificates the node handling the request uses to encrypt the communication of the cluster, e.g. to check none of them expires soon.",
+		Read:        dataSourceElasticsearchXpackSslCertificatesRead,
+
+		Schema: map[string]*schema.Schema{
+			"expires_within_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only list the certificates expiring within this many days, or already expired. Defaults to all the certificates.",
+			},
+			"certificates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The certificates, sorted by expiry.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The path of the file of the certificate, relative to the configuration directory of the node.",
+						},
+						"format": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`jks`, `PKCS12` or `PEM`.",
+						},
+						"alias": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The alias of the certificate in its keystore, empty for PEM files.",
+						},
+						"subject_dn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"serial_number": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"has_private_key": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"expiry": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "When the certificate expires, in RFC 3339 format.",
+						},
+						"days_until_expiry": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of whole days until the certificate expires, negative once it has.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackSslCertificatesRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityXpackSslCertificates.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", "/_ssl/certificates", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response []struct {
+		Path          string `json:"path"`
+		Format        string `json:"format"`
+		Alias         string `json:"alias"`
+		SubjectDn     string `json:"subject_dn"`
+		SerialNumber  string `json:"serial_number"`
+		HasPrivateKey bool   `json:"has_private_key"`
+		Expiry        string `json:"expiry"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling SSL certificates: %+v: %s", err, body)
+	}
+
+	within, filtered := d.GetOk("expires_within_days")
+	now := time.Now()
+	certificates := []map[string]interface{}{}
+	for _, c := range response {
+		expiry, err := time.Parse(time.RFC3339, c.Expiry)
+		if err != nil {
+			return fmt.Errorf("error parsing the expiry of certificate %s in %s: %+v", c.SubjectDn, c.Path, err)
+		}
+		days := int(expiry.Sub(now).Hours() / 24)
+		if filtered && days >= within.(int) {
+			continue
+		}
+		certificates = append(certificates, map[string]interface{}{
+			"path":              c.Path,
+			"format":            c.Format,
+			"alias":             c.Alias,
+			"subject_dn":        c.SubjectDn,
+			"serial_number":     c.SerialNumber,
+			"has_private_key":   c.HasPrivateKey,
+			"expiry":            expiry.UTC().Format(time.RFC3339),
+			"days_until_expiry": days,
+		})
+	}
+	sort.SliceStable(certificates, func(i, j int) bool {
+		return certificates[i]["expiry"].(string) < certificates[j]["expiry"].(string)
+	})
+
+	id := "_all"
+	if filtered {
+		id = strconv.Itoa(within.(int))
+	}
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("certificates", certificates)
+	return ds.err
+}
@@ -0,0 +1,53 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDataSourceElasticsearchXpackSslCertificatesRead(t *testing.T) {
+	soon := time.Now().Add(10 * 24 * time.Hour).Add(time.Hour).UTC().Format(time.RFC3339)
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_ssl/certificates":
+			_, _ = w.Write([]byte(`[
+				{"path": "certs/http.p12", "format": "PKCS12", "alias": "http", "subject_dn": "CN=es01", "serial_number": "a1", "has_private_key": true, "expiry": "2099-01-01T00:00:00.000Z"},
+				{"path": "certs/ca.crt", "format": "PEM", "alias": null, "subject_dn": "CN=Elastic CA", "serial_number": "b2", "has_private_key": false, "expiry": "` + soon + `"}
+			]`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackSslCertificates()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"certificates.#":                   2,
+		"certificates.0.subject_dn":        "CN=Elastic CA",
+		"certificates.0.alias":             "",
+		"certificates.0.days_until_expiry": 10,
+		"certificates.1.path":              "certs/http.p12",
+		"certificates.1.has_private_key":   true,
+		"certificates.1.expiry":            "2099-01-01T00:00:00Z",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+
+	_ = d.Set("expires_within_days", 30)
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Get("certificates.#"); actual != 1 {
+		t.Errorf("expected only the certificate expiring within 30 days, got %v", actual)
+	}
+}
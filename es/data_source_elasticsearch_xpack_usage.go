@@ -0,0 +1,111 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceElasticsearchXpackUsage() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_usage` reads which X-Pack features are available with the license of the cluster, enabled and used, e.g. to only create machine learning jobs or lifecycle policies where the feature is available.",
+		Read:        dataSourceElasticsearchXpackUsageRead,
+
+		Schema: map[string]*schema.Schema{
+			"available": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+				Description: "Whether each feature, e.g. `security`, `ilm` or `ml`, is available with the license of the cluster.",
+			},
+			"enabled": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+				Description: "Whether each feature is enabled in the settings of the cluster.",
+			},
+			"features": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The features, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"available": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"usage": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The JSON usage statistics of the feature, e.g. the `node_count` of `ml` or the `policy_count` of `ilm`, to read with `jsondecode`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackUsageRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityXpackUsage.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", "/_xpack/usage", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling X-Pack usage: %+v: %s", err, body)
+	}
+
+	names := make([]string, 0, len(response))
+	for name := range response {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	available := map[string]interface{}{}
+	enabled := map[string]interface{}{}
+	features := []map[string]interface{}{}
+	for _, name := range names {
+		var feature struct {
+			Available bool `json:"available"`
+			Enabled   bool `json:"enabled"`
+		}
+		if err := json.Unmarshal(response[name], &feature); err != nil {
+			return fmt.Errorf("error unmarshalling X-Pack usage of %s: %+v: %s", name, err, response[name])
+		}
+		available[name] = feature.Available
+		enabled[name] = feature.Enabled
+		features = append(features, map[string]interface{}{
+			"name":      name,
+			"available": feature.Available,
+			"enabled":   feature.Enabled,
+			"usage":     compactJson(response[name]),
+		})
+	}
+
+	d.SetId("_xpack_usage")
+	ds := &resourceDataSetter{d: d}
+	ds.set("available", available)
+	ds.set("enabled", enabled)
+	ds.set("features", features)
+	return ds.err
+}
@@ -0,0 +1,71 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestDataSourceElasticsearchXpackUsageRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0"}}`))
+		case "/_xpack/usage":
+			_, _ = w.Write([]byte(`{
+				"security": {"available": true, "enabled": true},
+				"ilm": {"available": true, "enabled": true, "policy_count": 12},
+				"ml": {"available": false, "enabled": true, "node_count": 0}
+			}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackUsage()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"available.security": true,
+		"available.ml":       false,
+		"enabled.ml":         true,
+		"features.#":         3,
+		"features.0.name":    "ilm",
+		"features.0.usage":   `{"available":true,"enabled":true,"policy_count":12}`,
+		"features.1.name":    "ml",
+		"features.2.enabled": true,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchDataSourceXpackUsage_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityXpackUsage.check(testAccProvider.Meta().(*ProviderConf)); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDataSourceXpackUsage,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_xpack_usage.test", "available.security", "true"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDataSourceXpackUsage = `
+data "elasticsearch_xpack_usage" "test" {}
+`
@@ -0,0 +1,163 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func dataSourceElasticsearchXpackWatch() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_watch` reads a watch and its status, and fails if it doesn't exist, e.g. to reference watches managed elsewhere.",
+		Read:        dataSourceElasticsearchXpackWatchRead,
+
+		Schema: map[string]*schema.Schema{
+			"watch_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the watch.",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON definition of the watch.",
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the watch is active, or only kept without being triggered.",
+			},
+			"execution_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The result of the last execution, e.g. `executed`, `execution_not_needed` or `throttled`, empty if it never ran.",
+			},
+			"last_checked": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the condition of the watch was last checked, in RFC 3339 format, empty if never.",
+			},
+			"last_met_condition": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the condition of the watch was last met, in RFC 3339 format, empty if never.",
+			},
+			"actions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The status of the actions of the watch, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ack_state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`awaits_successful_execution`, `ackable` or `acked`.",
+						},
+						"last_execution": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "When the action last ran, in RFC 3339 format, empty if never.",
+						},
+						"last_execution_successful": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"last_execution_reason": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Why the last execution failed, if it did.",
+						},
+					},
+				},
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the status of the watch.",
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackWatchRead(d *schema.ResourceData, m interface{}) error {
+	id := d.Get("watch_id").(string)
+	res, err := resourceElasticsearchGetWatch(id, m)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) {
+		return fmt.Errorf("watch %q not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the responses of the clients of each version differ in details, decode
+	// what they have in common
+	raw, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	var watch struct {
+		Found  bool            `json:"found"`
+		Watch  json.RawMessage `json:"watch"`
+		Status struct {
+			State struct {
+				Active bool `json:"active"`
+			} `json:"state"`
+			LastChecked      string          `json:"last_checked"`
+			LastMetCondition string          `json:"last_met_condition"`
+			ExecutionState   json.RawMessage `json:"execution_state"`
+			Version          int64           `json:"version"`
+			Actions          map[string]struct {
+				Ack struct {
+					State string `json:"state"`
+				} `json:"ack"`
+				LastExecution *struct {
+					Timestamp  string `json:"timestamp"`
+					Successful bool   `json:"successful"`
+					Reason     string `json:"reason"`
+				} `json:"last_execution"`
+			} `json:"actions"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &watch); err != nil {
+		return fmt.Errorf("error unmarshalling watch %s: %+v: %s", id, err, raw)
+	}
+	if !watch.Found {
+		return fmt.Errorf("watch %q not found", id)
+	}
+	var executionState string
+	_ = json.Unmarshal(watch.Status.ExecutionState, &executionState)
+
+	actions := []map[string]interface{}{}
+	for name, a := range watch.Status.Actions {
+		action := map[string]interface{}{
+			"name":      name,
+			"ack_state": a.Ack.State,
+		}
+		if a.LastExecution != nil {
+			action["last_execution"] = a.LastExecution.Timestamp
+			action["last_execution_successful"] = a.LastExecution.Successful
+			action["last_execution_reason"] = a.LastExecution.Reason
+		}
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i]["name"].(string) < actions[j]["name"].(string) })
+
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("body", compactJson(watch.Watch))
+	ds.set("active", watch.Status.State.Active)
+	ds.set("execution_state", executionState)
+	ds.set("last_checked", watch.Status.LastChecked)
+	ds.set("last_met_condition", watch.Status.LastMetCondition)
+	ds.set("actions", actions)
+	ds.set("version", watch.Status.Version)
+	return ds.err
+}
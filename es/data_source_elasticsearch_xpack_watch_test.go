@@ -0,0 +1,74 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDataSourceElasticsearchXpackWatchRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_watcher/watch/cluster_health":
+			_, _ = w.Write([]byte(`{
+				"found": true,
+				"_id": "cluster_health",
+				"status": {
+					"state": {"active": true, "timestamp": "2021-01-01T00:00:00.000Z"},
+					"last_checked": "2021-01-02T00:00:00.000Z",
+					"last_met_condition": "2021-01-02T00:00:00.000Z",
+					"execution_state": "executed",
+					"version": 42,
+					"actions": {
+						"notify": {
+							"ack": {"timestamp": "2021-01-02T00:00:00.000Z", "state": "ackable"},
+							"last_execution": {"timestamp": "2021-01-02T00:00:00.000Z", "successful": false, "reason": "connection refused"}
+						},
+						"log": {"ack": {"timestamp": "2021-01-01T00:00:00.000Z", "state": "awaits_successful_execution"}}
+					}
+				},
+				"watch": {"trigger": {"schedule": {"interval": "1m"}}, "input": {"none": {}}, "condition": {"always": {}}, "actions": {}}
+			}`))
+		case "/_watcher/watch/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"found": false, "_id": "missing"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackWatch()
+	d := r.TestResourceData()
+	_ = d.Set("watch_id", "cluster_health")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"active":                              true,
+		"execution_state":                     "executed",
+		"last_checked":                        "2021-01-02T00:00:00Z",
+		"version":                             42,
+		"actions.#":                           2,
+		"actions.0.name":                      "log",
+		"actions.0.last_execution":            "",
+		"actions.1.ack_state":                 "ackable",
+		"actions.1.last_execution_successful": false,
+		"actions.1.last_execution_reason":     "connection refused",
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+	if body := d.Get("body").(string); !strings.Contains(body, `"interval":"1m"`) {
+		t.Errorf("expected the body of the watch, got %s", body)
+	}
+
+	_ = d.Set("watch_id", "missing")
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), `"missing" not found`) {
+		t.Errorf("expected an error for a missing watch, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func dataSourceElasticsearchXpackWatcherStats() *schema.Resource {
+	return &schema.Resource{
+		Description: "`elasticsearch_xpack_watcher_stats` reads the state and load of the Watcher service of each node, e.g. to check it isn't backlogged before adding watches.",
+		Read:        dataSourceElasticsearchXpackWatcherStatsRead,
+
+		Schema: map[string]*schema.Schema{
+			"watcher_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the Watcher service, `started`, `starting`, `stopping` or `stopped`. When the nodes disagree, the state of the first node that isn't `started` or `stopped` like the others.",
+			},
+			"watch_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of watches of the cluster.",
+			},
+			"queue_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of watch executions waiting for a thread, on all the nodes.",
+			},
+			"current_watches": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of watches executing, on all the nodes.",
+			},
+			"queued_watches": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of watches queued for execution, on all the nodes.",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The stats of each node, sorted by node ID.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"node_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"watcher_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"watch_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of watches the node runs.",
+						},
+						"queue_size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of watch executions waiting for a thread.",
+						},
+						"max_size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the execution thread pool.",
+						},
+						"current_watches": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"queued_watches": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceElasticsearchXpackWatcherStatsRead(d *schema.ResourceData, m interface{}) error {
+	if err := capabilityWatcher.check(m.(*ProviderConf)); err != nil {
+		return err
+	}
+
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	path := "/_watcher/stats/_all"
+	if _, ok := esClient.(*elastic6.Client); ok {
+		path = "/_xpack/watcher/stats/_all"
+	}
+	body, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Stats []struct {
+			NodeId              string `json:"node_id"`
+			WatcherState        string `json:"watcher_state"`
+			WatchCount          int    `json:"watch_count"`
+			ExecutionThreadPool struct {
+				QueueSize int `json:"queue_size"`
+				MaxSize   int `json:"max_size"`
+			} `json:"execution_thread_pool"`
+			CurrentWatches []json.RawMessage `json:"current_watches"`
+			QueuedWatches  []json.RawMessage `json:"queued_watches"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling watcher stats: %+v: %s", err, body)
+	}
+
+	var states []string
+	watchCount, queueSize, currentWatches, queuedWatches := 0, 0, 0, 0
+	nodes := []map[string]interface{}{}
+	for _, s := range response.Stats {
+		states = append(states, s.WatcherState)
+		// each node runs the watches of its shards of the .watches index
+		watchCount += s.WatchCount
+		queueSize += s.ExecutionThreadPool.QueueSize
+		currentWatches += len(s.CurrentWatches)
+		queuedWatches += len(s.QueuedWatches)
+		nodes = append(nodes, map[string]interface{}{
+			"node_id":         s.NodeId,
+			"watcher_state":   s.WatcherState,
+			"watch_count":     s.WatchCount,
+			"queue_size":      s.ExecutionThreadPool.QueueSize,
+			"max_size":        s.ExecutionThreadPool.MaxSize,
+			"current_watches": len(s.CurrentWatches),
+			"queued_watches":  len(s.QueuedWatches),
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i]["node_id"].(string) < nodes[j]["node_id"].(string) })
+
+	d.SetId("watcher")
+	ds := &resourceDataSetter{d: d}
+	ds.set("watcher_state", watcherState(states))
+	ds.set("watch_count", watchCount)
+	ds.set("queue_size", queueSize)
+	ds.set("current_watches", currentWatches)
+	ds.set("queued_watches", queuedWatches)
+	ds.set("nodes", nodes)
+	return ds.err
+}
@@ -0,0 +1,54 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceElasticsearchXpackWatcherStatsRead(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_watcher/stats/_all":
+			_, _ = w.Write([]byte(`{
+				"_nodes": {"total": 2, "successful": 2, "failed": 0},
+				"cluster_name": "test",
+				"manually_stopped": false,
+				"stats": [
+					{"node_id": "node-2", "watcher_state": "started", "watch_count": 3, "execution_thread_pool": {"queue_size": 4, "max_size": 10},
+					 "current_watches": [{"watch_id": "a"}], "queued_watches": [{"watch_id": "b"}, {"watch_id": "c"}]},
+					{"node_id": "node-1", "watcher_state": "started", "watch_count": 2, "execution_thread_pool": {"queue_size": 1, "max_size": 10},
+					 "current_watches": [], "queued_watches": []}
+				]
+			}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := dataSourceElasticsearchXpackWatcherStats()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"watcher_state":           "started",
+		"watch_count":             5,
+		"queue_size":              5,
+		"current_watches":         1,
+		"queued_watches":          2,
+		"nodes.#":                 2,
+		"nodes.0.node_id":         "node-1",
+		"nodes.1.queue_size":      4,
+		"nodes.1.max_size":        10,
+		"nodes.1.queued_watches":  2,
+		"nodes.1.current_watches": 1,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
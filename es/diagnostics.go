@@ -0,0 +1,151 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+// errorDetails is the error object of an Elasticsearch response, decoded the
+// same way for all client versions.
+type errorDetails struct {
+	Type      string          `json:"type"`
+	Reason    string          `json:"reason"`
+	CausedBy  *errorDetails   `json:"caused_by"`
+	RootCause []*errorDetails `json:"root_cause"`
+}
+
+func (e *errorDetails) String() string {
+	return fmt.Sprintf("%s: %s", e.Type, e.Reason)
+}
+
+// clusterError returns the HTTP status and the error object of an error
+// returned by the cluster, or false if err didn't come from a response.
+func clusterError(err error) (int, *errorDetails, bool) {
+	var status int
+	var details interface{}
+	switch e := err.(type) {
+	case *elastic7.Error:
+		status, details = e.Status, e.Details
+	case *elastic6.Error:
+		status, details = e.Status, e.Details
+	case *elastic5.Error:
+		status, details = e.Status, e.Details
+	default:
+		return 0, nil, false
+	}
+
+	var d *errorDetails
+	if b, err := json.Marshal(details); err == nil {
+		_ = json.Unmarshal(b, &d)
+	}
+	return status, d, true
+}
+
+// describedError is an error returned by the cluster with the description of
+// describeError, unwrapping to the error of the client, e.g. *elastic7.Error.
+type describedError struct {
+	description string
+	err         error
+}
+
+func (e *describedError) Error() string {
+	return e.description
+}
+
+func (e *describedError) Unwrap() error {
+	return e.err
+}
+
+// describeError rewrites an error returned by the cluster to mention its
+// status, type, causes and root causes, which the clients leave out, and,
+// when it can tell, the attribute that caused it. Other errors are returned
+// unchanged.
+func describeError(err error, r *schema.Resource) error {
+	status, details, ok := clusterError(err)
+	if !ok {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s", status, http.StatusText(status))
+	if details == nil {
+		return &describedError{description: b.String(), err: err}
+	}
+	fmt.Fprintf(&b, ": %s", details)
+	for cause := details.CausedBy; cause != nil; cause = cause.CausedBy {
+		fmt.Fprintf(&b, "\n  caused by %s", cause)
+	}
+	for _, cause := range details.RootCause {
+		if cause.Type != details.Type || cause.Reason != details.Reason {
+			fmt.Fprintf(&b, "\n  root cause %s", cause)
+		}
+	}
+
+	switch {
+	case status == http.StatusUnauthorized:
+		b.WriteString("\n  check the credentials configured in the provider")
+	case status == http.StatusForbidden:
+		b.WriteString("\n  the configured user lacks the privileges to manage this resource")
+	case isParseError(details) && r != nil && r.Schema["body"] != nil:
+		b.WriteString("\n  attribute: body")
+	}
+
+	return &describedError{description: b.String(), err: err}
+}
+
+func isParseError(details *errorDetails) bool {
+	for d := details; d != nil; d = d.CausedBy {
+		if strings.HasSuffix(d.Type, "parse_exception") || strings.HasSuffix(d.Type, "parsing_exception") {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceWithDiagnostics makes the errors of each operation of r name the
+// resource and describe the response of the cluster, see describeError.
+func resourceWithDiagnostics(name string, r *schema.Resource) {
+	r.Create = withDiagnostics(r.Create, r, "creating", name)
+	r.Read = withDiagnostics(r.Read, r, "reading", name)
+	r.Update = withDiagnostics(r.Update, r, "updating", name)
+	r.Delete = withDiagnostics(r.Delete, r, "deleting", name)
+}
+
+func withDiagnostics(f func(*schema.ResourceData, interface{}) error, r *schema.Resource, operation string, name string) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		err := f(d, meta)
+		if err == nil {
+			return nil
+		}
+		// e.g. the error of a wrapped operation called by another one
+		if namesResource(err, name) {
+			return err
+		}
+		if d.Id() != "" {
+			return fmt.Errorf("error %s %s %q: %w", operation, name, d.Id(), describeError(err, r))
+		}
+		return fmt.Errorf("error %s %s: %w", operation, name, describeError(err, r))
+	}
+}
+
+// namesResource returns whether err already starts with the name of the
+// resource, as added by withDiagnostics.
+func namesResource(err error, name string) bool {
+	for _, operation := range []string{"creating", "reading", "updating", "deleting"} {
+		prefix := fmt.Sprintf("error %s %s", operation, name)
+		if strings.HasPrefix(err.Error(), prefix+" ") || strings.HasPrefix(err.Error(), prefix+":") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package es
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func TestDescribeError(t *testing.T) {
+	r := resourceElasticsearchComposableIndexTemplate()
+
+	err := describeError(&elastic7.Error{
+		Status: 400,
+		Details: &elastic7.ErrorDetails{
+			Type:   "x_content_parse_exception",
+			Reason: "[1:14] [index_template] failed to parse field [template]",
+			CausedBy: map[string]interface{}{
+				"type":   "illegal_argument_exception",
+				"reason": "unknown setting [index.foo]",
+			},
+			RootCause: []*elastic7.ErrorDetails{
+				{Type: "x_content_parse_exception", Reason: "[1:14] [index_template] failed to parse field [template]"},
+				{Type: "illegal_argument_exception", Reason: "unknown setting [index.foo]"},
+			},
+		},
+	}, r)
+	expected := `400 Bad Request: x_content_parse_exception: [1:14] [index_template] failed to parse field [template]
+  caused by illegal_argument_exception: unknown setting [index.foo]
+  root cause illegal_argument_exception: unknown setting [index.foo]
+  attribute: body`
+	if err.Error() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, err)
+	}
+
+	err = describeError(&elastic6.Error{
+		Status:  401,
+		Details: &elastic6.ErrorDetails{Type: "security_exception", Reason: "unable to authenticate user [elastic]"},
+	}, r)
+	if !strings.Contains(err.Error(), "401 Unauthorized: security_exception") || !strings.Contains(err.Error(), "check the credentials") {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	other := errors.New("connection refused")
+	if err = describeError(other, r); err != other {
+		t.Errorf("expected other errors to be unchanged, got %s", err)
+	}
+}
+
+func TestResourceWithDiagnostics(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Optional: true}},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return &elastic7.Error{Status: 403, Details: &elastic7.ErrorDetails{Type: "security_exception", Reason: "action [indices:admin/get] is unauthorized"}}
+		},
+	}
+	resourceWithDiagnostics("elasticsearch_index", r)
+
+	d := r.TestResourceData()
+	d.SetId("test")
+	err := r.Read(d, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), `error reading elasticsearch_index "test": 403 Forbidden: security_exception`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var esErr *elastic7.Error
+	if !errors.As(err, &esErr) || esErr.Status != 403 {
+		t.Errorf("expected the error of the client to be wrapped, got %#v", err)
+	}
+
+	// an operation returning the error of another one isn't prefixed twice
+	read := r.Read
+	r.Create = func(d *schema.ResourceData, meta interface{}) error {
+		d.SetId("test")
+		return read(d, meta)
+	}
+	resourceWithDiagnostics("elasticsearch_index", r)
+	err = r.Create(r.TestResourceData(), nil)
+	if err == nil || strings.Count(err.Error(), "elasticsearch_index") != 1 {
+		t.Errorf("expected the resource to be named once, got %v", err)
+	}
+}
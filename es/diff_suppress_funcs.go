@@ -7,200 +7,65 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
-func diffSuppressIndexTemplate(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeIndexTemplate(om)
-	}
-
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeIndexTemplate(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-/*
-diffSuppressComposableIndexTemplate compares an index_template (ES >= 7.8) Index template definition
-For legacy index templates (ES < 7.8) or /_template endpoint on ES >= 7.8 see diffSuppressIndexTemplate.
-*/
-func diffSuppressComposableIndexTemplate(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeComposableIndexTemplate(om)
-	}
-
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeComposableIndexTemplate(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-func diffSuppressComponentTemplate(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeComponentTemplate(om)
-	}
-
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeComponentTemplate(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-func diffSuppressDestination(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeDestination(om)
-	}
-
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeDestination(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-func diffSuppressMonitor(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeMonitor(om)
-	}
-
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeMonitor(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-func suppressEquivalentJson(k, old, new string, d *schema.ResourceData) bool {
-	var oldObj, newObj interface{}
-	if err := json.Unmarshal([]byte(old), &oldObj); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &newObj); err != nil {
-		return false
-	}
-	return reflect.DeepEqual(oldObj, newObj)
-}
-
-func diffSuppressIndexLifecyclePolicy(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeIndexLifecyclePolicy(om)
-	}
-
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeIndexLifecyclePolicy(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-func diffSuppressSnapshotLifecyclePolicy(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizeSnapshotLifecyclePolicy(om)
-	}
+// jsonNormalizer removes from, or rewrites in, a JSON object what the API
+// adds or changes when it's stored, e.g. defaults, server side metadata or
+// numbers returned as strings, so that it can be compared with the
+// configured object.
+type jsonNormalizer func(map[string]interface{})
+
+var (
+	suppressEquivalentJson = diffSuppressJson()
+
+	diffSuppressIndexTemplate = diffSuppressJson(normalizeIndexTemplate)
+	// diffSuppressComposableIndexTemplate compares an index_template (ES >=
+	// 7.8) Index template definition. For legacy index templates (ES < 7.8) or
+	// /_template endpoint on ES >= 7.8 see diffSuppressIndexTemplate.
+	diffSuppressComposableIndexTemplate = diffSuppressJson(normalizeComposableIndexTemplate)
+	diffSuppressComponentTemplate       = diffSuppressJson(normalizeComponentTemplate)
+	diffSuppressDestination             = diffSuppressJson(normalizeDestination)
+	diffSuppressMonitor                 = diffSuppressJson(normalizeMonitor)
+	diffSuppressIndexLifecyclePolicy    = diffSuppressJson(normalizeIndexLifecyclePolicy)
+	diffSuppressSnapshotLifecyclePolicy = diffSuppressJson(normalizeSnapshotLifecyclePolicy)
+	diffSuppressIngestPipeline          = diffSuppressJson()
+	diffSuppressPolicy                  = diffSuppressJson(normalizePolicy)
+	diffSuppressLicense                 = diffSuppressJson()
+	diffSuppressIndexMappings           = diffSuppressJson(normalizeIndexMappings)
+)
 
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizeSnapshotLifecyclePolicy(nm)
+// diffSuppressJson returns a DiffSuppressFunc ignoring the differences
+// between two JSON strings that disappear once both are normalized, like
+// formatting, key order and whatever the normalizers strip or coerce.
+func diffSuppressJson(normalizers ...jsonNormalizer) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return equivalentJson(old, new, normalizers...)
 	}
-
-	return reflect.DeepEqual(oo, no)
 }
 
-func diffSuppressIngestPipeline(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
+func equivalentJson(old, new string, normalizers ...jsonNormalizer) bool {
+	oo, err := normalizedJson(old, normalizers...)
+	if err != nil {
 		return false
 	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
+	no, err := normalizedJson(new, normalizers...)
+	if err != nil {
 		return false
 	}
 
 	return reflect.DeepEqual(oo, no)
 }
 
-func diffSuppressPolicy(k, old, new string, d *schema.ResourceData) bool {
-	var oo, no interface{}
-	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		return false
+// normalizedJson decodes a JSON string and, if it's an object, applies the
+// normalizers to it in order.
+func normalizedJson(s string, normalizers ...jsonNormalizer) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		return false
-	}
-
-	if om, ok := oo.(map[string]interface{}); ok {
-		normalizePolicy(om)
+	if m, ok := v.(map[string]interface{}); ok {
+		for _, normalize := range normalizers {
+			normalize(m)
+		}
 	}
 
-	if nm, ok := no.(map[string]interface{}); ok {
-		normalizePolicy(nm)
-	}
-
-	return reflect.DeepEqual(oo, no)
-}
-
-func diffSuppressLicense(k, old, new string, d *schema.ResourceData) bool {
-	var oldObj, newObj map[string]interface{}
-	if err := json.Unmarshal([]byte(old), &oldObj); err != nil {
-		return false
-	}
-	if err := json.Unmarshal([]byte(new), &newObj); err != nil {
-		return false
-	}
-	return reflect.DeepEqual(oldObj, newObj)
+	return v, nil
 }
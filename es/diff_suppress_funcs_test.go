@@ -0,0 +1,77 @@
+package es
+
+import (
+	"testing"
+)
+
+func TestDiffSuppressJson(t *testing.T) {
+	cases := []struct {
+		name     string
+		suppress func(k, old, new string) bool
+		old, new string
+		expected bool
+	}{
+		{
+			"key order and formatting",
+			func(k, old, new string) bool { return suppressEquivalentJson(k, old, new, nil) },
+			`{"a": 1, "b": [1, 2]}`,
+			`{"b":[1,2],"a":1}`,
+			true,
+		},
+		{
+			"different values",
+			func(k, old, new string) bool { return suppressEquivalentJson(k, old, new, nil) },
+			`{"a": 1}`,
+			`{"a": 2}`,
+			false,
+		},
+		{
+			"invalid json",
+			func(k, old, new string) bool { return suppressEquivalentJson(k, old, new, nil) },
+			`{"a": 1}`,
+			`{"a": `,
+			false,
+		},
+		{
+			"index settings returned as strings",
+			func(k, old, new string) bool { return diffSuppressIndexTemplate(k, old, new, nil) },
+			`{"index_patterns": ["a-*"], "version": 2, "settings": {"index": {"number_of_shards": "1", "max_result_window": "1000000"}}}`,
+			`{"index_patterns": ["a-*"], "settings": {"number_of_shards": 1, "max_result_window": 1000000}}`,
+			true,
+		},
+		{
+			"server managed index settings",
+			func(k, old, new string) bool { return diffSuppressComposableIndexTemplate(k, old, new, nil) },
+			`{"index_patterns": ["a-*"], "template": {"settings": {"index": {"number_of_shards": "1", "uuid": "x1", "creation_date": "1600000000000"}}}}`,
+			`{"index_patterns": ["a-*"], "template": {"settings": {"number_of_shards": 1}}}`,
+			true,
+		},
+		{
+			"data stream defaults",
+			func(k, old, new string) bool { return diffSuppressComposableIndexTemplate(k, old, new, nil) },
+			`{"index_patterns": ["logs-*"], "data_stream": {"hidden": false, "allow_custom_routing": false}}`,
+			`{"index_patterns": ["logs-*"], "data_stream": {}}`,
+			true,
+		},
+		{
+			"ism policy metadata",
+			func(k, old, new string) bool { return diffSuppressPolicy(k, old, new, nil) },
+			`{"policy": {"policy_id": "p", "last_updated_time": 1, "schema_version": 1, "error_notification": null, "states": []}}`,
+			`{"policy": {"states": []}}`,
+			true,
+		},
+		{
+			"destination metadata",
+			func(k, old, new string) bool { return diffSuppressDestination(k, old, new, nil) },
+			`{"id": "abc", "last_update_time": 1, "schema_version": 3, "name": "slack"}`,
+			`{"name": "slack"}`,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		if actual := c.suppress("body", c.old, c.new); actual != c.expected {
+			t.Errorf("%s: expected %t, got %t", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package es
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// failureStoreSchema is the failure store of a data stream, or of the data
+// streams created by a template, where the documents that fail ingestion,
+// e.g. because of mapping conflicts or failing pipelines, are stored instead
+// of being rejected.
+func failureStoreSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enabled": {
+					Type:        schema.TypeBool,
+					Required:    true,
+					Description: "Whether the documents failing ingestion are stored in the failure store.",
+				},
+				"retention": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+					Description:  "How long the failed documents are kept, e.g. `7d`. Defaults to the retention of the cluster. Requires Elasticsearch 8.19.",
+				},
+			},
+		},
+		Description: description,
+	}
+}
+
+// expandFailureStore returns the failure_store of the data stream options of
+// a failure store block.
+func expandFailureStore(block map[string]interface{}) map[string]interface{} {
+	failureStore := map[string]interface{}{
+		"enabled": block["enabled"].(bool),
+	}
+	if retention := block["retention"].(string); retention != "" {
+		failureStore["lifecycle"] = map[string]interface{}{
+			"data_retention": retention,
+		}
+	}
+	return failureStore
+}
+
+// flattenFailureStore returns the failure store block of the failure_store of
+// data stream options, disabled if there is none.
+func flattenFailureStore(failureStore map[string]interface{}) []interface{} {
+	enabled, _ := failureStore["enabled"].(bool)
+	retention := ""
+	if lifecycle, ok := failureStore["lifecycle"].(map[string]interface{}); ok {
+		retention, _ = lifecycle["data_retention"].(string)
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"enabled":   enabled,
+			"retention": retention,
+		},
+	}
+}
+
+// checkFailureStore returns an error if the cluster can't configure the
+// failure store block: before Elasticsearch 8.19, templates could only
+// enable it, without a retention.
+func checkFailureStore(conf *ProviderConf, block map[string]interface{}) error {
+	if err := capabilityDataStreamFailureStore.check(conf); err != nil {
+		return err
+	}
+	if block["retention"].(string) != "" {
+		if err := capabilityDataStreamOptions.check(conf); err != nil {
+			return fmt.Errorf("the retention of the failure store can't be configured: %+v", err)
+		}
+	}
+	return nil
+}
+
+// putTemplateFailureStore sets the failure store of the data streams created
+// by a composable index template in its body, in the data stream options of
+// the template from Elasticsearch 8.19, or as the failure_store flag of its
+// data_stream before.
+func putTemplateFailureStore(conf *ProviderConf, tpl map[string]interface{}, block map[string]interface{}) error {
+	if err := checkFailureStore(conf, block); err != nil {
+		return err
+	}
+	if capabilityDataStreamOptions.check(conf) != nil {
+		dataStream, ok := tpl["data_stream"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("the failure store requires the template to create data streams, with a data_stream object")
+		}
+		dataStream["failure_store"] = block["enabled"].(bool)
+		return nil
+	}
+
+	innerTpl, ok := tpl["template"].(map[string]interface{})
+	if !ok {
+		innerTpl = map[string]interface{}{}
+		tpl["template"] = innerTpl
+	}
+	options, ok := innerTpl["data_stream_options"].(map[string]interface{})
+	if !ok {
+		options = map[string]interface{}{}
+		innerTpl["data_stream_options"] = options
+	}
+	options["failure_store"] = expandFailureStore(block)
+	return nil
+}
+
+// popTemplateFailureStore removes the failure store from the body of a
+// composable index template, in either format, and returns it as a block.
+func popTemplateFailureStore(tpl map[string]interface{}) []interface{} {
+	failureStore := map[string]interface{}{}
+	if dataStream, ok := tpl["data_stream"].(map[string]interface{}); ok {
+		if enabled, ok := dataStream["failure_store"].(bool); ok {
+			failureStore["enabled"] = enabled
+		}
+		delete(dataStream, "failure_store")
+	}
+	if innerTpl, ok := tpl["template"].(map[string]interface{}); ok {
+		if options, ok := innerTpl["data_stream_options"].(map[string]interface{}); ok {
+			if fs, ok := options["failure_store"].(map[string]interface{}); ok {
+				failureStore = fs
+			}
+			delete(options, "failure_store")
+			if len(options) == 0 {
+				delete(innerTpl, "data_stream_options")
+				if len(innerTpl) == 0 {
+					delete(tpl, "template")
+				}
+			}
+		}
+	}
+	return flattenFailureStore(failureStore)
+}
@@ -1,7 +1,18 @@
 package es
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 type withHeader struct {
@@ -28,3 +39,263 @@ func (h withHeader) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	return h.rt.RoundTrip(req)
 }
+
+type withRestCompatibility struct {
+	rt      http.RoundTripper
+	version int
+}
+
+// WithRestCompatibility wraps rt so that requests ask the cluster to accept
+// and answer them in the format of the given major version, e.g. for the v7
+// client to talk to Elasticsearch 8. JSON and NDJSON bodies keep their format.
+//
+// Elasticsearch 8 is reached this way rather than with go-elasticsearch: the
+// resources switch on the olivere client types and check their errors, e.g.
+// with elastic7.IsNotFound, and the APIs only found in 8, like inference or
+// synonyms, are plain REST calls that performRequest already sends over this
+// transport, with its retries, rate limits and authentication.
+func WithRestCompatibility(rt http.RoundTripper, version int) withRestCompatibility {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return withRestCompatibility{rt: rt, version: version}
+}
+
+func (c withRestCompatibility) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Accept", fmt.Sprintf("application/vnd.elasticsearch+json;compatible-with=%d", c.version))
+	switch strings.SplitN(req.Header.Get("Content-Type"), ";", 2)[0] {
+	case "application/json":
+		req.Header.Set("Content-Type", fmt.Sprintf("application/vnd.elasticsearch+json;compatible-with=%d", c.version))
+	case "application/x-ndjson":
+		req.Header.Set("Content-Type", fmt.Sprintf("application/vnd.elasticsearch+x-ndjson;compatible-with=%d", c.version))
+	}
+
+	return c.rt.RoundTrip(req)
+}
+
+type withConcurrencyLimit struct {
+	rt      http.RoundTripper
+	slots   chan struct{}
+	metrics *apiMetrics
+}
+
+// WithConcurrencyLimit wraps rt so that at most cap(slots) requests sharing
+// slots are in flight at once, from sending the request until its response
+// body is closed. Requests wait for a slot until their context is done, the
+// waits being counted in metrics. A nil slots doesn't limit anything.
+func WithConcurrencyLimit(rt http.RoundTripper, slots chan struct{}, metrics *apiMetrics) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if slots == nil {
+		return rt
+	}
+
+	return withConcurrencyLimit{rt: rt, slots: slots, metrics: metrics}
+}
+
+func (l withConcurrencyLimit) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		start := time.Now()
+		select {
+		case l.slots <- struct{}{}:
+			l.metrics.waitedForSlot(req, time.Since(start))
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	res, err := l.rt.RoundTrip(req)
+	if err != nil {
+		<-l.slots
+		return nil, err
+	}
+	res.Body = &releaseOnClose{ReadCloser: res.Body, release: func() { <-l.slots }}
+	return res, nil
+}
+
+// releaseOnClose calls release once, when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+type withRetry struct {
+	rt         http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	metrics    *apiMetrics
+}
+
+// WithRetry wraps rt so that requests answered with 429 Too Many Requests or
+// 503 Service Unavailable are retried up to maxRetries times, using
+// exponential backoff between minBackoff and maxBackoff unless the server
+// sends a Retry-After header, which is capped at maxBackoff too.
+func WithRetry(rt http.RoundTripper, maxRetries int, minBackoff, maxBackoff time.Duration) withRetry {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return withRetry{rt: rt, maxRetries: maxRetries, minBackoff: minBackoff, maxBackoff: maxBackoff}
+}
+
+func (r withRetry) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the elastic clients don't set GetBody, so buffer the body to be able to
+	// send it again
+	if req.Body != nil && req.GetBody == nil && r.maxRetries > 0 {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		req.Body, _ = req.GetBody()
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := r.rt.RoundTrip(req)
+		if err != nil || attempt >= r.maxRetries || !isRetryableStatus(res.StatusCode) {
+			return res, err
+		}
+
+		wait := r.backoff(attempt, res.Header.Get("Retry-After"))
+		log.Printf("[INFO] %s %s returned %d, retrying in %s (attempt %d of %d)", req.Method, req.URL.Path, res.StatusCode, wait, attempt+1, r.maxRetries)
+		res.Body.Close()
+		r.metrics.retried(req)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func (r withRetry) backoff(attempt int, retryAfter string) time.Duration {
+	wait := time.Duration(float64(r.minBackoff) * math.Pow(2, float64(attempt)))
+	if wait <= 0 {
+		wait = r.maxBackoff
+	}
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			// large numbers of seconds overflow
+			wait = r.maxBackoff
+			if seconds < int(r.maxBackoff/time.Second)+1 {
+				wait = time.Duration(seconds) * time.Second
+			}
+		} else if t, err := http.ParseTime(retryAfter); err == nil {
+			wait = 0
+			if until := time.Until(t); until > 0 {
+				wait = until
+			}
+		}
+	}
+
+	// a cluster asking to retry in hours would hang the apply
+	if wait > r.maxBackoff {
+		wait = r.maxBackoff
+	}
+	return wait
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+type withTokenFile struct {
+	rt        http.RoundTripper
+	path      string
+	tokenName string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+// WithTokenFile wraps rt so that every request carries an Authorization
+// header with the token read from path. The file is read again whenever its
+// modification time changes, so rotated tokens are picked up mid-run.
+func WithTokenFile(rt http.RoundTripper, path string, tokenName string) *withTokenFile {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &withTokenFile{rt: rt, path: path, tokenName: tokenName}
+}
+
+func (t *withTokenFile) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.currentToken()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", t.tokenName, token))
+
+	return t.rt.RoundTrip(req)
+}
+
+func (t *withTokenFile) currentToken() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return "", fmt.Errorf("error reading token file: %+v", err)
+	}
+	if t.token == "" || !info.ModTime().Equal(t.modTime) {
+		token, err := readTokenFile(t.path)
+		if err != nil {
+			return "", err
+		}
+		log.Printf("[INFO] Loaded token from %s", t.path)
+		t.token = token
+		t.modTime = info.ModTime()
+	}
+
+	return t.token, nil
+}
+
+func readTokenFile(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading token file: %+v", err)
+	}
+	token := strings.TrimSpace(string(contents))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+
+	return token, nil
+}
+
+// tokenTransport adds the token from the configured token file or
+// credentials command to rt, if one is set.
+func tokenTransport(conf *ProviderConf, rt http.RoundTripper) http.RoundTripper {
+	if conf.tokenFile != "" {
+		return WithTokenFile(rt, conf.tokenFile, conf.tokenName)
+	}
+	if conf.credentialsCommand != nil {
+		return WithCredentialsCommand(rt, conf.credentialsCommand, conf.tokenName)
+	}
+	return rt
+}
@@ -0,0 +1,268 @@
+package es
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if body, _ := ioutil.ReadAll(r.Body); string(body) != `{"foo":"bar"}` {
+			t.Errorf("unexpected body on attempt %d: %s", calls, body)
+		}
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithRetry(nil, 3, time.Millisecond, 10*time.Millisecond)}
+	req, _ := http.NewRequest("PUT", server.URL, strings.NewReader(`{"foo":"bar"}`))
+	// like the elastic clients, don't provide a way to rewind the body
+	req.GetBody = nil
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", res.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 requests, got %d", calls)
+	}
+}
+
+func TestWithRetryBackoff(t *testing.T) {
+	r := WithRetry(nil, 3, 100*time.Millisecond, 30*time.Second)
+	for _, tt := range []struct {
+		attempt    int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{0, "", 100 * time.Millisecond},
+		{2, "", 400 * time.Millisecond},
+		{20, "", 30 * time.Second},
+		{0, "5", 5 * time.Second},
+		{0, "0", 0},
+		{0, "3600", 30 * time.Second},
+		{0, "99999999999999999", 30 * time.Second},
+		{0, time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 30 * time.Second},
+		{0, "Mon, 02 Jan 2006 15:04:05 GMT", 0},
+		{1, "soon", 200 * time.Millisecond},
+	} {
+		if actual := r.backoff(tt.attempt, tt.retryAfter); actual != tt.expected {
+			t.Errorf("attempt %d, Retry-After %q: expected %s, got %s", tt.attempt, tt.retryAfter, tt.expected, actual)
+		}
+	}
+}
+
+func TestWithRetryGivesUp(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithRetry(nil, 2, time.Millisecond, 10*time.Millisecond)}
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", res.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 requests, got %d", calls)
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	conf := &ProviderConf{
+		token:     "secret",
+		tokenName: "Bearer",
+		headers:   map[string]string{"X-Proxyauth": "proxy", "Authorization": "ignored"},
+	}
+	client := tokenHttpClient(conf, map[string]string{"kbn-xsrf": "true"})
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{
+		"X-Proxyauth":   "proxy",
+		"Authorization": "Bearer secret",
+		"Kbn-Xsrf":      "true",
+	}
+	for k, v := range expected {
+		if received.Get(k) != v {
+			t.Errorf("expected header %s to be %q, got %q", k, v, received.Get(k))
+		}
+	}
+}
+
+func TestWithRestCompatibility(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithRestCompatibility(nil, 7)}
+	tests := map[string]string{
+		"application/json":     "application/vnd.elasticsearch+json;compatible-with=7",
+		"application/x-ndjson": "application/vnd.elasticsearch+x-ndjson;compatible-with=7",
+		"text/plain":           "text/plain",
+	}
+	for contentType, expected := range tests {
+		if _, err := client.Post(server.URL, contentType, strings.NewReader("{}")); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if received.Get("Content-Type") != expected {
+			t.Errorf("expected Content-Type %q for %s, got %q", expected, contentType, received.Get("Content-Type"))
+		}
+		if received.Get("Accept") != "application/vnd.elasticsearch+json;compatible-with=7" {
+			t.Errorf("unexpected Accept %q", received.Get("Accept"))
+		}
+	}
+}
+
+func TestWithConcurrencyLimit(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithConcurrencyLimit(nil, make(chan struct{}, 2), nil)}
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("err: %s", err)
+				return
+			}
+			_, _ = ioutil.ReadAll(res.Body)
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestWithTokenFile(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	client := &http.Client{Transport: WithTokenFile(nil, path, "Bearer")}
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if received != "Bearer first" {
+		t.Errorf("expected Authorization to be %q, got %q", "Bearer first", received)
+	}
+
+	// rotate the token, making sure the modification time changes
+	if err := ioutil.WriteFile(path, []byte("second"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if received != "Bearer second" {
+		t.Errorf("expected Authorization to be %q, got %q", "Bearer second", received)
+	}
+}
+
+func TestWithCredentialsCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("expired\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			// the token expired, the next run of the command gets a new one
+			_ = ioutil.WriteFile(path, []byte("fresh\n"), 0600)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	command, err := newCredentialsCommand([]string{"cat", path}, time.Hour)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &http.Client{Transport: WithCredentialsCommand(nil, command, "Bearer")}
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected the request to succeed with a refreshed token, got %d", res.StatusCode)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+
+	if _, err := newCredentialsCommand(nil, time.Hour); err == nil {
+		t.Errorf("expected an error without a command")
+	}
+}
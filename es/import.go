@@ -0,0 +1,49 @@
+package es
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// parseCompositeId splits an import ID made of the named parts separated by
+// slashes, e.g. "index/object_id". The last part may itself contain slashes.
+// Malformed IDs return an error describing the expected format, since these
+// are typed by hand on the command line.
+func parseCompositeId(id string, parts ...string) ([]string, error) {
+	values := strings.SplitN(id, "/", len(parts))
+	if len(values) != len(parts) {
+		return nil, fmt.Errorf("unexpected ID %q, expected %s", id, strings.Join(parts, "/"))
+	}
+	for i, v := range values {
+		if v == "" {
+			return nil, fmt.Errorf("unexpected ID %q, expected %s with a non-empty %s", id, strings.Join(parts, "/"), parts[i])
+		}
+	}
+
+	return values, nil
+}
+
+// importStateCompositeId returns a StateFunc for resources whose Read needs
+// more than their ID, parsing an import ID made of parts into the attributes
+// with the same names. The resource's ID is then set to the value of the
+// idPart.
+func importStateCompositeId(idPart string, parts ...string) schema.StateFunc {
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		values, err := parseCompositeId(d.Id(), parts...)
+		if err != nil {
+			return nil, err
+		}
+
+		ds := &resourceDataSetter{d: d}
+		for i, part := range parts {
+			ds.set(part, values[i])
+			if part == idPart {
+				d.SetId(values[i])
+			}
+		}
+
+		return []*schema.ResourceData{d}, ds.err
+	}
+}
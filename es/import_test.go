@@ -0,0 +1,77 @@
+package es
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestParseCompositeId(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected []string
+		err      string
+	}{
+		{"logs-*/delete_after_15d", []string{"logs-*", "delete_after_15d"}, ""},
+		{".kibana/visualization:a/b", []string{".kibana", "visualization:a/b"}, ""},
+		{"logs-*", nil, `unexpected ID "logs-*", expected indexes/policy_id`},
+		{"logs-*/", nil, `unexpected ID "logs-*/", expected indexes/policy_id with a non-empty policy_id`},
+		{"/delete_after_15d", nil, "with a non-empty indexes"},
+	}
+
+	for _, tt := range tests {
+		values, err := parseCompositeId(tt.id, "indexes", "policy_id")
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: expected error containing %q, got %v", tt.id, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tt.id, err)
+		}
+		if !reflect.DeepEqual(values, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.id, tt.expected, values)
+		}
+	}
+}
+
+func TestImportStateCompositeId(t *testing.T) {
+	r := resourceElasticsearchOpenDistroISMPolicyMapping()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("logs-*/delete_after_15d")
+
+	results, err := r.Importer.State(d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Id() != "logs-*" {
+		t.Errorf("expected ID logs-*, got %s", results[0].Id())
+	}
+	if v := results[0].Get("policy_id").(string); v != "delete_after_15d" {
+		t.Errorf("expected policy_id delete_after_15d, got %s", v)
+	}
+
+	r = resourceElasticsearchKibanaObject()
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId(".kibana/index-pattern:cloudtrail")
+
+	results, err = r.Importer.State(d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if results[0].Id() != "index-pattern:cloudtrail" {
+		t.Errorf("expected ID index-pattern:cloudtrail, got %s", results[0].Id())
+	}
+	if v := results[0].Get("index").(string); v != ".kibana" {
+		t.Errorf("expected index .kibana, got %s", v)
+	}
+	if v := results[0].Get("body").(string); v != `[{"_id":"index-pattern:cloudtrail","_source":{}}]` {
+		t.Errorf("unexpected body %s", v)
+	}
+}
@@ -0,0 +1,325 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// ingestProcessorOptions are the options of the typed processor blocks, by
+// processor type. Options left to their default, the one of Elasticsearch,
+// are left out of the pipeline.
+var ingestProcessorOptions = map[string]map[string]*schema.Schema{
+	"set": {
+		"field":              ingestProcessorOption(schema.TypeString, true, "The field to set."),
+		"value":              ingestProcessorOption(schema.TypeString, false, "The value to set, with templates like `{{{other_field}}}`."),
+		"copy_from":          ingestProcessorOption(schema.TypeString, false, "The field to copy the value from, instead of `value`."),
+		"override":           ingestProcessorBoolOption(true, "Whether to replace the value of a field that is already set."),
+		"ignore_empty_value": ingestProcessorBoolOption(false, "Whether to do nothing if the value is empty."),
+		"media_type":         ingestProcessorOption(schema.TypeString, false, "The media type encoding the templates of `value`, e.g. `application/json`."),
+	},
+	"rename": {
+		"field":          ingestProcessorOption(schema.TypeString, true, "The field to rename."),
+		"target_field":   ingestProcessorOption(schema.TypeString, true, "The new name of the field."),
+		"ignore_missing": ingestProcessorBoolOption(false, "Whether to do nothing if the field doesn't exist."),
+	},
+	"grok": {
+		"field":               ingestProcessorOption(schema.TypeString, true, "The field to parse."),
+		"patterns":            ingestProcessorOption(schema.TypeList, true, "The grok patterns to match, the first matching one is used."),
+		"pattern_definitions": ingestProcessorOption(schema.TypeMap, false, "Custom patterns for `patterns`, by name."),
+		"ignore_missing":      ingestProcessorBoolOption(false, "Whether to do nothing if the field doesn't exist."),
+		"trace_match":         ingestProcessorBoolOption(false, "Whether to add the index of the matching pattern to the document."),
+	},
+	"date": {
+		"field":         ingestProcessorOption(schema.TypeString, true, "The field to parse."),
+		"formats":       ingestProcessorOption(schema.TypeList, true, "The formats of the date, e.g. `ISO8601` or `dd/MMM/yyyy:HH:mm:ss Z`."),
+		"target_field":  ingestProcessorOption(schema.TypeString, false, "The field to store the date in. Defaults to `@timestamp`."),
+		"timezone":      ingestProcessorOption(schema.TypeString, false, "The timezone of dates without one. Defaults to `UTC`."),
+		"locale":        ingestProcessorOption(schema.TypeString, false, "The locale of the names of months and days. Defaults to `ENGLISH`."),
+		"output_format": ingestProcessorOption(schema.TypeString, false, "The format of the stored date."),
+	},
+	"geoip": {
+		"field":          ingestProcessorOption(schema.TypeString, true, "The field with the IP address."),
+		"target_field":   ingestProcessorOption(schema.TypeString, false, "The field to store the location in. Defaults to `geoip`."),
+		"database_file":  ingestProcessorOption(schema.TypeString, false, "The database to look up the address in, e.g. `GeoLite2-City.mmdb`."),
+		"properties":     ingestProcessorOption(schema.TypeList, false, "The properties of the location to store, e.g. `country_iso_code`."),
+		"ignore_missing": ingestProcessorBoolOption(false, "Whether to do nothing if the field doesn't exist."),
+		"first_only":     ingestProcessorBoolOption(true, "Whether to only look up the first address of a field with several."),
+	},
+	"pipeline": {
+		"name":                    ingestProcessorOption(schema.TypeString, true, "The pipeline to run."),
+		"ignore_missing_pipeline": ingestProcessorBoolOption(false, "Whether to do nothing if the pipeline doesn't exist."),
+	},
+	"script": {
+		"lang":   ingestProcessorOption(schema.TypeString, false, "The language of `source`. Defaults to `painless`."),
+		"id":     ingestProcessorOption(schema.TypeString, false, "The ID of a stored script to run, instead of `source`."),
+		"source": ingestProcessorOption(schema.TypeString, false, "The script to run."),
+		"params": ingestProcessorJsonOption("The JSON object of the parameters of the script."),
+	},
+}
+
+// ingestProcessorCommonOptions are the options of every processor, set on the
+// processor block.
+var ingestProcessorCommonOptions = map[string]*schema.Schema{
+	"if":             ingestProcessorOption(schema.TypeString, false, "The painless condition to run the processor on."),
+	"tag":            ingestProcessorOption(schema.TypeString, false, "The identifier of the processor, e.g. in errors and stats."),
+	"description":    ingestProcessorOption(schema.TypeString, false, "The description of the processor."),
+	"ignore_failure": ingestProcessorBoolOption(false, "Whether to ignore the failures of the processor."),
+	"on_failure":     ingestProcessorJsonOption("The JSON array of the processors to run if the processor fails."),
+}
+
+// ingestProcessorJsonOptions are the options given as JSON.
+var ingestProcessorJsonOptions = map[string]bool{
+	"params":     true,
+	"on_failure": true,
+}
+
+func ingestProcessorOption(t schema.ValueType, required bool, description string) *schema.Schema {
+	s := &schema.Schema{
+		Type:        t,
+		Required:    required,
+		Optional:    !required,
+		Description: description,
+	}
+	if t == schema.TypeList || t == schema.TypeMap {
+		s.Elem = &schema.Schema{Type: schema.TypeString}
+	}
+	return s
+}
+
+func ingestProcessorBoolOption(def bool, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     def,
+		Description: description,
+	}
+}
+
+func ingestProcessorJsonOption(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateFunc:     validation.StringIsJSON,
+		DiffSuppressFunc: suppressEquivalentJson,
+		Description:      description,
+	}
+}
+
+// ingestProcessorTypes returns the types of the typed processor blocks,
+// sorted.
+func ingestProcessorTypes() []string {
+	types := make([]string, 0, len(ingestProcessorOptions))
+	for t := range ingestProcessorOptions {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func ingestProcessorSchema() *schema.Schema {
+	processor := map[string]*schema.Schema{
+		"json": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: suppressEquivalentJson,
+			Description:      "The JSON of any other processor, e.g. `{\"lowercase\": {\"field\": \"host.name\"}}`, with its options rather than the ones of the block.",
+		},
+	}
+	for name, s := range ingestProcessorCommonOptions {
+		processor[name] = s
+	}
+	for _, t := range ingestProcessorTypes() {
+		processor[t] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: fmt.Sprintf("A %s processor.", t),
+			Elem:        &schema.Resource{Schema: ingestProcessorOptions[t]},
+		}
+	}
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		ConflictsWith: []string{"body"},
+		Description:   fmt.Sprintf("The processors of the pipeline, in order, instead of `body`. Each has exactly one of the `%s` blocks or `json`.", strings.Join(ingestProcessorTypes(), "`, `")),
+		Elem:          &schema.Resource{Schema: processor},
+	}
+}
+
+// expandIngestProcessor returns the processor of a processor block.
+func expandIngestProcessor(block map[string]interface{}) (map[string]interface{}, error) {
+	var processorType string
+	var options map[string]interface{}
+	found := 0
+	if raw := block["json"].(string); raw != "" {
+		var processor map[string]map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &processor); err != nil || len(processor) != 1 {
+			return nil, fmt.Errorf("the json of a processor must be an object with the type of the processor as only key, got %s", raw)
+		}
+		for t, o := range processor {
+			processorType, options = t, o
+		}
+		found++
+	}
+	for _, t := range ingestProcessorTypes() {
+		if blocks, ok := block[t].([]interface{}); ok && len(blocks) > 0 {
+			values, _ := blocks[0].(map[string]interface{})
+			if values == nil {
+				values = map[string]interface{}{}
+			}
+			expanded, err := expandIngestProcessorOptions(ingestProcessorOptions[t], values)
+			if err != nil {
+				return nil, err
+			}
+			processorType, options = t, expanded
+			found++
+		}
+	}
+	if found != 1 {
+		return nil, fmt.Errorf("a processor must have exactly one of the %s blocks or json, got %d", strings.Join(ingestProcessorTypes(), ", "), found)
+	}
+
+	common, err := expandIngestProcessorOptions(ingestProcessorCommonOptions, block)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range common {
+		options[k] = v
+	}
+	return map[string]interface{}{processorType: options}, nil
+}
+
+func expandIngestProcessorOptions(schemas map[string]*schema.Schema, values map[string]interface{}) (map[string]interface{}, error) {
+	options := map[string]interface{}{}
+	for name, s := range schemas {
+		switch v := values[name].(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+			if ingestProcessorJsonOptions[name] {
+				var decoded interface{}
+				if err := json.Unmarshal([]byte(v), &decoded); err != nil {
+					return nil, fmt.Errorf("%s must be JSON: %+v", name, err)
+				}
+				options[name] = decoded
+			} else {
+				options[name] = v
+			}
+		case bool:
+			if v != s.Default {
+				options[name] = v
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				options[name] = v
+			}
+		case map[string]interface{}:
+			if len(v) > 0 {
+				options[name] = v
+			}
+		}
+	}
+	return options, nil
+}
+
+// flattenIngestProcessor returns the processor block of a processor, with a
+// typed block unless asJson or some of its options don't fit one.
+func flattenIngestProcessor(processor map[string]interface{}, asJson bool) map[string]interface{} {
+	block := map[string]interface{}{}
+	if !asJson && len(processor) == 1 {
+		for t, o := range processor {
+			options, ok := o.(map[string]interface{})
+			typeOptions, typed := ingestProcessorOptions[t]
+			if !ok || !typed {
+				break
+			}
+			values := ingestProcessorDefaults(typeOptions)
+			for k, v := range ingestProcessorDefaults(ingestProcessorCommonOptions) {
+				block[k] = v
+			}
+			for k, v := range options {
+				if s, ok := typeOptions[k]; ok && flattenIngestProcessorOption(s, v, values, k) {
+					continue
+				}
+				if s, ok := ingestProcessorCommonOptions[k]; ok && flattenIngestProcessorOption(s, v, block, k) {
+					continue
+				}
+				typed = false
+			}
+			if typed {
+				block[t] = []interface{}{values}
+				return block
+			}
+		}
+	}
+
+	raw, _ := json.Marshal(processor)
+	return map[string]interface{}{
+		"json": string(raw),
+	}
+}
+
+// flattenIngestProcessorOption sets the option name of values to v, and
+// returns whether v is of the type of the option.
+func flattenIngestProcessorOption(s *schema.Schema, v interface{}, values map[string]interface{}, name string) bool {
+	switch s.Type {
+	case schema.TypeString:
+		if ingestProcessorJsonOptions[name] {
+			raw, err := json.Marshal(v)
+			values[name] = string(raw)
+			return err == nil
+		}
+		str, ok := v.(string)
+		values[name] = str
+		return ok
+	case schema.TypeBool:
+		b, ok := v.(bool)
+		values[name] = b
+		return ok
+	case schema.TypeList:
+		list, ok := v.([]interface{})
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		values[name] = list
+		return ok
+	case schema.TypeMap:
+		m, ok := v.(map[string]interface{})
+		for _, item := range m {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		values[name] = m
+		return ok
+	}
+	return false
+}
+
+// ingestProcessorDefaults returns the values of options left out of a
+// processor.
+func ingestProcessorDefaults(schemas map[string]*schema.Schema) map[string]interface{} {
+	values := map[string]interface{}{}
+	for name, s := range schemas {
+		switch s.Type {
+		case schema.TypeString:
+			values[name] = ""
+		case schema.TypeBool:
+			values[name] = s.Default
+		case schema.TypeList:
+			values[name] = []interface{}{}
+		case schema.TypeMap:
+			values[name] = map[string]interface{}{}
+		}
+	}
+	return values
+}
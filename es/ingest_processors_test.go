@@ -0,0 +1,68 @@
+package es
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIngestProcessorRoundTrip(t *testing.T) {
+	processors := []string{
+		`{"set": {"field": "env", "value": "prod", "override": false}}`,
+		`{"rename": {"field": "host", "target_field": "host.name", "ignore_missing": true, "if": "ctx.host instanceof String"}}`,
+		`{"grok": {"field": "message", "patterns": ["%{IP:client.ip} %{WORD:verb}"], "pattern_definitions": {"VERB": "GET|POST"}}}`,
+		`{"date": {"field": "ts", "formats": ["ISO8601"], "timezone": "Europe/Paris", "tag": "parse-ts"}}`,
+		`{"geoip": {"field": "client.ip", "properties": ["country_iso_code"], "first_only": false}}`,
+		`{"pipeline": {"name": "common", "ignore_failure": true, "on_failure": [{"set": {"field": "error", "value": "{{ _ingest.on_failure_message }}"}}]}}`,
+		`{"script": {"source": "ctx.n = params.n", "params": {"n": 1}}}`,
+	}
+	for _, raw := range processors {
+		var processor map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &processor); err != nil {
+			t.Fatal(err)
+		}
+		block := flattenIngestProcessor(processor, false)
+		if block["json"] != nil {
+			t.Errorf("expected %s to be flattened into a typed block, got %v", raw, block)
+			continue
+		}
+		block["json"] = ""
+		expanded, err := expandIngestProcessor(block)
+		if err != nil {
+			t.Errorf("error expanding %s: %+v", raw, err)
+			continue
+		}
+		var expected interface{}
+		_ = json.Unmarshal([]byte(raw), &expected)
+		b, _ := json.Marshal(expanded)
+		var actual interface{}
+		_ = json.Unmarshal(b, &actual)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %s to round trip, got %s", raw, b)
+		}
+	}
+}
+
+func TestFlattenIngestProcessorJson(t *testing.T) {
+	for _, raw := range []string{
+		`{"lowercase": {"field": "host.name"}}`,
+		`{"set": {"field": "count", "value": 1}}`,
+		`{"set": {"field": "env", "value": "prod", "unknown_option": true}}`,
+	} {
+		var processor map[string]interface{}
+		_ = json.Unmarshal([]byte(raw), &processor)
+		block := flattenIngestProcessor(processor, false)
+		if !suppressEquivalentJson("", raw, block["json"].(string), nil) {
+			t.Errorf("expected %s to be flattened as JSON, got %v", raw, block)
+		}
+	}
+}
+
+func TestExpandIngestProcessorExactlyOne(t *testing.T) {
+	block := flattenIngestProcessor(map[string]interface{}{"set": map[string]interface{}{"field": "a", "value": "b"}}, false)
+	block["json"] = `{"lowercase": {"field": "a"}}`
+	if _, err := expandIngestProcessor(block); err == nil || !strings.Contains(err.Error(), "exactly one") {
+		t.Errorf("expected a processor with two types to fail, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package es
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	krbcredentials "github.com/jcmturner/gokrb5/v8/credentials"
+	krbkeytab "github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+)
+
+type withKerberos struct {
+	client *krbclient.Client
+	spn    string
+	rt     http.RoundTripper
+}
+
+// WithKerberos wraps rt so that every request is authenticated with a SPNEGO
+// token for spn, or for HTTP/<host> of the request if spn is empty.
+func WithKerberos(rt http.RoundTripper, client *krbclient.Client, spn string) withKerberos {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return withKerberos{client: client, spn: spn, rt: rt}
+}
+
+func (k withKerberos) RoundTrip(req *http.Request) (*http.Response, error) {
+	// SetSPNEGOHeader logs in to the KDC on first use, and again once the
+	// ticket has expired
+	if err := spnego.SetSPNEGOHeader(k.client, req, k.spn); err != nil {
+		return nil, fmt.Errorf("error negotiating kerberos authentication: %+v", err)
+	}
+
+	return k.rt.RoundTrip(req)
+}
+
+// kerberosTransport adds kerberos authentication to rt if it is configured.
+func kerberosTransport(conf *ProviderConf, rt http.RoundTripper) http.RoundTripper {
+	if conf.kerberos == nil {
+		return rt
+	}
+	return WithKerberos(rt, conf.kerberos, conf.kerberosSpn)
+}
+
+// kerberosClient creates the kerberos client from either a keytab or a
+// credential cache. Nothing is sent to the KDC until the first request.
+func kerberosClient(conf *ProviderConf) (*krbclient.Client, error) {
+	krb5conf, err := krbconfig.Load(conf.kerberosConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kerberos configuration %s: %+v", conf.kerberosConfig, err)
+	}
+
+	if conf.kerberosKeytab != "" {
+		if conf.kerberosPrincipal == "" {
+			return nil, errors.New("kerberos_principal must be set when using kerberos_keytab")
+		}
+		kt, err := krbkeytab.Load(conf.kerberosKeytab)
+		if err != nil {
+			return nil, fmt.Errorf("error loading kerberos keytab %s: %+v", conf.kerberosKeytab, err)
+		}
+		realm := conf.kerberosRealm
+		if realm == "" {
+			realm = krb5conf.LibDefaults.DefaultRealm
+		}
+		return krbclient.NewWithKeytab(conf.kerberosPrincipal, realm, kt, krb5conf, krbclient.DisablePAFXFAST(true)), nil
+	}
+
+	ccache, err := krbcredentials.LoadCCache(strings.TrimPrefix(conf.kerberosCCache, "FILE:"))
+	if err != nil {
+		return nil, fmt.Errorf("error loading kerberos credential cache %s: %+v", conf.kerberosCCache, err)
+	}
+	return krbclient.NewFromCCache(ccache, krb5conf, krbclient.DisablePAFXFAST(true))
+}
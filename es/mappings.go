@@ -0,0 +1,229 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+// updatableMappingParameters are the parameters of an existing field that the
+// put mapping API can change.
+var updatableMappingParameters = map[string]bool{
+	"ignore_above":          true,
+	"search_analyzer":       true,
+	"search_quote_analyzer": true,
+	"dynamic":               true,
+	"meta":                  true,
+}
+
+// rootMappingParameters are the parameters of typeless mappings. Any other
+// single key of mappings is the name of their type, as used by Elasticsearch 6
+// and older.
+var rootMappingParameters = map[string]bool{
+	"_all":                   true,
+	"_field_names":           true,
+	"_meta":                  true,
+	"_routing":               true,
+	"_source":                true,
+	"_data_stream_timestamp": true,
+	"date_detection":         true,
+	"dynamic":                true,
+	"dynamic_date_formats":   true,
+	"dynamic_templates":      true,
+	"enabled":                true,
+	"numeric_detection":      true,
+	"properties":             true,
+	"runtime":                true,
+	"subobjects":             true,
+}
+
+// normalizeIndexMappings removes the type mappings are wrapped in, so that the
+// mappings of Elasticsearch 6 compare equal to typeless ones, and the object
+// type of fields with properties, which the API leaves out of the mappings it
+// returns.
+func normalizeIndexMappings(mappings map[string]interface{}) {
+	if mappingType := mappingsType(mappings); mappingType != "" {
+		typed := mappings[mappingType].(map[string]interface{})
+		delete(mappings, mappingType)
+		for k, v := range typed {
+			mappings[k] = v
+		}
+	}
+	properties, _ := mappingProperties(mappings)
+	normalizeMappingProperties(properties)
+}
+
+func normalizeMappingProperties(properties map[string]interface{}) {
+	for _, f := range properties {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if sub, ok := field["properties"].(map[string]interface{}); ok {
+			if field["type"] == "object" {
+				delete(field, "type")
+			}
+			normalizeMappingProperties(sub)
+		}
+	}
+}
+
+// mappingProperties returns the properties of typeless mappings, or of the
+// only type of the mappings of Elasticsearch 6 and older, and that type.
+func mappingProperties(mappings map[string]interface{}) (map[string]interface{}, string) {
+	if properties, ok := mappings["properties"].(map[string]interface{}); ok {
+		return properties, ""
+	}
+	if len(mappings) == 1 {
+		for name, m := range mappings {
+			if typed, ok := m.(map[string]interface{}); ok {
+				if properties, ok := typed["properties"].(map[string]interface{}); ok {
+					return properties, name
+				}
+			}
+		}
+	}
+	return map[string]interface{}{}, ""
+}
+
+// mappingsType returns the name of the only type mappings are wrapped in, or ""
+// if they're typeless.
+func mappingsType(mappings map[string]interface{}) string {
+	if len(mappings) != 1 {
+		return ""
+	}
+	for name, m := range mappings {
+		if _, ok := m.(map[string]interface{}); ok && !rootMappingParameters[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// mappingsForClient converts mappings to the form the major version of the
+// cluster requires: typeless for Elasticsearch 7 and later, and OpenSearch,
+// which reject types, and wrapped in the _doc type for Elasticsearch 6, so
+// that the same mappings can be used with both. The mappings of
+// Elasticsearch 5, which doesn't allow the _doc type, are left as they are.
+func mappingsForClient(esClient interface{}, mappings map[string]interface{}) map[string]interface{} {
+	mappingType := mappingsType(mappings)
+	switch esClient.(type) {
+	case *elastic7.Client:
+		if mappingType != "" {
+			return mappings[mappingType].(map[string]interface{})
+		}
+	case *elastic6.Client:
+		if mappingType == "" {
+			return map[string]interface{}{"_doc": mappings}
+		}
+	}
+	return mappings
+}
+
+// breakingMappingChanges describes the changes from the current to the
+// desired properties that the put mapping API rejects: changing the type of
+// a field, or a parameter that can only be set when the field is created.
+// Adding fields, or parameters the current mapping leaves to their default,
+// is left to the API.
+func breakingMappingChanges(current, desired map[string]interface{}, prefix string) []string {
+	changes := []string{}
+	for name, d := range desired {
+		desiredField, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		currentField, ok := current[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := prefix + name
+
+		currentType, desiredType := mappingFieldType(currentField), mappingFieldType(desiredField)
+		if currentType != desiredType {
+			changes = append(changes, fmt.Sprintf("the type of %s changes from %s to %s", path, currentType, desiredType))
+			continue
+		}
+		for param, v := range desiredField {
+			if param == "type" || param == "properties" || param == "fields" || updatableMappingParameters[param] {
+				continue
+			}
+			if cv, ok := currentField[param]; ok && scalarString(cv) != scalarString(v) && !reflect.DeepEqual(cv, v) {
+				changes = append(changes, fmt.Sprintf("%s of %s changes from %v to %v", param, path, cv, v))
+			}
+		}
+		for _, sub := range []string{"properties", "fields"} {
+			desiredSub, ok := desiredField[sub].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			currentSub, _ := currentField[sub].(map[string]interface{})
+			changes = append(changes, breakingMappingChanges(currentSub, desiredSub, path+".")...)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// mappingFieldType returns the type of a field, object if left out.
+func mappingFieldType(field map[string]interface{}) string {
+	if t, ok := field["type"].(string); ok {
+		return t
+	}
+	return "object"
+}
+
+// indexMappings returns the mappings of index as stored, or nil if it doesn't
+// exist.
+func indexMappings(ctx context.Context, esClient interface{}, index string) (map[string]interface{}, error) {
+	path, err := uritemplates.Expand("/{index}/_mapping", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building URL path for mappings: %+v", err)
+	}
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var indices map[string]struct {
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	if err := json.Unmarshal(res, &indices); err != nil {
+		return nil, fmt.Errorf("error unmarshalling mappings of index %s: %+v: %s", index, err, res)
+	}
+	// index may be an alias, named differently in the response
+	for _, i := range indices {
+		return i.Mappings, nil
+	}
+	return nil, nil
+}
+
+// putIndexMappings adds the fields and parameters of mappings to index,
+// typeless or, for the mappings of Elasticsearch 6 and older, to their type.
+func putIndexMappings(ctx context.Context, esClient interface{}, index string, mappings map[string]interface{}) error {
+	mappings = mappingsForClient(esClient, mappings)
+	template, values := "/{index}/_mapping", map[string]string{"index": index}
+	var body interface{} = mappings
+	if _, mappingType := mappingProperties(mappings); mappingType != "" {
+		template, values["type"] = "/{index}/_mapping/{type}", mappingType
+		body = mappings[mappingType]
+	}
+	path, err := uritemplates.Expand(template, values)
+	if err != nil {
+		return fmt.Errorf("error building URL path for mappings: %+v", err)
+	}
+
+	_, err = performRequest(ctx, esClient, "PUT", path, nil, body)
+	return err
+}
@@ -0,0 +1,113 @@
+package es
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func TestBreakingMappingChanges(t *testing.T) {
+	current := `{
+  "properties": {
+    "sku": {"type": "keyword", "ignore_above": 256},
+    "name": {"type": "text", "analyzer": "standard", "fields": {"raw": {"type": "keyword"}}},
+    "price": {"type": "float"},
+    "vendor": {"properties": {"id": {"type": "long"}}}
+  }
+}`
+	cases := []struct {
+		desired  string
+		expected []string
+	}{
+		{
+			// additive and updatable changes
+			`{"properties": {"sku": {"type": "keyword", "ignore_above": 1024}, "name": {"type": "text", "fields": {"raw": {"type": "keyword"}, "en": {"type": "text"}}}, "stock": {"type": "integer"}, "vendor": {"type": "object", "properties": {"id": {"type": "long"}, "name": {"type": "keyword"}}}}}`,
+			[]string{},
+		},
+		{
+			`{"properties": {"price": {"type": "scaled_float", "scaling_factor": 100}, "name": {"type": "text", "analyzer": "english"}}}`,
+			[]string{"analyzer of name changes from standard to english", "the type of price changes from float to scaled_float"},
+		},
+		{
+			`{"properties": {"vendor": {"type": "nested"}, "name": {"type": "text", "fields": {"raw": {"type": "wildcard"}}}}}`,
+			[]string{"the type of name.raw changes from keyword to wildcard", "the type of vendor changes from object to nested"},
+		},
+		{
+			// the mappings of Elasticsearch 6
+			`{"_doc": {"properties": {"price": {"type": "double"}}}}`,
+			[]string{"the type of price changes from float to double"},
+		},
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(current), &m); err != nil {
+		t.Fatal(err)
+	}
+	currentProperties, _ := mappingProperties(m)
+	for _, c := range cases {
+		var desired map[string]interface{}
+		if err := json.Unmarshal([]byte(c.desired), &desired); err != nil {
+			t.Fatal(err)
+		}
+		desiredProperties, _ := mappingProperties(desired)
+		if actual := breakingMappingChanges(currentProperties, desiredProperties, ""); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("expected %v for %s, got %v", c.expected, c.desired, actual)
+		}
+	}
+}
+
+func TestDiffSuppressIndexMappings(t *testing.T) {
+	if !diffSuppressIndexMappings("mappings", `{"properties": {"vendor": {"properties": {"id": {"type": "long"}}}}}`, `{"properties": {"vendor": {"type": "object", "properties": {"id": {"type": "long"}}}}}`, nil) {
+		t.Errorf("expected the object type to be ignored")
+	}
+	if diffSuppressIndexMappings("mappings", `{"properties": {"vendor": {"properties": {"id": {"type": "long"}}}}}`, `{"properties": {"vendor": {"type": "nested", "properties": {"id": {"type": "long"}}}}}`, nil) {
+		t.Errorf("expected the nested type to be a change")
+	}
+}
+
+func TestDiffSuppressIndexMappingsType(t *testing.T) {
+	typeless := `{"dynamic": "strict", "properties": {"id": {"type": "keyword"}}}`
+	if !diffSuppressIndexMappings("mappings", `{"_doc": `+typeless+`}`, typeless, nil) {
+		t.Errorf("expected the _doc type of Elasticsearch 6 to be ignored")
+	}
+	if !diffSuppressIndexTemplate("body", `{"index_patterns": ["a-*"], "mappings": {"_doc": `+typeless+`}}`, `{"index_patterns": ["a-*"], "mappings": `+typeless+`}`, nil) {
+		t.Errorf("expected the _doc type of a template to be ignored")
+	}
+	if diffSuppressIndexMappings("mappings", `{"_doc": {"dynamic": "strict"}}`, `{"dynamic": "strict", "_source": {"enabled": false}}`, nil) {
+		t.Errorf("expected the source of typeless mappings to be a change")
+	}
+}
+
+func TestMappingsForClient(t *testing.T) {
+	typeless := map[string]interface{}{"properties": map[string]interface{}{"id": map[string]interface{}{"type": "keyword"}}}
+	typed := map[string]interface{}{"_doc": typeless}
+	cases := []struct {
+		client   interface{}
+		mappings map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{&elastic7.Client{}, typed, typeless},
+		{&elastic7.Client{}, typeless, typeless},
+		{&elastic6.Client{}, typeless, typed},
+		{&elastic6.Client{}, typed, typed},
+		{&elastic5.Client{}, typeless, typeless},
+		{&elastic7.Client{}, map[string]interface{}{"_source": map[string]interface{}{"enabled": false}}, map[string]interface{}{"_source": map[string]interface{}{"enabled": false}}},
+	}
+	for _, c := range cases {
+		if actual := mappingsForClient(c.client, c.mappings); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("expected %v for %T, got %v", c.expected, c.client, actual)
+		}
+	}
+
+	body, err := indexTemplateBodyForClient(&elastic7.Client{}, `{"index_patterns": ["a-*"], "mappings": {"_doc": {"dynamic": false}}}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if body != `{"index_patterns":["a-*"],"mappings":{"dynamic":false}}` {
+		t.Errorf("expected typeless mappings in the template, got %s", body)
+	}
+}
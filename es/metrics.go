@@ -0,0 +1,337 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// apiMetrics counts the requests sent by the clients of a provider instance,
+// i.e. of a single plan or apply, by endpoint category, to find out which
+// APIs a slow apply waits for.
+type apiMetrics struct {
+	// path is the file the summary is written to after each operation, if
+	// any.
+	path string
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointMetrics
+	// writeMu serializes the reports, outside of mu
+	writeMu sync.Mutex
+}
+
+type endpointMetrics struct {
+	requests    int
+	errors      int
+	retries     int
+	rateLimited int
+	latencies   []time.Duration
+	// the requests waiting before being sent, and for how long in total
+	slotWaits      int
+	slotWait       time.Duration
+	rateLimitWaits int
+	rateLimitWait  time.Duration
+}
+
+// apiMetricsSummary is the summary of the requests, as written to the
+// metrics_file of the provider.
+type apiMetricsSummary struct {
+	Requests        int                        `json:"requests"`
+	Errors          int                        `json:"errors"`
+	Retries         int                        `json:"retries"`
+	RateLimited     int                        `json:"rate_limited"`
+	SlotWaits       int                        `json:"slot_waits"`
+	SlotWaitMs      float64                    `json:"slot_wait_ms"`
+	RateLimitWaits  int                        `json:"rate_limit_waits"`
+	RateLimitWaitMs float64                    `json:"rate_limit_wait_ms"`
+	Endpoints       map[string]endpointSummary `json:"endpoints"`
+}
+
+type endpointSummary struct {
+	Requests        int     `json:"requests"`
+	Errors          int     `json:"errors"`
+	Retries         int     `json:"retries"`
+	RateLimited     int     `json:"rate_limited"`
+	TotalMs         float64 `json:"total_ms"`
+	P50Ms           float64 `json:"p50_ms"`
+	P90Ms           float64 `json:"p90_ms"`
+	P99Ms           float64 `json:"p99_ms"`
+	MaxMs           float64 `json:"max_ms"`
+	SlotWaits       int     `json:"slot_waits"`
+	SlotWaitMs      float64 `json:"slot_wait_ms"`
+	RateLimitWaits  int     `json:"rate_limit_waits"`
+	RateLimitWaitMs float64 `json:"rate_limit_wait_ms"`
+}
+
+func newApiMetrics(path string) *apiMetrics {
+	return &apiMetrics{path: path, endpoints: map[string]*endpointMetrics{}}
+}
+
+// endpointCategory returns the method and the API of a request, e.g.
+// PUT _index_template, GET _plugins/_ism or, for the APIs of the indices
+// themselves, GET index. Kibana APIs are categorized by their first segment
+// after api, whatever the space.
+func endpointCategory(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(segments) > 2 && segments[0] == "s" {
+		segments = segments[2:]
+	}
+
+	api := "index"
+	switch {
+	case segments[0] == "":
+		api = "/"
+	case segments[0] == "api" && len(segments) > 1:
+		api = "api/" + segments[1]
+	default:
+		for i, segment := range segments {
+			if !strings.HasPrefix(segment, "_") {
+				continue
+			}
+			api = segment
+			// the plugins and X-Pack of Elasticsearch 6 nest their APIs
+			if (segment == "_plugins" || segment == "_opendistro" || segment == "_xpack") && i+1 < len(segments) {
+				api += "/" + segments[i+1]
+			}
+			break
+		}
+	}
+	return req.Method + " " + api
+}
+
+func (m *apiMetrics) endpoint(req *http.Request) *endpointMetrics {
+	category := endpointCategory(req)
+	e, ok := m.endpoints[category]
+	if !ok {
+		e = &endpointMetrics{}
+		m.endpoints[category] = e
+	}
+	return e
+}
+
+// record counts a request answered with res after latency, or failing with
+// err. A nil metrics doesn't count anything.
+func (m *apiMetrics) record(req *http.Request, latency time.Duration, res *http.Response, err error) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.endpoint(req)
+	e.requests++
+	e.latencies = append(e.latencies, latency)
+	if err != nil || res.StatusCode >= 500 {
+		e.errors++
+	}
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		e.rateLimited++
+	}
+}
+
+// retried counts a request sent again after a retryable response.
+func (m *apiMetrics) retried(req *http.Request) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpoint(req).retries++
+}
+
+// waitedForSlot counts a request that waited for one of the
+// max_concurrent_requests slots before being sent.
+func (m *apiMetrics) waitedForSlot(req *http.Request, wait time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.endpoint(req)
+	e.slotWaits++
+	e.slotWait += wait
+}
+
+// waitedForRateLimit counts a request that waited for the read or write rate
+// limit before being sent.
+func (m *apiMetrics) waitedForRateLimit(req *http.Request, wait time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.endpoint(req)
+	e.rateLimitWaits++
+	e.rateLimitWait += wait
+}
+
+func (m *apiMetrics) summary() apiMetricsSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summary := apiMetricsSummary{Endpoints: map[string]endpointSummary{}}
+	var slotWait, rateLimitWait time.Duration
+	for category, e := range m.endpoints {
+		latencies := append([]time.Duration{}, e.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+
+		summary.Endpoints[category] = endpointSummary{
+			Requests:        e.requests,
+			Errors:          e.errors,
+			Retries:         e.retries,
+			RateLimited:     e.rateLimited,
+			TotalMs:         milliseconds(total),
+			P50Ms:           milliseconds(percentile(latencies, 50)),
+			P90Ms:           milliseconds(percentile(latencies, 90)),
+			P99Ms:           milliseconds(percentile(latencies, 99)),
+			MaxMs:           milliseconds(percentile(latencies, 100)),
+			SlotWaits:       e.slotWaits,
+			SlotWaitMs:      milliseconds(e.slotWait),
+			RateLimitWaits:  e.rateLimitWaits,
+			RateLimitWaitMs: milliseconds(e.rateLimitWait),
+		}
+		summary.Requests += e.requests
+		summary.Errors += e.errors
+		summary.Retries += e.retries
+		summary.RateLimited += e.rateLimited
+		summary.SlotWaits += e.slotWaits
+		summary.RateLimitWaits += e.rateLimitWaits
+		slotWait += e.slotWait
+		rateLimitWait += e.rateLimitWait
+	}
+	summary.SlotWaitMs = milliseconds(slotWait)
+	summary.RateLimitWaitMs = milliseconds(rateLimitWait)
+	return summary
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies, 0 if
+// there are none.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Round(time.Microsecond)) / float64(time.Millisecond)
+}
+
+// report logs the summary of the requests sent so far, the endpoints taking
+// the most time first, and writes it to the metrics file, if any. Reported
+// after each operation, the last summary covers the whole plan or apply.
+func (m *apiMetrics) report() {
+	if m == nil {
+		return
+	}
+
+	// the summaries of concurrent operations are written in order
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	summary := m.summary()
+	categories := make([]string, 0, len(summary.Endpoints))
+	for category := range summary.Endpoints {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := summary.Endpoints[categories[i]], summary.Endpoints[categories[j]]
+		if a.TotalMs != b.TotalMs {
+			return a.TotalMs > b.TotalMs
+		}
+		return categories[i] < categories[j]
+	})
+	lines := []string{fmt.Sprintf("%d requests, %d errors, %d retries, %d rate limited, %d waited %.1fms for a slot, %d waited %.1fms for the rate limit", summary.Requests, summary.Errors, summary.Retries, summary.RateLimited, summary.SlotWaits, summary.SlotWaitMs, summary.RateLimitWaits, summary.RateLimitWaitMs)}
+	for _, category := range categories {
+		e := summary.Endpoints[category]
+		lines = append(lines, fmt.Sprintf("%s: %d requests, %d errors, %d retries, %d rate limited, p50 %.1fms, p90 %.1fms, p99 %.1fms, max %.1fms, %d waited %.1fms for a slot, %d waited %.1fms for the rate limit", category, e.Requests, e.Errors, e.Retries, e.RateLimited, e.P50Ms, e.P90Ms, e.P99Ms, e.MaxMs, e.SlotWaits, e.SlotWaitMs, e.RateLimitWaits, e.RateLimitWaitMs))
+	}
+	log.Printf("[DEBUG] API calls: %s", strings.Join(lines, "; "))
+
+	if m.path == "" {
+		return
+	}
+	if err := writeMetricsFile(m.path, summary); err != nil {
+		log.Printf("[WARN] Error writing the API call metrics to %s: %+v", m.path, err)
+	}
+}
+
+// writeMetricsFile replaces the file at path with summary, so that it's never
+// read half written.
+func writeMetricsFile(path string, summary apiMetricsSummary) error {
+	b, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+type withMetrics struct {
+	rt      http.RoundTripper
+	metrics *apiMetrics
+}
+
+// WithMetrics wraps rt so that each request it sends, including each retry,
+// is counted in metrics with its latency until the response headers. The
+// time spent waiting for a slot or the rate limits before is counted by
+// WithConcurrencyLimit and WithRateLimit. A nil metrics doesn't count
+// anything.
+func WithMetrics(rt http.RoundTripper, metrics *apiMetrics) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if metrics == nil {
+		return rt
+	}
+
+	return withMetrics{rt: rt, metrics: metrics}
+}
+
+func (m withMetrics) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := m.rt.RoundTrip(req)
+	m.metrics.record(req, time.Since(start), res, err)
+	return res, err
+}
+
+// resourceWithMetrics reports the API call metrics of the provider after
+// each operation of r, a resource or data source.
+func resourceWithMetrics(r *schema.Resource) {
+	wrap := func(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			defer meta.(*ProviderConf).metrics.report()
+			return f(d, meta)
+		}
+	}
+	r.Create = wrap(r.Create)
+	r.Read = wrap(r.Read)
+	r.Update = wrap(r.Update)
+	r.Delete = wrap(r.Delete)
+}
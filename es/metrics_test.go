@@ -0,0 +1,165 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestEndpointCategory(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/":                                   "GET /",
+		"/logs-000001":                        "GET index",
+		"/logs-*/_settings":                   "GET _settings",
+		"/_index_template/logs":               "GET _index_template",
+		"/_plugins/_ism/policies/hot-warm":    "GET _plugins/_ism",
+		"/_xpack/security/user/jdoe":          "GET _xpack/security",
+		"/api/saved_objects/_import":          "GET api/saved_objects",
+		"/s/ops/api/saved_objects/dashboards": "GET api/saved_objects",
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		if actual := endpointCategory(req); actual != expected {
+			t.Errorf("%s: expected %q, got %q", path, expected, actual)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 200; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	for p, expected := range map[int]time.Duration{50: 100 * time.Millisecond, 99: 198 * time.Millisecond, 100: 200 * time.Millisecond} {
+		if actual := percentile(latencies, p); actual != expected {
+			t.Errorf("p%d: expected %s, got %s", p, expected, actual)
+		}
+	}
+	if percentile(nil, 50) != 0 {
+		t.Error("expected no latency without requests")
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	throttled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_bulk" && !throttled {
+			throttled = true
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	raw := map[string]interface{}{
+		"url":               server.URL,
+		"max_retries":       1,
+		"retry_backoff_min": "1ms",
+		"metrics_file":      path,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	client := &http.Client{Transport: clientTransport(conf, nil)}
+	for _, path := range []string{"/_bulk", "/_bulk", "/logs/_refresh"} {
+		res, err := client.Post(server.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Read:   func(d *schema.ResourceData, meta interface{}) error { return nil },
+	}
+	resourceWithMetrics(r)
+	if err := r.Read(r.TestResourceData(), conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var summary apiMetricsSummary
+	if err := json.Unmarshal(b, &summary); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if summary.Requests != 4 || summary.Retries != 1 || summary.RateLimited != 1 {
+		t.Errorf("expected 4 requests, 1 retried after being rate limited, got %s", b)
+	}
+	if bulk := summary.Endpoints["POST _bulk"]; bulk.Requests != 3 || bulk.MaxMs <= 0 {
+		t.Errorf("expected 3 bulk requests, got %s", b)
+	}
+	if refresh := summary.Endpoints["POST _refresh"]; refresh.Requests != 1 {
+		t.Errorf("expected 1 refresh request, got %s", b)
+	}
+}
+
+func TestWithMetricsWaits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	raw := map[string]interface{}{
+		"url":                       server.URL,
+		"max_concurrent_requests":   1,
+		"write_requests_per_second": 10.0,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	client := &http.Client{Transport: clientTransport(conf, nil)}
+
+	// the second read waits for the slot of the first one
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get(server.URL + "/logs/_search")
+			if err != nil {
+				t.Errorf("err: %s", err)
+				return
+			}
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+	// the writes after the burst of 10 wait for the rate limit
+	for i := 0; i < 12; i++ {
+		res, err := client.Post(server.URL+"/_bulk", "application/json", nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	summary := conf.metrics.summary()
+	if search := summary.Endpoints["GET _search"]; search.SlotWaits != 1 || search.SlotWaitMs <= 0 {
+		t.Errorf("expected a search to wait for a slot, got %+v", search)
+	}
+	if bulk := summary.Endpoints["POST _bulk"]; bulk.RateLimitWaits != 2 || bulk.RateLimitWaitMs < 100 {
+		t.Errorf("expected 2 bulk requests to wait for the rate limit, got %+v", bulk)
+	}
+	if summary.SlotWaits != 1 || summary.RateLimitWaits != 2 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
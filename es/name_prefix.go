@@ -0,0 +1,91 @@
+package es
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceNameAttributes are the attributes naming the objects each resource
+// manages or changes, which must start with the name_prefix of the provider.
+var resourceNameAttributes = map[string][]string{
+	"elasticsearch_blue_green_index":                {"alias", "index_prefix"},
+	"elasticsearch_bulk_documents":                  {"index"},
+	"elasticsearch_ccr_follower":                    {"index"},
+	"elasticsearch_component_template":              {"name"},
+	"elasticsearch_composable_index_template":       {"name"},
+	"elasticsearch_data_stream_failure_store":       {"data_stream"},
+	"elasticsearch_delete_by_query":                 {"index"},
+	"elasticsearch_document":                        {"index"},
+	"elasticsearch_force_merge":                     {"index"},
+	"elasticsearch_index":                           {"name"},
+	"elasticsearch_index_lifecycle_policy":          {"name"},
+	"elasticsearch_index_rollover":                  {"target", "new_index"},
+	"elasticsearch_index_state":                     {"index"},
+	"elasticsearch_index_template":                  {"name"},
+	"elasticsearch_ingest_geoip_database":           {"database_id"},
+	"elasticsearch_ingest_pipeline":                 {"name"},
+	"elasticsearch_logstash_pipeline":               {"pipeline_id"},
+	"elasticsearch_snapshot":                        {"name"},
+	"elasticsearch_snapshot_repository":             {"name"},
+	"elasticsearch_update_by_query":                 {"index"},
+	"elasticsearch_watch":                           {"watch_id"},
+	"elasticsearch_opendistro_ism_policy":           {"policy_id"},
+	"elasticsearch_opendistro_ism_policy_mapping":   {"indexes"},
+	"elasticsearch_opendistro_kibana_tenant":        {"tenant_name"},
+	"elasticsearch_opendistro_role":                 {"role_name"},
+	"elasticsearch_opendistro_roles_mapping":        {"role_name"},
+	"elasticsearch_opendistro_user":                 {"username"},
+	"elasticsearch_xpack_index_lifecycle_policy":    {"name"},
+	"elasticsearch_xpack_role":                      {"role_name"},
+	"elasticsearch_xpack_role_mapping":              {"role_mapping_name"},
+	"elasticsearch_xpack_snapshot_lifecycle_policy": {"name"},
+	"elasticsearch_xpack_user":                      {"username"},
+	"elasticsearch_xpack_watch":                     {"watch_id"},
+}
+
+// resourceWithNamePrefix fails the plan of r when one of the names in
+// attributes doesn't start with the name_prefix of the provider, so that
+// workspaces sharing a cluster can't change each other's objects.
+func resourceWithNamePrefix(r *schema.Resource, attributes []string) *schema.Resource {
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+		if prefix := meta.(*ProviderConf).namePrefix; prefix != "" {
+			for _, attribute := range attributes {
+				if !d.NewValueKnown(attribute) {
+					continue
+				}
+				value, _ := d.Get(attribute).(string)
+				if names := namesWithoutPrefix(value, prefix); len(names) > 0 {
+					return fmt.Errorf("%s %s doesn't start with %q, the name_prefix of the provider", attribute, strings.Join(names, ", "), prefix)
+				}
+			}
+		}
+
+		if customizeDiff != nil {
+			return customizeDiff(d, meta)
+		}
+		return nil
+	}
+
+	return r
+}
+
+// namesWithoutPrefix returns the names of a comma separated list that don't
+// start with prefix, ignoring the brackets of date math and the dash of
+// exclusions, e.g. <logs-{now/d}> or -logs-archive.
+func namesWithoutPrefix(value, prefix string) []string {
+	names := []string{}
+	if value == "" {
+		return names
+	}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		unwrapped := strings.TrimPrefix(strings.TrimPrefix(name, "-"), "<")
+		if !strings.HasPrefix(unwrapped, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,64 @@
+package es
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceNameAttributes(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	for name, attributes := range resourceNameAttributes {
+		r, ok := provider.ResourcesMap[name]
+		if !ok {
+			t.Errorf("%s isn't a resource of the provider", name)
+			continue
+		}
+		for _, attribute := range attributes {
+			if s, ok := r.Schema[attribute]; !ok || s.Type != schema.TypeString {
+				t.Errorf("%s doesn't have a %s string attribute", name, attribute)
+			}
+		}
+	}
+}
+
+func TestNamesWithoutPrefix(t *testing.T) {
+	for value, expected := range map[string][]string{
+		"":                            {},
+		"team-a-logs":                 {},
+		"<team-a-logs-{now/d}>":       {},
+		"team-a-logs,-team-a-archive": {},
+		"team-a-logs, team-b-logs":    {"team-b-logs"},
+		"logs-*":                      {"logs-*"},
+	} {
+		if actual := namesWithoutPrefix(value, "team-a-"); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%q: expected %v, got %v", value, expected, actual)
+		}
+	}
+}
+
+func TestResourceWithNamePrefix(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+	})
+	conf.namePrefix = "team-a-"
+	r := Provider().(*schema.Provider).ResourcesMap["elasticsearch_index"]
+
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "team-b-logs",
+	}), conf)
+	if err == nil || !strings.Contains(err.Error(), `name team-b-logs doesn't start with "team-a-", the name_prefix of the provider`) {
+		t.Errorf("expected a name prefix error, got %v", err)
+	}
+
+	_, err = r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "team-a-logs",
+	}), conf)
+	if err != nil {
+		t.Errorf("err: %s", err)
+	}
+}
@@ -0,0 +1,67 @@
+package es
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// The behaviors of creating a resource whose object already exists.
+const (
+	onExistingFail      = "fail"
+	onExistingAdopt     = "adopt"
+	onExistingOverwrite = "overwrite"
+)
+
+var onExistingBehaviors = []string{onExistingFail, onExistingAdopt, onExistingOverwrite}
+
+// resourceWithOnExisting adds on_existing to r, checking whether the object
+// named by the name attribute of lister already exists before creating it,
+// since the APIs of these objects silently overwrite existing objects.
+func resourceWithOnExisting(resourceType string, r *schema.Resource, lister objectLister) {
+	r.Schema["on_existing"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice(onExistingBehaviors, false),
+		Description:  "What to do when the object already exists at creation: `fail`, `adopt` the existing object like an import, changing it to the configuration on the next apply, or `overwrite` it. Defaults to the `on_existing` of the provider.",
+		// only used at creation
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			return d.Id() != ""
+		},
+		ForceNew: r.Update == nil,
+	}
+
+	create := r.Create
+	read := r.Read
+	r.Create = func(d *schema.ResourceData, meta interface{}) error {
+		behavior := d.Get("on_existing").(string)
+		if behavior == "" {
+			behavior = meta.(*ProviderConf).onExisting
+		}
+		if behavior == "" || behavior == onExistingOverwrite {
+			return create(d, meta)
+		}
+
+		name := d.Get(lister.nameAttribute).(string)
+		esClient, err := getClient(meta.(*ProviderConf))
+		if err != nil {
+			return err
+		}
+		objects, err := lister.list(providerContext(meta), esClient, resourceType)
+		if err != nil {
+			return fmt.Errorf("error checking whether %s %s already exists: %+v", lister.nameAttribute, name, err)
+		}
+		if _, ok := objects[name]; !ok {
+			return create(d, meta)
+		}
+
+		if behavior == onExistingFail {
+			return fmt.Errorf("%s %s already exists, import it, or set on_existing to adopt or overwrite it", lister.nameAttribute, name)
+		}
+		log.Printf("[INFO] Adopting the existing %s %s", resourceType, name)
+		d.SetId(name)
+		return read(d, meta)
+	}
+}
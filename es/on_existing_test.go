@@ -0,0 +1,127 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestObjectListersNameAttribute(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	for name, lister := range objectListers {
+		r, ok := provider.ResourcesMap[name]
+		if !ok {
+			t.Errorf("%s isn't a resource of the provider", name)
+			continue
+		}
+		if s, ok := r.Schema[lister.nameAttribute]; !ok || s.Type != schema.TypeString {
+			t.Errorf("%s doesn't have a %q string attribute", name, lister.nameAttribute)
+		}
+	}
+}
+
+func TestResourceWithOnExisting(t *testing.T) {
+	for behavior, expectedPut := range map[string]bool{
+		onExistingFail:      false,
+		onExistingAdopt:     false,
+		onExistingOverwrite: true,
+	} {
+		t.Run(behavior, func(t *testing.T) {
+			put := false
+			conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/":
+					_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+				case r.URL.Path == "/_ingest/pipeline/logs" && r.Method == "PUT":
+					put = true
+					_, _ = w.Write([]byte(`{"acknowledged": true}`))
+				case r.URL.Path == "/_ingest/pipeline" || r.URL.Path == "/_ingest/pipeline/logs":
+					_, _ = w.Write([]byte(`{"logs": {"description": "Existing", "processors": [{"set": {"field": "env", "value": "prod"}}]}}`))
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+				}
+			})
+			conf.onExisting = behavior
+
+			r := Provider().(*schema.Provider).ResourcesMap["elasticsearch_ingest_pipeline"]
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":        "logs",
+				"description": "Parses logs",
+				"processor": []interface{}{
+					map[string]interface{}{"json": `{"set": {"field": "env", "value": "dev"}}`},
+				},
+			})
+			diff, err := r.Diff(nil, config, conf)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			state, err := r.Apply(nil, diff, conf)
+
+			if put != expectedPut {
+				t.Errorf("expected the pipeline to be put: %t", expectedPut)
+			}
+			switch behavior {
+			case onExistingFail:
+				if err == nil || !strings.Contains(err.Error(), "name logs already exists") {
+					t.Errorf("expected an already exists error, got %v", err)
+				}
+			case onExistingAdopt:
+				if err != nil {
+					t.Fatalf("err: %s", err)
+				}
+				if state.ID != "logs" || state.Attributes["description"] != "Existing" {
+					t.Errorf("expected the existing pipeline to be adopted, got %v", state.Attributes)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("err: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceWithOnExistingCreatesMissing(t *testing.T) {
+	put := false
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_ingest/pipeline" && !put:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/_ingest/pipeline/logs" && r.Method == "PUT":
+			put = true
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_ingest/pipeline/logs":
+			_, _ = w.Write([]byte(`{"logs": {"description": "Parses logs", "processors": []}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := Provider().(*schema.Provider).ResourcesMap["elasticsearch_ingest_pipeline"]
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "logs",
+		"description": "Parses logs",
+		"on_existing": onExistingFail,
+		"processor": []interface{}{
+			map[string]interface{}{"json": `{"set": {"field": "env", "value": "dev"}}`},
+		},
+	})
+	diff, err := r.Diff(nil, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(nil, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !put {
+		t.Error("expected the missing pipeline to be created")
+	}
+}
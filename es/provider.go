@@ -7,9 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,11 +22,15 @@ import (
 	awsstscreds "github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	awssession "github.com/aws/aws-sdk-go/aws/session"
 	awssigv4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	awselasticsearch "github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	awselasticsearchiface "github.com/aws/aws-sdk-go/service/elasticsearchservice/elasticsearchserviceiface"
 	awssts "github.com/aws/aws-sdk-go/service/sts"
 	"github.com/deoxxa/aws_signing_client"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/pathorcontents"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
 	elastic7 "github.com/olivere/elastic/v7"
 	elastic5 "gopkg.in/olivere/elastic.v5"
 	elastic6 "gopkg.in/olivere/elastic.v6"
@@ -29,39 +38,163 @@ import (
 
 var awsUrlRegexp = regexp.MustCompile(`([a-z0-9-]+).es.amazonaws.com$`)
 
+const defaultOperationTimeout = 20 * time.Minute
+
+const (
+	flavorElasticsearch = "elasticsearch"
+	flavorOpenSearch    = "opensearch"
+
+	// buildFlavorServerless is the build flavor reported by Elastic Cloud
+	// Serverless projects.
+	buildFlavorServerless = "serverless"
+)
+
 type ProviderConf struct {
-	rawUrl             string
-	insecure           bool
-	sniffing           bool
-	healthchecking     bool
-	cacertFile         string
-	username           string
-	password           string
-	token              string
-	tokenName          string
-	parsedUrl          *url.URL
-	signAWSRequests    bool
-	esVersion          string
-	awsRegion          string
-	awsAssumeRoleArn   string
-	awsAccessKeyId     string
-	awsSecretAccessKey string
-	awsSessionToken    string
-	awsProfile         string
-	certPemPath        string
-	keyPemPath         string
-	kibanaUrl          string
-	hostOverride       string
+	rawUrl              string
+	urls                []string
+	insecure            bool
+	sniffing            bool
+	sniffInterval       time.Duration
+	sniffTimeout        time.Duration
+	healthchecking      bool
+	healthcheckInterval time.Duration
+	healthcheckTimeout  time.Duration
+	scheme              string
+	gzip                bool
+	cacertFile          string
+	caCertPem           string
+	rootCAs             *x509.CertPool
+	username            string
+	password            string
+	token               string
+	tokenName           string
+	parsedUrl           *url.URL
+	signAWSRequests     bool
+	esVersion           string
+	flavor              string
+	awsRegion           string
+	awsAssumeRoleArn    string
+	awsAccessKeyId      string
+	awsSecretAccessKey  string
+	awsSessionToken     string
+	awsProfile          string
+	certPemPath         string
+	keyPemPath          string
+	kibanaUrl           string
+	hostOverride        string
+	proxyUrl            *url.URL
+	maxRetries          int
+	requestSlots        chan struct{}
+	readLimiter         *rateLimiter
+	writeLimiter        *rateLimiter
+	retryBackoffMin     time.Duration
+	retryBackoffMax     time.Duration
+	timeout             time.Duration
+	connectTimeout      time.Duration
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	keepAlive           time.Duration
+	tlsSessionCache     tls.ClientSessionCache
+	responseCache       *responseCache
+	metrics             *apiMetrics
+	headers             map[string]string
+	endpoints           map[string]string
+	namePrefix          string
+	serverless          bool
+	onExisting          string
+	tokenFile           string
+	credentialsCommand  *credentialsCommand
+	kerberosPrincipal   string
+	kerberosRealm       string
+	kerberosKeytab      string
+	kerberosCCache      string
+	kerberosConfig      string
+	kerberosSpn         string
+	kerberos            *krbclient.Client
+	clients             *clientCache
+	ctx                 context.Context
+}
+
+// clientCache holds the clients created lazily by getClient and
+// getKibanaClient, so that configuring the provider never connects to the
+// cluster and each client is only built once per provider instance.
+type clientCache struct {
+	esMu         sync.Mutex
+	esClient     interface{}
+	kibanaMu     sync.Mutex
+	kibanaClient interface{}
+	// cluster is shared with the caches of the clients created for resources
+	// overriding the request timeout, by timeout
+	cluster   *clusterInfo
+	timeoutMu sync.Mutex
+	timeouts  map[time.Duration]*clientCache
+	// endpoints are the caches of the clients created for resources
+	// overriding the endpoint, by URL
+	endpointMu sync.Mutex
+	endpoints  map[string]*clientCache
+	// replacedMappings are the breaking changes of the mappings of the
+	// indices being replaced, by name, from the diff of the index to the diff
+	// of its replacement
+	replacedMu       sync.Mutex
+	replacedMappings map[string]string
+}
+
+// clusterInfo is the version and flavor of the cluster, detected once per
+// provider instance by the first client created, so that a plan only pings
+// the cluster once however many resources it has.
+type clusterInfo struct {
+	mu         sync.Mutex
+	version    string
+	flavor     string
+	serverless bool
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{cluster: &clusterInfo{}}
+}
+
+// withTimeout returns the cache of the clients using timeout instead of the
+// provider's timeout.
+func (c *clientCache) withTimeout(timeout time.Duration) *clientCache {
+	c.timeoutMu.Lock()
+	defer c.timeoutMu.Unlock()
+	if c.timeouts == nil {
+		c.timeouts = make(map[time.Duration]*clientCache)
+	}
+	if c.timeouts[timeout] == nil {
+		c.timeouts[timeout] = &clientCache{cluster: c.cluster}
+	}
+	return c.timeouts[timeout]
+}
+
+// withEndpoint returns the cache of the clients connecting to rawUrl instead
+// of the provider's URLs.
+func (c *clientCache) withEndpoint(rawUrl string) *clientCache {
+	c.endpointMu.Lock()
+	defer c.endpointMu.Unlock()
+	if c.endpoints == nil {
+		c.endpoints = make(map[string]*clientCache)
+	}
+	if c.endpoints[rawUrl] == nil {
+		c.endpoints[rawUrl] = &clientCache{cluster: c.cluster}
+	}
+	return c.endpoints[rawUrl]
 }
 
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"url": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_URL", nil),
-				Description: "Elasticsearch URL",
+				Description: "Elasticsearch URL, required unless `urls` or `aws_domain_name` is set",
+			},
+			"urls": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Elasticsearch URLs of several nodes to fail over between, e.g. coordinating nodes. Overrides `url`. Defaults to the comma separated `ELASTICSEARCH_URLS` from the environment, unless `url` is set.",
 			},
 			"kibana_url": {
 				Type:        schema.TypeString,
@@ -69,6 +202,12 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("KIBANA_URL", nil),
 				Description: "URL to reach the Kibana API",
 			},
+			"endpoints": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Named URLs of other endpoints of the same cluster, or Kibana hosts, e.g. a dedicated admin coordinating node, that resources can target with `endpoint_override`.",
+			},
 			"sniff": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -81,6 +220,47 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_HEALTH", true),
 				Description: "Set the client healthcheck option for the elastic client. Healthchecking is designed for direct access to the cluster.",
 			},
+			"sniff_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_SNIFF_INTERVAL", ""),
+				ValidateFunc: validateDuration,
+				Description:  "How often the client sniffs the cluster for nodes, e.g. 15m.",
+			},
+			"sniff_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_SNIFF_TIMEOUT", ""),
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of a sniffing request, e.g. 2s.",
+			},
+			"healthcheck_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_HEALTHCHECK_INTERVAL", ""),
+				ValidateFunc: validateDuration,
+				Description:  "How often the client checks the health of the nodes, e.g. 60s.",
+			},
+			"healthcheck_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_HEALTHCHECK_TIMEOUT", ""),
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of a healthcheck request, e.g. 1s.",
+			},
+			"scheme": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_SCHEME", ""),
+				ValidateFunc: validation.StringInSlice([]string{"", "http", "https"}, false),
+				Description:  "Scheme used for nodes found by sniffing, defaults to the scheme of `url`. Useful when a load balancer terminates TLS in front of nodes publishing plain http addresses.",
+			},
+			"gzip": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_GZIP", false),
+				Description: "Compress request bodies with gzip, e.g. when pushing large templates over slow links. Responses are always requested compressed.",
+			},
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -102,152 +282,496 @@ func Provider() terraform.ResourceProvider {
 			"token_name": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "ApiKey",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_TOKEN_NAME", "ApiKey"),
 				Description: "The type of token, usually ApiKey or Bearer",
 			},
+			"service_account_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_SERVICE_TOKEN", ""),
+				Description: "A service account token, sent as a Bearer token.",
+			},
+			"service_account_token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_SERVICE_TOKEN_FILE", ""),
+				Description: "Path to a file containing a service account token, e.g. a mounted Kubernetes secret. The file is read again whenever it changes.",
+			},
+			"credentials_command": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "A command and its arguments printing a short-lived token, e.g. from Vault, sent with `token_name`. It's run again after `credentials_refresh_interval` and when the cluster rejects the token.",
+			},
+			"credentials_refresh_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_CREDENTIALS_REFRESH_INTERVAL", "5m"),
+				ValidateFunc: validateDuration,
+				Description:  "How long to use a token from `credentials_command` before running it again.",
+			},
 			"aws_assume_role_arn": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_ASSUME_ROLE_ARN", ""),
 				Description: "Amazon Resource Name of an IAM Role to assume prior to making AWS API calls.",
 			},
 			"aws_access_key": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_ACCESS_KEY", ""),
 				Description: "The access key for use with AWS Elasticsearch Service domains",
 			},
 			"aws_secret_key": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_SECRET_KEY", ""),
 				Description: "The secret key for use with AWS Elasticsearch Service domains",
 			},
 			"aws_token": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_TOKEN", ""),
 				Description: "The session token for use with AWS Elasticsearch Service domains",
 			},
 			"aws_profile": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_PROFILE", ""),
 				Description: "The AWS profile for use with AWS Elasticsearch Service domains",
 			},
 			"aws_region": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_REGION", ""),
 				Description: "The AWS region for use in signing of AWS elasticsearch requests. Must be specified in order to use AWS URL signing with AWS ElasticSearch endpoint exposed on a custom DNS domain.",
 			},
+			"aws_domain_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_AWS_DOMAIN_NAME", nil),
+				Description: "Name of an Amazon OpenSearch Service domain in `aws_region` to connect to instead of `url`. Its endpoint is looked up with the AWS API, and requests to it are signed.",
+			},
 			"cacert_file": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
-				Description: "A Custom CA certificate",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_CACERT_FILE", ""),
+				Description: "A custom CA certificate used to verify the cluster certificates, as the path to a PEM file or its contents. May contain multiple certificates.",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("ELASTICSEARCH_CA_CERT_FILE", ""),
+				Description:   "Alias of `cacert_file`: the path to a PEM file or its contents, which may contain multiple certificates.",
+				ConflictsWith: []string{"cacert_file"},
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_CA_CERT_PEM", ""),
+				Description: "PEM encoded CA bundle used to verify the cluster certificates. May contain multiple certificates.",
 			},
 			"insecure": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Default:     false,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_INSECURE", false),
 				Description: "Disable SSL verification of API calls",
 			},
 			"client_cert_path": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
 				Description: "A X509 certificate to connect to elasticsearch",
 				DefaultFunc: schema.EnvDefaultFunc("ES_CLIENT_CERTIFICATE_PATH", ""),
 			},
 			"client_key_path": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
 				Description: "A X509 key to connect to elasticsearch",
 				DefaultFunc: schema.EnvDefaultFunc("ES_CLIENT_KEY_PATH", ""),
 			},
 			"sign_aws_requests": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Default:     true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_SIGN_AWS_REQUESTS", true),
 				Description: "Enable signing of AWS elasticsearch requests. The `url` must refer to AWS ES domain (`*.<region>.es.amazonaws.com`), or `aws_region` must be specified explicitly.",
 			},
 			"elasticsearch_version": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
-				Description: "ElasticSearch Version",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_VERSION", ""),
+				Description: "ElasticSearch Version, or just the major version, e.g. `7`",
+			},
+			"flavor": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_FLAVOR", ""),
+				ValidateFunc: validation.StringInSlice([]string{"", flavorElasticsearch, flavorOpenSearch}, false),
+				Description:  "Either `elasticsearch` or `opensearch`, detected from the cluster if not set",
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Headers added to every request sent to Elasticsearch and Kibana, e.g. for authenticating proxies or tracing.",
+			},
+			"kerberos_keytab": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_KERBEROS_KEYTAB", ""),
+				Description: "Path to a keytab used to authenticate with kerberos (SPNEGO), requires `kerberos_principal`.",
+			},
+			"kerberos_ccache": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_KERBEROS_CCACHE", ""),
+				Description: "Path to a kerberos credential cache, e.g. as created by kinit, used to authenticate with kerberos (SPNEGO).",
+			},
+			"kerberos_principal": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_KERBEROS_PRINCIPAL", ""),
+				Description: "The principal to authenticate as when using `kerberos_keytab`.",
+			},
+			"kerberos_realm": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_KERBEROS_REALM", ""),
+				Description: "The realm of `kerberos_principal`, defaults to the default realm of the kerberos configuration.",
+			},
+			"kerberos_config": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KRB5_CONFIG", "/etc/krb5.conf"),
+				Description: "Path to the kerberos configuration file.",
+			},
+			"kerberos_spn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_KERBEROS_SPN", ""),
+				Description: "The service principal of the cluster, defaults to HTTP/<host of the url>.",
+			},
+			"skip_version_ping": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_SKIP_VERSION_PING", false),
+				Description: "Don't contact the cluster to detect its version, sniff nodes or healthcheck when creating clients, e.g. to plan in CI without access to the cluster. Requires `elasticsearch_version`.",
+			},
+			"metrics_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_METRICS_FILE", ""),
+				Description: "Write a JSON summary of the API calls of the plan or apply to this file, with the number of requests, errors, retries and rate limited requests, latency percentiles, and the time waited for `max_concurrent_requests` and the rate limits, by endpoint, e.g. to tune `max_concurrent_requests` or the retries. It's rewritten after each resource operation. The summary is also logged at the DEBUG level.",
+			},
+			"serverless": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_SERVERLESS", false),
+				Description: "Whether the cluster is an Elastic Cloud Serverless project, which is otherwise detected from the cluster. It's only detected when the version is pinged, so set it along with `elasticsearch_version` or `skip_version_ping`. Sniffing, which serverless projects don't support, is disabled for them, the nodes only being sniffed once the cluster is known not to be one.",
+			},
+			"name_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_NAME_PREFIX", ""),
+				Description: "Fail the plan of resources whose objects, e.g. indices, templates, pipelines, users or roles, aren't named with this prefix, e.g. `team-a-`, so that teams sharing a cluster can't change each other's objects.",
+			},
+			"on_existing": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_ON_EXISTING", onExistingOverwrite),
+				ValidateFunc: validation.StringInSlice(onExistingBehaviors, false),
+				Description:  "What to do when creating users, roles, templates, ingest pipelines or ISM policies that already exist: `fail`, `adopt` the existing object like an import, or `overwrite` it, the behavior of the APIs. Resources can override it with their `on_existing`.",
 			},
 			"host_override": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_HOST_OVERRIDE", ""),
 				Description: "If provided, sets the 'Host' header of requests and the 'ServerName' for certificate validation to this value. See the documentation on connecting to Elasticsearch via an SSH tunnel.",
 			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_PROXY_URL", ""),
+				Description: "Proxy URL used for requests to Elasticsearch and Kibana, e.g. http://proxy:3128 or socks5://proxy:1080. If unset, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are honored.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_MAX_RETRIES", 3),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of times a request answered with 429 or 503 is retried. Set to 0 to disable retries.",
+			},
+			"retry_backoff_min": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_RETRY_BACKOFF_MIN", "100ms"),
+				ValidateFunc: validateDuration,
+				Description:  "Initial wait between retries, doubled on each attempt. A Retry-After header sent by the server takes precedence.",
+			},
+			"retry_backoff_max": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_RETRY_BACKOFF_MAX", "30s"),
+				ValidateFunc: validateDuration,
+				Description:  "Maximum wait between retries, whether backing off exponentially or as asked by a `Retry-After` header.",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of requests in flight at once, for all resources. Requests over the limit wait for a slot. Defaults to 0, no limit.",
+			},
+			"read_requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_READ_REQUESTS_PER_SECOND", 0.0),
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum average number of GET and HEAD requests sent per second, for all resources. Defaults to 0, no limit.",
+			},
+			"write_requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_WRITE_REQUESTS_PER_SECOND", 0.0),
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum average number of other requests sent per second, for all resources. Defaults to 0, no limit.",
+			},
+			"timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_TIMEOUT", ""),
+				ValidateFunc: validateDuration,
+				Description:  "Timeout for a single API request, including any retries, e.g. 90s. Can be overridden on resources supporting `request_timeout`. Defaults to no timeout.",
+			},
+			"connect_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_CONNECT_TIMEOUT", "30s"),
+				ValidateFunc: validateDuration,
+				Description:  "Timeout for establishing a connection to the cluster.",
+			},
+			"max_idle_connections_per_host": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_MAX_IDLE_CONNECTIONS_PER_HOST", 10),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of idle connections kept open to each host for reuse. Connections over the limit are closed after each request, which with a high parallelism can exhaust the ephemeral ports of NAT gateways. Defaults to 10, the default parallelism of Terraform.",
+			},
+			"idle_connection_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_IDLE_CONNECTION_TIMEOUT", "90s"),
+				ValidateFunc: validateDuration,
+				Description:  "How long an idle connection is kept open for reuse, e.g. shorter than the idle timeout of a load balancer in front of the cluster. Set to 0 to keep idle connections open indefinitely.",
+			},
+			"keep_alive": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ELASTICSEARCH_KEEP_ALIVE", "30s"),
+				ValidateFunc: validateDuration,
+				Description:  "Interval of the TCP keep-alive probes of open connections, keeping them from being dropped by NAT gateways or firewalls. Set to -1s to disable them.",
+			},
+			"cache_data_source_reads": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_CACHE_DATA_SOURCE_READS", true),
+				Description: "Send the identical GET requests of data sources that look up definitions, e.g. `elasticsearch_cluster_info` or `elasticsearch_ingest_pipeline`, once per plan or apply, however many times the data sources are evaluated. Any write to the cluster clears the cache.",
+			},
+			"tls_session_reuse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_TLS_SESSION_REUSE", true),
+				Description: "Resume the TLS sessions of earlier connections to the same host, skipping the full handshake when new connections are opened.",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"elasticsearch_destination":                     resourceElasticsearchDeprecatedDestination(),
-			"elasticsearch_index":                           resourceElasticsearchIndex(),
-			"elasticsearch_index_lifecycle_policy":          resourceElasticsearchDeprecatedIndexLifecyclePolicy(),
-			"elasticsearch_index_template":                  resourceElasticsearchIndexTemplate(),
-			"elasticsearch_composable_index_template":       resourceElasticsearchComposableIndexTemplate(),
-			"elasticsearch_component_template":              resourceElasticsearchComponentTemplate(),
-			"elasticsearch_ingest_pipeline":                 resourceElasticsearchIngestPipeline(),
-			"elasticsearch_kibana_alert":                    resourceElasticsearchKibanaAlert(),
-			"elasticsearch_kibana_object":                   resourceElasticsearchKibanaObject(),
-			"elasticsearch_monitor":                         resourceElasticsearchDeprecatedMonitor(),
-			"elasticsearch_snapshot_repository":             resourceElasticsearchSnapshotRepository(),
-			"elasticsearch_watch":                           resourceElasticsearchDeprecatedWatch(),
-			"elasticsearch_opendistro_destination":          resourceElasticsearchOpenDistroDestination(),
-			"elasticsearch_opendistro_ism_policy":           resourceElasticsearchOpenDistroISMPolicy(),
-			"elasticsearch_opendistro_ism_policy_mapping":   resourceElasticsearchOpenDistroISMPolicyMapping(),
-			"elasticsearch_opendistro_monitor":              resourceElasticsearchOpenDistroMonitor(),
-			"elasticsearch_opendistro_roles_mapping":        resourceElasticsearchOpenDistroRolesMapping(),
-			"elasticsearch_opendistro_role":                 resourceElasticsearchOpenDistroRole(),
-			"elasticsearch_opendistro_user":                 resourceElasticsearchOpenDistroUser(),
-			"elasticsearch_opendistro_kibana_tenant":        resourceElasticsearchOpenDistroKibanaTenant(),
-			"elasticsearch_xpack_index_lifecycle_policy":    resourceElasticsearchXpackIndexLifecyclePolicy(),
-			"elasticsearch_xpack_license":                   resourceElasticsearchXpackLicense(),
-			"elasticsearch_xpack_role":                      resourceElasticsearchXpackRole(),
-			"elasticsearch_xpack_role_mapping":              resourceElasticsearchXpackRoleMapping(),
-			"elasticsearch_xpack_snapshot_lifecycle_policy": resourceElasticsearchXpackSnapshotLifecyclePolicy(),
-			"elasticsearch_xpack_user":                      resourceElasticsearchXpackUser(),
-			"elasticsearch_xpack_watch":                     resourceElasticsearchXpackWatch(),
+			"elasticsearch_blue_green_index":                    resourceElasticsearchBlueGreenIndex(),
+			"elasticsearch_bulk_documents":                      resourceElasticsearchBulkDocuments(),
+			"elasticsearch_ccr_follower":                        resourceElasticsearchCcrFollower(),
+			"elasticsearch_cluster_health_check":                resourceElasticsearchClusterHealthCheck(),
+			"elasticsearch_cluster_routing_weights":             resourceElasticsearchClusterRoutingWeights(),
+			"elasticsearch_cluster_settings":                    resourceElasticsearchClusterSettings(),
+			"elasticsearch_data_stream_failure_store":           resourceElasticsearchDataStreamFailureStore(),
+			"elasticsearch_delete_by_query":                     resourceElasticsearchDeleteByQuery(),
+			"elasticsearch_destination":                         resourceElasticsearchDeprecatedDestination(),
+			"elasticsearch_document":                            resourceElasticsearchDocument(),
+			"elasticsearch_force_merge":                         resourceElasticsearchForceMerge(),
+			"elasticsearch_index":                               resourceElasticsearchIndex(),
+			"elasticsearch_index_rollover":                      resourceElasticsearchIndexRollover(),
+			"elasticsearch_index_state":                         resourceElasticsearchIndexState(),
+			"elasticsearch_index_lifecycle_policy":              resourceElasticsearchDeprecatedIndexLifecyclePolicy(),
+			"elasticsearch_index_template":                      resourceElasticsearchIndexTemplate(),
+			"elasticsearch_ingest_geoip_database":               resourceElasticsearchIngestGeoipDatabase(),
+			"elasticsearch_ingest_geoip_downloader":             resourceElasticsearchIngestGeoipDownloader(),
+			"elasticsearch_composable_index_template":           resourceElasticsearchComposableIndexTemplate(),
+			"elasticsearch_component_template":                  resourceElasticsearchComponentTemplate(),
+			"elasticsearch_ingest_pipeline":                     resourceElasticsearchIngestPipeline(),
+			"elasticsearch_kibana_alert":                        resourceElasticsearchKibanaAlert(),
+			"elasticsearch_kibana_object":                       resourceElasticsearchKibanaObject(),
+			"elasticsearch_kibana_saved_objects_import":         resourceElasticsearchKibanaSavedObjectsImport(),
+			"elasticsearch_kibana_tag":                          resourceElasticsearchKibanaTag(),
+			"elasticsearch_logstash_pipeline":                   resourceElasticsearchLogstashPipeline(),
+			"elasticsearch_monitor":                             resourceElasticsearchDeprecatedMonitor(),
+			"elasticsearch_reload_secure_settings":              resourceElasticsearchReloadSecureSettings(),
+			"elasticsearch_snapshot":                            resourceElasticsearchSnapshot(),
+			"elasticsearch_snapshot_repository":                 resourceElasticsearchSnapshotRepository(),
+			"elasticsearch_snapshot_repository_cleanup":         resourceElasticsearchSnapshotRepositoryCleanup(),
+			"elasticsearch_snapshot_repository_verify":          resourceElasticsearchSnapshotRepositoryVerify(),
+			"elasticsearch_update_by_query":                     resourceElasticsearchUpdateByQuery(),
+			"elasticsearch_watch":                               resourceElasticsearchDeprecatedWatch(),
+			"elasticsearch_opendistro_destination":              resourceElasticsearchOpenDistroDestination(),
+			"elasticsearch_opendistro_ism_policy":               resourceElasticsearchOpenDistroISMPolicy(),
+			"elasticsearch_opendistro_ism_policy_mapping":       resourceElasticsearchOpenDistroISMPolicyMapping(),
+			"elasticsearch_opendistro_monitor":                  resourceElasticsearchOpenDistroMonitor(),
+			"elasticsearch_opendistro_roles_mapping":            resourceElasticsearchOpenDistroRolesMapping(),
+			"elasticsearch_opendistro_role":                     resourceElasticsearchOpenDistroRole(),
+			"elasticsearch_opendistro_user":                     resourceElasticsearchOpenDistroUser(),
+			"elasticsearch_opendistro_kibana_tenant":            resourceElasticsearchOpenDistroKibanaTenant(),
+			"elasticsearch_opendistro_dashboards_index_pattern": resourceElasticsearchOpenDistroDashboardsIndexPattern(),
+			"elasticsearch_opendistro_security_cache_flush":     resourceElasticsearchOpenDistroSecurityCacheFlush(),
+			"elasticsearch_opendistro_tenancy_config":           resourceElasticsearchOpenDistroTenancyConfig(),
+			"elasticsearch_xpack_index_lifecycle_mode":          resourceElasticsearchXpackIndexLifecycleMode(),
+			"elasticsearch_xpack_index_lifecycle_policy":        resourceElasticsearchXpackIndexLifecyclePolicy(),
+			"elasticsearch_xpack_license":                       resourceElasticsearchXpackLicense(),
+			"elasticsearch_xpack_ml_upgrade_mode":               resourceElasticsearchXpackMlUpgradeMode(),
+			"elasticsearch_xpack_monitoring_settings":           resourceElasticsearchXpackMonitoringSettings(),
+			"elasticsearch_xpack_role":                          resourceElasticsearchXpackRole(),
+			"elasticsearch_xpack_role_mapping":                  resourceElasticsearchXpackRoleMapping(),
+			"elasticsearch_xpack_snapshot_lifecycle_mode":       resourceElasticsearchXpackSnapshotLifecycleMode(),
+			"elasticsearch_xpack_snapshot_lifecycle_policy":     resourceElasticsearchXpackSnapshotLifecyclePolicy(),
+			"elasticsearch_xpack_transform":                     resourceElasticsearchXpackTransform(),
+			"elasticsearch_xpack_user":                          resourceElasticsearchXpackUser(),
+			"elasticsearch_xpack_watch":                         resourceElasticsearchXpackWatch(),
+			"elasticsearch_xpack_watcher_mode":                  resourceElasticsearchXpackWatcherMode(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"elasticsearch_destination":            dataSourceElasticsearchDeprecatedDestination(),
-			"elasticsearch_host":                   dataSourceElasticsearchHost(),
-			"elasticsearch_opendistro_destination": dataSourceElasticsearchOpenDistroDestination(),
+			"elasticsearch_alias":                       dataSourceElasticsearchAlias(),
+			"elasticsearch_cat_allocation":              dataSourceElasticsearchCatAllocation(),
+			"elasticsearch_cat_shards":                  dataSourceElasticsearchCatShards(),
+			"elasticsearch_ccr_stats":                   dataSourceElasticsearchCcrStats(),
+			"elasticsearch_cluster_info":                dataSourceElasticsearchClusterInfo(),
+			"elasticsearch_data_stream":                 dataSourceElasticsearchDataStream(),
+			"elasticsearch_deprecations":                dataSourceElasticsearchDeprecations(),
+			"elasticsearch_enrich_policies":             dataSourceElasticsearchEnrichPolicies(),
+			"elasticsearch_destination":                 dataSourceElasticsearchDeprecatedDestination(),
+			"elasticsearch_health_report":               dataSourceElasticsearchHealthReport(),
+			"elasticsearch_host":                        dataSourceElasticsearchHost(),
+			"elasticsearch_import_blocks":               dataSourceElasticsearchImportBlocks(),
+			"elasticsearch_indices":                     dataSourceElasticsearchIndices(),
+			"elasticsearch_ingest_pipeline":             dataSourceElasticsearchIngestPipeline(),
+			"elasticsearch_ingest_simulate":             dataSourceElasticsearchIngestSimulate(),
+			"elasticsearch_kibana_saved_objects_export": dataSourceElasticsearchKibanaSavedObjectsExport(),
+			"elasticsearch_kibana_spaces":               dataSourceElasticsearchKibanaSpaces(),
+			"elasticsearch_node_stats":                  dataSourceElasticsearchNodeStats(),
+			"elasticsearch_objects":                     dataSourceElasticsearchObjects(),
+			"elasticsearch_opendistro_destination":      dataSourceElasticsearchOpenDistroDestination(),
+			"elasticsearch_opendistro_monitors":         dataSourceElasticsearchOpenDistroMonitors(),
+			"elasticsearch_opendistro_ism_explain":      dataSourceElasticsearchOpenDistroISMExplain(),
+			"elasticsearch_script":                      dataSourceElasticsearchScript(),
+			"elasticsearch_search":                      dataSourceElasticsearchSearch(),
+			"elasticsearch_snapshot_repository":         dataSourceElasticsearchSnapshotRepository(),
+			"elasticsearch_snapshot_status":             dataSourceElasticsearchSnapshotStatus(),
+			"elasticsearch_snapshots":                   dataSourceElasticsearchSnapshots(),
+			"elasticsearch_xpack_builtin_privileges":    dataSourceElasticsearchXpackBuiltinPrivileges(),
+			"elasticsearch_xpack_license":               dataSourceElasticsearchXpackLicense(),
+			"elasticsearch_xpack_service_accounts":      dataSourceElasticsearchXpackServiceAccounts(),
+			"elasticsearch_xpack_ssl_certificates":      dataSourceElasticsearchXpackSslCertificates(),
+			"elasticsearch_xpack_usage":                 dataSourceElasticsearchXpackUsage(),
+			"elasticsearch_xpack_watch":                 dataSourceElasticsearchXpackWatch(),
+			"elasticsearch_xpack_watcher_stats":         dataSourceElasticsearchXpackWatcherStats(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	for name, r := range provider.ResourcesMap {
+		if lister, ok := objectListers[name]; ok {
+			resourceWithOnExisting(name, r, lister)
+		}
+		resourceWithTimeouts(r)
+		resourceWithEndpointOverride(r, strings.HasPrefix(name, "elasticsearch_kibana_") || strings.HasPrefix(name, "elasticsearch_opendistro_dashboards_"))
+		resourceWithDiagnostics(name, r)
+		if c, ok := resourceCapabilities[name]; ok {
+			resourceWithCapability(r, c)
+		}
+		if attributes, ok := resourceNameAttributes[name]; ok {
+			resourceWithNamePrefix(r, attributes)
+		}
+		resourceWithMetrics(r)
+	}
+
+	for name, r := range provider.DataSourcesMap {
+		resourceWithDiagnostics(name, r)
+		if cachedDataSources[name] {
+			dataSourceWithResponseCache(r)
+		}
+		resourceWithMetrics(r)
 	}
+
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		meta, err := providerConfigure(d)
+		if err != nil {
+			return nil, err
+		}
+		// cancel in-flight requests when terraform is interrupted
+		meta.(*ProviderConf).ctx = provider.StopContext()
+		return meta, nil
+	}
+
+	return provider
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	rawUrl := d.Get("url").(string)
+	urls := expandStringList(d.Get("urls").([]interface{}))
+	if v := os.Getenv("ELASTICSEARCH_URLS"); len(urls) == 0 && rawUrl == "" && v != "" {
+		// lists can't have a DefaultFunc
+		urls = strings.Split(v, ",")
+	}
+	if len(urls) > 0 {
+		rawUrl = urls[0]
+	} else if rawUrl != "" {
+		urls = []string{rawUrl}
+	} else if d.Get("aws_domain_name").(string) == "" {
+		return nil, errors.New("one of url, urls or aws_domain_name must be set")
+	}
 	parsedUrl, err := url.Parse(rawUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ProviderConf{
+	var proxyUrl *url.URL
+	if rawProxyUrl := d.Get("proxy_url").(string); rawProxyUrl != "" {
+		proxyUrl, err = url.Parse(rawProxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing proxy_url: %+v", err)
+		}
+		switch proxyUrl.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("proxy_url scheme must be one of http, https or socks5, got: %q", proxyUrl.Scheme)
+		}
+	}
+
+	conf := &ProviderConf{
 		rawUrl:          rawUrl,
+		urls:            urls,
 		kibanaUrl:       d.Get("kibana_url").(string),
 		insecure:        d.Get("insecure").(bool),
+		gzip:            d.Get("gzip").(bool),
 		sniffing:        d.Get("sniff").(bool),
 		healthchecking:  d.Get("healthcheck").(bool),
 		cacertFile:      d.Get("cacert_file").(string),
+		caCertPem:       d.Get("ca_cert_pem").(string),
 		username:        d.Get("username").(string),
 		password:        d.Get("password").(string),
 		token:           d.Get("token").(string),
 		tokenName:       d.Get("token_name").(string),
 		parsedUrl:       parsedUrl,
 		signAWSRequests: d.Get("sign_aws_requests").(bool),
-		esVersion:       d.Get("elasticsearch_version").(string),
+		esVersion:       normalizeVersion(d.Get("elasticsearch_version").(string)),
+		flavor:          d.Get("flavor").(string),
 		awsRegion:       d.Get("aws_region").(string),
 
 		awsAssumeRoleArn:   d.Get("aws_assume_role_arn").(string),
@@ -258,15 +782,215 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		certPemPath:        d.Get("client_cert_path").(string),
 		keyPemPath:         d.Get("client_key_path").(string),
 		hostOverride:       d.Get("host_override").(string),
-	}, nil
+		namePrefix:         d.Get("name_prefix").(string),
+		serverless:         d.Get("serverless").(bool),
+		onExisting:         d.Get("on_existing").(string),
+		proxyUrl:           proxyUrl,
+		clients:            newClientCache(),
+	}
+	if conf.cacertFile == "" {
+		conf.cacertFile = d.Get("ca_cert_file").(string)
+	}
+
+	conf.rootCAs, err = rootCAs(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	conf.endpoints = make(map[string]string)
+	for name, v := range d.Get("endpoints").(map[string]interface{}) {
+		if _, err := url.Parse(v.(string)); err != nil {
+			return nil, fmt.Errorf("error parsing the URL of endpoint %s: %+v", name, err)
+		}
+		conf.endpoints[name] = v.(string)
+	}
+
+	conf.headers = make(map[string]string)
+	for k, v := range d.Get("headers").(map[string]interface{}) {
+		conf.headers[k] = v.(string)
+	}
+
+	serviceToken := d.Get("service_account_token").(string)
+	serviceTokenFile := d.Get("service_account_token_file").(string)
+	if serviceToken != "" || serviceTokenFile != "" {
+		if conf.token != "" || serviceToken != "" && serviceTokenFile != "" {
+			return nil, errors.New("only one of token, service_account_token and service_account_token_file can be set")
+		}
+		conf.token = serviceToken
+		conf.tokenName = "Bearer"
+		if serviceTokenFile != "" {
+			if _, err := readTokenFile(serviceTokenFile); err != nil {
+				return nil, err
+			}
+			conf.tokenFile = serviceTokenFile
+		}
+	}
+
+	if rawCommand := d.Get("credentials_command").([]interface{}); len(rawCommand) > 0 {
+		if conf.token != "" || conf.tokenFile != "" {
+			return nil, errors.New("credentials_command can't be combined with token or service_account_token")
+		}
+		var args []string
+		for _, arg := range rawCommand {
+			s, _ := arg.(string)
+			args = append(args, s)
+		}
+		interval, _ := time.ParseDuration(d.Get("credentials_refresh_interval").(string))
+		conf.credentialsCommand, err = newCredentialsCommand(args, interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conf.kerberosKeytab = d.Get("kerberos_keytab").(string)
+	conf.kerberosCCache = d.Get("kerberos_ccache").(string)
+	if conf.kerberosKeytab != "" || conf.kerberosCCache != "" {
+		if conf.kerberosKeytab != "" && conf.kerberosCCache != "" {
+			return nil, errors.New("only one of kerberos_keytab and kerberos_ccache can be set")
+		}
+		if conf.username != "" || conf.token != "" || conf.tokenFile != "" || conf.credentialsCommand != nil {
+			return nil, errors.New("kerberos authentication can't be combined with username or token")
+		}
+		conf.kerberosPrincipal = d.Get("kerberos_principal").(string)
+		conf.kerberosRealm = d.Get("kerberos_realm").(string)
+		conf.kerberosConfig = d.Get("kerberos_config").(string)
+		conf.kerberosSpn = d.Get("kerberos_spn").(string)
+		conf.kerberos, err = kerberosClient(conf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conf.scheme = parsedUrl.Scheme
+	if scheme := d.Get("scheme").(string); scheme != "" {
+		conf.scheme = scheme
+	}
+	for attr, duration := range map[string]*time.Duration{
+		"sniff_interval":       &conf.sniffInterval,
+		"sniff_timeout":        &conf.sniffTimeout,
+		"healthcheck_interval": &conf.healthcheckInterval,
+		"healthcheck_timeout":  &conf.healthcheckTimeout,
+	} {
+		if v := d.Get(attr).(string); v != "" {
+			*duration, _ = time.ParseDuration(v)
+		}
+	}
+
+	if d.Get("skip_version_ping").(bool) {
+		if conf.esVersion == "" {
+			return nil, errors.New("elasticsearch_version must be set when skip_version_ping is enabled")
+		}
+		// creating a client with sniffing or healthchecks enabled connects to
+		// the cluster, so defer any connection to the first request instead
+		conf.sniffing = false
+		conf.healthchecking = false
+	}
+
+	conf.maxRetries = d.Get("max_retries").(int)
+	if n := d.Get("max_concurrent_requests").(int); n > 0 {
+		conf.requestSlots = make(chan struct{}, n)
+	}
+	conf.readLimiter = newRateLimiter(d.Get("read_requests_per_second").(float64))
+	conf.writeLimiter = newRateLimiter(d.Get("write_requests_per_second").(float64))
+	conf.retryBackoffMin, _ = time.ParseDuration(d.Get("retry_backoff_min").(string))
+	conf.retryBackoffMax, _ = time.ParseDuration(d.Get("retry_backoff_max").(string))
+	if v := d.Get("timeout").(string); v != "" {
+		conf.timeout, _ = time.ParseDuration(v)
+	}
+	conf.connectTimeout, _ = time.ParseDuration(d.Get("connect_timeout").(string))
+	conf.maxIdleConnsPerHost = d.Get("max_idle_connections_per_host").(int)
+	conf.idleConnTimeout, _ = time.ParseDuration(d.Get("idle_connection_timeout").(string))
+	conf.keepAlive, _ = time.ParseDuration(d.Get("keep_alive").(string))
+	conf.metrics = newApiMetrics(d.Get("metrics_file").(string))
+	if d.Get("cache_data_source_reads").(bool) {
+		conf.responseCache = newResponseCache()
+	}
+	if d.Get("tls_session_reuse").(bool) {
+		// shared by the transports of all clients, e.g. Elasticsearch and Kibana
+		conf.tlsSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+	if conf.retryBackoffMin > conf.retryBackoffMax {
+		return nil, fmt.Errorf("retry_backoff_min (%s) must not be greater than retry_backoff_max (%s)", conf.retryBackoffMin, conf.retryBackoffMax)
+	}
+
+	if domain := d.Get("aws_domain_name").(string); domain != "" {
+		if conf.rawUrl != "" {
+			return nil, errors.New("aws_domain_name can't be combined with url or urls")
+		}
+		if conf.awsRegion == "" {
+			return nil, errors.New("aws_region must be set with aws_domain_name")
+		}
+		// the transport of the session depends on the timeouts above
+		svc := awselasticsearch.New(awsSession(conf.awsRegion, conf))
+		endpoint, err := awsDomainEndpoint(context.Background(), svc, domain)
+		if err != nil {
+			return nil, err
+		}
+		conf.rawUrl = "https://" + endpoint
+		conf.urls = []string{conf.rawUrl}
+		conf.parsedUrl, _ = url.Parse(conf.rawUrl)
+		if conf.scheme == "" {
+			conf.scheme = conf.parsedUrl.Scheme
+		}
+	}
+
+	return conf, nil
 }
 
+// normalizeVersion expands a major version, e.g. "7", to a full version so it
+// can be compared with the versions reported by the cluster.
+func normalizeVersion(version string) string {
+	if version != "" && !strings.Contains(version, ".") {
+		return version + ".0.0"
+	}
+	return version
+}
+
+// getClient returns the elasticsearch client for the configured cluster,
+// creating it on first use. Failures aren't cached, so a cluster that is still
+// starting up is retried on the next call.
 func getClient(conf *ProviderConf) (interface{}, error) {
+	if conf.clients == nil {
+		return newClient(conf)
+	}
+
+	conf.clients.esMu.Lock()
+	defer conf.clients.esMu.Unlock()
+	if conf.clients.esClient == nil {
+		client, err := newClient(conf)
+		if err != nil {
+			return nil, err
+		}
+		conf.clients.esClient = client
+	}
+
+	return conf.clients.esClient, nil
+}
+
+func newClient(conf *ProviderConf) (interface{}, error) {
+	// serverless projects don't expose their nodes
+	sniff := conf.sniffing && !conf.serverless
 	opts := []elastic7.ClientOptionFunc{
-		elastic7.SetURL(conf.rawUrl),
-		elastic7.SetScheme(conf.parsedUrl.Scheme),
-		elastic7.SetSniff(conf.sniffing),
+		elastic7.SetURL(conf.urls...),
+		elastic7.SetScheme(conf.scheme),
 		elastic7.SetHealthcheck(conf.healthchecking),
+		elastic7.SetGzip(conf.gzip),
+	}
+	if conf.sniffInterval > 0 {
+		opts = append(opts, elastic7.SetSnifferInterval(conf.sniffInterval))
+	}
+	if conf.sniffTimeout > 0 {
+		opts = append(opts, elastic7.SetSnifferTimeout(conf.sniffTimeout), elastic7.SetSnifferTimeoutStartup(conf.sniffTimeout))
+	}
+	if conf.healthcheckInterval > 0 {
+		opts = append(opts, elastic7.SetHealthcheckInterval(conf.healthcheckInterval))
+	}
+	if conf.healthcheckTimeout > 0 {
+		opts = append(opts, elastic7.SetHealthcheckTimeout(conf.healthcheckTimeout), elastic7.SetHealthcheckTimeoutStartup(conf.healthcheckTimeout))
+	}
+	if len(conf.urls) > 1 {
+		// retry failed requests on the next available node
+		opts = append(opts, elastic7.SetRetrier(elastic7.NewBackoffRetrier(elastic7.NewExponentialBackoff(conf.retryBackoffMin, conf.retryBackoffMax))))
 	}
 
 	if conf.parsedUrl.User.Username() != "" {
@@ -277,44 +1001,94 @@ func getClient(conf *ProviderConf) (interface{}, error) {
 		opts = append(opts, elastic7.SetBasicAuth(conf.username, conf.password))
 	}
 
+	var httpClient *http.Client
 	if m := awsUrlRegexp.FindStringSubmatch(conf.parsedUrl.Hostname()); m != nil && conf.signAWSRequests {
 		log.Printf("[INFO] Using AWS: %+v", m[1])
-		opts = append(opts, elastic7.SetHttpClient(awsHttpClient(m[1], conf, map[string]string{})), elastic7.SetSniff(false))
+		httpClient = awsHttpClient(m[1], conf, map[string]string{})
+		sniff = false
 	} else if awsRegion := conf.awsRegion; conf.awsRegion != "" && conf.signAWSRequests {
 		log.Printf("[INFO] Using AWS: %+v", awsRegion)
-		opts = append(opts, elastic7.SetHttpClient(awsHttpClient(awsRegion, conf, map[string]string{})), elastic7.SetSniff(false))
-	} else if conf.insecure || conf.cacertFile != "" {
-		opts = append(opts, elastic7.SetHttpClient(tlsHttpClient(conf, map[string]string{})), elastic7.SetSniff(false))
-	} else if conf.token != "" {
-		opts = append(opts, elastic7.SetHttpClient(tokenHttpClient(conf, map[string]string{})), elastic7.SetSniff(false))
+		httpClient = awsHttpClient(awsRegion, conf, map[string]string{})
+		sniff = false
+	} else if conf.insecure || conf.rootCAs != nil {
+		httpClient = tlsHttpClient(conf, map[string]string{})
+		sniff = false
+	} else if conf.token != "" || conf.tokenFile != "" || conf.credentialsCommand != nil {
+		httpClient = tokenHttpClient(conf, map[string]string{})
+		sniff = false
 	} else {
-		opts = append(opts, elastic7.SetHttpClient(defaultHttpClient(conf, map[string]string{})))
+		httpClient = defaultHttpClient(conf, map[string]string{})
 	}
+	opts = append(opts, elastic7.SetHttpClient(httpClient))
 
+	// creating a client sniffs the nodes right away, so a client pinging the
+	// cluster first finds out whether it's a serverless project
+	detect := conf.esVersion == ""
 	var relevantClient interface{}
-	client, err := elastic7.NewClient(opts...)
+	client, err := elastic7.NewClient(append(opts, elastic7.SetSniff(sniff && !detect))...)
 	if err != nil {
 		return nil, err
 	}
 	relevantClient = client
 
 	// Use the v7 client to ping the cluster to determine the version if one was not provided
-	if conf.esVersion == "" {
-		log.Printf("[INFO] Pinging url to determine version %+v", conf.rawUrl)
-		info, _, err := client.Ping(conf.rawUrl).Do(context.TODO())
-		if err != nil {
+	if detect {
+		if err := detectVersion(conf, client); err != nil {
 			return nil, err
 		}
-		conf.esVersion = info.Version.Number
+		if sniff && !conf.serverless {
+			client.Stop()
+			if client, err = elastic7.NewClient(append(opts, elastic7.SetSniff(true))...); err != nil {
+				return nil, err
+			}
+			relevantClient = client
+		}
+	}
+
+	// compare major versions, versions as strings don't sort numerically
+	major := majorVersion(conf.esVersion)
+	if conf.flavor == flavorOpenSearch {
+		// OpenSearch was forked from 7.10 and kept its APIs, whatever its own
+		// version number is
+		log.Printf("[INFO] Using OpenSearch %s", conf.esVersion)
+		major = 7
 	}
 
-	if conf.esVersion < "7.0.0" && conf.esVersion >= "6.0.0" {
+	if conf.serverless {
+		// Serverless projects only accept requests in their own format, which
+		// the v7 client mostly understands, and report the version of the
+		// API rather than of their deployment
+		log.Printf("[INFO] Using Elastic Cloud Serverless")
+	} else if major >= 8 {
+		// The v7 client keeps working with Elasticsearch 8 through its REST API
+		// compatibility, APIs that only exist in 8 are called with
+		// PerformRequest
+		log.Printf("[INFO] Using ES 8 with the 7.x compatible REST API")
+		httpClient.Transport = WithRestCompatibility(httpClient.Transport, 7)
+	} else if major == 6 {
 		log.Printf("[INFO] Using ES 6")
 		opts := []elastic6.ClientOptionFunc{
-			elastic6.SetURL(conf.rawUrl),
-			elastic6.SetScheme(conf.parsedUrl.Scheme),
+			elastic6.SetURL(conf.urls...),
+			elastic6.SetScheme(conf.scheme),
 			elastic6.SetSniff(conf.sniffing),
 			elastic6.SetHealthcheck(conf.healthchecking),
+			elastic6.SetGzip(conf.gzip),
+		}
+		if conf.sniffInterval > 0 {
+			opts = append(opts, elastic6.SetSnifferInterval(conf.sniffInterval))
+		}
+		if conf.sniffTimeout > 0 {
+			opts = append(opts, elastic6.SetSnifferTimeout(conf.sniffTimeout), elastic6.SetSnifferTimeoutStartup(conf.sniffTimeout))
+		}
+		if conf.healthcheckInterval > 0 {
+			opts = append(opts, elastic6.SetHealthcheckInterval(conf.healthcheckInterval))
+		}
+		if conf.healthcheckTimeout > 0 {
+			opts = append(opts, elastic6.SetHealthcheckTimeout(conf.healthcheckTimeout), elastic6.SetHealthcheckTimeoutStartup(conf.healthcheckTimeout))
+		}
+		if len(conf.urls) > 1 {
+			// retry failed requests on the next available node
+			opts = append(opts, elastic6.SetRetrier(elastic6.NewBackoffRetrier(elastic6.NewExponentialBackoff(conf.retryBackoffMin, conf.retryBackoffMax))))
 		}
 
 		if conf.parsedUrl.User.Username() != "" {
@@ -331,9 +1105,9 @@ func getClient(conf *ProviderConf) (interface{}, error) {
 		} else if awsRegion := conf.awsRegion; conf.awsRegion != "" && conf.signAWSRequests {
 			log.Printf("[INFO] Using AWS: %+v", conf.awsRegion)
 			opts = append(opts, elastic6.SetHttpClient(awsHttpClient(awsRegion, conf, map[string]string{})), elastic6.SetSniff(false))
-		} else if conf.insecure || conf.cacertFile != "" {
+		} else if conf.insecure || conf.rootCAs != nil {
 			opts = append(opts, elastic6.SetHttpClient(tlsHttpClient(conf, map[string]string{})), elastic6.SetSniff(false))
-		} else if conf.token != "" {
+		} else if conf.token != "" || conf.tokenFile != "" || conf.credentialsCommand != nil {
 			opts = append(opts, elastic6.SetHttpClient(tokenHttpClient(conf, map[string]string{})), elastic6.SetSniff(false))
 		} else {
 			opts = append(opts, elastic6.SetHttpClient(defaultHttpClient(conf, map[string]string{})))
@@ -343,13 +1117,30 @@ func getClient(conf *ProviderConf) (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-	} else if conf.esVersion < "6.0.0" && conf.esVersion >= "5.0.0" {
+	} else if major == 5 {
 		log.Printf("[INFO] Using ES 5")
 		opts := []elastic5.ClientOptionFunc{
-			elastic5.SetURL(conf.rawUrl),
-			elastic5.SetScheme(conf.parsedUrl.Scheme),
+			elastic5.SetURL(conf.urls...),
+			elastic5.SetScheme(conf.scheme),
 			elastic5.SetSniff(conf.sniffing),
 			elastic5.SetHealthcheck(conf.healthchecking),
+			elastic5.SetGzip(conf.gzip),
+		}
+		if conf.sniffInterval > 0 {
+			opts = append(opts, elastic5.SetSnifferInterval(conf.sniffInterval))
+		}
+		if conf.sniffTimeout > 0 {
+			opts = append(opts, elastic5.SetSnifferTimeout(conf.sniffTimeout), elastic5.SetSnifferTimeoutStartup(conf.sniffTimeout))
+		}
+		if conf.healthcheckInterval > 0 {
+			opts = append(opts, elastic5.SetHealthcheckInterval(conf.healthcheckInterval))
+		}
+		if conf.healthcheckTimeout > 0 {
+			opts = append(opts, elastic5.SetHealthcheckTimeout(conf.healthcheckTimeout), elastic5.SetHealthcheckTimeoutStartup(conf.healthcheckTimeout))
+		}
+		if len(conf.urls) > 1 {
+			// retry failed requests on the next available node
+			opts = append(opts, elastic5.SetRetrier(elastic5.NewBackoffRetrier(elastic5.NewExponentialBackoff(conf.retryBackoffMin, conf.retryBackoffMax))))
 		}
 
 		if conf.parsedUrl.User.Username() != "" {
@@ -365,9 +1156,9 @@ func getClient(conf *ProviderConf) (interface{}, error) {
 		} else if awsRegion := conf.awsRegion; conf.awsRegion != "" && conf.signAWSRequests {
 			log.Printf("[INFO] Using AWS: %+v", conf.awsRegion)
 			opts = append(opts, elastic5.SetHttpClient(awsHttpClient(awsRegion, conf, map[string]string{})), elastic5.SetSniff(false))
-		} else if conf.insecure || conf.cacertFile != "" {
+		} else if conf.insecure || conf.rootCAs != nil {
 			opts = append(opts, elastic5.SetHttpClient(tlsHttpClient(conf, map[string]string{})), elastic5.SetSniff(false))
-		} else if conf.token != "" {
+		} else if conf.token != "" || conf.tokenFile != "" || conf.credentialsCommand != nil {
 			opts = append(opts, elastic5.SetHttpClient(tokenHttpClient(conf, map[string]string{})), elastic5.SetSniff(false))
 		} else {
 			opts = append(opts, elastic5.SetHttpClient(defaultHttpClient(conf, map[string]string{})))
@@ -377,14 +1168,126 @@ func getClient(conf *ProviderConf) (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-	} else if conf.esVersion < "5.0.0" {
-		return nil, errors.New("ElasticSearch is older than 5.0.0!")
+	} else if major < 5 {
+		return nil, fmt.Errorf("ElasticSearch %s is older than 5.0.0! Set flavor to opensearch if it's an OpenSearch cluster", conf.esVersion)
 	}
 
 	return relevantClient, nil
 }
 
+// detectVersion sets the version and flavor of conf to the ones of the
+// cluster, only pinging it with client if no other client of the provider
+// instance did already. Failures aren't cached.
+func detectVersion(conf *ProviderConf, client *elastic7.Client) error {
+	var cluster *clusterInfo
+	if conf.clients != nil {
+		cluster = conf.clients.cluster
+	}
+	if cluster != nil {
+		cluster.mu.Lock()
+		defer cluster.mu.Unlock()
+		if cluster.version != "" {
+			conf.esVersion = cluster.version
+			if conf.flavor == "" {
+				conf.flavor = cluster.flavor
+			}
+			conf.serverless = conf.serverless || cluster.serverless
+			return nil
+		}
+	}
+
+	var err error
+	for _, u := range conf.urls {
+		log.Printf("[INFO] Pinging url to determine version %+v", u)
+		var info *elastic7.PingResult
+		info, _, err = client.Ping(u).Do(providerContext(conf))
+		if err == nil {
+			conf.esVersion = info.Version.Number
+			if conf.flavor == "" {
+				conf.flavor = pingedFlavor(info)
+			}
+			conf.serverless = conf.serverless || info.Version.BuildFlavor == buildFlavorServerless
+			break
+		}
+		log.Printf("[WARN] Failed to ping %s: %+v", u, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if cluster != nil {
+		cluster.version, cluster.flavor, cluster.serverless = conf.esVersion, conf.flavor, conf.serverless
+	}
+	return nil
+}
+
+// pingedFlavor returns the flavor of the cluster answering a ping. OpenSearch
+// says so in its tagline, and otherwise reports major versions below 5, which
+// no Elasticsearch supported by the provider has.
+func pingedFlavor(info *elastic7.PingResult) string {
+	major := majorVersion(info.Version.Number)
+	if strings.Contains(info.TagLine, "OpenSearch") || major > 0 && major < 5 {
+		return flavorOpenSearch
+	}
+	return ""
+}
+
+// majorVersion returns the major version of a version number, 0 if it can't
+// be parsed.
+func majorVersion(v string) int {
+	major, _ := strconv.Atoi(strings.SplitN(v, ".", 2)[0])
+	return major
+}
+
+// clusterVersion returns the configured or detected version and flavor of
+// the cluster. The version is empty if no client was created yet.
+func clusterVersion(conf *ProviderConf) (string, string) {
+	if conf.esVersion != "" || conf.clients == nil || conf.clients.cluster == nil {
+		return conf.esVersion, conf.flavor
+	}
+
+	cluster := conf.clients.cluster
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	flavor := conf.flavor
+	if flavor == "" {
+		flavor = cluster.flavor
+	}
+	return cluster.version, flavor
+}
+
+// clusterServerless returns whether the cluster is configured or was detected
+// as an Elastic Cloud Serverless project.
+func clusterServerless(conf *ProviderConf) bool {
+	if conf.serverless || conf.clients == nil || conf.clients.cluster == nil {
+		return conf.serverless
+	}
+
+	cluster := conf.clients.cluster
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+	return cluster.serverless
+}
+
 func getKibanaClient(conf *ProviderConf) (interface{}, error) {
+	if conf.clients == nil {
+		return newKibanaClient(conf)
+	}
+
+	conf.clients.kibanaMu.Lock()
+	defer conf.clients.kibanaMu.Unlock()
+	if conf.clients.kibanaClient == nil {
+		client, err := newKibanaClient(conf)
+		if err != nil {
+			return nil, err
+		}
+		conf.clients.kibanaClient = client
+	}
+
+	return conf.clients.kibanaClient, nil
+}
+
+func newKibanaClient(conf *ProviderConf) (interface{}, error) {
 	// use either the provided version of elasticsearch or the version of
 	// elasticsearch determined by pinging the cluster. Base AWS or other auth
 	// off of the same ES config
@@ -401,6 +1304,7 @@ func getKibanaClient(conf *ProviderConf) (interface{}, error) {
 			// kibana api does not support sniff/health check
 			elastic7.SetSniff(false),
 			elastic7.SetHealthcheck(false),
+			elastic7.SetGzip(conf.gzip),
 		}
 
 		if conf.parsedUrl.User.Username() != "" {
@@ -411,21 +1315,7 @@ func getKibanaClient(conf *ProviderConf) (interface{}, error) {
 			opts = append(opts, elastic7.SetBasicAuth(conf.username, conf.password))
 		}
 
-		headers := map[string]string{"kbn-xsrf": "true"}
-
-		if m := awsUrlRegexp.FindStringSubmatch(conf.parsedUrl.Hostname()); m != nil && conf.signAWSRequests {
-			log.Printf("[INFO] Using AWS: %+v", m[1])
-			opts = append(opts, elastic7.SetHttpClient(awsHttpClient(m[1], conf, headers)), elastic7.SetSniff(false))
-		} else if awsRegion := conf.awsRegion; conf.awsRegion != "" && conf.signAWSRequests {
-			log.Printf("[INFO] Using AWS: %+v", awsRegion)
-			opts = append(opts, elastic7.SetHttpClient(awsHttpClient(awsRegion, conf, headers)), elastic7.SetSniff(false))
-		} else if conf.insecure || conf.cacertFile != "" {
-			opts = append(opts, elastic7.SetHttpClient(tlsHttpClient(conf, headers)))
-		} else if conf.token != "" {
-			opts = append(opts, elastic7.SetHttpClient(tokenHttpClient(conf, headers)), elastic7.SetSniff(false))
-		} else {
-			opts = append(opts, elastic7.SetHttpClient(defaultHttpClient(conf, headers)))
-		}
+		opts = append(opts, elastic7.SetHttpClient(kibanaHttpClient(conf)))
 
 		return elastic7.NewClient(opts...)
 	case *elastic6.Client:
@@ -484,64 +1374,82 @@ func awsSession(region string, conf *ProviderConf) *awssession.Session {
 		sessOpts.SharedConfigState = awssession.SharedConfigEnable
 	}
 
-	// If configured as insecure, turn off SSL verification
-	if conf.insecure {
-		client := &http.Client{Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}}
-		sessOpts.Config.HTTPClient = client
-	} else if conf.hostOverride != "" {
-		// Only use `host_override` to set `ServerName` if we're using a secure connection
-		client := &http.Client{Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{ServerName: conf.hostOverride},
-		}}
-		sessOpts.Config.HTTPClient = client
-	}
+	sessOpts.Config.HTTPClient = &http.Client{Transport: newTransport(conf)}
 
 	return awssession.Must(awssession.NewSessionWithOptions(sessOpts))
 }
 
+// awsDomainEndpoint looks up the endpoint of an Amazon OpenSearch Service
+// domain, its VPC endpoint if it's only reachable from a VPC.
+func awsDomainEndpoint(ctx context.Context, svc awselasticsearchiface.ElasticsearchServiceAPI, name string) (string, error) {
+	out, err := svc.DescribeElasticsearchDomainWithContext(ctx, &awselasticsearch.DescribeElasticsearchDomainInput{
+		DomainName: aws.String(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error describing AWS domain %s: %+v", name, err)
+	}
+
+	status := out.DomainStatus
+	if endpoint := aws.StringValue(status.Endpoint); endpoint != "" {
+		return endpoint, nil
+	}
+	if endpoint := aws.StringValue(status.Endpoints["vpc"]); endpoint != "" {
+		return endpoint, nil
+	}
+	if aws.BoolValue(status.Deleted) {
+		return "", fmt.Errorf("AWS domain %s is being deleted", name)
+	}
+	return "", fmt.Errorf("AWS domain %s has no endpoint yet, it may still be being created", name)
+}
+
 func awsHttpClient(region string, conf *ProviderConf, headers map[string]string) *http.Client {
 	session := awsSession(region, conf)
 	signer := awssigv4.NewSigner(session.Config.Credentials)
-	client, err := aws_signing_client.New(signer, session.Config.HTTPClient, "es", region)
+	// aws_signing_client replaces the transport of the client it is given, so
+	// don't hand it the session's client, which is also used to fetch credentials
+	sessionTransport := session.Config.HTTPClient.Transport
+	client, err := aws_signing_client.New(signer, &http.Client{Transport: sessionTransport}, "es", region)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	rt := WithHeader(client.Transport)
 	rt.hostOverride = conf.hostOverride
+	for k, v := range conf.headers {
+		rt.Set(k, v)
+	}
 	for k, v := range headers {
 		rt.Set(k, v)
 	}
-	client.Transport = rt
+	client.Transport = clientTransport(conf, rt)
+	client.Timeout = conf.timeout
 
 	return client
 }
 
 func tokenHttpClient(conf *ProviderConf, headers map[string]string) *http.Client {
-	client := http.DefaultClient
-
-	rt := WithHeader(client.Transport)
+	rt := WithHeader(tokenTransport(conf, newTransport(conf)))
 	rt.hostOverride = conf.hostOverride
-	rt.Set("Authorization", fmt.Sprintf("%s %s", conf.tokenName, conf.token))
+	for k, v := range conf.headers {
+		rt.Set(k, v)
+	}
+	if conf.token != "" {
+		rt.Set("Authorization", fmt.Sprintf("%s %s", conf.tokenName, conf.token))
+	}
 	for k, v := range headers {
 		rt.Set(k, v)
 	}
-	client.Transport = rt
 
-	if conf.insecure {
-		client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify = true
-	} else if conf.hostOverride != "" {
-		client.Transport.(*http.Transport).TLSClientConfig.ServerName = conf.hostOverride
+	return &http.Client{
+		Transport: clientTransport(conf, rt),
+		Timeout:   conf.timeout,
 	}
-
-	return client
 }
 
 func tlsHttpClient(conf *ProviderConf, headers map[string]string) *http.Client {
 	// Configure TLS/SSL
-	tlsConfig := &tls.Config{}
+	transport := newTransport(conf)
+	tlsConfig := transport.TLSClientConfig
 	if conf.certPemPath != "" && conf.keyPemPath != "" {
 		certPem, _, err := pathorcontents.Read(conf.certPemPath)
 		if err != nil {
@@ -558,49 +1466,318 @@ func tlsHttpClient(conf *ProviderConf, headers map[string]string) *http.Client {
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	// If a cacertFile has been specified, use that for cert validation
-	if conf.cacertFile != "" {
-		caCert, _, _ := pathorcontents.Read(conf.cacertFile)
-
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM([]byte(caCert))
-		tlsConfig.RootCAs = caCertPool
+	rt := WithHeader(tokenTransport(conf, kerberosTransport(conf, transport)))
+	rt.hostOverride = conf.hostOverride
+	for k, v := range conf.headers {
+		rt.Set(k, v)
 	}
-
-	// If configured as insecure, turn off SSL verification
-	if conf.insecure {
-		tlsConfig.InsecureSkipVerify = true
-	} else if conf.hostOverride != "" {
-		tlsConfig.ServerName = conf.hostOverride
+	// a token can be combined with a custom CA or client certificates
+	if conf.token != "" {
+		rt.Set("Authorization", fmt.Sprintf("%s %s", conf.tokenName, conf.token))
 	}
-
-	transport := &http.Transport{TLSClientConfig: tlsConfig}
-
-	rt := WithHeader(transport)
-	rt.hostOverride = conf.hostOverride
 	for k, v := range headers {
 		rt.Set(k, v)
 	}
 
-	client := &http.Client{Transport: rt}
+	client := &http.Client{
+		Transport: clientTransport(conf, rt),
+		Timeout:   conf.timeout,
+	}
 
 	return client
 }
 
 func defaultHttpClient(conf *ProviderConf, headers map[string]string) *http.Client {
-	// Gets the default HTTP client
-	client := http.DefaultClient
-	rt := WithHeader(client.Transport)
+	rt := WithHeader(kerberosTransport(conf, newTransport(conf)))
+	for k, v := range conf.headers {
+		rt.Set(k, v)
+	}
 	for k, v := range headers {
 		rt.Set(k, v)
 	}
 	rt.hostOverride = conf.hostOverride
-	client.Transport = rt
 
+	return &http.Client{
+		Transport: clientTransport(conf, rt),
+		Timeout:   conf.timeout,
+	}
+}
+
+// clientTransport wraps rt, which sets the headers of the requests, with the
+// response cache, retries, rate and concurrency limits, metrics and trace
+// logging shared by all clients.
+func clientTransport(conf *ProviderConf, rt http.RoundTripper) http.RoundTripper {
+	rt = WithConcurrencyLimit(WithMetrics(WithTraceLogging(rt), conf.metrics), conf.requestSlots, conf.metrics)
+	rt = WithRateLimit(rt, conf.readLimiter, conf.writeLimiter, conf.metrics)
+	retry := WithRetry(rt, conf.maxRetries, conf.retryBackoffMin, conf.retryBackoffMax)
+	retry.metrics = conf.metrics
+	return WithResponseCache(retry, conf.responseCache)
+}
+
+// newTransport returns the HTTP transport shared by all clients, with the
+// provider's proxy, certificate validation and connection pool settings
+// applied.
+func newTransport(conf *ProviderConf) *http.Transport {
+	tlsConfig := &tls.Config{RootCAs: conf.rootCAs, ClientSessionCache: conf.tlsSessionCache}
+	// If configured as insecure, turn off SSL verification
 	if conf.insecure {
-		client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify = true
+		tlsConfig.InsecureSkipVerify = true
 	} else if conf.hostOverride != "" {
-		client.Transport.(*http.Transport).TLSClientConfig.ServerName = conf.hostOverride
+		// Only use `host_override` to set `ServerName` if we're using a secure connection
+		tlsConfig.ServerName = conf.hostOverride
 	}
-	return client
+
+	dialer := &net.Dialer{
+		Timeout:   conf.connectTimeout,
+		KeepAlive: conf.keepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:               proxyFunc(conf),
+		DialContext:         dialer.DialContext,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: conf.connectTimeout,
+		MaxIdleConnsPerHost: conf.maxIdleConnsPerHost,
+		IdleConnTimeout:     conf.idleConnTimeout,
+	}
+}
+
+// rootCAs builds the certificate pool used to verify the cluster from
+// cacert_file and ca_cert_pem. It returns nil if none are set,
+// so that the system roots are used.
+func rootCAs(conf *ProviderConf) (*x509.CertPool, error) {
+	var bundles []string
+	if conf.cacertFile != "" {
+		caCert, _, err := pathorcontents.Read(conf.cacertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cacert_file: %+v", err)
+		}
+		bundles = append(bundles, caCert)
+	}
+	if conf.caCertPem != "" {
+		bundles = append(bundles, conf.caCertPem)
+	}
+	if len(bundles) == 0 {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+	for _, bundle := range bundles {
+		// AppendCertsFromPEM adds every CERTIFICATE block in the bundle
+		if !pool.AppendCertsFromPEM([]byte(bundle)) {
+			return nil, errors.New("no valid PEM encoded certificates found in the configured CA bundle")
+		}
+	}
+
+	return pool, nil
+}
+
+// resourceConf returns the provider configuration to use for a resource,
+// overriding the provider level timeout with the resource's request_timeout
+// when it is set.
+func resourceConf(d *schema.ResourceData, meta interface{}) *ProviderConf {
+	conf := meta.(*ProviderConf)
+	if v, ok := d.GetOk("request_timeout"); ok {
+		resourceConf := *conf
+		resourceConf.timeout, _ = time.ParseDuration(v.(string))
+		// the provider's clients use its own timeout
+		if conf.clients != nil {
+			resourceConf.clients = conf.clients.withTimeout(resourceConf.timeout)
+		}
+		return &resourceConf
+	}
+	return conf
+}
+
+// kibanaHttpClient returns the HTTP client of the requests to Kibana, signed
+// or authenticated like those to Elasticsearch. Basic authentication is left
+// to the caller.
+func kibanaHttpClient(conf *ProviderConf) *http.Client {
+	headers := map[string]string{"kbn-xsrf": "true"}
+
+	if m := awsUrlRegexp.FindStringSubmatch(conf.parsedUrl.Hostname()); m != nil && conf.signAWSRequests {
+		log.Printf("[INFO] Using AWS: %+v", m[1])
+		return awsHttpClient(m[1], conf, headers)
+	} else if awsRegion := conf.awsRegion; conf.awsRegion != "" && conf.signAWSRequests {
+		log.Printf("[INFO] Using AWS: %+v", awsRegion)
+		return awsHttpClient(awsRegion, conf, headers)
+	} else if conf.insecure || conf.rootCAs != nil {
+		return tlsHttpClient(conf, headers)
+	} else if conf.token != "" || conf.tokenFile != "" || conf.credentialsCommand != nil {
+		return tokenHttpClient(conf, headers)
+	}
+	return defaultHttpClient(conf, headers)
+}
+
+// resourceWithEndpointOverride adds endpoint_override to r, sending the
+// requests of every operation to another URL than the provider's, either the
+// Elasticsearch or, for Kibana resources, the Kibana URL.
+func resourceWithEndpointOverride(r *schema.Resource, kibana bool) {
+	r.Schema["endpoint_override"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The name of one of the provider `endpoints`, or a URL, to send the requests for this resource to instead of the provider's URL, e.g. a dedicated admin endpoint.",
+		// resources without updates must be replaced on any change
+		ForceNew: r.Update == nil,
+	}
+
+	wrap := func(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			conf, err := endpointConf(d.Get("endpoint_override").(string), kibana, meta)
+			if err != nil {
+				return err
+			}
+			return f(d, conf)
+		}
+	}
+	r.Create = wrap(r.Create)
+	r.Read = wrap(r.Read)
+	r.Update = wrap(r.Update)
+	r.Delete = wrap(r.Delete)
+
+	if exists := r.Exists; exists != nil {
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			conf, err := endpointConf(d.Get("endpoint_override").(string), kibana, meta)
+			if err != nil {
+				return false, err
+			}
+			return exists(d, conf)
+		}
+	}
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+			if !d.NewValueKnown("endpoint_override") {
+				return customizeDiff(d, meta)
+			}
+			conf, err := endpointConf(d.Get("endpoint_override").(string), kibana, meta)
+			if err != nil {
+				return err
+			}
+			return customizeDiff(d, conf)
+		}
+	}
+}
+
+// endpointConf returns the configuration of the provider connecting to
+// endpoint, the name of one of the provider's endpoints or a URL, or the
+// provider's configuration if endpoint is empty.
+func endpointConf(endpoint string, kibana bool, meta interface{}) (*ProviderConf, error) {
+	conf := meta.(*ProviderConf)
+	if endpoint == "" {
+		return conf, nil
+	}
+	rawUrl := endpoint
+	if u, ok := conf.endpoints[endpoint]; ok {
+		rawUrl = u
+	} else if !strings.Contains(endpoint, "://") {
+		return nil, fmt.Errorf("endpoint_override %q is neither one of the provider endpoints nor a URL", endpoint)
+	}
+
+	endpointConf := *conf
+	if kibana {
+		endpointConf.kibanaUrl = rawUrl
+	} else {
+		parsedUrl, err := url.Parse(rawUrl)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing endpoint_override: %+v", err)
+		}
+		endpointConf.rawUrl = rawUrl
+		endpointConf.urls = []string{rawUrl}
+		endpointConf.parsedUrl = parsedUrl
+		endpointConf.scheme = parsedUrl.Scheme
+		// sniffing would spread the requests over every node again
+		endpointConf.sniffing = false
+	}
+	if conf.clients != nil {
+		endpointConf.clients = conf.clients.withEndpoint(rawUrl)
+	}
+	return &endpointConf, nil
+}
+
+// resourceWithTimeouts adds a timeouts block to r, limiting each operation,
+// including every request it makes, to the configured duration.
+func resourceWithTimeouts(r *schema.Resource) {
+	if r.Timeouts == nil {
+		r.Timeouts = &schema.ResourceTimeout{}
+	}
+	if r.Create != nil && r.Timeouts.Create == nil {
+		r.Timeouts.Create = schema.DefaultTimeout(defaultOperationTimeout)
+	}
+	if r.Update != nil && r.Timeouts.Update == nil {
+		r.Timeouts.Update = schema.DefaultTimeout(defaultOperationTimeout)
+	}
+	if r.Delete != nil && r.Timeouts.Delete == nil {
+		r.Timeouts.Delete = schema.DefaultTimeout(defaultOperationTimeout)
+	}
+	r.Create = withOperationTimeout(r.Create, schema.TimeoutCreate)
+	r.Update = withOperationTimeout(r.Update, schema.TimeoutUpdate)
+	r.Delete = withOperationTimeout(r.Delete, schema.TimeoutDelete)
+}
+
+func withOperationTimeout(f func(*schema.ResourceData, interface{}) error, key string) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		ctx, cancel := context.WithTimeout(providerContext(meta), d.Timeout(key))
+		defer cancel()
+		conf := *meta.(*ProviderConf)
+		conf.ctx = ctx
+		return f(d, &conf)
+	}
+}
+
+// cachedDataSources look up definitions that don't change during a run unless
+// the run changes them, whose GET requests are cached. Data sources reading
+// statistics, health or progress, e.g. polling a snapshot, aren't.
+var cachedDataSources = map[string]bool{
+	"elasticsearch_alias":                    true,
+	"elasticsearch_cluster_info":             true,
+	"elasticsearch_data_stream":              true,
+	"elasticsearch_destination":              true,
+	"elasticsearch_enrich_policies":          true,
+	"elasticsearch_host":                     true,
+	"elasticsearch_ingest_pipeline":          true,
+	"elasticsearch_objects":                  true,
+	"elasticsearch_opendistro_destination":   true,
+	"elasticsearch_script":                   true,
+	"elasticsearch_snapshot_repository":      true,
+	"elasticsearch_xpack_builtin_privileges": true,
+	"elasticsearch_xpack_license":            true,
+	"elasticsearch_xpack_service_accounts":   true,
+	"elasticsearch_xpack_ssl_certificates":   true,
+	"elasticsearch_xpack_usage":              true,
+	"elasticsearch_xpack_watch":              true,
+}
+
+// dataSourceWithResponseCache answers the GET requests of the data source r
+// from the response cache of the provider.
+func dataSourceWithResponseCache(r *schema.Resource) {
+	read := r.Read
+	r.Read = func(d *schema.ResourceData, meta interface{}) error {
+		conf := *meta.(*ProviderConf)
+		conf.ctx = withCachedReads(providerContext(meta))
+		return read(d, &conf)
+	}
+}
+
+// providerContext returns the context to make requests with, which is
+// cancelled when terraform is interrupted.
+func providerContext(meta interface{}) context.Context {
+	if conf, ok := meta.(*ProviderConf); ok && conf.ctx != nil {
+		return conf.ctx
+	}
+	return context.Background()
+}
+
+// proxyFunc returns the proxy selection function for the HTTP transports,
+// either the explicitly configured proxy or the one from the environment.
+func proxyFunc(conf *ProviderConf) func(*http.Request) (*url.URL, error) {
+	if conf.proxyUrl != nil {
+		return http.ProxyURL(conf.proxyUrl)
+	}
+	return http.ProxyFromEnvironment
 }
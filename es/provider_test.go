@@ -1,12 +1,36 @@
 package es
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awselasticsearch "github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	awselasticsearchiface "github.com/aws/aws-sdk-go/service/elasticsearchservice/elasticsearchserviceiface"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
 )
 
 var testAccProviders map[string]terraform.ResourceProvider
@@ -216,6 +240,59 @@ func TestAWSCredsAssumeRole(t *testing.T) {
 	}
 }
 
+type fakeAwsDomains struct {
+	awselasticsearchiface.ElasticsearchServiceAPI
+	domains map[string]*awselasticsearch.ElasticsearchDomainStatus
+}
+
+func (f *fakeAwsDomains) DescribeElasticsearchDomainWithContext(ctx aws.Context, in *awselasticsearch.DescribeElasticsearchDomainInput, opts ...request.Option) (*awselasticsearch.DescribeElasticsearchDomainOutput, error) {
+	status, ok := f.domains[aws.StringValue(in.DomainName)]
+	if !ok {
+		return nil, awserr.New(awselasticsearch.ErrCodeResourceNotFoundException, "Domain not found: "+aws.StringValue(in.DomainName), nil)
+	}
+	return &awselasticsearch.DescribeElasticsearchDomainOutput{DomainStatus: status}, nil
+}
+
+func TestAWSDomainEndpoint(t *testing.T) {
+	svc := &fakeAwsDomains{domains: map[string]*awselasticsearch.ElasticsearchDomainStatus{
+		"logs":     {Endpoint: aws.String("search-logs-abc.us-east-1.es.amazonaws.com")},
+		"internal": {Endpoints: map[string]*string{"vpc": aws.String("vpc-internal-def.us-east-1.es.amazonaws.com")}},
+		"creating": {Processing: aws.Bool(true)},
+	}}
+
+	for name, expected := range map[string]string{
+		"logs":     "search-logs-abc.us-east-1.es.amazonaws.com",
+		"internal": "vpc-internal-def.us-east-1.es.amazonaws.com",
+	} {
+		endpoint, err := awsDomainEndpoint(context.Background(), svc, name)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if endpoint != expected {
+			t.Errorf("expected the endpoint of %s to be %s, got %s", name, expected, endpoint)
+		}
+	}
+
+	if _, err := awsDomainEndpoint(context.Background(), svc, "creating"); err == nil || !strings.Contains(err.Error(), "has no endpoint yet") {
+		t.Errorf("expected an error for a domain without endpoint, got %v", err)
+	}
+	if _, err := awsDomainEndpoint(context.Background(), svc, "missing"); err == nil || !strings.Contains(err.Error(), "Domain not found") {
+		t.Errorf("expected an error for a missing domain, got %v", err)
+	}
+}
+
+func TestProviderConfigureAWSDomainNameWithURL(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":             "http://localhost:9200",
+		"aws_domain_name": "logs",
+		"aws_region":      "us-east-1",
+	}
+	_, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err == nil || !strings.Contains(err.Error(), "aws_domain_name can't be combined with url or urls") {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+}
+
 func getCreds(t *testing.T, region string, config map[string]interface{}) credentials.Value {
 	awsAccessKey := ""
 	awsSecretKey := ""
@@ -245,3 +322,566 @@ func getCreds(t *testing.T, region string, config map[string]interface{}) creden
 	}
 	return creds
 }
+
+func TestProviderProxyUrl(t *testing.T) {
+	proxyUrl, _ := url.Parse("socks5://proxy.example.com:1080")
+	conf := &ProviderConf{proxyUrl: proxyUrl}
+
+	req, _ := http.NewRequest("GET", "https://elasticsearch.example.com:9200", nil)
+	u, err := proxyFunc(conf)(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if u.String() != proxyUrl.String() {
+		t.Errorf("proxy should have been %s (we got %s)", proxyUrl, u)
+	}
+}
+
+func TestProviderConnectionPool(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":                           "http://127.0.0.1:9200",
+		"max_idle_connections_per_host": 32,
+		"idle_connection_timeout":       "50s",
+		"keep_alive":                    "-1s",
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	transport := newTransport(conf)
+	if transport.MaxIdleConnsPerHost != 32 || transport.IdleConnTimeout != 50*time.Second {
+		t.Errorf("unexpected connection pool: %d idle connections for %s", transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+	if conf.keepAlive >= 0 {
+		t.Errorf("expected the keep-alive probes to be disabled, got %s", conf.keepAlive)
+	}
+	if transport.TLSClientConfig.ClientSessionCache == nil || transport.TLSClientConfig.ClientSessionCache != newTransport(conf).TLSClientConfig.ClientSessionCache {
+		t.Errorf("expected the TLS sessions to be shared by all transports")
+	}
+
+	raw["tls_session_reuse"] = false
+	meta, err = providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if newTransport(meta.(*ProviderConf)).TLSClientConfig.ClientSessionCache != nil {
+		t.Errorf("expected TLS sessions not to be reused")
+	}
+}
+
+func TestProviderRootCAs(t *testing.T) {
+	pool, err := rootCAs(&ProviderConf{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if pool != nil {
+		t.Errorf("expected no custom pool when no CA is configured")
+	}
+
+	bundle := testCACertPem(t, "ca-one") + testCACertPem(t, "ca-two")
+	pool, err = rootCAs(&ProviderConf{caCertPem: bundle})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if n := len(pool.Subjects()); n != 2 {
+		t.Errorf("expected 2 certificates in the pool, got %d", n)
+	}
+
+	_, err = rootCAs(&ProviderConf{caCertPem: "not a certificate"})
+	if err == nil {
+		t.Errorf("expected an error for an invalid PEM bundle")
+	}
+}
+
+func TestProviderCACertFileAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := ioutil.WriteFile(path, []byte(testCACertPem(t, "ca-one")+testCACertPem(t, "ca-two")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	raw := map[string]interface{}{
+		"url":                   "https://127.0.0.1:9200",
+		"ca_cert_file":          path,
+		"skip_version_ping":     true,
+		"elasticsearch_version": "7.10.2",
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if pool := meta.(*ProviderConf).rootCAs; pool == nil || len(pool.Subjects()) != 2 {
+		t.Errorf("expected the bundle of ca_cert_file to be read like cacert_file")
+	}
+
+	if warnings, errs := Provider().(*schema.Provider).Validate(terraform.NewResourceConfigRaw(raw)); len(warnings) != 0 || len(errs) != 0 {
+		t.Errorf("expected ca_cert_file to be valid on its own, got %v %v", warnings, errs)
+	}
+	raw["cacert_file"] = path
+	if _, errs := Provider().(*schema.Provider).Validate(terraform.NewResourceConfigRaw(raw)); len(errs) == 0 {
+		t.Errorf("expected ca_cert_file to conflict with cacert_file")
+	}
+}
+
+func testCACertPem(t *testing.T, commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestProviderSkipVersionPing(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":               "http://127.0.0.1:9200",
+		"skip_version_ping": true,
+	}
+	_, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err == nil {
+		t.Fatalf("expected an error when elasticsearch_version is not set")
+	}
+
+	raw["elasticsearch_version"] = "7.10.0"
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	if conf.sniffing || conf.healthchecking {
+		t.Errorf("expected sniffing and healthchecks to be disabled")
+	}
+
+	// nothing is listening, so this only succeeds if the client doesn't connect
+	conf.rawUrl = "http://127.0.0.1:1"
+	if _, err := getClient(conf); err != nil {
+		t.Errorf("err: %s", err)
+	}
+}
+
+func TestProviderFlavor(t *testing.T) {
+	cases := []struct {
+		flavor  string
+		version string
+		es6     bool
+	}{
+		{"", "6", true},
+		{"elasticsearch", "7", false},
+		{"elasticsearch", "8", false},
+		{"opensearch", "2.11.0", false},
+	}
+	for _, c := range cases {
+		raw := map[string]interface{}{
+			"url":                   "http://127.0.0.1:1",
+			"skip_version_ping":     true,
+			"elasticsearch_version": c.version,
+			"flavor":                c.flavor,
+		}
+		meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		client, err := getClient(meta.(*ProviderConf))
+		if err != nil {
+			t.Fatalf("%s %s: %s", c.flavor, c.version, err)
+		}
+		switch client.(type) {
+		case *elastic7.Client:
+			if c.es6 {
+				t.Errorf("expected an ES 6 client for %s %s", c.flavor, c.version)
+			}
+		case *elastic6.Client:
+			if !c.es6 {
+				t.Errorf("expected an ES 7 client for %s %s", c.flavor, c.version)
+			}
+		default:
+			t.Errorf("unexpected client %T for %s %s", client, c.flavor, c.version)
+		}
+	}
+}
+
+func TestProviderStopCancelsRequests(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	raw := map[string]interface{}{
+		"url":                   "http://127.0.0.1:9200",
+		"elasticsearch_version": "7.10.0",
+	}
+	if err := provider.Configure(terraform.NewResourceConfigRaw(raw)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ctx := providerContext(provider.Meta())
+	if ctx.Err() != nil {
+		t.Fatalf("expected the context to be active before stopping")
+	}
+	if err := provider.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expected the context to be cancelled after stopping")
+	}
+}
+
+func TestProviderDetectsVersionOnce(t *testing.T) {
+	var pings int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			pings++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}, "tagline": "You Know, for Search"}`))
+	}))
+	defer server.Close()
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	r := resourceElasticsearchComposableIndexTemplate()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"request_timeout": "1m"})
+	for i := 0; i < 3; i++ {
+		// each operation works on a copy of the configuration
+		operationConf := *conf
+		if err := capabilityComposableIndexTemplates.check(resourceConf(d, &operationConf)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if _, err := getClient(&operationConf); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	if pings != 1 {
+		t.Errorf("expected the cluster to be pinged once, got %d", pings)
+	}
+	if v, _ := clusterVersion(&ProviderConf{clients: conf.clients}); v != "7.10.2" {
+		t.Errorf("expected the detected version to be cached, got %q", v)
+	}
+}
+
+func TestProviderDetectsServerless(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0", "build_flavor": "serverless"}, "tagline": "You Know, for Search"}`))
+		case "/_index_template":
+			accept = r.Header.Get("Accept")
+			_, _ = w.Write([]byte(`{"index_templates": []}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	if err := capabilityIndexLifecyclePolicies.check(conf); err == nil {
+		t.Error("expected index lifecycle policies to be unavailable")
+	}
+	if !clusterServerless(&ProviderConf{clients: conf.clients}) {
+		t.Error("expected the serverless project to be cached")
+	}
+
+	esClient, err := getClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := performRequest(providerContext(conf), esClient, "GET", "/_index_template", nil, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(accept, "compatible-with") {
+		t.Errorf("expected no REST API compatibility, got %s", accept)
+	}
+}
+
+func TestProviderSniffsAfterDetectingServerless(t *testing.T) {
+	for flavor, expected := range map[string][]string{
+		"default":    {"/", "/_nodes/http"},
+		"serverless": {"/"},
+	} {
+		t.Run(flavor, func(t *testing.T) {
+			var requests []string
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				requests = append(requests, r.URL.Path)
+				switch r.URL.Path {
+				case "/":
+					_, _ = fmt.Fprintf(w, `{"version": {"number": "8.11.0", "build_flavor": %q}}`, flavor)
+				case "/_nodes/http":
+					_, _ = fmt.Fprintf(w, `{"nodes": {"node-1": {"name": "node-1", "http": {"publish_address": %q}}}}`, strings.TrimPrefix(server.URL, "http://"))
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+				}
+			}))
+			defer server.Close()
+
+			raw := map[string]interface{}{
+				"url":         server.URL,
+				"sniff":       true,
+				"healthcheck": false,
+			}
+			meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if _, err := getClient(meta.(*ProviderConf)); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(requests, expected) {
+				t.Errorf("expected the requests %v, got %v", expected, requests)
+			}
+		})
+	}
+}
+
+func TestResourceWithTimeouts(t *testing.T) {
+	var deadline time.Time
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			deadline, _ = providerContext(meta).Deadline()
+			return nil
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error { return nil },
+	}
+	resourceWithTimeouts(r)
+
+	if r.Timeouts.Create == nil || r.Timeouts.Update != nil {
+		t.Fatalf("expected only a create timeout, got %+v", r.Timeouts)
+	}
+	if err := r.Create(r.TestResourceData(), &ProviderConf{}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > defaultOperationTimeout {
+		t.Errorf("expected the create to be limited to %s, %s left", defaultOperationTimeout, remaining)
+	}
+}
+
+func TestResourceWithEndpointOverride(t *testing.T) {
+	var adminRequests int
+	admin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adminRequests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+	}))
+	t.Cleanup(admin.Close)
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to the provider url: %s", r.URL)
+	})
+	conf.endpoints = map[string]string{"admin": admin.URL}
+
+	var hosts []string
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			esClient, err := getClient(meta.(*ProviderConf))
+			if err != nil {
+				return err
+			}
+			hosts = append(hosts, meta.(*ProviderConf).parsedUrl.Host)
+			_, err = performRequest(providerContext(meta), esClient, "GET", "/", nil, nil)
+			return err
+		},
+	}
+	resourceWithEndpointOverride(r, false)
+	if !r.Schema["endpoint_override"].ForceNew {
+		t.Errorf("expected endpoint_override to force a new resource without update")
+	}
+
+	for _, endpoint := range []string{"admin", admin.URL} {
+		d := r.TestResourceData()
+		_ = d.Set("endpoint_override", endpoint)
+		if err := r.Read(d, conf); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if adminRequests != 3 {
+		t.Errorf("expected the version ping and both reads to go to the admin endpoint, got %d requests", adminRequests)
+	}
+	if len(hosts) != 2 || hosts[0] != strings.TrimPrefix(admin.URL, "http://") {
+		t.Errorf("unexpected hosts: %v", hosts)
+	}
+
+	d := r.TestResourceData()
+	_ = d.Set("endpoint_override", "unknown")
+	if err := r.Read(d, conf); err == nil || !strings.Contains(err.Error(), "neither one of the provider endpoints nor a URL") {
+		t.Errorf("expected an unknown endpoint to fail, got %v", err)
+	}
+}
+
+func TestProviderLazyClient(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":                   "http://127.0.0.1:1",
+		"elasticsearch_version": "7.10.0",
+		"sniff":                 false,
+		"healthcheck":           false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	if conf.clients.esClient != nil {
+		t.Fatalf("expected no client to be created when configuring the provider")
+	}
+
+	first, err := getClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	second, err := getClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if first != second {
+		t.Errorf("expected the client to be reused")
+	}
+}
+
+func TestProviderKerberosConflicts(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":             "http://127.0.0.1:9200",
+		"kerberos_keytab": "/etc/elasticsearch.keytab",
+		"kerberos_ccache": "/tmp/krb5cc_0",
+	}
+	_, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err == nil {
+		t.Errorf("expected an error when both a keytab and credential cache are set")
+	}
+
+	raw = map[string]interface{}{
+		"url":             "http://127.0.0.1:9200",
+		"kerberos_ccache": "/tmp/krb5cc_0",
+		"token":           "secret",
+	}
+	_, err = providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err == nil {
+		t.Errorf("expected an error when kerberos is combined with a token")
+	}
+}
+
+func TestProviderSniffingOptions(t *testing.T) {
+	raw := map[string]interface{}{
+		"url":                  "https://127.0.0.1:9200",
+		"scheme":               "http",
+		"sniff_interval":       "5m",
+		"healthcheck_timeout":  "3s",
+		"healthcheck_interval": "",
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	if conf.scheme != "http" {
+		t.Errorf("expected scheme to be overridden, got %s", conf.scheme)
+	}
+	if conf.sniffInterval != 5*time.Minute || conf.healthcheckTimeout != 3*time.Second {
+		t.Errorf("unexpected sniff interval %s or healthcheck timeout %s", conf.sniffInterval, conf.healthcheckTimeout)
+	}
+	if conf.healthcheckInterval != 0 || conf.sniffTimeout != 0 {
+		t.Errorf("expected unset durations to use the client defaults")
+	}
+}
+
+func TestProviderUrls(t *testing.T) {
+	os.Unsetenv("ELASTICSEARCH_URL")
+	_, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{}))
+	if err == nil {
+		t.Errorf("expected an error when neither url nor urls is set")
+	}
+
+	raw := map[string]interface{}{
+		"url":  "http://127.0.0.1:9200",
+		"urls": []interface{}{"https://node-1:9200", "https://node-2:9200"},
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	if len(conf.urls) != 2 || conf.rawUrl != "https://node-1:9200" || conf.scheme != "https" {
+		t.Errorf("expected urls to take precedence over url, got %v", conf.urls)
+	}
+}
+
+// testProviderConf returns the configuration of a provider for a fake cluster
+// served by handler, which must answer the version ping on "/".
+func testProviderConf(t *testing.T, handler http.HandlerFunc) *ProviderConf {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return meta.(*ProviderConf)
+}
+
+func TestProviderConfigureEnv(t *testing.T) {
+	previousUrl := os.Getenv("ELASTICSEARCH_URL")
+	os.Unsetenv("ELASTICSEARCH_URL")
+	defer os.Setenv("ELASTICSEARCH_URL", previousUrl)
+	env := map[string]string{
+		"ELASTICSEARCH_URLS":              "http://es-1:9200,http://es-2:9200",
+		"ELASTICSEARCH_INSECURE":          "true",
+		"ELASTICSEARCH_RETRY_BACKOFF_MAX": "1m",
+		"ELASTICSEARCH_AWS_REGION":        "eu-west-1",
+		"ES_CLIENT_CERTIFICATE_PATH":      "/etc/certs/client.pem",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	raw := map[string]interface{}{
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+	if strings.Join(conf.urls, " ") != "http://es-1:9200 http://es-2:9200" || conf.rawUrl != "http://es-1:9200" {
+		t.Errorf("expected the URLs of ELASTICSEARCH_URLS, got %v", conf.urls)
+	}
+	if !conf.insecure || conf.retryBackoffMax != time.Minute || conf.awsRegion != "eu-west-1" || conf.certPemPath != "/etc/certs/client.pem" {
+		t.Errorf("expected the configuration from the environment, got insecure %t, retry_backoff_max %s, aws_region %s, client_cert_path %s", conf.insecure, conf.retryBackoffMax, conf.awsRegion, conf.certPemPath)
+	}
+}
@@ -0,0 +1,99 @@
+package es
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket allowing perSecond requests per second on
+// average, with bursts of up to one second worth of requests.
+type rateLimiter struct {
+	perSecond float64
+	burst     float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	burst := math.Max(1, math.Ceil(perSecond))
+	return &rateLimiter{perSecond: perSecond, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// Wait blocks until a request may be sent, or ctx is done, and returns how
+// long it blocked. A nil limiter never blocks.
+func (l *rateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.perSecond)
+	l.last = now
+	// take the token now, going into debt if there is none, so that waiting
+	// requests are served in order
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return wait, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+}
+
+type withRateLimit struct {
+	rt      http.RoundTripper
+	reads   *rateLimiter
+	writes  *rateLimiter
+	metrics *apiMetrics
+}
+
+// WithRateLimit wraps rt so that GET and HEAD requests are limited by reads,
+// and all other requests by writes, the waits being counted in metrics. A nil
+// limiter doesn't limit anything.
+func WithRateLimit(rt http.RoundTripper, reads, writes *rateLimiter, metrics *apiMetrics) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if reads == nil && writes == nil {
+		return rt
+	}
+
+	return withRateLimit{rt: rt, reads: reads, writes: writes, metrics: metrics}
+}
+
+func (r withRateLimit) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter := r.writes
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		limiter = r.reads
+	}
+	wait, err := limiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		r.metrics.waitedForRateLimit(req, wait)
+	}
+
+	return r.rt.RoundTrip(req)
+}
@@ -0,0 +1,72 @@
+package es
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(100)
+	start := time.Now()
+	// the first 100 requests are a burst, the next 10 wait 10ms each
+	for i := 0; i < 110; i++ {
+		if _, err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected 110 requests to take about 100ms, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = newRateLimiter(0.001)
+	_, _ = l.Wait(ctx)
+	if _, err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("expected waiting to stop when the context is done, got %v", err)
+	}
+
+	if newRateLimiter(0) != nil {
+		t.Errorf("expected no limiter without a rate")
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	raw := map[string]interface{}{
+		"url":                       server.URL,
+		"write_requests_per_second": 1.0,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &http.Client{Transport: clientTransport(meta.(*ProviderConf), nil)}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected reads not to be limited, took %s", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Post(server.URL, "application/json", nil); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("expected the second write to wait for a second, took %s", elapsed)
+	}
+}
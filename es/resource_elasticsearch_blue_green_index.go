@@ -0,0 +1,368 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func resourceElasticsearchBlueGreenIndex() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchBlueGreenIndexCreate,
+		Read:   resourceElasticsearchBlueGreenIndexRead,
+		Update: resourceElasticsearchBlueGreenIndexUpdate,
+		Delete: resourceElasticsearchBlueGreenIndexDelete,
+		CustomizeDiff: customdiff.If(
+			func(d *schema.ResourceDiff, meta interface{}) bool {
+				return d.HasChange("settings") || d.HasChange("mappings")
+			},
+			func(d *schema.ResourceDiff, meta interface{}) error {
+				if err := d.SetNewComputed("index"); err != nil {
+					return err
+				}
+				if err := d.SetNewComputed("previous_index"); err != nil {
+					return err
+				}
+				return d.SetNewComputed("reindexed")
+			},
+		),
+		Schema: map[string]*schema.Schema{
+			"alias": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The alias the applications read and write through, pointing to the current index.",
+			},
+			"index_prefix": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The prefix of the names of the indices, followed by the time they were created, e.g. `products-` for `products-20210301100000`.",
+			},
+			"settings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON settings of the index. Changing them creates a new index.",
+			},
+			"mappings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON mappings of the index. Changing them creates a new index.",
+			},
+			"block_writes": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Block writes to the current index while it is reindexed, failing them rather than losing them once the alias is swapped. The previous index stays read-only. Otherwise the documents written during the reindexing are only in the previous index.",
+			},
+			"delete_previous_index": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the previous index once the alias is swapped, otherwise keep it, e.g. to swap back by hand.",
+			},
+			"slices":              byQuerySlicesSchema(),
+			"deletion_protection": deletionProtectionSchema(),
+			"index": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the current index, the alias points to.",
+			},
+			"previous_index": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the index the alias pointed to before the last swap, empty after creating the resource.",
+			},
+			"reindexed": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents copied from the previous index during the last swap.",
+			},
+		},
+		Description: "Manages an index behind an alias, changing its settings and mappings without downtime: a change creates a new index, reindexes the current one into it, then atomically swaps the alias. When the alias already exists, creating the resource migrates the index it points to the same way.",
+	}
+}
+
+func resourceElasticsearchBlueGreenIndexCreate(d *schema.ResourceData, meta interface{}) error {
+	alias := d.Get("alias").(string)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	current, err := aliasIndex(providerContext(meta), esClient, alias)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(alias)
+	if err := blueGreenReindex(d, meta, esClient, current, schema.TimeoutCreate); err != nil {
+		d.SetId("")
+		return err
+	}
+	return resourceElasticsearchBlueGreenIndexRead(d, meta)
+}
+
+func resourceElasticsearchBlueGreenIndexRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	index, err := aliasIndex(providerContext(meta), esClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if index == "" {
+		log.Printf("[WARN] Alias (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("alias", d.Id())
+	ds.set("index", index)
+	return ds.err
+}
+
+func resourceElasticsearchBlueGreenIndexUpdate(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("settings") && !d.HasChange("mappings") {
+		return resourceElasticsearchBlueGreenIndexRead(d, meta)
+	}
+	// keep the previous settings and mappings in the state if the reindexing
+	// fails, so that the next apply tries again
+	d.Partial(true)
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	current, err := aliasIndex(providerContext(meta), esClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if err := blueGreenReindex(d, meta, esClient, current, schema.TimeoutUpdate); err != nil {
+		return err
+	}
+	d.Partial(false)
+	return resourceElasticsearchBlueGreenIndexRead(d, meta)
+}
+
+func resourceElasticsearchBlueGreenIndexDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+	index, err := aliasIndex(ctx, esClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if index != "" {
+		if err := deleteIndex(ctx, esClient, index); err != nil {
+			return err
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+// blueGreenReindex creates a new index with the settings and mappings of d,
+// copies the documents of the current index into it, if any, and then points
+// the alias to it. The new index is deleted again if any step fails before
+// the swap.
+func blueGreenReindex(d *schema.ResourceData, meta interface{}, esClient interface{}, current string, timeout string) error {
+	ctx := providerContext(meta)
+	alias := d.Get("alias").(string)
+	index := d.Get("index_prefix").(string) + time.Now().UTC().Format("20060102150405")
+
+	body := map[string]interface{}{
+		"settings": json.RawMessage(d.Get("settings").(string)),
+		"mappings": json.RawMessage(d.Get("mappings").(string)),
+	}
+	if current == "" {
+		body["aliases"] = map[string]interface{}{alias: map[string]interface{}{}}
+	}
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for index: %+v", err)
+	}
+	if _, err := performRequest(ctx, esClient, "PUT", path, nil, body); err != nil {
+		return err
+	}
+
+	if current == "" {
+		ds := &resourceDataSetter{d: d}
+		ds.set("previous_index", "")
+		ds.set("reindexed", 0)
+		return ds.err
+	}
+
+	reindexed, err := blueGreenCopy(d, meta, esClient, current, index, timeout)
+	if err == nil {
+		_, err = performRequest(ctx, esClient, "POST", "/_aliases", nil, map[string]interface{}{
+			"actions": []map[string]interface{}{
+				{"remove": map[string]interface{}{"index": current, "alias": alias}},
+				{"add": map[string]interface{}{"index": index, "alias": alias}},
+			},
+		})
+		if err != nil {
+			err = fmt.Errorf("error swapping alias %s from %s to %s: %+v", alias, current, index, err)
+			if d.Get("block_writes").(bool) {
+				if err := setIndexWriteBlock(ctx, esClient, current, false); err != nil {
+					log.Printf("[WARN] Error unblocking writes to index %s: %+v", current, err)
+				}
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("[WARN] Deleting index %s, the migration to it failed: %+v", index, err)
+		if err := deleteIndex(ctx, esClient, index); err != nil {
+			log.Printf("[WARN] Error deleting index %s: %+v", index, err)
+		}
+		return err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("previous_index", current)
+	ds.set("reindexed", reindexed)
+
+	if d.Get("delete_previous_index").(bool) {
+		if err := deleteIndex(ctx, esClient, current); err != nil {
+			return err
+		}
+	}
+	return ds.err
+}
+
+// blueGreenCopy reindexes from into to, blocking writes to from during the
+// copy unless block_writes is false, and returns the number of documents
+// copied. Writes are unblocked again if the copy fails.
+func blueGreenCopy(d *schema.ResourceData, meta interface{}, esClient interface{}, from, to string, timeout string) (int, error) {
+	ctx := providerContext(meta)
+	block := d.Get("block_writes").(bool)
+	if block {
+		if err := setIndexWriteBlock(ctx, esClient, from, true); err != nil {
+			return 0, err
+		}
+	}
+
+	params := url.Values{
+		"slices":  []string{d.Get("slices").(string)},
+		"refresh": []string{"true"},
+	}
+	taskId, err := startTask(ctx, esClient, "_reindex", "/_reindex", params, map[string]interface{}{
+		"source": map[string]interface{}{"index": from},
+		"dest":   map[string]interface{}{"index": to},
+	})
+	var result byQueryResponse
+	if err == nil {
+		var res json.RawMessage
+		res, err = waitForTask(ctx, esClient, taskId, d.Timeout(timeout))
+		if err == nil {
+			if err = json.Unmarshal(res, &result); err != nil {
+				err = fmt.Errorf("error unmarshalling _reindex result: %+v: %s", err, res)
+			} else if len(result.Failures) > 0 {
+				err = fmt.Errorf("_reindex failed on %d documents, e.g. %s", len(result.Failures), compactJson(result.Failures[0]))
+			}
+		}
+	}
+
+	if err != nil && block {
+		if err := setIndexWriteBlock(ctx, esClient, from, false); err != nil {
+			log.Printf("[WARN] Error unblocking writes to index %s: %+v", from, err)
+		}
+	}
+	return result.Created, err
+}
+
+func setIndexWriteBlock(ctx context.Context, esClient interface{}, index string, block bool) error {
+	path, err := uritemplates.Expand("/{index}/_settings", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for index settings: %+v", err)
+	}
+	_, err = performRequest(ctx, esClient, "PUT", path, nil, map[string]interface{}{
+		"index.blocks.write": block,
+	})
+	return err
+}
+
+func deleteIndex(ctx context.Context, esClient interface{}, index string) error {
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for index: %+v", err)
+	}
+	_, err = performRequest(ctx, esClient, "DELETE", path, nil, nil)
+	if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) && !elastic5.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// aliasIndex returns the index alias points to, its write index if it points
+// to several, or an empty string if the alias doesn't exist.
+func aliasIndex(ctx context.Context, esClient interface{}, alias string) (string, error) {
+	path, err := uritemplates.Expand("/_alias/{alias}", map[string]string{
+		"alias": alias,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for alias: %+v", err)
+	}
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var indices map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}
+	if err := json.Unmarshal(res, &indices); err != nil {
+		return "", fmt.Errorf("error unmarshalling alias %s: %+v: %s", alias, err, res)
+	}
+	names := []string{}
+	for name, index := range indices {
+		if index.Aliases[alias].IsWriteIndex {
+			return name, nil
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	switch len(names) {
+	case 0:
+		return "", nil
+	case 1:
+		return names[0], nil
+	}
+	return "", fmt.Errorf("alias %s points to several indices without a write index: %v", alias, names)
+}
@@ -0,0 +1,112 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchBlueGreenIndexCreate(t *testing.T) {
+	current := "products-1"
+	var created string
+	var blocked bool
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_alias/products":
+			_, _ = w.Write([]byte(`{"` + current + `": {"aliases": {"products": {}}}}`))
+		case r.URL.Path == "/products-1/_settings" && r.Method == "PUT":
+			blocked = true
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case strings.HasPrefix(r.URL.Path, "/products-2") && r.Method == "PUT":
+			created = strings.TrimPrefix(r.URL.Path, "/")
+			_, _ = w.Write([]byte(`{"acknowledged": true, "index": "` + created + `"}`))
+		case r.URL.Path == "/_reindex":
+			if !blocked {
+				t.Errorf("expected writes to be blocked before reindexing")
+			}
+			_, _ = w.Write([]byte(`{"task": "node-1:10"}`))
+		case r.URL.Path == "/_tasks/node-1:10":
+			_, _ = w.Write([]byte(`{"completed": true, "response": {"total": 3, "created": 3, "failures": []}}`))
+		case r.URL.Path == "/_aliases":
+			var body struct {
+				Actions []map[string]map[string]string `json:"actions"`
+			}
+			raw, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(raw, &body); err != nil || len(body.Actions) != 2 || body.Actions[1]["add"]["index"] != created {
+				t.Errorf("unexpected body: %s", raw)
+			}
+			current = created
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchBlueGreenIndex()
+	d := r.TestResourceData()
+	_ = d.Set("alias", "products")
+	_ = d.Set("index_prefix", "products-")
+	_ = d.Set("settings", `{"number_of_shards": 1}`)
+	_ = d.Set("mappings", `{"properties": {"sku": {"type": "keyword"}}}`)
+	_ = d.Set("block_writes", true)
+	_ = d.Set("slices", "1")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual := d.Get("index"); actual != created {
+		t.Errorf("expected the alias to point to %s, got %v", created, actual)
+	}
+	if actual := d.Get("previous_index"); actual != "products-1" {
+		t.Errorf("unexpected previous_index: %v", actual)
+	}
+	if actual := d.Get("reindexed"); actual != 3 {
+		t.Errorf("expected 3 documents to be reindexed, got %v", actual)
+	}
+}
+
+func TestAccElasticsearchBlueGreenIndex(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchBlueGreenIndex("keyword"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("elasticsearch_blue_green_index.test", "index"),
+				),
+			},
+			{
+				Config: testAccElasticsearchBlueGreenIndex("text"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("elasticsearch_blue_green_index.test", "previous_index"),
+				),
+			},
+		},
+	})
+}
+
+func testAccElasticsearchBlueGreenIndex(fieldType string) string {
+	return `
+resource "elasticsearch_blue_green_index" "test" {
+  alias                 = "terraform-test-blue-green"
+  index_prefix          = "terraform-test-blue-green-"
+  delete_previous_index = true
+  settings              = jsonencode({ number_of_shards = 1, number_of_replicas = 0 })
+  mappings = jsonencode({
+    properties = {
+      name = { type = "` + fieldType + `" }
+    }
+  })
+}
+`
+}
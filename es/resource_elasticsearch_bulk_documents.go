@@ -0,0 +1,315 @@
+package es
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+// bulkBatchSize is the number of documents sent per _bulk request.
+const bulkBatchSize = 1000
+
+type bulkResponse struct {
+	Errors bool                                `json:"errors"`
+	Items  []map[string]bulkResponseItemResult `json:"items"`
+}
+
+type bulkResponseItemResult struct {
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+func resourceElasticsearchBulkDocuments() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchBulkDocumentsCreate,
+		Read:   resourceElasticsearchBulkDocumentsRead,
+		Update: resourceElasticsearchBulkDocumentsUpdate,
+		Delete: resourceElasticsearchBulkDocumentsDelete,
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The index to load the documents into, created with the default settings and dynamic mappings if it doesn't exist.",
+			},
+			"documents": {
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    hashSum,
+				ValidateFunc: validateNdjsonDocuments,
+				Description:  "The documents to load, as newline delimited JSON objects, e.g. read with `file` or rendered with `templatefile`. Only their SHA-256 hash is stored in the state, and the documents are loaded again when it changes.",
+			},
+			"id_field": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The field of the documents holding their ID, so that loading them again overwrites them. Without it, the documents get generated IDs.",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "append",
+				ValidateFunc: validation.StringInSlice([]string{"append", "replace"}, false),
+				Description:  "`append` adds the documents to the index, `replace` first deletes every document of the index, so that it only contains the loaded documents.",
+			},
+			"refresh": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Refresh the index once the documents are loaded, making them visible to searches and enrich policies.",
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete every document of the index when the resource is destroyed, e.g. for an index dedicated to these documents, otherwise only remove the resource from the state.",
+			},
+			"document_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents loaded by the last apply.",
+			},
+		},
+		Description: "Loads documents into an index with the `_bulk` API, e.g. to seed the source index of an enrich policy or a lookup index from a file.",
+	}
+}
+
+func resourceElasticsearchBulkDocumentsCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchLoadBulkDocuments(d, meta); err != nil {
+		return err
+	}
+	d.SetId(d.Get("index").(string))
+	return nil
+}
+
+func resourceElasticsearchBulkDocumentsRead(d *schema.ResourceData, meta interface{}) error {
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": d.Id(),
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for index: %+v", err)
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	_, err = performRequest(providerContext(meta), esClient, "HEAD", path, nil, nil)
+	if err != nil {
+		if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) {
+			log.Printf("[WARN] Index (%s) of the bulk documents not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("index", d.Id())
+	return ds.err
+}
+
+func resourceElasticsearchBulkDocumentsUpdate(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChanges("documents", "id_field", "mode") {
+		return nil
+	}
+	return resourceElasticsearchLoadBulkDocuments(d, meta)
+}
+
+func resourceElasticsearchBulkDocumentsDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("delete_on_destroy").(bool) {
+		err := deleteAllDocuments(d.Id(), meta)
+		if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) {
+			return err
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceElasticsearchLoadBulkDocuments(d *schema.ResourceData, meta interface{}) error {
+	index := d.Get("index").(string)
+	docs, err := parseNdjsonDocuments(d.Get("documents").(string))
+	if err != nil {
+		return err
+	}
+
+	if d.Get("mode").(string) == "replace" {
+		err := deleteAllDocuments(index, meta)
+		if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) {
+			return err
+		}
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	path, err := uritemplates.Expand("/{index}/_bulk", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for bulk: %+v", err)
+	}
+	var action map[string]interface{}
+	if _, ok := esClient.(*elastic6.Client); ok {
+		action = map[string]interface{}{"_type": "_doc"}
+	}
+	idField := d.Get("id_field").(string)
+
+	for start := 0; start < len(docs); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		body, err := bulkIndexBody(docs[start:end], idField, action)
+		if err != nil {
+			return err
+		}
+		err = retryOnTransientErrors(meta, func() error {
+			res, err := performRequest(providerContext(meta), esClient, "POST", path, nil, body)
+			if err != nil {
+				return err
+			}
+			return bulkResponseError(res)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.Get("refresh").(bool) {
+		refreshPath, err := uritemplates.Expand("/{index}/_refresh", map[string]string{
+			"index": index,
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for refresh: %+v", err)
+		}
+		if _, err := performRequest(providerContext(meta), esClient, "POST", refreshPath, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("document_count", len(docs))
+	return ds.err
+}
+
+// bulkIndexBody returns the NDJSON body of a _bulk request indexing docs,
+// with the IDs taken from idField if set.
+func bulkIndexBody(docs []map[string]interface{}, idField string, action map[string]interface{}) (string, error) {
+	var b strings.Builder
+	for i, doc := range docs {
+		meta := make(map[string]interface{}, len(action)+1)
+		for k, v := range action {
+			meta[k] = v
+		}
+		if idField != "" {
+			id, ok := doc[idField]
+			if !ok || id == nil {
+				return "", fmt.Errorf("document %d has no %s field", i+1, idField)
+			}
+			meta["_id"] = scalarString(id)
+		}
+		actionLine, err := json.Marshal(map[string]interface{}{"index": meta})
+		if err != nil {
+			return "", err
+		}
+		docLine, err := json.Marshal(doc)
+		if err != nil {
+			return "", err
+		}
+		b.Write(actionLine)
+		b.WriteByte('\n')
+		b.Write(docLine)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// bulkResponseError returns the first failure of a _bulk response, which
+// succeeds even if some of its items failed.
+func bulkResponseError(body json.RawMessage) error {
+	var res bulkResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return fmt.Errorf("error unmarshalling bulk response: %+v: %s", err, body)
+	}
+	if !res.Errors {
+		return nil
+	}
+	var failed int
+	var first string
+	for _, item := range res.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			failed++
+			if first == "" {
+				first = fmt.Sprintf("document %q: %s: %s", result.ID, result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+	return fmt.Errorf("%d of %d documents failed to load, e.g. %s", failed, len(res.Items), first)
+}
+
+func deleteAllDocuments(index string, meta interface{}) error {
+	path, err := uritemplates.Expand("/{index}/_delete_by_query", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for delete by query: %+v", err)
+	}
+	params := url.Values{
+		"conflicts": []string{"proceed"},
+		"refresh":   []string{"true"},
+	}
+	body := map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	_, err = performRequest(providerContext(meta), esClient, "POST", path, params, body)
+	return err
+}
+
+// parseNdjsonDocuments decodes newline delimited JSON objects, ignoring blank
+// lines.
+func parseNdjsonDocuments(s string) ([]map[string]interface{}, error) {
+	var docs []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(text, &doc); err != nil {
+			return nil, fmt.Errorf("line %d isn't a JSON object: %+v", line, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, scanner.Err()
+}
+
+func validateNdjsonDocuments(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := parseNdjsonDocuments(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %+v", k, err))
+	}
+	return
+}
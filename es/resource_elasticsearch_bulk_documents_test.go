@@ -0,0 +1,140 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchBulkDocumentsCreate(t *testing.T) {
+	var requests []string
+	var bulk string
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/countries/_delete_by_query":
+			_, _ = w.Write([]byte(`{"deleted": 3}`))
+		case "/countries/_bulk":
+			body, _ := ioutil.ReadAll(r.Body)
+			bulk = string(body)
+			_, _ = w.Write([]byte(`{"errors": false, "items": []}`))
+		case "/countries/_refresh":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchBulkDocuments()
+	d := r.TestResourceData()
+	_ = d.Set("index", "countries")
+	_ = d.Set("documents", "{\"code\": \"fr\", \"name\": \"France\"}\n\n{\"code\": 44, \"name\": \"United Kingdom\"}\n")
+	_ = d.Set("id_field", "code")
+	_ = d.Set("mode", "replace")
+	_ = d.Set("refresh", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedRequests := []string{"POST /countries/_delete_by_query", "POST /countries/_bulk", "POST /countries/_refresh"}
+	if strings.Join(requests, ", ") != strings.Join(expectedRequests, ", ") {
+		t.Errorf("expected requests %v, got %v", expectedRequests, requests)
+	}
+	expectedBulk := `{"index":{"_id":"fr"}}
+{"code":"fr","name":"France"}
+{"index":{"_id":"44"}}
+{"code":44,"name":"United Kingdom"}
+`
+	if bulk != expectedBulk {
+		t.Errorf("expected bulk body:\n%s\ngot:\n%s", expectedBulk, bulk)
+	}
+	if count := d.Get("document_count").(int); count != 2 {
+		t.Errorf("expected document_count to be 2, got %d", count)
+	}
+	if d.Id() != "countries" {
+		t.Errorf("unexpected ID: %s", d.Id())
+	}
+}
+
+func TestBulkResponseError(t *testing.T) {
+	err := bulkResponseError(json.RawMessage(`{"errors": true, "items": [
+		{"index": {"_id": "1", "status": 201}},
+		{"index": {"_id": "2", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse field [population]"}}}
+	]}`))
+	expected := `1 of 2 documents failed to load, e.g. document "2": mapper_parsing_exception: failed to parse field [population]`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+
+	if err := bulkResponseError(json.RawMessage(`{"errors": false, "items": [{"index": {"_id": "1", "status": 201}}]}`)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestValidateNdjsonDocuments(t *testing.T) {
+	if _, errs := validateNdjsonDocuments("{\"a\": 1}\n{\"a\": 2}", "documents"); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, errs := validateNdjsonDocuments("{\"a\": 1}\n[2]", "documents"); len(errs) != 1 || !strings.Contains(errs[0].Error(), "line 2") {
+		t.Errorf("expected an error on line 2, got %v", errs)
+	}
+}
+
+func TestAccElasticsearchBulkDocuments(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchBulkDocuments(`{"code": "fr", "name": "France"}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_bulk_documents.test", "document_count", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_search.test", "total", "1"),
+				),
+			},
+			{
+				Config: testAccElasticsearchBulkDocuments(`{"code": "de", "name": "Germany"}\n{"code": "it", "name": "Italy"}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_bulk_documents.test", "document_count", "2"),
+					resource.TestCheckResourceAttr("data.elasticsearch_search.test", "total", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccElasticsearchBulkDocuments(documents string) string {
+	return fmt.Sprintf(`
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-bulk-documents"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  force_destroy      = true
+}
+
+resource "elasticsearch_bulk_documents" "test" {
+  index     = elasticsearch_index.test.name
+  documents = "%s"
+  id_field  = "code"
+  mode      = "replace"
+}
+
+data "elasticsearch_search" "test" {
+  index = elasticsearch_index.test.name
+
+  depends_on = [elasticsearch_bulk_documents.test]
+}
+`, strings.ReplaceAll(documents, `"`, `\"`))
+}
@@ -0,0 +1,232 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+const (
+	ccrFollowerActive = "active"
+	ccrFollowerPaused = "paused"
+)
+
+// ccrFollowerInfo is a follower index of the CCR info API.
+type ccrFollowerInfo struct {
+	FollowerIndex string `json:"follower_index"`
+	RemoteCluster string `json:"remote_cluster"`
+	LeaderIndex   string `json:"leader_index"`
+	Status        string `json:"status"`
+}
+
+func resourceElasticsearchCcrFollower() *schema.Resource {
+	return &schema.Resource{
+		Description: "Creates a follower index replicating a leader index of a remote cluster with cross-cluster replication, e.g. to keep a copy of an index in another region. Replication can be paused and resumed with `paused`, e.g. during maintenance of the remote cluster. Destroying the resource deletes the follower index.",
+		Create:      resourceElasticsearchCcrFollowerCreate,
+		Read:        resourceElasticsearchCcrFollowerRead,
+		Update:      resourceElasticsearchCcrFollowerUpdate,
+		Delete:      resourceElasticsearchCcrFollowerDelete,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the follower index, created by the resource.",
+			},
+			"remote_cluster": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The alias of the remote cluster of the leader index, as configured in the `cluster.remote` settings.",
+			},
+			"leader_index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the index of the remote cluster to replicate.",
+			},
+			"paused": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Pause the replication, keeping the follower index and the operations replicated so far. Setting it back to false resumes the replication where it stopped.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "`active` or `paused`.",
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+func resourceElasticsearchCcrFollowerCreate(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
+	index := d.Get("index").(string)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+
+	path, err := ccrFollowerPath(index, "follow")
+	if err != nil {
+		return err
+	}
+	// wait for the primary shards of the follower, so that it can be paused
+	params := url.Values{"wait_for_active_shards": []string{"1"}}
+	body := map[string]interface{}{
+		"remote_cluster": d.Get("remote_cluster").(string),
+		"leader_index":   d.Get("leader_index").(string),
+	}
+	if _, err := performRequest(ctx, esClient, "PUT", path, params, body); err != nil {
+		return err
+	}
+	d.SetId(index)
+
+	if d.Get("paused").(bool) {
+		if err := setCcrFollowerPaused(ctx, esClient, index, true); err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchCcrFollowerRead(d, meta)
+}
+
+func resourceElasticsearchCcrFollowerRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	info, err := ccrFollower(providerContext(meta), esClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		log.Printf("[WARN] Follower index (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("index", info.FollowerIndex)
+	ds.set("remote_cluster", info.RemoteCluster)
+	ds.set("leader_index", info.LeaderIndex)
+	ds.set("paused", info.Status == ccrFollowerPaused)
+	ds.set("status", info.Status)
+	return ds.err
+}
+
+func resourceElasticsearchCcrFollowerUpdate(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	if d.HasChange("paused") {
+		if err := setCcrFollowerPaused(providerContext(meta), esClient, d.Id(), d.Get("paused").(bool)); err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchCcrFollowerRead(d, meta)
+}
+
+func resourceElasticsearchCcrFollowerDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
+
+	ctx := providerContext(meta)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	info, err := ccrFollower(ctx, esClient, d.Id())
+	if err != nil {
+		return err
+	}
+	// pausing stops the shard follow tasks before the index goes away
+	if info != nil && info.Status == ccrFollowerActive {
+		if err := setCcrFollowerPaused(ctx, esClient, d.Id(), true); err != nil {
+			return err
+		}
+	}
+
+	path, err := uritemplates.Expand("/{index}", map[string]string{
+		"index": d.Id(),
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for follower index: %+v", err)
+	}
+	_, err = performRequest(ctx, esClient, "DELETE", path, nil, nil)
+	if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) && !elastic5.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func ccrFollowerPath(index, api string) (string, error) {
+	path, err := uritemplates.Expand("/{index}/_ccr/{api}", map[string]string{
+		"index": index,
+		"api":   api,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for follower index: %+v", err)
+	}
+	return path, nil
+}
+
+// setCcrFollowerPaused pauses or resumes the replication of a follower index.
+func setCcrFollowerPaused(ctx context.Context, esClient interface{}, index string, paused bool) error {
+	api := "resume_follow"
+	var body interface{} = map[string]interface{}{}
+	if paused {
+		api, body = "pause_follow", nil
+	}
+	path, err := ccrFollowerPath(index, api)
+	if err != nil {
+		return err
+	}
+	_, err = performRequest(ctx, esClient, "POST", path, nil, body)
+	return err
+}
+
+// ccrFollower returns the follower index named index, or nil if it doesn't
+// exist or isn't a follower index.
+func ccrFollower(ctx context.Context, esClient interface{}, index string) (*ccrFollowerInfo, error) {
+	path, err := ccrFollowerPath(index, "info")
+	if err != nil {
+		return nil, err
+	}
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		FollowerIndices []ccrFollowerInfo `json:"follower_indices"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshalling follower index %s: %+v: %s", index, err, res)
+	}
+	for _, f := range response.FollowerIndices {
+		if f.FollowerIndex == index {
+			return &f, nil
+		}
+	}
+	return nil, nil
+}
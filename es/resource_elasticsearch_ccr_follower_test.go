@@ -0,0 +1,101 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestResourceElasticsearchCcrFollower(t *testing.T) {
+	status := ""
+	var requests []string
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version": {"number": "7.17.0"}}`))
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /logs-copy/_ccr/follow":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body["remote_cluster"] != "eu" || body["leader_index"] != "logs" || r.URL.Query().Get("wait_for_active_shards") != "1" {
+				t.Errorf("unexpected follow request: %v %s", body, r.URL.RawQuery)
+			}
+			status = ccrFollowerActive
+			_, _ = w.Write([]byte(`{"follow_index_created": true, "follow_index_shards_acked": true, "index_following_started": true}`))
+		case "POST /logs-copy/_ccr/pause_follow":
+			status = ccrFollowerPaused
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "POST /logs-copy/_ccr/resume_follow":
+			status = ccrFollowerActive
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "GET /logs-copy/_ccr/info":
+			if status == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error": {"type": "index_not_found_exception", "reason": "no such index [logs-copy]"}, "status": 404}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"follower_indices": [{"follower_index": "logs-copy", "remote_cluster": "eu", "leader_index": "logs", "status": "` + status + `"}]}`))
+		case "DELETE /logs-copy":
+			status = ""
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchCcrFollower()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"index":          "logs-copy",
+		"remote_cluster": "eu",
+		"leader_index":   "logs",
+		"paused":         true,
+	})
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "logs-copy" || d.Get("status") != ccrFollowerPaused {
+		t.Errorf("expected a paused follower index, got %s %s", d.Id(), d.Get("status"))
+	}
+
+	_ = d.Set("paused", false)
+	if err := r.Update(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("status") != ccrFollowerActive {
+		t.Errorf("expected the replication to be resumed, got %s", d.Get("status"))
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d.SetId("logs-copy")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the follower index to be gone")
+	}
+
+	expected := []string{
+		"PUT /logs-copy/_ccr/follow",
+		"POST /logs-copy/_ccr/pause_follow",
+		"GET /logs-copy/_ccr/info",
+		"POST /logs-copy/_ccr/resume_follow",
+		"GET /logs-copy/_ccr/info",
+		"GET /logs-copy/_ccr/info",
+		"POST /logs-copy/_ccr/pause_follow",
+		"DELETE /logs-copy",
+		"GET /logs-copy/_ccr/info",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected the requests %v, got %v", expected, requests)
+	}
+}
@@ -0,0 +1,166 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// clusterHealthLevels orders the statuses of the cluster health.
+var clusterHealthLevels = map[string]int{
+	"red":    0,
+	"yellow": 1,
+	"green":  2,
+}
+
+type clusterHealthResponse struct {
+	Status             string `json:"status"`
+	NumberOfNodes      int    `json:"number_of_nodes"`
+	RelocatingShards   int    `json:"relocating_shards"`
+	InitializingShards int    `json:"initializing_shards"`
+	UnassignedShards   int    `json:"unassigned_shards"`
+}
+
+func resourceElasticsearchClusterHealthCheck() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchClusterHealthCheckCreate,
+		Read:   resourceElasticsearchClusterHealthCheckRead,
+		Delete: resourceElasticsearchClusterHealthCheckDelete,
+		Schema: map[string]*schema.Schema{
+			"minimum_status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "yellow",
+				ValidateFunc: validation.StringInSlice([]string{"green", "yellow", "red"}, false),
+				Description:  "Fail unless the health of the cluster, or of `index`, is at least this status, `green`, `yellow` or `red`.",
+			},
+			"max_relocating_shards": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      -1,
+				ValidateFunc: validation.IntAtLeast(-1),
+				Description:  "Fail if more shards than this are relocating, e.g. `0` to wait for rebalancing to finish, `-1` not to check them.",
+			},
+			"index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Only check the health of this index, or comma separated list of indices, with wildcards. Defaults to the whole cluster.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that check the health again when they change, e.g. a hash of the template whose replacement the check gates.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the health when the check passed.",
+			},
+			"number_of_nodes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"relocating_shards": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"initializing_shards": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"unassigned_shards": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Description: "Checks once, when the resource is created or any of its arguments changes, that the cluster is healthy enough, waiting up to the create timeout for it to be, e.g. to gate with `depends_on` destructive changes like the replacement of a template or a reindex.",
+	}
+}
+
+func resourceElasticsearchClusterHealthCheckCreate(d *schema.ResourceData, meta interface{}) error {
+	path := "/_cluster/health"
+	index := d.Get("index").(string)
+	if index != "" {
+		var err error
+		path, err = uritemplates.Expand("/_cluster/health/{index}", map[string]string{
+			"index": index,
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for cluster health: %+v", err)
+		}
+	}
+	minimumStatus := d.Get("minimum_status").(string)
+	maxRelocating := d.Get("max_relocating_shards").(int)
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+
+	var health clusterHealthResponse
+	err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		body, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		health = clusterHealthResponse{}
+		if err := json.Unmarshal(body, &health); err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error unmarshalling cluster health: %+v: %s", err, body))
+		}
+		if err := checkClusterHealth(health, minimumStatus, maxRelocating); err != nil {
+			log.Printf("[DEBUG] Cluster health check failed: %+v", err)
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if index != "" {
+		d.SetId(index)
+	} else {
+		d.SetId("_cluster")
+	}
+	ds := &resourceDataSetter{d: d}
+	ds.set("status", health.Status)
+	ds.set("number_of_nodes", health.NumberOfNodes)
+	ds.set("relocating_shards", health.RelocatingShards)
+	ds.set("initializing_shards", health.InitializingShards)
+	ds.set("unassigned_shards", health.UnassignedShards)
+	return ds.err
+}
+
+// checkClusterHealth returns why the health doesn't pass the check, if it
+// doesn't.
+func checkClusterHealth(health clusterHealthResponse, minimumStatus string, maxRelocating int) error {
+	if level, ok := clusterHealthLevels[health.Status]; !ok || level < clusterHealthLevels[minimumStatus] {
+		return fmt.Errorf("the health of the cluster is %s, %s is required, with %d unassigned and %d initializing shards", health.Status, minimumStatus, health.UnassignedShards, health.InitializingShards)
+	}
+	if maxRelocating >= 0 && health.RelocatingShards > maxRelocating {
+		return fmt.Errorf("%d shards are relocating, at most %d are allowed", health.RelocatingShards, maxRelocating)
+	}
+	return nil
+}
+
+// resourceElasticsearchClusterHealthCheckRead keeps the state of the check,
+// which only gates the changes depending on it when it's created.
+func resourceElasticsearchClusterHealthCheckRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchClusterHealthCheckDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
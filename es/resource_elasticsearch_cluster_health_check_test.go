@@ -0,0 +1,74 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchClusterHealthCheckCreate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_cluster/health/logs-*":
+			_, _ = w.Write([]byte(`{"status": "yellow", "number_of_nodes": 3, "relocating_shards": 1, "initializing_shards": 0, "unassigned_shards": 2}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchClusterHealthCheck()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs-*")
+	_ = d.Set("minimum_status", "yellow")
+	_ = d.Set("max_relocating_shards", 1)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "logs-*" {
+		t.Errorf("unexpected ID: %s", d.Id())
+	}
+	if actual := d.Get("unassigned_shards").(int); actual != 2 {
+		t.Errorf("expected 2 unassigned shards, got %d", actual)
+	}
+}
+
+func TestCheckClusterHealth(t *testing.T) {
+	health := clusterHealthResponse{Status: "yellow", RelocatingShards: 2, UnassignedShards: 1}
+
+	if err := checkClusterHealth(health, "yellow", -1); err != nil {
+		t.Errorf("expected a yellow cluster to pass, got %v", err)
+	}
+	if err := checkClusterHealth(health, "green", -1); err == nil || !strings.Contains(err.Error(), "is yellow, green is required") {
+		t.Errorf("expected a yellow cluster to fail, got %v", err)
+	}
+	if err := checkClusterHealth(health, "red", 1); err == nil || !strings.Contains(err.Error(), "2 shards are relocating") {
+		t.Errorf("expected relocating shards to fail, got %v", err)
+	}
+}
+
+func TestAccElasticsearchClusterHealthCheck(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchClusterHealthCheck,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("elasticsearch_cluster_health_check.test", "status"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchClusterHealthCheck = `
+resource "elasticsearch_cluster_health_check" "test" {
+  minimum_status = "yellow"
+}
+`
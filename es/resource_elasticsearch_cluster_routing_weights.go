@@ -0,0 +1,287 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func resourceElasticsearchClusterRoutingWeights() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceElasticsearchClusterRoutingWeightsCreate,
+		Read:          resourceElasticsearchClusterRoutingWeightsRead,
+		Update:        resourceElasticsearchClusterRoutingWeightsUpdate,
+		Delete:        resourceElasticsearchClusterRoutingWeightsDelete,
+		CustomizeDiff: customizeDiffClusterRoutingWeights,
+		Schema: map[string]*schema.Schema{
+			"attribute": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The awareness attribute of the nodes to weigh, e.g. `zone`, one of the `cluster.routing.allocation.awareness.attributes`.",
+			},
+			"weights": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeFloat},
+				Description: "The share of the search traffic routed to the nodes of each value of the attribute, e.g. `{ zone-a = 1, zone-b = 1, zone-c = 0 }` to drain `zone-c`.",
+			},
+			"decommissioned_value": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The value of the attribute whose nodes are decommissioned, i.e. removed from the cluster, e.g. `zone-c` for a zonal failover. Its weight must be 0. Removing it recommissions the nodes.",
+			},
+			"decommission_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the decommissioning of `decommissioned_value`, e.g. `in_progress`, `successful` or `failed`, empty if no nodes are decommissioned.",
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the weights, incremented by each change, used to fail updates that would overwrite the changes of others.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages the weighted routing of the search traffic of an OpenSearch cluster between the values of an awareness attribute, and the decommissioning of the nodes of one of them, e.g. to drive the zonal failover drills of a cluster spread over availability zones. Destroying the resource recommissions the nodes and removes the weights. Requires OpenSearch >= 2.4.",
+	}
+}
+
+// customizeDiffClusterRoutingWeights fails the plan when the decommissioned
+// value still gets traffic, which the cluster rejects.
+func customizeDiffClusterRoutingWeights(d *schema.ResourceDiff, meta interface{}) error {
+	value := d.Get("decommissioned_value").(string)
+	if value == "" || !d.NewValueKnown("weights") {
+		return nil
+	}
+	weights := d.Get("weights").(map[string]interface{})
+	if weight, ok := weights[value]; !ok || fmt.Sprint(weight) != "0" {
+		return fmt.Errorf("the weight of the decommissioned value %s must be 0", value)
+	}
+	return nil
+}
+
+func resourceElasticsearchClusterRoutingWeightsCreate(d *schema.ResourceData, meta interface{}) error {
+	attribute := d.Get("attribute").(string)
+	// -1 creates the weights, which mustn't exist yet
+	if err := putClusterRoutingWeights(d, meta, -1); err != nil {
+		return err
+	}
+	d.SetId(attribute)
+	if value, ok := d.GetOk("decommissioned_value"); ok {
+		if err := decommissionAwarenessValue(d, meta, value.(string)); err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchClusterRoutingWeightsRead(d, meta)
+}
+
+func resourceElasticsearchClusterRoutingWeightsRead(d *schema.ResourceData, meta interface{}) error {
+	attribute := d.Id()
+	path, err := clusterRoutingWeightsPath(attribute)
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Weights map[string]string `json:"weights"`
+		Version int               `json:"_version"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return fmt.Errorf("error unmarshalling routing weights: %+v: %s", err, res)
+	}
+	if len(response.Weights) == 0 {
+		log.Printf("[WARN] Routing weights (%s) not found, removing from state", attribute)
+		d.SetId("")
+		return nil
+	}
+	weights := map[string]interface{}{}
+	for value, weight := range response.Weights {
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing the weight of %s: %+v", value, err)
+		}
+		weights[value] = w
+	}
+
+	decommissioned, status, err := awarenessDecommissionStatus(ctx, esClient, attribute)
+	if err != nil {
+		return err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("attribute", attribute)
+	ds.set("weights", weights)
+	ds.set("decommissioned_value", decommissioned)
+	ds.set("decommission_status", status)
+	ds.set("version", response.Version)
+	return ds.err
+}
+
+func resourceElasticsearchClusterRoutingWeightsUpdate(d *schema.ResourceData, meta interface{}) error {
+	// recommission first, the weights of a decommissioned value can't change
+	old, new := d.GetChange("decommissioned_value")
+	if old.(string) != new.(string) && old.(string) != "" {
+		if err := recommissionAwarenessValues(meta); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("weights") {
+		if err := putClusterRoutingWeights(d, meta, d.Get("version").(int)); err != nil {
+			return err
+		}
+	}
+	if old.(string) != new.(string) && new.(string) != "" {
+		if err := decommissionAwarenessValue(d, meta, new.(string)); err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchClusterRoutingWeightsRead(d, meta)
+}
+
+func resourceElasticsearchClusterRoutingWeightsDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Get("decommissioned_value").(string) != "" {
+		if err := recommissionAwarenessValues(meta); err != nil {
+			return err
+		}
+	}
+	path, err := clusterRoutingWeightsPath(d.Id())
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"_version": d.Get("version").(int),
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, nil, body)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// putClusterRoutingWeights puts the weights, failing if their version isn't
+// version anymore.
+func putClusterRoutingWeights(d *schema.ResourceData, meta interface{}, version int) error {
+	path, err := clusterRoutingWeightsPath(d.Get("attribute").(string))
+	if err != nil {
+		return err
+	}
+	weights := map[string]string{}
+	for value, weight := range d.Get("weights").(map[string]interface{}) {
+		weights[value] = strconv.FormatFloat(weight.(float64), 'f', -1, 64)
+	}
+	body := map[string]interface{}{
+		"weights":  weights,
+		"_version": version,
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", path, nil, body)
+		return err
+	})
+}
+
+func decommissionAwarenessValue(d *schema.ResourceData, meta interface{}, value string) error {
+	path, err := uritemplates.Expand("/_cluster/decommission/awareness/{attribute}/{value}", map[string]string{
+		"attribute": d.Get("attribute").(string),
+		"value":     value,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for decommission: %+v", err)
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	_, err = performRequest(providerContext(meta), esClient, "PUT", path, nil, nil)
+	return err
+}
+
+// recommissionAwarenessValues recommissions the decommissioned nodes, of
+// whichever attribute.
+func recommissionAwarenessValues(meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", "/_cluster/decommission/awareness", nil, nil)
+		return err
+	})
+}
+
+// awarenessDecommissionStatus returns the decommissioned value of attribute
+// and the status of its decommissioning, empty if there is none.
+func awarenessDecommissionStatus(ctx context.Context, esClient interface{}, attribute string) (string, string, error) {
+	path, err := uritemplates.Expand("/_cluster/decommission/awareness/{attribute}/_status", map[string]string{
+		"attribute": attribute,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("error building URL path for decommission status: %+v", err)
+	}
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	var status map[string]string
+	if err := json.Unmarshal(res, &status); err != nil {
+		return "", "", fmt.Errorf("error unmarshalling decommission status: %+v: %s", err, res)
+	}
+	values := make([]string, 0, len(status))
+	for value := range status {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		return value, status[value], nil
+	}
+	return "", "", nil
+}
+
+func clusterRoutingWeightsPath(attribute string) (string, error) {
+	path, err := uritemplates.Expand("/_cluster/routing/awareness/{attribute}/weights", map[string]string{
+		"attribute": attribute,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for routing weights: %+v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,118 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchClusterRoutingWeights(t *testing.T) {
+	var weights map[string]interface{}
+	version := 0
+	decommissioned := map[string]string{}
+	var requests []string
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/" && r.Method != "GET" {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+		}
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+		case r.URL.Path == "/_cluster/routing/awareness/zone/weights" && r.Method == "PUT":
+			var body struct {
+				Weights map[string]interface{} `json:"weights"`
+				Version int                    `json:"_version"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Version != version && !(version == 0 && body.Version == -1) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": {"type": "version_conflict_engine_exception", "reason": "wrong version"}, "status": 400}`))
+				return
+			}
+			weights, version = body.Weights, version+1
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/routing/awareness/zone/weights" && r.Method == "DELETE":
+			weights = nil
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/routing/awareness/zone/weights":
+			rendered := map[string]interface{}{}
+			for value, weight := range weights {
+				rendered[value] = weight.(string) + ".0"
+			}
+			b, _ := json.Marshal(map[string]interface{}{"weights": rendered, "_version": version, "discovered_cluster_manager": true})
+			_, _ = w.Write(b)
+		case r.URL.Path == "/_cluster/decommission/awareness/zone/zone-c" && r.Method == "PUT":
+			decommissioned["zone-c"] = "successful"
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/decommission/awareness" && r.Method == "DELETE":
+			decommissioned = map[string]string{}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/decommission/awareness/zone/_status":
+			b, _ := json.Marshal(decommissioned)
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchClusterRoutingWeights()
+	config := func(decommissioned string) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"attribute": "zone",
+			"weights":   map[string]interface{}{"zone-a": 1, "zone-b": 1, "zone-c": 0},
+		}
+		if decommissioned != "" {
+			raw["decommissioned_value"] = decommissioned
+		}
+		return terraform.NewResourceConfigRaw(raw)
+	}
+	diff, err := r.Diff(nil, config(""), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if weights["zone-c"] != "0" || state.Attributes["version"] != "1" || state.Attributes["weights.zone-a"] != "1" {
+		t.Errorf("unexpected weights %v, state %v", weights, state.Attributes)
+	}
+	if diff, err = r.Diff(state, config(""), conf); err != nil || !diff.Empty() {
+		t.Errorf("expected no changes, got %v %v", diff, err)
+	}
+
+	diff, err = r.Diff(state, config("zone-c"), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err = r.Apply(state, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Attributes["decommission_status"] != "successful" {
+		t.Errorf("expected zone-c to be decommissioned, got %v", state.Attributes)
+	}
+
+	if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := "PUT /_cluster/routing/awareness/zone/weights,PUT /_cluster/decommission/awareness/zone/zone-c,DELETE /_cluster/decommission/awareness,DELETE /_cluster/routing/awareness/zone/weights"
+	if actual := strings.Join(requests, ","); actual != expected {
+		t.Errorf("expected the requests %s, got %s", expected, actual)
+	}
+
+	_, err = r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"attribute":            "zone",
+		"weights":              map[string]interface{}{"zone-a": 1, "zone-b": 1},
+		"decommissioned_value": "zone-b",
+	}), conf)
+	if err == nil || !strings.Contains(err.Error(), "weight of the decommissioned value zone-b must be 0") {
+		t.Errorf("expected a weight error, got %v", err)
+	}
+}
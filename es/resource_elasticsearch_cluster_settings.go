@@ -0,0 +1,143 @@
+package es
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// clusterSettingsScopes are the arguments of the scopes of cluster settings,
+// named after them.
+var clusterSettingsScopes = []string{"persistent", "transient"}
+
+func resourceElasticsearchClusterSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchClusterSettingsUpdate,
+		Read:   resourceElasticsearchClusterSettingsRead,
+		Update: resourceElasticsearchClusterSettingsUpdate,
+		Delete: resourceElasticsearchClusterSettingsDelete,
+		Schema: map[string]*schema.Schema{
+			"persistent": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The persistent cluster settings, surviving full cluster restarts, by their flat name, e.g. `{ \"cluster.routing.allocation.enable\" = \"primaries\" }`. Lists are joined by commas.",
+			},
+			"transient": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The transient cluster settings, lost on full cluster restarts, by their flat name. Transient settings take precedence over persistent ones, and are deprecated since Elasticsearch 7.16.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchClusterSettingsImport,
+		},
+		Description: "Manages the persistent and transient cluster settings it declares, other settings are left alone. Settings removed from the resource, or all of them when it is destroyed, are reset to their defaults. There is only one per cluster, its ID is `settings`.",
+	}
+}
+
+// resourceElasticsearchClusterSettingsImport imports all the settings set on
+// the cluster, the reads that follow only refreshing the declared ones.
+func resourceElasticsearchClusterSettingsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return nil, err
+	}
+	persistent, transient, err := clusterSettings(providerContext(meta), esClient)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("persistent", flattenClusterSettings(persistent))
+	ds.set("transient", flattenClusterSettings(transient))
+	return []*schema.ResourceData{d}, ds.err
+}
+
+func resourceElasticsearchClusterSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
+	settings := map[string]map[string]interface{}{}
+	for _, scope := range clusterSettingsScopes {
+		// reset the settings removed from the scope
+		old, new := d.GetChange(scope)
+		settings[scope] = map[string]interface{}{}
+		for setting := range old.(map[string]interface{}) {
+			settings[scope][setting] = nil
+		}
+		for setting, v := range new.(map[string]interface{}) {
+			settings[scope][setting] = v
+		}
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		return putClusterSettings(providerContext(meta), esClient, settings["persistent"], settings["transient"])
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId("settings")
+	return resourceElasticsearchClusterSettingsRead(d, meta)
+}
+
+func resourceElasticsearchClusterSettingsRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	persistent, transient, err := clusterSettings(providerContext(meta), esClient)
+	if err != nil {
+		return err
+	}
+	current := map[string]map[string]interface{}{
+		"persistent": persistent,
+		"transient":  transient,
+	}
+
+	// only the declared settings are refreshed, a setting reset outside of
+	// Terraform is missing and set again by the next apply
+	ds := &resourceDataSetter{d: d}
+	for _, scope := range clusterSettingsScopes {
+		settings := map[string]interface{}{}
+		for setting := range d.Get(scope).(map[string]interface{}) {
+			if v, ok := current[scope][setting]; ok {
+				settings[setting] = clusterSettingString(v)
+			}
+		}
+		ds.set(scope, settings)
+	}
+	return ds.err
+}
+
+func resourceElasticsearchClusterSettingsDelete(d *schema.ResourceData, meta interface{}) error {
+	settings := map[string]map[string]interface{}{}
+	for _, scope := range clusterSettingsScopes {
+		settings[scope] = map[string]interface{}{}
+		for setting := range d.Get(scope).(map[string]interface{}) {
+			settings[scope][setting] = nil
+		}
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		return putClusterSettings(providerContext(meta), esClient, settings["persistent"], settings["transient"])
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// flattenClusterSettings returns flat cluster settings as strings.
+func flattenClusterSettings(settings map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(settings))
+	for setting, v := range settings {
+		flat[setting] = clusterSettingString(v)
+	}
+	return flat
+}
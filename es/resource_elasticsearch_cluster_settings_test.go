@@ -0,0 +1,150 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchClusterSettings(t *testing.T) {
+	settings := map[string]map[string]interface{}{
+		"persistent": {"xpack.monitoring.collection.enabled": "true"},
+		"transient":  {},
+	}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_cluster/settings" && r.Method == "PUT":
+			var body map[string]map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			for scope, values := range body {
+				for k, v := range values {
+					if v == nil {
+						delete(settings[scope], k)
+					} else {
+						settings[scope][k] = v
+					}
+				}
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/settings":
+			b, _ := json.Marshal(settings)
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+	assertSettings := func(expected map[string]map[string]interface{}) {
+		t.Helper()
+		b, _ := json.Marshal(settings)
+		e, _ := json.Marshal(expected)
+		if string(b) != string(e) {
+			t.Errorf("expected the settings %s, got %s", e, b)
+		}
+	}
+
+	r := resourceElasticsearchClusterSettings()
+	diff, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"persistent": map[string]interface{}{
+			"cluster.routing.allocation.enable":  "primaries",
+			"indices.recovery.max_bytes_per_sec": "100mb",
+		},
+		"transient": map[string]interface{}{
+			"cluster.routing.rebalance.enable": "none",
+		},
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assertSettings(map[string]map[string]interface{}{
+		"persistent": {
+			"cluster.routing.allocation.enable":   "primaries",
+			"indices.recovery.max_bytes_per_sec":  "100mb",
+			"xpack.monitoring.collection.enabled": "true",
+		},
+		"transient": {"cluster.routing.rebalance.enable": "none"},
+	})
+	if state.ID != "settings" || state.Attributes["persistent.%"] != "2" || state.Attributes["transient.%"] != "1" {
+		t.Errorf("expected only the declared settings to be read back, got %s %v", state.ID, state.Attributes)
+	}
+
+	// removed settings are reset, even when moved to the other scope
+	diff, err = r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"persistent": map[string]interface{}{
+			"cluster.routing.rebalance.enable": "none",
+		},
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state, err = r.Apply(state, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assertSettings(map[string]map[string]interface{}{
+		"persistent": {
+			"cluster.routing.rebalance.enable":    "none",
+			"xpack.monitoring.collection.enabled": "true",
+		},
+		"transient": {},
+	})
+
+	// the import takes all the settings of the cluster
+	d := r.TestResourceData()
+	d.SetId("settings")
+	imported, err := r.Importer.State(d, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := r.Read(imported[0], conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := imported[0].Get("persistent").(map[string]interface{}); len(actual) != 2 || actual["xpack.monitoring.collection.enabled"] != "true" {
+		t.Errorf("expected all the persistent settings to be imported, got %v", actual)
+	}
+
+	if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assertSettings(map[string]map[string]interface{}{
+		"persistent": {"xpack.monitoring.collection.enabled": "true"},
+		"transient":  {},
+	})
+}
+
+func TestAccElasticsearchClusterSettings(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchClusterSettings,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_cluster_settings.test", "persistent.cluster.routing.allocation.enable", "primaries"),
+					resource.TestCheckResourceAttr("elasticsearch_cluster_settings.test", "transient.indices.recovery.max_bytes_per_sec", "50mb"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchClusterSettings = `
+resource "elasticsearch_cluster_settings" "test" {
+  persistent = {
+    "cluster.routing.allocation.enable" = "primaries"
+  }
+  transient = {
+    "indices.recovery.max_bytes_per_sec" = "50mb"
+  }
+}
+`
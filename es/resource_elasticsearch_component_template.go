@@ -6,20 +6,22 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	elastic7 "github.com/olivere/elastic/v7"
 )
 
-var componentTemplateMinimalVersion, _ = version.NewVersion("7.8.0")
-
 func resourceElasticsearchComponentTemplate() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceElasticsearchComponentTemplateCreate,
 		Read:   resourceElasticsearchComponentTemplateRead,
 		Update: resourceElasticsearchComponentTemplateUpdate,
 		Delete: resourceElasticsearchComponentTemplateDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffValidateBody("body", componentTemplateBodySchema),
+			customizeDiffCheckBodyIndexSettings("body", "template", "settings"),
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -34,6 +36,8 @@ func resourceElasticsearchComponentTemplate() *schema.Resource {
 				ValidateFunc:     validation.StringIsJSON,
 				Description:      "The JSON body of the template.",
 			},
+			"request_timeout":     requestTimeoutSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -52,25 +56,21 @@ func resourceElasticsearchComponentTemplateCreate(d *schema.ResourceData, meta i
 }
 
 func resourceElasticsearchComponentTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var result string
-	var elasticVersion *version.Version
 
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		elasticVersion, err = elastic7GetVersion(client)
+		err = capabilityComponentTemplates.check(meta.(*ProviderConf))
 		if err == nil {
-			if elasticVersion.LessThan(componentTemplateMinimalVersion) {
-				err = fmt.Errorf("component_template endpoint only available from ElasticSearch >= 7.8, got version %s", elasticVersion.String())
-			} else {
-				result, err = elastic7GetComponentTemplate(client, id)
-			}
+			result, err = elastic7GetComponentTemplate(ctx, client, id)
 		}
 	default:
 		err = fmt.Errorf("component_template endpoint only available from ElasticSearch >= 7.8, got version < 7.0.0")
@@ -87,12 +87,12 @@ func resourceElasticsearchComponentTemplateRead(d *schema.ResourceData, meta int
 
 	ds := &resourceDataSetter{d: d}
 	ds.set("name", d.Id())
-	ds.set("body", result)
+	ds.set("body", strippedJson(result, stripComposableIndexTemplate))
 	return ds.err
 }
 
-func elastic7GetComponentTemplate(client *elastic7.Client, id string) (string, error) {
-	res, err := client.IndexGetComponentTemplate(id).Do(context.TODO())
+func elastic7GetComponentTemplate(ctx context.Context, client *elastic7.Client, id string) (string, error) {
+	res, err := client.IndexGetComponentTemplate(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -112,24 +112,25 @@ func resourceElasticsearchComponentTemplateUpdate(d *schema.ResourceData, meta i
 }
 
 func resourceElasticsearchComponentTemplateDelete(d *schema.ResourceData, meta interface{}) error {
-	id := d.Id()
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
 
-	var elasticVersion *version.Version
+	ctx := providerContext(meta)
+	id := d.Id()
 
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		elasticVersion, err = elastic7GetVersion(client)
+		err = capabilityComponentTemplates.check(meta.(*ProviderConf))
 		if err == nil {
-			if elasticVersion.LessThan(componentTemplateMinimalVersion) {
-				err = fmt.Errorf("component_template endpoint only available from ElasticSearch >= 7.8, got version %s", elasticVersion.String())
-			} else {
-				err = elastic7DeleteComponentTemplate(client, id)
-			}
+			err = retryOnTransientErrors(meta, func() error {
+				return elastic7DeleteComponentTemplate(ctx, client, id)
+			})
 		}
 	default:
 		err = fmt.Errorf("component_template endpoint only available from ElasticSearch >= 7.8, got version < 7.0.0")
@@ -142,31 +143,28 @@ func resourceElasticsearchComponentTemplateDelete(d *schema.ResourceData, meta i
 	return nil
 }
 
-func elastic7DeleteComponentTemplate(client *elastic7.Client, id string) error {
-	_, err := client.IndexDeleteComponentTemplate(id).Do(context.TODO())
+func elastic7DeleteComponentTemplate(ctx context.Context, client *elastic7.Client, id string) error {
+	_, err := client.IndexDeleteComponentTemplate(id).Do(ctx)
 	return err
 }
 
 func resourceElasticsearchPutComponentTemplate(d *schema.ResourceData, meta interface{}, create bool) error {
+	ctx := providerContext(meta)
 	name := d.Get("name").(string)
 	body := d.Get("body").(string)
 
-	var elasticVersion *version.Version
-
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		elasticVersion, err = elastic7GetVersion(client)
+		err = capabilityComponentTemplates.check(meta.(*ProviderConf))
 		if err == nil {
-			if elasticVersion.LessThan(componentTemplateMinimalVersion) {
-				err = fmt.Errorf("component_template endpoint only available from ElasticSearch >= 7.8, got version %s", elasticVersion.String())
-			} else {
-				err = elastic7PutComponentTemplate(client, name, body, create)
-			}
+			err = retryOnTransientErrors(meta, func() error {
+				return elastic7PutComponentTemplate(ctx, client, name, body, create)
+			})
 		}
 	default:
 		err = fmt.Errorf("component_template endpoint only available from ElasticSearch >= 7.8, got version < 7.0.0")
@@ -175,7 +173,7 @@ func resourceElasticsearchPutComponentTemplate(d *schema.ResourceData, meta inte
 	return err
 }
 
-func elastic7PutComponentTemplate(client *elastic7.Client, name string, body string, create bool) error {
-	_, err := client.IndexPutComponentTemplate(name).BodyString(body).Create(create).Do(context.TODO())
+func elastic7PutComponentTemplate(ctx context.Context, client *elastic7.Client, name string, body string, create bool) error {
+	_, err := client.IndexPutComponentTemplate(name).BodyString(body).Create(create).Do(ctx)
 	return err
 }
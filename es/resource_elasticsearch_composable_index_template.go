@@ -5,21 +5,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
 	elastic7 "github.com/olivere/elastic/v7"
 )
 
-var minimalESComposableTemplateVersion, _ = version.NewVersion("7.8.0")
-
 func resourceElasticsearchComposableIndexTemplate() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceElasticsearchComposableIndexTemplateCreate,
 		Read:   resourceElasticsearchComposableIndexTemplateRead,
 		Update: resourceElasticsearchComposableIndexTemplateUpdate,
 		Delete: resourceElasticsearchComposableIndexTemplateDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffValidateBody("body", composableIndexTemplateBodySchema),
+			customizeDiffCheckComposableIndexTemplatePriority,
+			customizeDiffCheckComposableIndexTemplateFailureStore,
+			customizeDiffCheckBodyIndexSettings("body", "template", "settings"),
+			customizeDiffSimulateComposableIndexTemplate,
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -32,6 +40,20 @@ func resourceElasticsearchComposableIndexTemplate() *schema.Resource {
 				DiffSuppressFunc: diffSuppressComposableIndexTemplate,
 				ValidateFunc:     validation.StringIsJSON,
 			},
+			"failure_store":       failureStoreSchema("The failure store of the data streams created by the template, instead of configuring it in `body`. Requires Elasticsearch 8.15."),
+			"request_timeout":     requestTimeoutSchema(),
+			"deletion_protection": deletionProtectionSchema(),
+			"simulated_template": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON settings, mappings and aliases that new matching indices get, once the component templates are merged, as simulated during plan. Empty if the cluster can't simulate templates.",
+			},
+			"overlapping_templates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The other templates matching some of the same index patterns, with a lower priority, which this template overrides, as simulated during plan.",
+			},
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -49,25 +71,21 @@ func resourceElasticsearchComposableIndexTemplateCreate(d *schema.ResourceData,
 }
 
 func resourceElasticsearchComposableIndexTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var result string
-	var elasticVersion *version.Version
 
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		elasticVersion, err = elastic7GetVersion(client)
+		err = capabilityComposableIndexTemplates.check(meta.(*ProviderConf))
 		if err == nil {
-			if elasticVersion.LessThan(minimalESComposableTemplateVersion) {
-				err = fmt.Errorf("index_template endpoint only available from ElasticSearch >= 7.8, got version %s", elasticVersion.String())
-			} else {
-				result, err = elastic7GetIndexTemplate(client, id)
-			}
+			result, err = elastic7GetIndexTemplate(ctx, client, id)
 		}
 	default:
 		err = fmt.Errorf("index_template endpoint only available from ElasticSearch >= 7.8, got version < 7.0.0")
@@ -84,24 +102,61 @@ func resourceElasticsearchComposableIndexTemplateRead(d *schema.ResourceData, me
 
 	ds := &resourceDataSetter{d: d}
 	ds.set("name", d.Id())
-	ds.set("body", result)
+	if _, ok := d.GetOk("failure_store"); ok {
+		var tpl map[string]interface{}
+		if err := json.Unmarshal([]byte(result), &tpl); err != nil {
+			return fmt.Errorf("error unmarshalling index template %s: %+v: %s", id, err, result)
+		}
+		ds.set("failure_store", popTemplateFailureStore(tpl))
+		b, err := json.Marshal(tpl)
+		if err != nil {
+			return err
+		}
+		result = string(b)
+	}
+	ds.set("body", strippedJson(result, stripComposableIndexTemplate))
+	if capabilityIndexTemplateSimulation.check(meta.(*ProviderConf)) == nil {
+		simulated, overlapping, err := simulateComposableIndexTemplate(ctx, esClient, id, "")
+		if err != nil {
+			log.Printf("[WARN] Error simulating index template (%s): %+v", id, err)
+		} else {
+			ds.set("simulated_template", simulated)
+			ds.set("overlapping_templates", overlapping)
+		}
+	}
 	return ds.err
 }
 
-func elastic7GetIndexTemplate(client *elastic7.Client, id string) (string, error) {
-	res, err := client.IndexGetIndexTemplate(id).Do(context.TODO())
+// elastic7GetIndexTemplate returns the body of an index template as stored,
+// read without the typed client response, which leaves out e.g. its
+// data_stream and data stream options.
+func elastic7GetIndexTemplate(ctx context.Context, client *elastic7.Client, id string) (string, error) {
+	path, err := uritemplates.Expand("/_index_template/{name}", map[string]string{
+		"name": id,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for index template: %+v", err)
+	}
+	res, err := performRequest(ctx, client, "GET", path, nil, nil)
 	if err != nil {
 		return "", err
 	}
+	var response struct {
+		IndexTemplates []struct {
+			Name          string          `json:"name"`
+			IndexTemplate json.RawMessage `json:"index_template"`
+		} `json:"index_templates"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return "", fmt.Errorf("error unmarshalling index template %s: %+v: %s", id, err, res)
+	}
 
 	// No more than 1 element is expected, if the index template is not found, previous call should
 	// return a 404 error
-	t := res.IndexTemplates[0].IndexTemplate
-	tj, err := json.Marshal(t)
-	if err != nil {
-		return "", err
+	if len(response.IndexTemplates) == 0 {
+		return "", fmt.Errorf("index template %s not found: %s", id, res)
 	}
-	return string(tj), nil
+	return compactJson(response.IndexTemplates[0].IndexTemplate), nil
 }
 
 func resourceElasticsearchComposableIndexTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -109,24 +164,25 @@ func resourceElasticsearchComposableIndexTemplateUpdate(d *schema.ResourceData,
 }
 
 func resourceElasticsearchComposableIndexTemplateDelete(d *schema.ResourceData, meta interface{}) error {
-	id := d.Id()
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
 
-	var elasticVersion *version.Version
+	ctx := providerContext(meta)
+	id := d.Id()
 
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		elasticVersion, err = elastic7GetVersion(client)
+		err = capabilityComposableIndexTemplates.check(meta.(*ProviderConf))
 		if err == nil {
-			if elasticVersion.LessThan(minimalESComposableTemplateVersion) {
-				err = fmt.Errorf("index_template endpoint only available from ElasticSearch >= 7.8, got version %s", elasticVersion.String())
-			} else {
-				err = elastic7DeleteIndexTemplate(client, id)
-			}
+			err = retryOnTransientErrors(meta, func() error {
+				return elastic7DeleteIndexTemplate(ctx, client, id)
+			})
 		}
 	default:
 		err = fmt.Errorf("index_template endpoint only available from ElasticSearch >= 7.8, got version < 7.0.0")
@@ -139,31 +195,42 @@ func resourceElasticsearchComposableIndexTemplateDelete(d *schema.ResourceData,
 	return nil
 }
 
-func elastic7DeleteIndexTemplate(client *elastic7.Client, id string) error {
-	_, err := client.IndexDeleteIndexTemplate(id).Do(context.TODO())
+func elastic7DeleteIndexTemplate(ctx context.Context, client *elastic7.Client, id string) error {
+	_, err := client.IndexDeleteIndexTemplate(id).Do(ctx)
 	return err
 }
 
 func resourceElasticsearchPutComposableIndexTemplate(d *schema.ResourceData, meta interface{}, create bool) error {
+	ctx := providerContext(meta)
 	name := d.Get("name").(string)
 	body := d.Get("body").(string)
 
-	var elasticVersion *version.Version
-
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
+	if v, ok := d.GetOk("failure_store"); ok {
+		var tpl map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &tpl); err != nil {
+			return fmt.Errorf("error unmarshalling index template %s: %+v", name, err)
+		}
+		if err := putTemplateFailureStore(meta.(*ProviderConf), tpl, v.([]interface{})[0].(map[string]interface{})); err != nil {
+			return err
+		}
+		b, err := json.Marshal(tpl)
+		if err != nil {
+			return err
+		}
+		body = string(b)
+	}
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		elasticVersion, err = elastic7GetVersion(client)
+		err = capabilityComposableIndexTemplates.check(meta.(*ProviderConf))
 		if err == nil {
-			if elasticVersion.LessThan(minimalESComposableTemplateVersion) {
-				err = fmt.Errorf("index_template endpoint only available from ElasticSearch >= 7.8, got version %s", elasticVersion.String())
-			} else {
-				err = elastic7PutIndexTemplate(client, name, body, create)
-			}
+			err = retryOnTransientErrors(meta, func() error {
+				return elastic7PutIndexTemplate(ctx, client, name, body, create)
+			})
 		}
 	default:
 		err = fmt.Errorf("index_template endpoint only available from ElasticSearch >= 7.8, got version < 7.0.0")
@@ -172,7 +239,218 @@ func resourceElasticsearchPutComposableIndexTemplate(d *schema.ResourceData, met
 	return err
 }
 
-func elastic7PutIndexTemplate(client *elastic7.Client, name string, body string, create bool) error {
-	_, err := client.IndexPutIndexTemplate(name).BodyString(body).Create(create).Do(context.TODO())
+func elastic7PutIndexTemplate(ctx context.Context, client *elastic7.Client, name string, body string, create bool) error {
+	_, err := client.IndexPutIndexTemplate(name).BodyString(body).Create(create).Do(ctx)
 	return err
 }
+
+// customizeDiffCheckComposableIndexTemplatePriority fails the plan when the
+// template has the same priority as another template of the cluster matching
+// some of the same index patterns, which the cluster would otherwise only
+// reject, or resolve ambiguously, when the template is put or much later
+// when a matching index gets created.
+func customizeDiffCheckComposableIndexTemplatePriority(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("body") {
+		return nil
+	}
+	if !d.NewValueKnown("body") || !d.NewValueKnown("name") {
+		return nil
+	}
+
+	var template composableIndexTemplatePriority
+	if err := json.Unmarshal([]byte(d.Get("body").(string)), &template); err != nil {
+		// reported by the validation of the body
+		return nil
+	}
+
+	conf := meta.(*ProviderConf)
+	esClient, err := getClient(conf)
+	if err != nil {
+		log.Printf("[WARN] Skipping the priority check of the index template, the cluster can't be reached: %+v", err)
+		return nil
+	}
+	if err := capabilityComposableIndexTemplates.check(conf); err != nil {
+		return nil
+	}
+
+	name := d.Get("name").(string)
+	collisions, err := composableIndexTemplatePriorityCollisions(providerContext(meta), esClient, name, template)
+	if err != nil {
+		return fmt.Errorf("error checking the priority of index template %s: %+v", name, err)
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("index template %s has the same priority %d as the index templates %s matching some of the same index patterns %v, give them different priorities", name, template.Priority, strings.Join(collisions, ", "), template.IndexPatterns)
+	}
+	return nil
+}
+
+// customizeDiffCheckComposableIndexTemplateFailureStore fails the plan when
+// the failure store is configured both in the body and in the failure_store
+// block, or can't be configured on the cluster.
+func customizeDiffCheckComposableIndexTemplateFailureStore(d *schema.ResourceDiff, meta interface{}) error {
+	v, ok := d.GetOk("failure_store")
+	if !ok || (!d.HasChange("failure_store") && !d.HasChange("body")) {
+		return nil
+	}
+	if d.NewValueKnown("body") {
+		var tpl struct {
+			DataStream map[string]interface{} `json:"data_stream"`
+			Template   struct {
+				DataStreamOptions map[string]interface{} `json:"data_stream_options"`
+			} `json:"template"`
+		}
+		if err := json.Unmarshal([]byte(d.Get("body").(string)), &tpl); err == nil {
+			_, inDataStream := tpl.DataStream["failure_store"]
+			_, inOptions := tpl.Template.DataStreamOptions["failure_store"]
+			if inDataStream || inOptions {
+				return fmt.Errorf("the failure store of index template %s is configured both in body and in failure_store, remove it from body", d.Get("name"))
+			}
+		}
+	}
+
+	conf := meta.(*ProviderConf)
+	if _, err := getClient(conf); err != nil {
+		log.Printf("[WARN] Skipping the version check of the failure store, the cluster can't be reached: %+v", err)
+		return nil
+	}
+	block, ok := v.([]interface{})[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return checkFailureStore(conf, block)
+}
+
+// composableIndexTemplatePriority is the part of a composable index template
+// deciding which template applies to a new index.
+type composableIndexTemplatePriority struct {
+	IndexPatterns []string `json:"index_patterns"`
+	Priority      int64    `json:"priority"`
+}
+
+// composableIndexTemplatePriorityCollisions returns the names of the other
+// composable index templates of the cluster with the same priority as
+// template and an overlapping index pattern, sorted.
+func composableIndexTemplatePriorityCollisions(ctx context.Context, esClient interface{}, name string, template composableIndexTemplatePriority) ([]string, error) {
+	res, err := performRequest(ctx, esClient, "GET", "/_index_template", nil, nil)
+	if err != nil {
+		if elastic7.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var existing struct {
+		IndexTemplates []struct {
+			Name          string                          `json:"name"`
+			IndexTemplate composableIndexTemplatePriority `json:"index_template"`
+		} `json:"index_templates"`
+	}
+	if err := json.Unmarshal(res, &existing); err != nil {
+		return nil, fmt.Errorf("error unmarshalling index templates: %+v: %s", err, res)
+	}
+
+	collisions := []string{}
+	for _, t := range existing.IndexTemplates {
+		if t.Name == name || t.IndexTemplate.Priority != template.Priority {
+			continue
+		}
+	patterns:
+		for _, a := range template.IndexPatterns {
+			for _, b := range t.IndexTemplate.IndexPatterns {
+				if indexPatternsOverlap(a, b) {
+					collisions = append(collisions, t.Name)
+					break patterns
+				}
+			}
+		}
+	}
+	sort.Strings(collisions)
+	return collisions, nil
+}
+
+// customizeDiffSimulateComposableIndexTemplate simulates the template
+// whenever its body changes, so that the plan shows what the matching indices
+// will get and which templates it overlaps, and fails on invalid
+// compositions, e.g. conflicting mappings of the component templates.
+func customizeDiffSimulateComposableIndexTemplate(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("body") {
+		return nil
+	}
+	unknown := func() error {
+		if err := d.SetNewComputed("simulated_template"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("overlapping_templates")
+	}
+	if !d.NewValueKnown("body") || !d.NewValueKnown("name") {
+		return unknown()
+	}
+
+	conf := meta.(*ProviderConf)
+	esClient, err := getClient(conf)
+	if err != nil {
+		log.Printf("[WARN] Skipping the simulation of the index template, the cluster can't be reached: %+v", err)
+		return unknown()
+	}
+	if err := capabilityIndexTemplateSimulation.check(conf); err != nil {
+		return unknown()
+	}
+
+	name := d.Get("name").(string)
+	simulated, overlapping, err := simulateComposableIndexTemplate(providerContext(meta), esClient, name, d.Get("body").(string))
+	if err != nil {
+		// component templates created in the same apply don't exist yet
+		if strings.Contains(err.Error(), "that do not exist") {
+			log.Printf("[WARN] Skipping the simulation of the index template %s: %+v", name, err)
+			return unknown()
+		}
+		return fmt.Errorf("error simulating index template %s: %+v", name, err)
+	}
+	for _, o := range overlapping {
+		log.Printf("[WARN] Index template %s overlaps with the lower priority index template %s", name, o)
+	}
+
+	if err := d.SetNew("simulated_template", simulated); err != nil {
+		return err
+	}
+	return d.SetNew("overlapping_templates", overlapping)
+}
+
+// simulateComposableIndexTemplate returns the JSON of the template that new
+// indices matching the index template get, and the names of the other
+// templates it overlaps. The template named name is simulated as stored, or
+// as body if set.
+func simulateComposableIndexTemplate(ctx context.Context, esClient interface{}, name string, body string) (string, []string, error) {
+	path, err := uritemplates.Expand("/_index_template/_simulate/{name}", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("error building URL path for index template simulation: %+v", err)
+	}
+	var requestBody interface{}
+	if body != "" {
+		requestBody = json.RawMessage(body)
+	}
+
+	res, err := performRequest(ctx, esClient, "POST", path, nil, requestBody)
+	if err != nil {
+		return "", nil, err
+	}
+	var simulation struct {
+		Template    json.RawMessage `json:"template"`
+		Overlapping []struct {
+			Name string `json:"name"`
+		} `json:"overlapping"`
+	}
+	if err := json.Unmarshal(res, &simulation); err != nil {
+		return "", nil, fmt.Errorf("error unmarshalling index template simulation: %+v: %s", err, res)
+	}
+
+	overlapping := []string{}
+	for _, o := range simulation.Overlapping {
+		if o.Name != name {
+			overlapping = append(overlapping, o.Name)
+		}
+	}
+	sort.Strings(overlapping)
+	return compactJson(simulation.Template), overlapping, nil
+}
@@ -2,8 +2,12 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 
 	elastic7 "github.com/olivere/elastic/v7"
@@ -47,6 +51,7 @@ func TestAccElasticsearchComposableIndexTemplate(t *testing.T) {
 				Config: testAccElasticsearchComposableIndexTemplate,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchComposableIndexTemplateExists("elasticsearch_composable_index_template.test"),
+					resource.TestCheckResourceAttrSet("elasticsearch_composable_index_template.test", "simulated_template"),
 				),
 			},
 		},
@@ -95,6 +100,138 @@ func TestAccElasticsearchComposableIndexTemplate_importBasic(t *testing.T) {
 	})
 }
 
+func TestResourceElasticsearchComposableIndexTemplateSimulate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_index_template":
+			_, _ = w.Write([]byte(`{"index_templates": []}`))
+		case "/_index_template/_simulate/logs":
+			_, _ = w.Write([]byte(`{
+  "template": {"settings": {"index": {"number_of_shards": "2"}}, "mappings": {}, "aliases": {}},
+  "overlapping": [{"name": "logs", "index_patterns": ["logs-*"]}, {"name": "legacy", "index_patterns": ["logs-*"]}]
+}`))
+		case "/_index_template/_simulate/broken":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"type": "illegal_argument_exception", "reason": "composable template [broken] template after composition is invalid"}, "status": 400}`))
+		case "/_index_template/_simulate/pending":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"type": "invalid_index_template_exception", "reason": "index_template [pending] invalid, cause [index template [pending] specifies component templates [base] that do not exist]"}, "status": 400}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchComposableIndexTemplate()
+	diff := func(name string) (*terraform.InstanceDiff, error) {
+		return r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name": name,
+			"body": `{"index_patterns": ["logs-*"], "composed_of": ["base"], "priority": 10}`,
+		}), conf)
+	}
+
+	d, err := diff("logs")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Attributes["simulated_template"].New; actual != `{"settings":{"index":{"number_of_shards":"2"}},"mappings":{},"aliases":{}}` {
+		t.Errorf("unexpected simulated_template: %s", actual)
+	}
+	if actual := d.Attributes["overlapping_templates.#"].New; actual != "1" {
+		t.Errorf("expected 1 overlapping template, got %s", actual)
+	}
+	if actual := d.Attributes["overlapping_templates.0"].New; actual != "legacy" {
+		t.Errorf("expected the overlapping template legacy, got %s", actual)
+	}
+
+	if _, err := diff("broken"); err == nil || !strings.Contains(err.Error(), "after composition is invalid") {
+		t.Errorf("expected the plan to fail, got %v", err)
+	}
+
+	d, err = diff("pending")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !d.Attributes["simulated_template"].NewComputed {
+		t.Errorf("expected simulated_template to be computed")
+	}
+}
+
+func TestResourceElasticsearchComposableIndexTemplateOpenSearch(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			// a tagline customized by the distribution
+			_, _ = w.Write([]byte(`{"version": {"number": "2.11.0"}, "tagline": "You Know, for Search"}`))
+		case "/_index_template/logs":
+			_, _ = w.Write([]byte(`{"index_templates": [{"name": "logs", "index_template": {"index_patterns": ["logs-*"], "priority": 10}}]}`))
+		case "/_index_template/_simulate/logs":
+			_, _ = w.Write([]byte(`{"template": {"settings": {}, "mappings": {}, "aliases": {}}, "overlapping": []}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchComposableIndexTemplate()
+	d := r.TestResourceData()
+	d.SetId("logs")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Get("body"); actual != `{"index_patterns":["logs-*"],"priority":10}` {
+		t.Errorf("unexpected body: %s", actual)
+	}
+	if _, flavor := clusterVersion(conf); flavor != flavorOpenSearch {
+		t.Errorf("expected the cluster to be detected as OpenSearch, got %q", flavor)
+	}
+}
+
+func TestResourceElasticsearchComposableIndexTemplatePriority(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.8.1"}}`))
+		case "/_index_template":
+			_, _ = w.Write([]byte(`{"index_templates": [
+  {"name": "logs", "index_template": {"index_patterns": ["logs-*"], "priority": 10}},
+  {"name": "logs-app", "index_template": {"index_patterns": ["logs-app-*"], "priority": 10}},
+  {"name": "logs-default", "index_template": {"index_patterns": ["logs-*"]}},
+  {"name": "metrics", "index_template": {"index_patterns": ["metrics-*"], "priority": 10}}
+]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchComposableIndexTemplate()
+	diff := func(name, body string) error {
+		_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name": name,
+			"body": body,
+		}), conf)
+		return err
+	}
+
+	err := diff("logs", `{"index_patterns": ["logs-*"], "priority": 10}`)
+	if err == nil || !strings.Contains(err.Error(), "same priority 10 as the index templates logs-app") {
+		t.Errorf("expected a priority collision with logs-app, got %v", err)
+	}
+	err = diff("logs-web", `{"index_patterns": ["logs-web-*"]}`)
+	if err == nil || !strings.Contains(err.Error(), "index templates logs-default ") {
+		t.Errorf("expected a priority collision with logs-default, got %v", err)
+	}
+	if err := diff("logs-web", `{"index_patterns": ["logs-web-*"], "priority": 20}`); err != nil {
+		t.Errorf("err: %s", err)
+	}
+	if err := diff("traces", `{"index_patterns": ["traces-*"], "priority": 10}`); err != nil {
+		t.Errorf("err: %s", err)
+	}
+}
+
 func testCheckElasticsearchComposableIndexTemplateExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[name]
@@ -190,3 +327,90 @@ resource "elasticsearch_composable_index_template" "test" {
 EOF
 }
 `
+
+func TestResourceElasticsearchComposableIndexTemplateFailureStore(t *testing.T) {
+	var stored json.RawMessage
+	handler := func(version string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/":
+				_, _ = w.Write([]byte(`{"version": {"number": "` + version + `"}}`))
+			case r.URL.Path == "/_index_template":
+				_, _ = w.Write([]byte(`{"index_templates": []}`))
+			case r.URL.Path == "/_index_template/_simulate/logs":
+				_, _ = w.Write([]byte(`{"template": {"settings": {}, "mappings": {}, "aliases": {}}, "overlapping": [{"name": "legacy", "index_patterns": ["logs-*"]}]}`))
+			case r.URL.Path == "/_index_template/logs" && r.Method == "PUT":
+				stored, _ = ioutil.ReadAll(r.Body)
+				_, _ = w.Write([]byte(`{"acknowledged": true}`))
+			case r.URL.Path == "/_index_template/logs":
+				b, _ := json.Marshal(map[string]interface{}{
+					"index_templates": []interface{}{
+						map[string]interface{}{"name": "logs", "index_template": stored},
+					},
+				})
+				_, _ = w.Write(b)
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			}
+		}
+	}
+	conf := testProviderConf(t, handler("8.19.0"))
+
+	r := resourceElasticsearchComposableIndexTemplate()
+	config := func(body string, retention string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name": "logs",
+			"body": body,
+			"failure_store": []interface{}{
+				map[string]interface{}{"enabled": true, "retention": retention},
+			},
+		})
+	}
+	body := `{"index_patterns": ["logs-*"], "data_stream": {}, "priority": 10}`
+	apply := func(c *terraform.ResourceConfig) *terraform.InstanceState {
+		t.Helper()
+		diff, err := r.Diff(nil, c, conf)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		state, err := r.Apply(nil, diff, conf)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		diff, err = r.Diff(state, c, conf)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !diff.Empty() {
+			for k, a := range diff.Attributes {
+				t.Errorf("expected no changes, got %s: %#v", k, a)
+			}
+		}
+		return state
+	}
+
+	state := apply(config(body, "7d"))
+	expected := `{"index_patterns": ["logs-*"], "data_stream": {}, "priority": 10, "template": {"data_stream_options": {"failure_store": {"enabled": true, "lifecycle": {"data_retention": "7d"}}}}}`
+	if !suppressEquivalentJson("", expected, string(stored), nil) {
+		t.Errorf("expected the template %s, got %s", expected, stored)
+	}
+	if state.Attributes["failure_store.0.retention"] != "7d" || !suppressEquivalentJson("", body, state.Attributes["body"], nil) {
+		t.Errorf("expected the failure store to be read back out of the body, got %v", state.Attributes)
+	}
+
+	conf = testProviderConf(t, handler("8.15.0"))
+	_ = apply(config(body, ""))
+	expected = `{"index_patterns": ["logs-*"], "data_stream": {"failure_store": true}, "priority": 10}`
+	if !suppressEquivalentJson("", expected, string(stored), nil) {
+		t.Errorf("expected the template %s, got %s", expected, stored)
+	}
+	if _, err := r.Diff(nil, config(body, "7d"), conf); err == nil || !strings.Contains(err.Error(), "data stream options require Elasticsearch >= 8.19.0") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+
+	withFailureStore := `{"index_patterns": ["logs-*"], "data_stream": {"failure_store": true}}`
+	if _, err := r.Diff(nil, config(withFailureStore, ""), conf); err == nil || !strings.Contains(err.Error(), "both in body and in failure_store") {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func resourceElasticsearchDataStreamFailureStore() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchDataStreamFailureStorePut,
+		Read:   resourceElasticsearchDataStreamFailureStoreRead,
+		Update: resourceElasticsearchDataStreamFailureStorePut,
+		Delete: resourceElasticsearchDataStreamFailureStoreDelete,
+		Schema: map[string]*schema.Schema{
+			"data_stream": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the data stream.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the documents failing ingestion are stored in the failure store of the data stream.",
+			},
+			"retention": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "How long the failed documents are kept, e.g. `7d`. Defaults to the retention of the cluster.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Enables or disables the failure store of an existing data stream, overriding its template, so that the documents failing ingestion, e.g. because of mapping conflicts or failing pipelines, are kept instead of being rejected. Destroying the resource reverts the data stream to the failure store of its template. Requires Elasticsearch >= 8.19.",
+	}
+}
+
+func resourceElasticsearchDataStreamFailureStorePut(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("data_stream").(string)
+	path, err := dataStreamOptionsPath(name)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"failure_store": expandFailureStore(map[string]interface{}{
+			"enabled":   d.Get("enabled"),
+			"retention": d.Get("retention"),
+		}),
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", path, nil, body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(name)
+	return resourceElasticsearchDataStreamFailureStoreRead(d, meta)
+}
+
+func resourceElasticsearchDataStreamFailureStoreRead(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	path, err := dataStreamOptionsPath(id)
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	res, err := performRequest(providerContext(meta), esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) {
+		log.Printf("[WARN] Data stream (%s) not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var response struct {
+		DataStreams []struct {
+			Name    string `json:"name"`
+			Options struct {
+				FailureStore map[string]interface{} `json:"failure_store"`
+			} `json:"options"`
+		} `json:"data_streams"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return fmt.Errorf("error unmarshalling data stream options: %+v: %s", err, res)
+	}
+	for _, dataStream := range response.DataStreams {
+		if dataStream.Name != id {
+			continue
+		}
+		failureStore := flattenFailureStore(dataStream.Options.FailureStore)[0].(map[string]interface{})
+		ds := &resourceDataSetter{d: d}
+		ds.set("data_stream", id)
+		ds.set("enabled", failureStore["enabled"])
+		ds.set("retention", failureStore["retention"])
+		return ds.err
+	}
+
+	log.Printf("[WARN] Data stream (%s) not found, removing from state", id)
+	d.SetId("")
+	return nil
+}
+
+func resourceElasticsearchDataStreamFailureStoreDelete(d *schema.ResourceData, meta interface{}) error {
+	path, err := dataStreamOptionsPath(d.Id())
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, nil, nil)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func dataStreamOptionsPath(name string) (string, error) {
+	path, err := uritemplates.Expand("/_data_stream/{name}/_options", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for data stream options: %+v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,58 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestResourceElasticsearchDataStreamFailureStore(t *testing.T) {
+	var options map[string]interface{}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "9.1.0"}}`))
+		case r.URL.Path == "/_data_stream/logs-app-default/_options" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_data_stream/logs-app-default/_options" && r.Method == "DELETE":
+			options = nil
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_data_stream/logs-app-default/_options":
+			b, _ := json.Marshal(map[string]interface{}{
+				"data_streams": []interface{}{
+					map[string]interface{}{"name": "logs-app-default", "options": options},
+				},
+			})
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchDataStreamFailureStore()
+	d := r.TestResourceData()
+	_ = d.Set("data_stream", "logs-app-default")
+	_ = d.Set("enabled", true)
+	_ = d.Set("retention", "7d")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	b, _ := json.Marshal(options)
+	if string(b) != `{"failure_store":{"enabled":true,"lifecycle":{"data_retention":"7d"}}}` {
+		t.Errorf("unexpected options: %s", b)
+	}
+	if d.Id() != "logs-app-default" || d.Get("enabled") != true || d.Get("retention") != "7d" {
+		t.Errorf("expected the failure store to be read back, got %s %v", d.Id(), d.State())
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if options != nil {
+		t.Errorf("expected the options to be reset, got %v", options)
+	}
+}
@@ -0,0 +1,87 @@
+package es
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceElasticsearchDeleteByQuery() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchDeleteByQueryCreate,
+		Read:   resourceElasticsearchByQueryRead,
+		Delete: resourceElasticsearchByQueryDelete,
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The index, data stream or alias to delete documents from, or comma separated list of them, with wildcards.",
+			},
+			"query": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON query selecting the documents to delete, e.g. `{\"term\": {\"tenant\": \"acme\"}}`.",
+			},
+			"conflicts":           byQueryConflictsSchema(),
+			"slices":              byQuerySlicesSchema(),
+			"wait_for_completion": byQueryWaitForCompletionSchema(),
+			"triggers":            byQueryTriggersSchema(),
+			"task_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the task running the deletion.",
+			},
+			"total": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents processed, 0 if `wait_for_completion` is false.",
+			},
+			"deleted": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents deleted, 0 if `wait_for_completion` is false.",
+			},
+			"version_conflicts": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents that changed while they were deleted, 0 if `wait_for_completion` is false.",
+			},
+		},
+		Description: "Runs `_delete_by_query` once, when the resource is created or any of its arguments changes, e.g. to purge the documents of an offboarded tenant. Destroying the resource doesn't restore the documents.",
+	}
+}
+
+func resourceElasticsearchDeleteByQueryCreate(d *schema.ResourceData, meta interface{}) error {
+	body := map[string]interface{}{
+		"query": json.RawMessage(d.Get("query").(string)),
+	}
+	params := url.Values{
+		"conflicts": []string{d.Get("conflicts").(string)},
+		"slices":    []string{d.Get("slices").(string)},
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	taskId, result, err := runByQuery(providerContext(meta), esClient, "_delete_by_query", d.Get("index").(string), params, body, d.Get("wait_for_completion").(bool), d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(taskId)
+	ds := &resourceDataSetter{d: d}
+	ds.set("task_id", taskId)
+	if result != nil {
+		ds.set("total", result.Total)
+		ds.set("deleted", result.Deleted)
+		ds.set("version_conflicts", result.VersionConflicts)
+	}
+	return ds.err
+}
@@ -0,0 +1,123 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchDeleteByQueryCreate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/tenants-*/_delete_by_query":
+			var body struct {
+				Query map[string]interface{} `json:"query"`
+			}
+			raw, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(raw, &body); err != nil || body.Query["term"] == nil {
+				t.Errorf("unexpected body: %s", raw)
+			}
+			_, _ = w.Write([]byte(`{"task": "node-1:7"}`))
+		case "/_tasks/node-1:7":
+			_, _ = w.Write([]byte(`{"completed": true, "response": {"total": 12, "deleted": 12, "version_conflicts": 0, "failures": []}}`))
+		case "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchDeleteByQuery()
+	d := r.TestResourceData()
+	_ = d.Set("index", "tenants-*")
+	_ = d.Set("query", `{"term": {"tenant": "acme"}}`)
+	_ = d.Set("conflicts", "proceed")
+	_ = d.Set("slices", "1")
+	_ = d.Set("wait_for_completion", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "node-1:7" {
+		t.Errorf("unexpected ID: %s", d.Id())
+	}
+	if deleted := d.Get("deleted").(int); deleted != 12 {
+		t.Errorf("expected deleted to be 12, got %d", deleted)
+	}
+}
+
+func TestResourceElasticsearchDeleteByQueryCreateNoWait(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/tenants-*/_delete_by_query":
+			_, _ = w.Write([]byte(`{"task": "node-1:8"}`))
+		case "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchDeleteByQuery()
+	d := r.TestResourceData()
+	_ = d.Set("index", "tenants-*")
+	_ = d.Set("query", `{"match_all": {}}`)
+	_ = d.Set("conflicts", "abort")
+	_ = d.Set("slices", "1")
+	_ = d.Set("wait_for_completion", false)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if taskId := d.Get("task_id").(string); taskId != "node-1:8" {
+		t.Errorf("unexpected task_id: %s", taskId)
+	}
+	if deleted := d.Get("deleted").(int); deleted != 0 {
+		t.Errorf("expected deleted to be 0, got %d", deleted)
+	}
+}
+
+func TestAccElasticsearchDeleteByQuery(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDeleteByQuery,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_delete_by_query.test", "deleted", "1"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchDeleteByQuery = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-delete-by-query"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  force_destroy      = true
+}
+
+resource "elasticsearch_bulk_documents" "test" {
+  index     = elasticsearch_index.test.name
+  documents = "${jsonencode({ tenant = "acme" })}\n${jsonencode({ tenant = "other" })}"
+}
+
+resource "elasticsearch_delete_by_query" "test" {
+  index = elasticsearch_bulk_documents.test.index
+  query = jsonencode({ term = { "tenant.keyword" = "acme" } })
+}
+`
@@ -0,0 +1,237 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+type documentResponse struct {
+	Version     int64           `json:"_version"`
+	SeqNo       *int64          `json:"_seq_no"`
+	PrimaryTerm *int64          `json:"_primary_term"`
+	Source      json.RawMessage `json:"_source"`
+}
+
+func resourceElasticsearchDocument() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchDocumentCreate,
+		Read:   resourceElasticsearchDocumentRead,
+		Update: resourceElasticsearchDocumentUpdate,
+		Delete: resourceElasticsearchDocumentDelete,
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The index of the document, created with the default settings and dynamic mappings if it doesn't exist.",
+			},
+			"document_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the document.",
+			},
+			"body": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON source of the document, replacing the whole document when it changes.",
+			},
+			"refresh": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "false",
+				ValidateFunc: validation.StringInSlice([]string{"true", "false", "wait_for"}, false),
+				Description:  "Whether to make the changes visible to searches before returning: `true` refreshes the affected shards, `wait_for` waits for the next refresh.",
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Delete the document when the resource is destroyed, otherwise only remove it from the state.",
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the document, incremented on every change.",
+			},
+			"seq_no": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The sequence number of the last change of the document. Updates fail if the document changed since it was read.",
+			},
+			"primary_term": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchDocumentImport,
+		},
+		Description: "Manages a single JSON document at a known index and ID, e.g. configuration documents read by an application, or the source records of an enrich policy.",
+	}
+}
+
+func resourceElasticsearchDocumentCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutDocument(d, meta, false); err != nil {
+		return err
+	}
+	d.SetId(documentId(d.Get("index").(string), d.Get("document_id").(string)))
+	return resourceElasticsearchDocumentRead(d, meta)
+}
+
+func resourceElasticsearchDocumentRead(d *schema.ResourceData, meta interface{}) error {
+	index, id, err := parseDocumentId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, err := documentPath(index, id)
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(meta), esClient, "GET", path, nil, nil)
+	if err != nil {
+		if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) {
+			log.Printf("[WARN] Document (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	var doc documentResponse
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("error unmarshalling document: %+v: %s", err, body)
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("index", index)
+	ds.set("document_id", id)
+	ds.set("body", compactJson(doc.Source))
+	ds.set("version", doc.Version)
+	if doc.SeqNo != nil && doc.PrimaryTerm != nil {
+		ds.set("seq_no", *doc.SeqNo)
+		ds.set("primary_term", *doc.PrimaryTerm)
+	}
+	return ds.err
+}
+
+func resourceElasticsearchDocumentUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutDocument(d, meta, true); err != nil {
+		return err
+	}
+	return resourceElasticsearchDocumentRead(d, meta)
+}
+
+func resourceElasticsearchDocumentDelete(d *schema.ResourceData, meta interface{}) error {
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[INFO] Leaving document (%s) in the cluster, delete_on_destroy is false", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	index, id, err := parseDocumentId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, err := documentPath(index, id)
+	if err != nil {
+		return err
+	}
+	params := url.Values{"refresh": []string{d.Get("refresh").(string)}}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, params, nil)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceElasticsearchPutDocument indexes the document, replacing it. When
+// updating, the write is conditional on the sequence number read last, so
+// that changes made outside of Terraform since aren't overwritten silently.
+func resourceElasticsearchPutDocument(d *schema.ResourceData, meta interface{}, update bool) error {
+	index := d.Get("index").(string)
+	id := d.Get("document_id").(string)
+	path, err := documentPath(index, id)
+	if err != nil {
+		return err
+	}
+	params := url.Values{"refresh": []string{d.Get("refresh").(string)}}
+	if update && d.Get("primary_term").(int) > 0 {
+		params.Set("if_seq_no", strconv.Itoa(d.Get("seq_no").(int)))
+		params.Set("if_primary_term", strconv.Itoa(d.Get("primary_term").(int)))
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", path, params, json.RawMessage(d.Get("body").(string)))
+		// unlike the other conflicts, this one doesn't go away when retrying
+		if elastic7.IsConflict(err) || elastic6.IsConflict(err) {
+			return fmt.Errorf("document %s/%s was changed since it was read, refresh and apply again: %+v", index, id, err)
+		}
+		return err
+	})
+}
+
+func resourceElasticsearchDocumentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseDocumentId(d.Id()); err != nil {
+		return nil, err
+	}
+	ds := &resourceDataSetter{d: d}
+	ds.set("refresh", "false")
+	ds.set("delete_on_destroy", true)
+	return []*schema.ResourceData{d}, ds.err
+}
+
+func documentId(index, id string) string {
+	return index + "/" + id
+}
+
+// parseDocumentId splits an ID made of the index and the document ID, which,
+// unlike index names, can contain slashes.
+func parseDocumentId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid document ID %q, expected <index>/<document_id>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func documentPath(index, id string) (string, error) {
+	path, err := uritemplates.Expand("/{index}/_doc/{id}", map[string]string{
+		"index": index,
+		"id":    id,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for document: %+v", err)
+	}
+	return path, nil
+}
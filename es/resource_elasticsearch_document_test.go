@@ -0,0 +1,152 @@
+package es
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func TestResourceElasticsearchDocumentUpdate(t *testing.T) {
+	var put string
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/config/_doc/app/settings" && r.Method == "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			put = string(body)
+			if r.URL.Query().Get("if_seq_no") != "4" || r.URL.Query().Get("if_primary_term") != "1" {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error": {"type": "version_conflict_engine_exception"}, "status": 409}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result": "updated", "_seq_no": 5, "_primary_term": 1}`))
+		case r.URL.Path == "/config/_doc/app/settings" && r.Method == "GET":
+			_, _ = w.Write([]byte(`{"_index": "config", "_id": "app/settings", "_version": 3, "_seq_no": 5, "_primary_term": 1, "found": true, "_source": {"theme": "dark"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchDocument()
+	d := r.TestResourceData()
+	d.SetId("config/app/settings")
+	_ = d.Set("index", "config")
+	_ = d.Set("document_id", "app/settings")
+	_ = d.Set("body", `{"theme": "dark"}`)
+	_ = d.Set("seq_no", 4)
+	_ = d.Set("primary_term", 1)
+	if err := r.Update(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if put != `{"theme":"dark"}` {
+		t.Errorf("unexpected document: %s", put)
+	}
+	if seqNo := d.Get("seq_no").(int); seqNo != 5 {
+		t.Errorf("expected seq_no to be 5, got %d", seqNo)
+	}
+	if body := d.Get("body").(string); body != `{"theme":"dark"}` {
+		t.Errorf("unexpected body: %s", body)
+	}
+
+	// the document changed since it was read
+	_ = d.Set("seq_no", 3)
+	err := r.Update(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "was changed since it was read") {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+}
+
+func TestParseDocumentId(t *testing.T) {
+	index, id, err := parseDocumentId("config/app/settings")
+	if err != nil || index != "config" || id != "app/settings" {
+		t.Errorf("unexpected parse: %q, %q, %v", index, id, err)
+	}
+	for _, invalid := range []string{"config", "config/", "/settings"} {
+		if _, _, err := parseDocumentId(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestAccElasticsearchDocument(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchDocumentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchDocument(`{"theme": "dark"}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_document.test", "id", "terraform-test-document/app-settings"),
+					resource.TestCheckResourceAttr("elasticsearch_document.test", "version", "1"),
+				),
+			},
+			{
+				Config: testAccElasticsearchDocument(`{"theme": "light"}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_document.test", "body", `{"theme":"light"}`),
+					resource.TestCheckResourceAttr("elasticsearch_document.test", "version", "2"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_document.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"refresh"},
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchDocumentDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_document" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+		esClient, err := getClient(meta.(*ProviderConf))
+		if err != nil {
+			return err
+		}
+		_, err = performRequest(providerContext(meta), esClient, "GET", "/terraform-test-document/_doc/app-settings", nil, nil)
+		if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Document %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func testAccElasticsearchDocument(body string) string {
+	return fmt.Sprintf(`
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-document"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  force_destroy      = true
+}
+
+resource "elasticsearch_document" "test" {
+  index       = elasticsearch_index.test.name
+  document_id = "app-settings"
+  body        = %q
+  refresh     = "wait_for"
+}
+`, body)
+}
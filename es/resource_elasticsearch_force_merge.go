@@ -0,0 +1,160 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+func resourceElasticsearchForceMerge() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchForceMergeCreate,
+		Read:   resourceElasticsearchForceMergeRead,
+		Delete: resourceElasticsearchForceMergeDelete,
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The index, data stream or alias to force merge, or comma separated list of them, with wildcards.",
+			},
+			"max_num_segments": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IntAtLeast(1),
+				ConflictsWith: []string{"only_expunge_deletes"},
+				Description:   "The number of segments to merge each shard into, e.g. 1 for indices that are no longer written to. Defaults to merging only if needed.",
+			},
+			"only_expunge_deletes": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{"max_num_segments"},
+				Description:   "Only merge the segments with deleted documents, to reclaim their space.",
+			},
+			"flush": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Flush the indices after the force merge.",
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Wait for the force merge to complete, up to the create timeout, failing if any shard failed. Otherwise only start it, on clusters running force merges as tasks. A task still running at the timeout, or when the apply is interrupted, keeps running, and the next apply waits for it instead of starting another.",
+			},
+			"triggers": byQueryTriggersSchema(),
+			"task_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the task running the force merge, empty on clusters running it synchronously, before Elasticsearch 7.12 and OpenSearch 2.7.",
+			},
+			"successful_shards": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of shards merged, 0 if `wait_for_completion` is false.",
+			},
+		},
+		Description: "Force merges indices once, when the resource is created or any of its arguments changes, e.g. to optimize an index after reindexing into it. Destroying the resource does nothing.",
+	}
+}
+
+// forceMergeResponse is the result of _forcemerge.
+type forceMergeResponse struct {
+	Shards struct {
+		Successful int               `json:"successful"`
+		Failed     int               `json:"failed"`
+		Failures   []json.RawMessage `json:"failures"`
+	} `json:"_shards"`
+}
+
+func resourceElasticsearchForceMergeCreate(d *schema.ResourceData, meta interface{}) error {
+	index := d.Get("index").(string)
+	path, err := uritemplates.Expand("/{index}/_forcemerge", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for force merge: %+v", err)
+	}
+	params := url.Values{
+		"only_expunge_deletes": []string{strconv.FormatBool(d.Get("only_expunge_deletes").(bool))},
+		"flush":                []string{strconv.FormatBool(d.Get("flush").(bool))},
+	}
+	if v, ok := d.GetOk("max_num_segments"); ok {
+		params.Set("max_num_segments", strconv.Itoa(v.(int)))
+	}
+
+	conf := meta.(*ProviderConf)
+	esClient, err := getClient(conf)
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+
+	var taskId string
+	var res json.RawMessage
+	if err := capabilityForceMergeTasks.check(conf); err != nil {
+		log.Printf("[INFO] Force merging %s synchronously: %+v", index, err)
+		res, err = performRequest(ctx, esClient, "POST", path, params, nil)
+		if err != nil {
+			return err
+		}
+	} else {
+		taskId, err = startTask(ctx, esClient, "_forcemerge", path, params, nil)
+		if err != nil {
+			return err
+		}
+		if d.Get("wait_for_completion").(bool) {
+			res, err = waitForTask(ctx, esClient, taskId, d.Timeout(schema.TimeoutCreate))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var result forceMergeResponse
+	if len(res) > 0 {
+		if err := json.Unmarshal(res, &result); err != nil {
+			return fmt.Errorf("error unmarshalling force merge result: %+v: %s", err, res)
+		}
+		if result.Shards.Failed > 0 {
+			failure := "{}"
+			if len(result.Shards.Failures) > 0 {
+				failure = compactJson(result.Shards.Failures[0])
+			}
+			return fmt.Errorf("force merge of %s failed on %d shards, e.g. %s", index, result.Shards.Failed, failure)
+		}
+	}
+
+	if taskId != "" {
+		d.SetId(taskId)
+	} else {
+		d.SetId(index)
+	}
+	ds := &resourceDataSetter{d: d}
+	ds.set("task_id", taskId)
+	ds.set("successful_shards", result.Shards.Successful)
+	return ds.err
+}
+
+// resourceElasticsearchForceMergeRead keeps the state of the force merge, an
+// action without anything to read back.
+func resourceElasticsearchForceMergeRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchForceMergeDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,106 @@
+package es
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchForceMergeCreate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.12.0"}}`))
+		case "/logs-reindexed/_forcemerge":
+			if actual := r.URL.Query().Get("max_num_segments"); actual != "1" {
+				t.Errorf("unexpected max_num_segments: %s", actual)
+			}
+			if actual := r.URL.Query().Get("wait_for_completion"); actual != "false" {
+				t.Errorf("unexpected wait_for_completion: %s", actual)
+			}
+			_, _ = w.Write([]byte(`{"task": "node-1:9"}`))
+		case "/_tasks/node-1:9":
+			_, _ = w.Write([]byte(`{"completed": true, "response": {"_shards": {"total": 2, "successful": 2, "failed": 0}}}`))
+		case "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchForceMerge()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs-reindexed")
+	_ = d.Set("max_num_segments", 1)
+	_ = d.Set("flush", true)
+	_ = d.Set("wait_for_completion", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "node-1:9" {
+		t.Errorf("unexpected ID: %s", d.Id())
+	}
+	if actual := d.Get("successful_shards").(int); actual != 2 {
+		t.Errorf("expected 2 successful shards, got %d", actual)
+	}
+}
+
+func TestResourceElasticsearchForceMergeCreateSynchronous(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/logs-reindexed/_forcemerge":
+			if actual := r.URL.Query().Get("wait_for_completion"); actual != "" {
+				t.Errorf("unexpected wait_for_completion: %s", actual)
+			}
+			_, _ = w.Write([]byte(`{"_shards": {"total": 2, "successful": 1, "failed": 1, "failures": [{"shard": 1, "index": "logs-reindexed", "reason": {"type": "io_exception"}}]}}`))
+		case "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchForceMerge()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs-reindexed")
+	_ = d.Set("wait_for_completion", true)
+	err := r.Create(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "failed on 1 shards") {
+		t.Errorf("expected the force merge to fail, got %v", err)
+	}
+}
+
+func TestAccElasticsearchForceMerge(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchForceMerge,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("elasticsearch_force_merge.test", "successful_shards"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchForceMerge = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-force-merge"
+  number_of_shards   = 1
+  number_of_replicas = 0
+}
+
+resource "elasticsearch_force_merge" "test" {
+  index            = elasticsearch_index.test.name
+  max_num_segments = 1
+}
+`
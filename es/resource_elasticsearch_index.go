@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
 
 	elastic7 "github.com/olivere/elastic/v7"
 	elastic5 "gopkg.in/olivere/elastic.v5"
@@ -16,13 +19,18 @@ import (
 )
 
 var (
+	// staticSettingsKeys can only be set when the index is created, changing
+	// them replaces the index.
 	staticSettingsKeys = []string{
 		"number_of_shards",
 		"codec",
 		"routing_partition_size",
 		"load_fixed_bitset_filters_eagerly",
 		"shard.check_on_startup",
+		"replication.type",
 	}
+	// dynamicsSettingsKeys are updated in place with the update index settings
+	// API.
 	dynamicsSettingsKeys = []string{
 		"number_of_replicas",
 		"auto_expand_replicas",
@@ -64,8 +72,21 @@ var (
 		"indexing.slowlog.threshold.index.trace",
 		"indexing.slowlog.level",
 		"indexing.slowlog.source",
+		"indexing.slowlog.reformat",
+		"remote_store.translog.buffer_interval",
 	}
 	settingsKeys = append(staticSettingsKeys, dynamicsSettingsKeys...)
+	// indexSettingsAttributes are the attributes named differently from the
+	// index setting they set.
+	indexSettingsAttributes = map[string]string{
+		"lifecycle.name": "lifecycle_policy",
+	}
+	// readOnlySettingsKeys are set by the cluster itself, and only read.
+	readOnlySettingsKeys = []string{
+		"remote_store.enabled",
+		"remote_store.segment.repository",
+		"remote_store.translog.repository",
+	}
 )
 
 var (
@@ -82,10 +103,12 @@ var (
 			Default:     false,
 			Optional:    true,
 		},
+		"deletion_protection": deletionProtectionSchema(),
+		"status":              indexStatusSchema(false),
 		// Static settings that can only be set on creation
 		"number_of_shards": {
 			Type:        schema.TypeString,
-			Description: "Number of shards for the index. This can be set only on creation.",
+			Description: "Number of shards for the index. This can be set only on creation. Elastic Cloud Serverless projects manage the shards themselves, the default isn't sent to them and other values fail the plan.",
 			ForceNew:    true,
 			Default:     "1",
 			Optional:    true,
@@ -114,6 +137,14 @@ var (
 			ForceNew:    true,
 			Optional:    true,
 		},
+		"replication_type": {
+			Type:         schema.TypeString,
+			Description:  "How the replicas of the index are kept up to date: `DOCUMENT` to index each document on every copy, or `SEGMENT` to copy the segments of the primary instead. Defaults to the `cluster.indices.replication.strategy` of the cluster. This can be set only on creation. Requires OpenSearch 2.7.",
+			ForceNew:     true,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.StringInSlice([]string{"DOCUMENT", "SEGMENT"}, false),
+		},
 		// Dynamic settings that can be changed at runtime
 		"number_of_replicas": {
 			Type:        schema.TypeString,
@@ -287,27 +318,27 @@ var (
 		},
 		"indexing_slowlog_threshold_index_warn": {
 			Type:        schema.TypeString,
-			Description: "Set the cutoff for shard level slow search logging of slow searches for indexing queries, in time units, e.g. `10s`",
+			Description: "Set the cutoff for shard level slow logging of slow indexing requests, in time units, e.g. `10s`",
 			Optional:    true,
 		},
 		"indexing_slowlog_threshold_index_info": {
 			Type:        schema.TypeString,
-			Description: "Set the cutoff for shard level slow search logging of slow searches for indexing queries, in time units, e.g. `5s`",
+			Description: "Set the cutoff for shard level slow logging of slow indexing requests, in time units, e.g. `5s`",
 			Optional:    true,
 		},
 		"indexing_slowlog_threshold_index_debug": {
 			Type:        schema.TypeString,
-			Description: "Set the cutoff for shard level slow search logging of slow searches for indexing queries, in time units, e.g. `2s`",
+			Description: "Set the cutoff for shard level slow logging of slow indexing requests, in time units, e.g. `2s`",
 			Optional:    true,
 		},
 		"indexing_slowlog_threshold_index_trace": {
 			Type:        schema.TypeString,
-			Description: "Set the cutoff for shard level slow search logging of slow searches for indexing queries, in time units, e.g. `500ms`",
+			Description: "Set the cutoff for shard level slow logging of slow indexing requests, in time units, e.g. `500ms`",
 			Optional:    true,
 		},
 		"indexing_slowlog_level": {
 			Type:        schema.TypeString,
-			Description: "Set which logging level to use for the search slow log, can be: `warn`, `info`, `debug`, `trace`",
+			Description: "Set which logging level to use for the indexing slow log, can be: `warn`, `info`, `debug`, `trace`",
 			Optional:    true,
 		},
 		"indexing_slowlog_source": {
@@ -315,13 +346,46 @@ var (
 			Description: "Set the number of characters of the `_source` to include in the slowlog lines, `false` or `0` will skip logging the source entirely and setting it to `true` will log the entire source regardless of size. The original `_source` is reformatted by default to make sure that it fits on a single log line.",
 			Optional:    true,
 		},
-		// Other attributes
-		"mappings": {
+		"indexing_slowlog_reformat": {
 			Type:         schema.TypeString,
-			Description:  "A JSON string defining how documents in the index, and the fields they contain, are stored and indexed. To avoid the complexities of field mapping updates, updates of this field are not allowed via this provider. See the upstream [Elasticsearch docs](https://www.elastic.co/guide/en/elasticsearch/reference/6.8/indices-put-mapping.html#updating-field-mappings) for more details.",
+			Description:  "Set to `false` to log the `_source` as is, e.g. keeping its newlines, rather than reformatted to fit on a single log line. Defaults to `true`.",
 			Optional:     true,
-			ForceNew:     true,
-			ValidateFunc: validation.StringIsJSON,
+			ValidateFunc: validation.StringInSlice([]string{"true", "false"}, false),
+		},
+		// Other attributes
+		"remote_store_translog_buffer_interval": {
+			Type:        schema.TypeString,
+			Description: "How often the translog of the index is uploaded to the remote store of a remote-backed cluster, e.g. `650ms`. Requires OpenSearch 2.10.",
+			Optional:    true,
+		},
+		"remote_store_enabled": {
+			Type:        schema.TypeBool,
+			Description: "Whether the data of the index is stored in the remote store, which the nodes of remote-backed clusters enable for every new index.",
+			Computed:    true,
+		},
+		"remote_store_segment_repository": {
+			Type:        schema.TypeString,
+			Description: "The snapshot repository storing the segments of the index in a remote-backed cluster.",
+			Computed:    true,
+		},
+		"remote_store_translog_repository": {
+			Type:        schema.TypeString,
+			Description: "The snapshot repository storing the translog of the index in a remote-backed cluster.",
+			Computed:    true,
+		},
+		"mappings": {
+			Type:             schema.TypeString,
+			Description:      "A JSON string defining how documents in the index, and the fields they contain, are stored and indexed. New fields, and the parameters that can be updated, e.g. `ignore_above`, are added in place. Typeless mappings, and mappings wrapped in the `_doc` type, are converted to the form the cluster requires, so the same mappings work with Elasticsearch 6 and 7 or later. Changing the type of an existing field, or a parameter that can only be set when the field is created, replaces the index, as detected during plan by comparing with the mappings of the index. Removed fields are kept by the index. See the upstream [Elasticsearch docs](https://www.elastic.co/guide/en/elasticsearch/reference/7.10/indices-put-mapping.html#updating-field-mappings) for more details.",
+			Optional:         true,
+			DiffSuppressFunc: diffSuppressIndexMappings,
+			ValidateFunc:     validation.StringIsJSON,
+		},
+		"mappings_breaking_changes": {
+			Type:        schema.TypeString,
+			Description: "The changes of `mappings` that require replacing the index, e.g. changing the type of a field, shown during plan. Empty once applied.",
+			Computed:    true,
+			// set during plan to replace the index
+			ForceNew: true,
 		},
 		"aliases": {
 			Type:        schema.TypeString,
@@ -360,11 +424,29 @@ var (
 			ForceNew:     true, // To add a normalizer, the index must be closed, updated, and then reopened; we can't handle that here.
 			ValidateFunc: validation.StringIsJSON,
 		},
-		// Computed attributes
-		"rollover_alias": {
+		"lifecycle_policy": {
 			Type:     schema.TypeString,
 			Optional: true,
-			Computed: true,
+			// set by the index templates of most indices managed by a policy
+			Computed:    true,
+			Description: "The name of the index lifecycle policy managing the index, the `index.lifecycle.name` setting. The policy must exist when the index is created or the attribute changes. If unset, the policy of the index, e.g. from an index template, is read and left as is.",
+		},
+		"bootstrap_rollover_alias": {
+			Type:         schema.TypeBool,
+			Optional:     true,
+			RequiredWith: []string{"rollover_alias"},
+			// only used to create the index, e.g. not when importing it
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				return d.Id() != ""
+			},
+			Description: "When creating the index, create `rollover_alias` with the index as its write index, bootstrapping e.g. `logs-000001` as the first index the lifecycle policy rolls over.",
+		},
+		// Computed attributes
+		"rollover_alias": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "The alias rolled over by the lifecycle policy of the index, the `index.lifecycle.rollover_alias` setting on clusters with index lifecycle management. The index resolves to the current write index of the alias.",
 		},
 	}
 )
@@ -377,6 +459,10 @@ func resourceElasticsearchIndex() *schema.Resource {
 		Update:      resourceElasticsearchIndexUpdate,
 		Delete:      resourceElasticsearchIndexDelete,
 		Schema:      configSchema,
+		CustomizeDiff: customdiff.All(
+			customizeDiffIndexMappings,
+			customizeDiffCheckIndexSettings,
+		),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -388,9 +474,13 @@ func resourceElasticsearchIndexCreate(d *schema.ResourceData, meta interface{})
 		name     = d.Get("name").(string)
 		settings = settingsFromIndexResourceData(d)
 		body     = make(map[string]interface{})
-		ctx      = context.Background()
+		ctx      = providerContext(meta)
 		err      error
 	)
+	if clusterServerless(meta.(*ProviderConf)) && settings["number_of_shards"] == configSchema["number_of_shards"].Default {
+		// serverless projects manage the shards of their indices
+		delete(settings, "number_of_shards")
+	}
 	if len(settings) > 0 {
 		body["settings"] = settings
 	}
@@ -404,6 +494,18 @@ func resourceElasticsearchIndexCreate(d *schema.ResourceData, meta interface{})
 		}
 		body["aliases"] = aliases
 	}
+	if d.Get("bootstrap_rollover_alias").(bool) {
+		alias := d.Get("rollover_alias").(string)
+		aliases, _ := body["aliases"].(map[string]interface{})
+		if aliases == nil {
+			aliases = map[string]interface{}{}
+		}
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("the rollover alias %s is already defined in aliases, bootstrap_rollover_alias would override it", alias)
+		}
+		aliases[alias] = map[string]interface{}{"is_write_index": true}
+		body["aliases"] = aliases
+	}
 
 	analysis := map[string]interface{}{}
 	settings["analysis"] = analysis
@@ -465,6 +567,20 @@ func resourceElasticsearchIndexCreate(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return err
 	}
+	if mappings, ok := body["mappings"].(map[string]interface{}); ok {
+		body["mappings"] = mappingsForClient(esClient, mappings)
+	}
+	if policy, ok := d.GetOk("lifecycle_policy"); ok {
+		if err := checkIndexLifecyclePolicyExists(ctx, esClient, policy.(string)); err != nil {
+			return err
+		}
+		settings["lifecycle.name"] = policy
+		body["settings"] = settings
+	}
+	if alias, ok := d.GetOk("rollover_alias"); ok && capabilityIndexLifecyclePolicies.check(meta.(*ProviderConf)) == nil {
+		settings["lifecycle.rollover_alias"] = alias
+		body["settings"] = settings
+	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		resp, requestErr := client.CreateIndex(name).BodyJson(body).Do(ctx)
@@ -490,12 +606,18 @@ func resourceElasticsearchIndexCreate(d *schema.ResourceData, meta interface{})
 
 	}
 
-	if err == nil {
-		// Let terraform know the resource was created
-		d.SetId(resolvedName)
-		return resourceElasticsearchIndexRead(d, meta)
+	if err != nil {
+		return err
 	}
-	return err
+
+	// Let terraform know the resource was created
+	d.SetId(resolvedName)
+	if d.Get("status").(string) == indexStatusClosed {
+		if err := setIndexStatus(ctx, esClient, resolvedName, indexStatusClosed); err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchIndexRead(d, meta)
 }
 
 func settingsFromIndexResourceData(d *schema.ResourceData) map[string]interface{} {
@@ -512,7 +634,7 @@ func settingsFromIndexResourceData(d *schema.ResourceData) map[string]interface{
 
 func indexResourceDataFromSettings(settings map[string]interface{}, d *schema.ResourceData) {
 	log.Printf("[INFO] indexResourceDataFromSettings: %+v", settings)
-	for _, key := range settingsKeys {
+	for _, key := range append(append([]string{}, settingsKeys...), readOnlySettingsKeys...) {
 		rawValue, okRaw := settings[key]
 		rawPrefixedValue, okPrefixed := settings["index."+key]
 		var value interface{}
@@ -525,6 +647,10 @@ func indexResourceDataFromSettings(settings map[string]interface{}, d *schema.Re
 		}
 
 		schemaName := strings.Replace(key, ".", "_", -1)
+		// flat settings are strings, whatever their type
+		if s, ok := value.(string); ok && configSchema[schemaName].Type == schema.TypeBool {
+			value, _ = strconv.ParseBool(s)
+		}
 		err := d.Set(schemaName, value)
 		if err != nil {
 			log.Printf("[ERROR] indexResourceDataFromSettings: %+v", err)
@@ -533,9 +659,13 @@ func indexResourceDataFromSettings(settings map[string]interface{}, d *schema.Re
 }
 
 func resourceElasticsearchIndexDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
+
 	var (
 		name = d.Id()
-		ctx  = context.Background()
+		ctx  = providerContext(meta)
 		err  error
 	)
 
@@ -571,8 +701,13 @@ func resourceElasticsearchIndexDelete(d *schema.ResourceData, meta interface{})
 func allowIndexDestroy(indexName string, d *schema.ResourceData, meta interface{}) bool {
 	force := d.Get("force_destroy").(bool)
 
+	// a closed index can't be counted
+	if force {
+		return true
+	}
+
 	var (
-		ctx   = context.Background()
+		ctx   = providerContext(meta)
 		count int64
 		err   error
 	)
@@ -605,15 +740,30 @@ func allowIndexDestroy(indexName string, d *schema.ResourceData, meta interface{
 
 func resourceElasticsearchIndexUpdate(d *schema.ResourceData, meta interface{}) error {
 	settings := make(map[string]interface{})
-	for _, key := range settingsKeys {
+	for _, key := range dynamicsSettingsKeys {
 		schemaName := strings.Replace(key, ".", "_", -1)
 		if d.HasChange(schemaName) {
-			settings[key] = d.Get(schemaName)
+			if raw, ok := d.GetOk(schemaName); ok {
+				settings[key] = raw
+			} else {
+				// removed from the configuration, reset to the default
+				settings[key] = nil
+			}
+		}
+	}
+
+	if policy, ok := d.GetOk("lifecycle_policy"); ok && d.HasChange("lifecycle_policy") {
+		settings["lifecycle.name"] = policy
+	}
+	if d.HasChange("rollover_alias") && capabilityIndexLifecyclePolicies.check(meta.(*ProviderConf)) == nil {
+		if alias, ok := d.GetOk("rollover_alias"); ok {
+			settings["lifecycle.rollover_alias"] = alias
 		}
 	}
 
-	// if we're not changing any settings, no-op this function
-	if len(settings) == 0 {
+	// if we're not changing any settings, mappings or the status, no-op this
+	// function
+	if len(settings) == 0 && !d.HasChange("mappings") && !d.HasChange("status") {
 		return resourceElasticsearchIndexRead(d, meta)
 	}
 
@@ -625,7 +775,7 @@ func resourceElasticsearchIndexUpdate(d *schema.ResourceData, meta interface{})
 
 	var (
 		name = d.Id()
-		ctx  = context.Background()
+		ctx  = providerContext(meta)
 		err  error
 	)
 
@@ -637,20 +787,168 @@ func resourceElasticsearchIndexUpdate(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		_, err = client.IndexPutSettings(name).BodyJson(body).Do(ctx)
+	if policy, ok := settings["lifecycle.name"].(string); ok {
+		if err := checkIndexLifecyclePolicyExists(ctx, esClient, policy); err != nil {
+			return err
+		}
+	}
+	if len(settings) > 0 {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			_, err = client.IndexPutSettings(name).BodyJson(body).Do(ctx)
 
-	case *elastic6.Client:
-		_, err = client.IndexPutSettings(name).BodyJson(body).Do(ctx)
+		case *elastic6.Client:
+			_, err = client.IndexPutSettings(name).BodyJson(body).Do(ctx)
 
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		_, err = elastic5Client.IndexPutSettings(name).BodyJson(body).Do(ctx)
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			_, err = elastic5Client.IndexPutSettings(name).BodyJson(body).Do(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("mappings") {
+		var mappings map[string]interface{}
+		if err := json.Unmarshal([]byte(d.Get("mappings").(string)), &mappings); err != nil {
+			return fmt.Errorf("fail to unmarshal: %v", err)
+		}
+		if err := putIndexMappings(ctx, esClient, name, mappings); err != nil {
+			return err
+		}
+	}
+
+	// settings are changed first, which closed indices allow too
+	if d.HasChange("status") {
+		if err := setIndexStatus(ctx, esClient, name, d.Get("status").(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourceElasticsearchIndexRead(d, meta.(*ProviderConf))
+}
+
+// customizeDiffIndexMappings replaces the index when the changed mappings
+// can't be applied in place, comparing them with the mappings of the index,
+// or with the previous mappings if the cluster can't be reached. New indices
+// have no mappings to compare with, except for the diff of a replacement,
+// which explains it with the changes found by the diff of the index.
+func customizeDiffIndexMappings(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("mappings") || !d.NewValueKnown("mappings") || !d.NewValueKnown("name") {
+		return nil
+	}
+	index := d.Id()
+	if index == "" {
+		if changes := takeReplacedMappings(meta, d.Get("name").(string)); changes != "" {
+			return d.SetNew("mappings_breaking_changes", changes)
+		}
+		return nil
+	}
+	o, n := d.GetChange("mappings")
+	var previous, desired map[string]interface{}
+	if err := json.Unmarshal([]byte(n.(string)), &desired); err != nil {
+		// reported by the validation of mappings
+		return nil
+	}
+	if o.(string) != "" {
+		if err := json.Unmarshal([]byte(o.(string)), &previous); err != nil {
+			return nil
+		}
 	}
 
+	current := previous
+	esClient, err := getClient(meta.(*ProviderConf))
 	if err == nil {
-		return resourceElasticsearchIndexRead(d, meta.(*ProviderConf))
+		var stored map[string]interface{}
+		if stored, err = indexMappings(providerContext(meta), esClient, index); stored != nil {
+			current = stored
+		}
+	}
+	if err != nil {
+		log.Printf("[WARN] Comparing the mappings of index %s with the previous ones, they can't be read: %+v", index, err)
+	}
+
+	currentProperties, _ := mappingProperties(current)
+	desiredProperties, _ := mappingProperties(desired)
+	previousProperties, _ := mappingProperties(previous)
+	for name := range previousProperties {
+		if _, ok := desiredProperties[name]; !ok {
+			log.Printf("[WARN] Field %s is removed from the mappings of index %s, which keeps it until the index is replaced", name, index)
+		}
+	}
+	if changes := breakingMappingChanges(currentProperties, desiredProperties, ""); len(changes) > 0 {
+		log.Printf("[WARN] Index %s must be replaced, its mappings can't be updated in place: %s", index, strings.Join(changes, "; "))
+		putReplacedMappings(meta, d.Get("name").(string), strings.Join(changes, "; "))
+		return d.SetNew("mappings_breaking_changes", strings.Join(changes, "; "))
+	}
+	return nil
+}
+
+// putReplacedMappings records the breaking changes of the mappings of an
+// index being replaced, for the diff of its replacement.
+func putReplacedMappings(meta interface{}, index, changes string) {
+	clients := meta.(*ProviderConf).clients
+	if clients == nil {
+		return
+	}
+	clients.replacedMu.Lock()
+	defer clients.replacedMu.Unlock()
+	if clients.replacedMappings == nil {
+		clients.replacedMappings = map[string]string{}
+	}
+	clients.replacedMappings[index] = changes
+}
+
+// takeReplacedMappings returns and forgets the breaking changes of the
+// mappings of an index being replaced, if any.
+func takeReplacedMappings(meta interface{}, index string) string {
+	clients := meta.(*ProviderConf).clients
+	if clients == nil {
+		return ""
+	}
+	clients.replacedMu.Lock()
+	defer clients.replacedMu.Unlock()
+	changes := clients.replacedMappings[index]
+	delete(clients.replacedMappings, index)
+	return changes
+}
+
+// customizeDiffCheckIndexSettings fails the plan when the cluster doesn't
+// accept the configured settings, e.g. segment replication on Elasticsearch.
+func customizeDiffCheckIndexSettings(d *schema.ResourceDiff, meta interface{}) error {
+	settings := map[string]interface{}{}
+	for key := range indexSettingsCapabilities {
+		schemaName, ok := indexSettingsAttributes[key]
+		if !ok {
+			schemaName = strings.Replace(key, ".", "_", -1)
+		}
+		if _, ok := configSchema[schemaName]; !ok {
+			continue
+		}
+		if d.Id() != "" && !d.HasChange(schemaName) {
+			continue
+		}
+		// defaults are only sent if the cluster accepts them
+		if v, ok := d.GetOk(schemaName); ok && v != configSchema[schemaName].Default {
+			settings[key] = v
+		}
+	}
+	return checkIndexSettingsCapabilities(meta.(*ProviderConf), settings)
+}
+
+// checkIndexLifecyclePolicyExists fails unless the index lifecycle policy
+// exists, which the index settings don't require.
+func checkIndexLifecyclePolicyExists(ctx context.Context, esClient interface{}, policy string) error {
+	path, err := uritemplates.Expand("/_ilm/policy/{policy}", map[string]string{
+		"policy": policy,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for index lifecycle policy: %+v", err)
+	}
+	_, err = performRequest(ctx, esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return fmt.Errorf("the index lifecycle policy %s doesn't exist", policy)
 	}
 	return err
 }
@@ -658,7 +956,7 @@ func resourceElasticsearchIndexUpdate(d *schema.ResourceData, meta interface{})
 func getWriteIndexByAlias(alias string, d *schema.ResourceData, meta interface{}) string {
 	var (
 		index   = d.Id()
-		ctx     = context.Background()
+		ctx     = providerContext(meta)
 		columns = []string{"index", "is_write_index"}
 	)
 
@@ -712,7 +1010,7 @@ func getWriteIndexByAlias(alias string, d *schema.ResourceData, meta interface{}
 func resourceElasticsearchIndexRead(d *schema.ResourceData, meta interface{}) error {
 	var (
 		index    = d.Id()
-		ctx      = context.Background()
+		ctx      = providerContext(meta)
 		settings map[string]interface{}
 	)
 
@@ -797,7 +1095,26 @@ func resourceElasticsearchIndexRead(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
+	if err := d.Set("lifecycle_policy", settings["index.lifecycle.name"]); err != nil {
+		return err
+	}
+
 	indexResourceDataFromSettings(settings, d)
 
+	// only set during plan, to explain the replacement
+	if err := d.Set("mappings_breaking_changes", ""); err != nil {
+		return err
+	}
+
+	status, err := indexStatus(ctx, esClient, index)
+	if err != nil {
+		return err
+	}
+	if status != "" {
+		if err := d.Set("status", status); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
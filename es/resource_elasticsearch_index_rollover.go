@@ -0,0 +1,167 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+func resourceElasticsearchIndexRollover() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchIndexRolloverCreate,
+		Read:   resourceElasticsearchIndexRolloverRead,
+		Delete: resourceElasticsearchIndexRolloverDelete,
+		Schema: map[string]*schema.Schema{
+			"target": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The alias or data stream to roll over.",
+			},
+			"new_index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The name of the index to create, with date math, e.g. `<logs-{now/d}-000002>`. Defaults to incrementing the number ending the name of the current write index; data streams always use the generated name.",
+			},
+			"max_age": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only roll over if the write index is older than this, e.g. `7d`.",
+			},
+			"max_docs": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Only roll over if the write index has at least this many documents.",
+			},
+			"max_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only roll over if the primary shards of the write index are at least this large, e.g. `50gb`.",
+			},
+			"max_primary_shard_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only roll over if the largest primary shard of the write index is at least this large, e.g. `50gb`. Requires Elasticsearch 7.13.",
+			},
+			"dry_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Only check the conditions and the name of the new index, without rolling over.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that roll over again when they change, e.g. the ID of the index template whose new settings the new index should get.",
+			},
+			"resolved_new_index": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the new write index, with any date math resolved.",
+			},
+			"old_index": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The write index before the rollover.",
+			},
+			"rolled_over": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the target was rolled over, false if none of the conditions were met or `dry_run` is true.",
+			},
+			"conditions": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+				Description: "Whether each of the conditions was met, by their description, e.g. `[max_age: 7d]`.",
+			},
+		},
+		Description: "Rolls over an alias or data stream once, when the resource is created or any of its arguments changes, e.g. so that the settings of a changed template take effect on a new write index right away. Destroying the resource doesn't undo the rollover.",
+	}
+}
+
+func resourceElasticsearchIndexRolloverCreate(d *schema.ResourceData, meta interface{}) error {
+	target := d.Get("target").(string)
+	template := "/{target}/_rollover"
+	if _, ok := d.GetOk("new_index"); ok {
+		template = "/{target}/_rollover/{new_index}"
+	}
+	path, err := uritemplates.Expand(template, map[string]string{
+		"target":    target,
+		"new_index": d.Get("new_index").(string),
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for rollover: %+v", err)
+	}
+	params := url.Values{
+		"dry_run": []string{strconv.FormatBool(d.Get("dry_run").(bool))},
+	}
+
+	conditions := map[string]interface{}{}
+	for _, key := range []string{"max_age", "max_docs", "max_size", "max_primary_shard_size"} {
+		if v, ok := d.GetOk(key); ok {
+			conditions[key] = v
+		}
+	}
+	var body interface{}
+	if len(conditions) > 0 {
+		body = map[string]interface{}{
+			"conditions": conditions,
+		}
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	// not retried, a rollover that timed out may have happened anyway
+	res, err := performRequest(providerContext(meta), esClient, "POST", path, params, body)
+	if err != nil {
+		return err
+	}
+	var rollover struct {
+		OldIndex   string          `json:"old_index"`
+		NewIndex   string          `json:"new_index"`
+		RolledOver bool            `json:"rolled_over"`
+		Conditions map[string]bool `json:"conditions"`
+	}
+	if err := json.Unmarshal(res, &rollover); err != nil {
+		return fmt.Errorf("error unmarshalling rollover: %+v: %s", err, res)
+	}
+
+	d.SetId(target + "/" + rollover.NewIndex)
+	ds := &resourceDataSetter{d: d}
+	ds.set("resolved_new_index", rollover.NewIndex)
+	ds.set("old_index", rollover.OldIndex)
+	ds.set("rolled_over", rollover.RolledOver)
+	ds.set("conditions", rollover.Conditions)
+	return ds.err
+}
+
+// resourceElasticsearchIndexRolloverRead keeps the state of the rollover, an
+// action without anything to read back.
+func resourceElasticsearchIndexRolloverRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchIndexRolloverDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
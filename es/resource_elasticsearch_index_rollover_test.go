@@ -0,0 +1,91 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchIndexRolloverCreate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/logs/_rollover":
+			if actual := r.URL.Query().Get("dry_run"); actual != "true" {
+				t.Errorf("unexpected dry_run: %s", actual)
+			}
+			var body struct {
+				Conditions map[string]interface{} `json:"conditions"`
+			}
+			raw, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(raw, &body); err != nil || body.Conditions["max_age"] != "7d" {
+				t.Errorf("unexpected body: %s", raw)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": false, "shards_acknowledged": false, "old_index": "logs-000001", "new_index": "logs-000002", "rolled_over": false, "dry_run": true, "conditions": {"[max_age: 7d]": true}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIndexRollover()
+	d := r.TestResourceData()
+	_ = d.Set("target", "logs")
+	_ = d.Set("max_age", "7d")
+	_ = d.Set("dry_run", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "logs/logs-000002" {
+		t.Errorf("unexpected ID: %s", d.Id())
+	}
+	if actual := d.Get("old_index"); actual != "logs-000001" {
+		t.Errorf("unexpected old_index: %v", actual)
+	}
+	if actual := d.Get("conditions").(map[string]interface{})["[max_age: 7d]"]; actual != true {
+		t.Errorf("expected the max_age condition to be met, got %v", d.Get("conditions"))
+	}
+}
+
+func TestAccElasticsearchIndexRollover(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchIndexRollover,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_index_rollover.test", "rolled_over", "true"),
+					resource.TestCheckResourceAttr("elasticsearch_index_rollover.test", "resolved_new_index", "terraform-test-rollover-000002"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchIndexRollover = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-rollover-000001"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  force_destroy      = true
+  aliases = jsonencode({
+    "terraform-test-rollover" = {
+      is_write_index = true
+    }
+  })
+}
+
+resource "elasticsearch_index_rollover" "test" {
+  target = "terraform-test-rollover"
+
+  triggers = {
+    index = elasticsearch_index.test.id
+  }
+}
+`
@@ -0,0 +1,164 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+const (
+	indexStatusOpen   = "open"
+	indexStatusClosed = "closed"
+)
+
+func resourceElasticsearchIndexState() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchIndexStatePut,
+		Read:   resourceElasticsearchIndexStateRead,
+		Update: resourceElasticsearchIndexStatePut,
+		Delete: resourceElasticsearchIndexStateDelete,
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the index, e.g. one created by a rollover or an application rather than by Terraform.",
+			},
+			"status": indexStatusSchema(true),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Opens or closes an index that isn't managed by an `elasticsearch_index` resource, e.g. to archive it without deleting it. Destroying the resource opens the index again.",
+	}
+}
+
+func indexStatusSchema(required bool) *schema.Schema {
+	s := &schema.Schema{
+		Type:         schema.TypeString,
+		ValidateFunc: validation.StringInSlice([]string{indexStatusOpen, indexStatusClosed}, false),
+		Description:  "Whether the index is `open` or `closed`. A closed index keeps its data on disk but can't be read or written, e.g. to archive it or to change its static settings.",
+	}
+	if required {
+		s.Required = true
+	} else {
+		s.Optional = true
+		s.Default = indexStatusOpen
+	}
+	return s
+}
+
+func resourceElasticsearchIndexStatePut(d *schema.ResourceData, meta interface{}) error {
+	index := d.Get("index").(string)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	if err := setIndexStatus(providerContext(meta), esClient, index, d.Get("status").(string)); err != nil {
+		return err
+	}
+	d.SetId(index)
+	return resourceElasticsearchIndexStateRead(d, meta)
+}
+
+func resourceElasticsearchIndexStateRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	status, err := indexStatus(providerContext(meta), esClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if status == "" {
+		log.Printf("[WARN] Index (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("index", d.Id())
+	ds.set("status", status)
+	return ds.err
+}
+
+func resourceElasticsearchIndexStateDelete(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = setIndexStatus(providerContext(meta), esClient, d.Id(), indexStatusOpen)
+	if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) && !elastic5.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// setIndexStatus opens or closes index, which does nothing if it already is.
+func setIndexStatus(ctx context.Context, esClient interface{}, index string, status string) error {
+	api := "_open"
+	if status == indexStatusClosed {
+		api = "_close"
+	}
+	path, err := uritemplates.Expand("/{index}/{api}", map[string]string{
+		"index": index,
+		"api":   api,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for index status: %+v", err)
+	}
+
+	_, err = performRequest(ctx, esClient, "POST", path, nil, nil)
+	return err
+}
+
+// indexStatus returns whether index is open or closed, or an empty string if
+// it doesn't exist.
+func indexStatus(ctx context.Context, esClient interface{}, index string) (string, error) {
+	path, err := uritemplates.Expand("/_cat/indices/{index}", map[string]string{
+		"index": index,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for index status: %+v", err)
+	}
+	params := url.Values{
+		"h":      []string{"index,status"},
+		"format": []string{"json"},
+	}
+
+	res, err := performRequest(ctx, esClient, "GET", path, params, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var indices []struct {
+		Index  string `json:"index"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(res, &indices); err != nil {
+		return "", fmt.Errorf("error unmarshalling index status: %+v: %s", err, res)
+	}
+	for _, i := range indices {
+		if i.Index != index {
+			continue
+		}
+		// the cat API calls closed indices "close"
+		if i.Status == "close" {
+			return indexStatusClosed, nil
+		}
+		return indexStatusOpen, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,81 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchIndexStateCreate(t *testing.T) {
+	var closed bool
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/logs-2019/_close":
+			closed = true
+			_, _ = w.Write([]byte(`{"acknowledged": true, "shards_acknowledged": true}`))
+		case "/_cat/indices/logs-2019":
+			status := "open"
+			if closed {
+				status = "close"
+			}
+			_, _ = w.Write([]byte(`[{"index": "logs-2019", "status": "` + status + `"}]`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIndexState()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs-2019")
+	_ = d.Set("status", "closed")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "logs-2019" {
+		t.Errorf("unexpected ID: %s", d.Id())
+	}
+	if actual := d.Get("status"); actual != "closed" {
+		t.Errorf("expected the index to be closed, got %v", actual)
+	}
+}
+
+func TestAccElasticsearchIndexState(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchIndexState,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_index_state.test", "status", "closed"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_index_state.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+var testAccElasticsearchIndexState = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-index-state"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  lifecycle {
+    ignore_changes = [status]
+  }
+}
+
+resource "elasticsearch_index_state" "test" {
+  index  = elasticsearch_index.test.name
+  status = "closed"
+}
+`
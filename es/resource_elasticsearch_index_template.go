@@ -3,8 +3,10 @@ package es
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	elastic7 "github.com/olivere/elastic/v7"
@@ -18,6 +20,10 @@ func resourceElasticsearchIndexTemplate() *schema.Resource {
 		Read:   resourceElasticsearchIndexTemplateRead,
 		Update: resourceElasticsearchIndexTemplateUpdate,
 		Delete: resourceElasticsearchIndexTemplateDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffValidateBody("body", indexTemplateBodySchema),
+			customizeDiffCheckBodyIndexSettings("body", "settings"),
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -30,6 +36,8 @@ func resourceElasticsearchIndexTemplate() *schema.Resource {
 				DiffSuppressFunc: diffSuppressIndexTemplate,
 				ValidateFunc:     validation.StringIsJSON,
 			},
+			"request_timeout":     requestTimeoutSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -47,22 +55,23 @@ func resourceElasticsearchIndexTemplateCreate(d *schema.ResourceData, meta inter
 }
 
 func resourceElasticsearchIndexTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var result string
 	var err error
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		result, err = elastic7IndexGetTemplate(client, id)
+		result, err = elastic7IndexGetTemplate(ctx, client, id)
 	case *elastic6.Client:
-		result, err = elastic6IndexGetTemplate(client, id)
+		result, err = elastic6IndexGetTemplate(ctx, client, id)
 	default:
 		elastic5Client := esClient.(*elastic5.Client)
-		result, err = elastic5IndexGetTemplate(elastic5Client, id)
+		result, err = elastic5IndexGetTemplate(ctx, elastic5Client, id)
 	}
 	if err != nil {
 		if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
@@ -76,12 +85,12 @@ func resourceElasticsearchIndexTemplateRead(d *schema.ResourceData, meta interfa
 
 	ds := &resourceDataSetter{d: d}
 	ds.set("name", d.Id())
-	ds.set("body", result)
+	ds.set("body", strippedJson(result, stripIndexTemplate))
 	return ds.err
 }
 
-func elastic7IndexGetTemplate(client *elastic7.Client, id string) (string, error) {
-	res, err := client.IndexGetTemplate(id).Do(context.TODO())
+func elastic7IndexGetTemplate(ctx context.Context, client *elastic7.Client, id string) (string, error) {
+	res, err := client.IndexGetTemplate(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -94,8 +103,8 @@ func elastic7IndexGetTemplate(client *elastic7.Client, id string) (string, error
 	return string(tj), nil
 }
 
-func elastic6IndexGetTemplate(client *elastic6.Client, id string) (string, error) {
-	res, err := client.IndexGetTemplate(id).Do(context.TODO())
+func elastic6IndexGetTemplate(ctx context.Context, client *elastic6.Client, id string) (string, error) {
+	res, err := client.IndexGetTemplate(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -108,8 +117,8 @@ func elastic6IndexGetTemplate(client *elastic6.Client, id string) (string, error
 	return string(tj), nil
 }
 
-func elastic5IndexGetTemplate(client *elastic5.Client, id string) (string, error) {
-	res, err := client.IndexGetTemplate(id).Do(context.TODO())
+func elastic5IndexGetTemplate(ctx context.Context, client *elastic5.Client, id string) (string, error) {
+	res, err := client.IndexGetTemplate(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -128,22 +137,29 @@ func resourceElasticsearchIndexTemplateUpdate(d *schema.ResourceData, meta inter
 }
 
 func resourceElasticsearchIndexTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
+
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var err error
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7IndexDeleteTemplate(client, id)
-	case *elastic6.Client:
-		err = elastic6IndexDeleteTemplate(client, id)
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		err = elastic5IndexDeleteTemplate(elastic5Client, id)
-	}
+	err = retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7IndexDeleteTemplate(ctx, client, id)
+		case *elastic6.Client:
+			return elastic6IndexDeleteTemplate(ctx, client, id)
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			return elastic5IndexDeleteTemplate(ctx, elastic5Client, id)
+		}
+	})
 
 	if err != nil {
 		return err
@@ -152,54 +168,78 @@ func resourceElasticsearchIndexTemplateDelete(d *schema.ResourceData, meta inter
 	return nil
 }
 
-func elastic7IndexDeleteTemplate(client *elastic7.Client, id string) error {
-	_, err := client.IndexDeleteTemplate(id).Do(context.TODO())
+func elastic7IndexDeleteTemplate(ctx context.Context, client *elastic7.Client, id string) error {
+	_, err := client.IndexDeleteTemplate(id).Do(ctx)
 	return err
 }
 
-func elastic6IndexDeleteTemplate(client *elastic6.Client, id string) error {
-	_, err := client.IndexDeleteTemplate(id).Do(context.TODO())
+func elastic6IndexDeleteTemplate(ctx context.Context, client *elastic6.Client, id string) error {
+	_, err := client.IndexDeleteTemplate(id).Do(ctx)
 	return err
 }
 
-func elastic5IndexDeleteTemplate(client *elastic5.Client, id string) error {
-	_, err := client.IndexDeleteTemplate(id).Do(context.TODO())
+func elastic5IndexDeleteTemplate(ctx context.Context, client *elastic5.Client, id string) error {
+	_, err := client.IndexDeleteTemplate(id).Do(ctx)
 	return err
 }
 
 func resourceElasticsearchPutIndexTemplate(d *schema.ResourceData, meta interface{}, create bool) error {
+	ctx := providerContext(meta)
 	name := d.Get("name").(string)
 	body := d.Get("body").(string)
 
 	var err error
-	esClient, err := getClient(meta.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, meta))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7IndexPutTemplate(client, name, body, create)
-	case *elastic6.Client:
-		err = elastic6IndexPutTemplate(client, name, body, create)
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		err = elastic5IndexPutTemplate(elastic5Client, name, body, create)
+	body, err = indexTemplateBodyForClient(esClient, body)
+	if err != nil {
+		return err
 	}
+	return retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7IndexPutTemplate(ctx, client, name, body, create)
+		case *elastic6.Client:
+			return elastic6IndexPutTemplate(ctx, client, name, body, create)
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			return elastic5IndexPutTemplate(ctx, elastic5Client, name, body, create)
+		}
+	})
+}
 
-	return err
+// indexTemplateBodyForClient converts the mappings of a template to the form
+// the major version of the cluster requires, see mappingsForClient.
+func indexTemplateBodyForClient(esClient interface{}, body string) (string, error) {
+	var tpl map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &tpl); err != nil {
+		return "", fmt.Errorf("error unmarshalling template body: %+v", err)
+	}
+	mappings, ok := tpl["mappings"].(map[string]interface{})
+	if !ok {
+		return body, nil
+	}
+	tpl["mappings"] = mappingsForClient(esClient, mappings)
+	b, err := json.Marshal(tpl)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling template body: %+v", err)
+	}
+	return string(b), nil
 }
 
-func elastic7IndexPutTemplate(client *elastic7.Client, name string, body string, create bool) error {
-	_, err := client.IndexPutTemplate(name).BodyString(body).Create(create).Do(context.TODO())
+func elastic7IndexPutTemplate(ctx context.Context, client *elastic7.Client, name string, body string, create bool) error {
+	_, err := client.IndexPutTemplate(name).BodyString(body).Create(create).Do(ctx)
 	return err
 }
 
-func elastic6IndexPutTemplate(client *elastic6.Client, name string, body string, create bool) error {
-	_, err := client.IndexPutTemplate(name).BodyString(body).Create(create).Do(context.TODO())
+func elastic6IndexPutTemplate(ctx context.Context, client *elastic6.Client, name string, body string, create bool) error {
+	_, err := client.IndexPutTemplate(name).BodyString(body).Create(create).Do(ctx)
 	return err
 }
 
-func elastic5IndexPutTemplate(client *elastic5.Client, name string, body string, create bool) error {
-	_, err := client.IndexPutTemplate(name).BodyString(body).Create(create).Do(context.TODO())
+func elastic5IndexPutTemplate(ctx context.Context, client *elastic5.Client, name string, body string, create bool) error {
+	_, err := client.IndexPutTemplate(name).BodyString(body).Create(create).Do(ctx)
 	return err
 }
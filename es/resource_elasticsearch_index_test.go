@@ -2,9 +2,14 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -42,6 +47,7 @@ resource "elasticsearch_index" "test" {
 	search_slowlog_level = "warn"
 	indexing_slowlog_threshold_index_warn = "5s"
 	indexing_slowlog_level = "warn"
+	indexing_slowlog_reformat = "false"
 }
 `
 	testAccElasticsearchIndexAnalysis = `
@@ -116,6 +122,15 @@ resource "elasticsearch_index" "test" {
   number_of_replicas = 2
   force_destroy = true
 }
+`
+	testAccElasticsearchIndexClosed = `
+resource "elasticsearch_index" "test" {
+  name = "terraform-test"
+  number_of_shards = 1
+  number_of_replicas = 2
+  force_destroy = true
+  status = "closed"
+}
 `
 	testAccElasticsearchIndexDateMath = `
 resource "elasticsearch_index" "test_date_math" {
@@ -260,6 +275,12 @@ func TestAccElasticsearchIndex(t *testing.T) {
 					checkElasticsearchIndexUpdated("elasticsearch_index.test"),
 				),
 			},
+			{
+				Config: testAccElasticsearchIndexClosed,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_index.test", "status", "closed"),
+				),
+			},
 		},
 	})
 }
@@ -280,6 +301,484 @@ func TestAccElasticsearchIndexAnalysis(t *testing.T) {
 	})
 }
 
+func TestResourceElasticsearchIndexMappingsDiff(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/products/_mapping":
+			_, _ = w.Write([]byte(`{"products": {"mappings": {"properties": {"sku": {"type": "keyword"}, "name": {"type": "text"}}}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIndex()
+	state := &terraform.InstanceState{
+		ID: "products",
+		Attributes: map[string]string{
+			"name":             "products",
+			"number_of_shards": "1",
+			"force_destroy":    "false",
+			"status":           "open",
+			"mappings":         `{"properties": {"sku": {"type": "keyword"}}}`,
+		},
+	}
+	diff := func(mappings string) *terraform.InstanceDiff {
+		d, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":     "products",
+			"mappings": mappings,
+		}), conf)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return d
+	}
+
+	// the name field was added dynamically, with the same type
+	d := diff(`{"properties": {"sku": {"type": "keyword"}, "name": {"type": "text"}, "price": {"type": "float"}}}`)
+	if d.RequiresNew() {
+		t.Errorf("expected new fields to be added in place")
+	}
+
+	d = diff(`{"properties": {"sku": {"type": "keyword"}, "name": {"type": "keyword"}}}`)
+	if !d.RequiresNew() {
+		t.Errorf("expected a type change to replace the index")
+	}
+	if actual := d.Attributes["mappings_breaking_changes"].New; actual != "the type of name changes from text to keyword" {
+		t.Errorf("unexpected mappings_breaking_changes: %s", actual)
+	}
+}
+
+func TestResourceElasticsearchIndexMappingsDiffOffline(t *testing.T) {
+	raw := map[string]interface{}{
+		// nothing listens on the discard port
+		"url":                   "http://127.0.0.1:9",
+		"skip_version_ping":     true,
+		"elasticsearch_version": "7.10.2",
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := resourceElasticsearchIndex()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "products",
+		"mappings": `{"properties": {"sku": {"type": "keyword"}}}`,
+	})
+	d, err := r.Diff(nil, config, meta)
+	if err != nil {
+		t.Fatalf("expected a new index to be planned offline, got %s", err)
+	}
+	if d.Attributes["mappings"] == nil {
+		t.Errorf("expected the mappings to be created, got %v", d)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "products",
+		Attributes: map[string]string{
+			"name":             "products",
+			"number_of_shards": "1",
+			"force_destroy":    "false",
+			"status":           "open",
+			"mappings":         `{"properties": {"sku": {"type": "keyword"}}}`,
+		},
+	}
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "products",
+		"mappings": `{"properties": {"sku": {"type": "text"}}}`,
+	})
+	d, err = r.Diff(state, config, meta)
+	if err != nil {
+		t.Fatalf("expected the mappings to be compared with the previous ones offline, got %s", err)
+	}
+	if !d.RequiresNew() {
+		t.Errorf("expected a type change to replace the index")
+	}
+}
+
+func TestIndexSettingsScope(t *testing.T) {
+	for _, key := range staticSettingsKeys {
+		name := strings.Replace(key, ".", "_", -1)
+		if s, ok := configSchema[name]; !ok || !s.ForceNew {
+			t.Errorf("expected the static setting %s to replace the index", name)
+		}
+	}
+	for _, key := range dynamicsSettingsKeys {
+		name := strings.Replace(key, ".", "_", -1)
+		if s, ok := configSchema[name]; !ok || s.ForceNew {
+			t.Errorf("expected the dynamic setting %s to be updated in place", name)
+		}
+	}
+	for _, key := range readOnlySettingsKeys {
+		name := strings.Replace(key, ".", "_", -1)
+		if s, ok := configSchema[name]; !ok || !s.Computed || s.Optional {
+			t.Errorf("expected the read-only setting %s to be computed only", name)
+		}
+	}
+}
+
+func TestResourceElasticsearchIndexOpenSearchSettings(t *testing.T) {
+	var created map[string]interface{}
+	handler := func(number, distribution string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/" && distribution == "opensearch":
+				_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "` + number + `"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+			case r.URL.Path == "/":
+				_, _ = w.Write([]byte(`{"version": {"number": "` + number + `"}}`))
+			case r.URL.Path == "/logs" && r.Method == "PUT":
+				if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+					t.Fatal(err)
+				}
+				_, _ = w.Write([]byte(`{"acknowledged": true, "index": "logs"}`))
+			case r.URL.Path == "/logs/_settings":
+				_, _ = w.Write([]byte(`{"logs": {"settings": {"index.number_of_shards": "1", "index.replication.type": "SEGMENT", "index.remote_store.enabled": "true", "index.remote_store.segment.repository": "segments", "index.remote_store.translog.repository": "translogs", "index.remote_store.translog.buffer_interval": "300ms"}}}`))
+			case r.URL.Path == "/_cat/indices/logs":
+				_, _ = w.Write([]byte(`[{"index": "logs", "status": "open"}]`))
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			}
+		}
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                                  "logs",
+		"replication_type":                      "SEGMENT",
+		"remote_store_translog_buffer_interval": "300ms",
+	})
+
+	r := resourceElasticsearchIndex()
+	for _, tc := range []struct {
+		number, distribution, err string
+	}{
+		{"7.10.2", "elasticsearch", "index setting remote_store.translog.buffer_interval can't be set: remote store settings are not available on Elasticsearch"},
+		{"2.9.0", "opensearch", "index setting remote_store.translog.buffer_interval can't be set: remote store settings require OpenSearch >= 2.10.0"},
+	} {
+		_, err := r.Diff(nil, config, testProviderConf(t, handler(tc.number, tc.distribution)))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s %s: expected %q, got %v", tc.distribution, tc.number, tc.err, err)
+		}
+	}
+
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":             "logs",
+		"replication_type": "SEGMENT",
+	}), testProviderConf(t, handler("2.6.0", "opensearch")))
+	if err == nil || !strings.Contains(err.Error(), "index setting replication.type can't be set: segment replication settings require OpenSearch >= 2.7.0") {
+		t.Errorf("expected a segment replication version error, got %v", err)
+	}
+
+	conf := testProviderConf(t, handler("2.11.0", "opensearch"))
+	diff, err := r.Diff(nil, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	settings := created["settings"].(map[string]interface{})
+	if settings["replication.type"] != "SEGMENT" || settings["remote_store.translog.buffer_interval"] != "300ms" {
+		t.Errorf("unexpected settings: %v", settings)
+	}
+	if state.Attributes["remote_store_enabled"] != "true" || state.Attributes["remote_store_segment_repository"] != "segments" {
+		t.Errorf("expected the remote store to be read, got %v", state.Attributes)
+	}
+	diff, err = r.Diff(state, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff once applied, got %v", diff)
+	}
+}
+
+func TestResourceElasticsearchIndexLifecyclePolicyFromTemplate(t *testing.T) {
+	var updated bool
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/logs-000001" && r.Method == "PUT":
+			_, _ = w.Write([]byte(`{"acknowledged": true, "index": "logs-000001"}`))
+		case r.URL.Path == "/logs-000001/_settings" && r.Method == "PUT":
+			updated = true
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.Contains(string(body), "lifecycle") {
+				t.Errorf("expected the policy not to be updated, got %s", body)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/logs-000001/_settings":
+			// the policy is set by a matching index template
+			_, _ = w.Write([]byte(`{"logs-000001": {"settings": {"index.number_of_shards": "1", "index.lifecycle.name": "logs", "index.provided_name": "logs-000001"}}}`))
+		case r.URL.Path == "/_cat/indices/logs-000001":
+			_, _ = w.Write([]byte(`[{"index": "logs-000001", "status": "open"}]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+	config := map[string]interface{}{
+		"name":               "logs-000001",
+		"number_of_replicas": "1",
+	}
+
+	r := resourceElasticsearchIndex()
+	diff, err := r.Diff(nil, terraform.NewResourceConfigRaw(config), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Attributes["lifecycle_policy"] != "logs" {
+		t.Errorf("expected the policy of the template to be read, got %v", state.Attributes)
+	}
+
+	diff, err = r.Diff(state, terraform.NewResourceConfigRaw(config), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff for the policy of the template, got %v", diff)
+	}
+
+	config["number_of_replicas"] = "2"
+	diff, err = r.Diff(state, terraform.NewResourceConfigRaw(config), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := diff.Attributes["lifecycle_policy"]; ok {
+		t.Errorf("expected the policy to be left as is, got %v", diff)
+	}
+	if _, err := r.Apply(state, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !updated {
+		t.Error("expected the replicas to be updated")
+	}
+}
+
+func TestResourceElasticsearchIndexServerless(t *testing.T) {
+	var created map[string]interface{}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0", "build_flavor": "serverless"}}`))
+		case r.URL.Path == "/logs" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true, "index": "logs"}`))
+		case r.URL.Path == "/logs/_settings":
+			_, _ = w.Write([]byte(`{"logs": {"settings": {"index.number_of_shards": "1", "index.provided_name": "logs"}}}`))
+		case r.URL.Path == "/_cat/indices/logs":
+			_, _ = w.Write([]byte(`[{"index": "logs", "status": "open"}]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIndex()
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "logs",
+		"number_of_replicas": "2",
+	}), conf)
+	if err == nil || !strings.Contains(err.Error(), "index setting number_of_replicas can't be set: shard and replica settings are not available on Elastic Cloud Serverless") {
+		t.Errorf("expected the replicas to be rejected, got %v", err)
+	}
+
+	diff, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "logs",
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(nil, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if created["settings"] != nil {
+		t.Errorf("expected the default number of shards not to be sent, got %v", created["settings"])
+	}
+}
+
+func TestResourceElasticsearchIndexLifecyclePolicy(t *testing.T) {
+	var created map[string]interface{}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_ilm/policy/logs":
+			_, _ = w.Write([]byte(`{"logs": {"version": 1, "policy": {"phases": {}}}}`))
+		case r.URL.Path == "/_ilm/policy/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"type": "resource_not_found_exception"}, "status": 404}`))
+		case r.URL.Path == "/logs-000001" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true, "index": "logs-000001"}`))
+		case r.URL.Path == "/_cat/aliases/logs":
+			_, _ = w.Write([]byte(`[{"index": "logs-000001", "is_write_index": "true"}]`))
+		case r.URL.Path == "/logs-000001/_settings":
+			_, _ = w.Write([]byte(`{"logs-000001": {"settings": {"index.number_of_shards": "1", "index.lifecycle.name": "logs", "index.lifecycle.rollover_alias": "logs", "index.provided_name": "logs-000001"}}}`))
+		case r.URL.Path == "/_cat/indices/logs-000001":
+			_, _ = w.Write([]byte(`[{"index": "logs-000001", "status": "open"}]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+	config := map[string]interface{}{
+		"name":                     "logs-000001",
+		"lifecycle_policy":         "logs",
+		"rollover_alias":           "logs",
+		"bootstrap_rollover_alias": true,
+	}
+
+	r := resourceElasticsearchIndex()
+	diff, err := r.Diff(nil, terraform.NewResourceConfigRaw(config), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	settings := created["settings"].(map[string]interface{})
+	if settings["lifecycle.name"] != "logs" || settings["lifecycle.rollover_alias"] != "logs" {
+		t.Errorf("unexpected settings: %v", settings)
+	}
+	expected := map[string]interface{}{"logs": map[string]interface{}{"is_write_index": true}}
+	if !reflect.DeepEqual(created["aliases"], expected) {
+		t.Errorf("expected the write alias to be bootstrapped, got %v", created["aliases"])
+	}
+	if state.Attributes["lifecycle_policy"] != "logs" {
+		t.Errorf("expected the lifecycle policy to be read, got %v", state.Attributes)
+	}
+	diff, err = r.Diff(state, terraform.NewResourceConfigRaw(config), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff once applied, got %v", diff)
+	}
+
+	config["lifecycle_policy"] = "missing"
+	diff, err = r.Diff(state, terraform.NewResourceConfigRaw(config), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, err = r.Apply(state, diff, conf)
+	if err == nil || !strings.Contains(err.Error(), "the index lifecycle policy missing doesn't exist") {
+		t.Errorf("expected a missing policy error, got %v", err)
+	}
+}
+
+func TestCheckBodyIndexSettings(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "8.11.0"}}`))
+	})
+	r := resourceElasticsearchComponentTemplate()
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "segments",
+		"body": `{"template": {"settings": {"index": {"replication": {"type": "SEGMENT"}}}}}`,
+	}), conf)
+	if err == nil || !strings.Contains(err.Error(), "index setting replication.type can't be set: segment replication settings are not available on Elasticsearch") {
+		t.Errorf("expected a capability error, got %v", err)
+	}
+
+	_, err = r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "shards",
+		"body": `{"template": {"settings": {"index.number_of_shards": 2}}}`,
+	}), conf)
+	if err != nil {
+		t.Errorf("err: %s", err)
+	}
+}
+
+func TestResourceElasticsearchIndexUpdateSettings(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/products/_settings" && r.Method == "PUT":
+			var body struct {
+				Settings map[string]interface{} `json:"settings"`
+			}
+			raw, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(raw, &body); err != nil {
+				t.Errorf("unexpected body: %s", raw)
+			}
+			expected := map[string]interface{}{"number_of_replicas": "2", "refresh_interval": nil}
+			if !reflect.DeepEqual(body.Settings, expected) {
+				t.Errorf("expected settings %v, got %v", expected, body.Settings)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/products/_settings":
+			_, _ = w.Write([]byte(`{"products": {"settings": {"index.number_of_shards": "1", "index.number_of_replicas": "2"}}}`))
+		case r.URL.Path == "/_cat/indices/products":
+			_, _ = w.Write([]byte(`[{"index": "products", "status": "open"}]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIndex()
+	state := &terraform.InstanceState{
+		ID: "products",
+		Attributes: map[string]string{
+			"name":               "products",
+			"number_of_shards":   "1",
+			"number_of_replicas": "1",
+			"refresh_interval":   "30s",
+			"force_destroy":      "false",
+			"status":             "open",
+		},
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "products",
+		"number_of_replicas": "2",
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff.RequiresNew() {
+		t.Fatalf("expected dynamic settings to be updated in place")
+	}
+	if _, err := r.Apply(state, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestResourceDeletionProtection(t *testing.T) {
+	for name, r := range map[string]*schema.Resource{
+		"elasticsearch_index":                     resourceElasticsearchIndex(),
+		"elasticsearch_index_template":            resourceElasticsearchIndexTemplate(),
+		"elasticsearch_composable_index_template": resourceElasticsearchComposableIndexTemplate(),
+		"elasticsearch_component_template":        resourceElasticsearchComponentTemplate(),
+		"elasticsearch_snapshot_repository":       resourceElasticsearchSnapshotRepository(),
+	} {
+		d := r.TestResourceData()
+		d.SetId("test")
+		if err := d.Set("deletion_protection", true); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		// the check happens before any request, so there is no need for a client
+		err := r.Delete(d, nil)
+		if err == nil || !regexp.MustCompile("deletion_protection is enabled").MatchString(err.Error()) {
+			t.Errorf("%s: expected deletion to be prevented, got %v", name, err)
+		}
+	}
+}
+
 func TestAccElasticsearchIndex_handleInvalid(t *testing.T) {
 	provider := Provider().(*schema.Provider)
 	err := provider.Configure(&terraform.ResourceConfig{})
@@ -0,0 +1,191 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func resourceElasticsearchIngestGeoipDatabase() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchIngestGeoipDatabaseCreate,
+		Read:   resourceElasticsearchIngestGeoipDatabaseRead,
+		Update: resourceElasticsearchIngestGeoipDatabaseUpdate,
+		Delete: resourceElasticsearchIngestGeoipDatabaseDelete,
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The ID of the database configuration.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the database to download, e.g. `GeoIP2-City`, which the `database_file` of geoip and ip_location processors refers to with a `.mmdb` suffix.",
+			},
+			"maxmind_account_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"maxmind_account_id", "ipinfo"},
+				Description:  "The ID of the MaxMind account to download the database with. Its license key belongs in the `ingest.geoip.downloader.maxmind.license_key` secure setting of the nodes.",
+			},
+			"ipinfo": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ExactlyOneOf: []string{"maxmind_account_id", "ipinfo"},
+				Description:  "Whether to download the database from IPinfo, with the token of the `ingest.ip_location.downloader.ipinfo.token` secure setting of the nodes. Requires Elasticsearch >= 8.16.",
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the configuration, incremented by each change.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages a database configuration of the GeoIP downloader, e.g. a commercial MaxMind database for the geoip processors of ingest pipelines. Requires Elasticsearch >= 8.15.",
+	}
+}
+
+// geoipDatabase is a database configuration of the GeoIP downloader.
+type geoipDatabase struct {
+	Name    string `json:"name"`
+	Maxmind *struct {
+		AccountId string `json:"account_id"`
+	} `json:"maxmind,omitempty"`
+	Ipinfo *struct{} `json:"ipinfo,omitempty"`
+}
+
+func resourceElasticsearchIngestGeoipDatabaseCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutIngestGeoipDatabase(d, meta); err != nil {
+		return err
+	}
+	d.SetId(d.Get("database_id").(string))
+	return resourceElasticsearchIngestGeoipDatabaseRead(d, meta)
+}
+
+func resourceElasticsearchIngestGeoipDatabaseRead(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	path, err := ingestGeoipDatabasePath(id)
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(meta), esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) {
+		log.Printf("[WARN] GeoIP database (%s) not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Databases []struct {
+			Id       string        `json:"id"`
+			Version  int           `json:"version"`
+			Database geoipDatabase `json:"database"`
+		} `json:"databases"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("error unmarshalling GeoIP database: %+v: %s", err, body)
+	}
+	if len(response.Databases) == 0 {
+		log.Printf("[WARN] GeoIP database (%s) not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+	database := response.Databases[0]
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("database_id", id)
+	ds.set("name", database.Database.Name)
+	if database.Database.Maxmind != nil {
+		ds.set("maxmind_account_id", database.Database.Maxmind.AccountId)
+	} else {
+		ds.set("maxmind_account_id", "")
+	}
+	ds.set("ipinfo", database.Database.Ipinfo != nil)
+	ds.set("version", database.Version)
+	return ds.err
+}
+
+func resourceElasticsearchIngestGeoipDatabaseUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutIngestGeoipDatabase(d, meta); err != nil {
+		return err
+	}
+	return resourceElasticsearchIngestGeoipDatabaseRead(d, meta)
+}
+
+func resourceElasticsearchIngestGeoipDatabaseDelete(d *schema.ResourceData, meta interface{}) error {
+	path, err := ingestGeoipDatabasePath(d.Id())
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, nil, nil)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceElasticsearchPutIngestGeoipDatabase(d *schema.ResourceData, meta interface{}) error {
+	path, err := ingestGeoipDatabasePath(d.Get("database_id").(string))
+	if err != nil {
+		return err
+	}
+
+	database := geoipDatabase{
+		Name: d.Get("name").(string),
+	}
+	if accountId, ok := d.GetOk("maxmind_account_id"); ok {
+		database.Maxmind = &struct {
+			AccountId string `json:"account_id"`
+		}{accountId.(string)}
+	}
+	if d.Get("ipinfo").(bool) {
+		database.Ipinfo = &struct{}{}
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", path, nil, database)
+		return err
+	})
+}
+
+func ingestGeoipDatabasePath(id string) (string, error) {
+	path, err := uritemplates.Expand("/_ingest/geoip/database/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for GeoIP database: %+v", err)
+	}
+	return path, nil
+}
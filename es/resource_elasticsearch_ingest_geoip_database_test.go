@@ -0,0 +1,60 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestResourceElasticsearchIngestGeoipDatabase(t *testing.T) {
+	var stored map[string]interface{}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.15.0"}}`))
+		case r.URL.Path == "/_ingest/geoip/database/city" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&stored); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_ingest/geoip/database/city" && r.Method == "DELETE":
+			stored = nil
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_ingest/geoip/database/city" && stored != nil:
+			b, _ := json.Marshal(stored)
+			_, _ = w.Write([]byte(`{"databases": [{"id": "city", "version": 1, "modified_date_millis": 1, "database": ` + string(b) + `}]}`))
+		case r.URL.Path == "/_ingest/geoip/database/city":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"type": "resource_not_found_exception", "reason": "database configuration not found"}, "status": 404}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIngestGeoipDatabase()
+	d := r.TestResourceData()
+	_ = d.Set("database_id", "city")
+	_ = d.Set("name", "GeoIP2-City")
+	_ = d.Set("maxmind_account_id", "1234")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if b, _ := json.Marshal(stored); string(b) != `{"maxmind":{"account_id":"1234"},"name":"GeoIP2-City"}` {
+		t.Errorf("unexpected database configuration %s", b)
+	}
+	if d.Id() != "city" || d.Get("maxmind_account_id") != "1234" || d.Get("ipinfo") != false || d.Get("version") != 1 {
+		t.Errorf("expected the database to be read back, got %v", d.State())
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d.SetId("city")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deleted database to be removed from state")
+	}
+}
@@ -0,0 +1,99 @@
+package es
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	geoipDownloaderEnabledSetting      = "ingest.geoip.downloader.enabled"
+	geoipDownloaderPollIntervalSetting = "ingest.geoip.downloader.poll.interval"
+)
+
+func resourceElasticsearchIngestGeoipDownloader() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchIngestGeoipDownloaderUpdate,
+		Read:   resourceElasticsearchIngestGeoipDownloaderRead,
+		Update: resourceElasticsearchIngestGeoipDownloaderUpdate,
+		Delete: resourceElasticsearchIngestGeoipDownloaderDelete,
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the nodes download the GeoIP databases and their updates, `ingest.geoip.downloader.enabled`, e.g. false for clusters without internet access that ship their own databases.",
+			},
+			"poll_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "How often the updates of the databases are checked, at least `1d`, `ingest.geoip.downloader.poll.interval`. Defaults to the default of the cluster, `3d`.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages the cluster settings of the GeoIP downloader of the geoip processors of ingest pipelines. Destroying the resource resets them to their defaults. There is only one per cluster, its ID is `geoip_downloader`.",
+	}
+}
+
+func resourceElasticsearchIngestGeoipDownloaderUpdate(d *schema.ResourceData, meta interface{}) error {
+	settings := map[string]interface{}{
+		geoipDownloaderEnabledSetting:      d.Get("enabled"),
+		geoipDownloaderPollIntervalSetting: nil,
+	}
+	if v, ok := d.GetOk("poll_interval"); ok {
+		settings[geoipDownloaderPollIntervalSetting] = v
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	if err := putPersistentClusterSettings(providerContext(meta), esClient, settings); err != nil {
+		return err
+	}
+	d.SetId("geoip_downloader")
+	return resourceElasticsearchIngestGeoipDownloaderRead(d, meta)
+}
+
+func resourceElasticsearchIngestGeoipDownloaderRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	settings, err := persistentClusterSettings(providerContext(meta), esClient)
+	if err != nil {
+		return err
+	}
+
+	// the downloader is enabled by default
+	enabled := true
+	if v, ok := settings[geoipDownloaderEnabledSetting]; ok {
+		enabled = clusterSettingString(v) == "true"
+	}
+	pollInterval := ""
+	if v, ok := settings[geoipDownloaderPollIntervalSetting]; ok {
+		pollInterval = clusterSettingString(v)
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("enabled", enabled)
+	ds.set("poll_interval", pollInterval)
+	return ds.err
+}
+
+func resourceElasticsearchIngestGeoipDownloaderDelete(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = putPersistentClusterSettings(providerContext(meta), esClient, map[string]interface{}{
+		geoipDownloaderEnabledSetting:      nil,
+		geoipDownloaderPollIntervalSetting: nil,
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
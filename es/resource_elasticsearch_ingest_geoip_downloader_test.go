@@ -0,0 +1,67 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestResourceElasticsearchIngestGeoipDownloader(t *testing.T) {
+	persistent := map[string]interface{}{}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.17.0"}}`))
+		case r.URL.Path == "/_cluster/settings" && r.Method == "PUT":
+			var body struct {
+				Persistent map[string]interface{} `json:"persistent"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range body.Persistent {
+				if v == nil {
+					delete(persistent, k)
+				} else {
+					// the settings API returns strings
+					persistent[k] = scalarString(v)
+				}
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/settings":
+			b, _ := json.Marshal(map[string]interface{}{"persistent": persistent})
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIngestGeoipDownloader()
+	d := r.TestResourceData()
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("enabled") != true {
+		t.Errorf("expected the downloader to be enabled by default")
+	}
+
+	_ = d.Set("enabled", false)
+	_ = d.Set("poll_interval", "7d")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if persistent[geoipDownloaderEnabledSetting] != "false" || persistent[geoipDownloaderPollIntervalSetting] != "7d" {
+		t.Errorf("unexpected settings %v", persistent)
+	}
+	if d.Id() != "geoip_downloader" || d.Get("enabled") != false || d.Get("poll_interval") != "7d" {
+		t.Errorf("expected the settings to be read back, got %v", d.State())
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(persistent) != 0 {
+		t.Errorf("expected the settings to be reset, got %v", persistent)
+	}
+}
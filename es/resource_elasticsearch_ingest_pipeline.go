@@ -3,7 +3,9 @@ package es
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	elastic7 "github.com/olivere/elastic/v7"
@@ -17,6 +19,10 @@ func resourceElasticsearchIngestPipeline() *schema.Resource {
 		Read:   resourceElasticsearchIngestPipelineRead,
 		Update: resourceElasticsearchIngestPipelineUpdate,
 		Delete: resourceElasticsearchIngestPipelineDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffIngestPipelineProcessors,
+			customizeDiffValidateBody("body", ingestPipelineBodySchema),
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -26,9 +32,24 @@ func resourceElasticsearchIngestPipeline() *schema.Resource {
 			"body": {
 				Type:             schema.TypeString,
 				DiffSuppressFunc: diffSuppressIngestPipeline,
-				Required:         true,
+				Optional:         true,
+				Computed:         true,
+				ExactlyOneOf:     []string{"body", "processor"},
 				ValidateFunc:     validation.StringIsJSON,
 			},
+			"description": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"body"},
+				Description:   "The description of the pipeline defined by `processor` blocks.",
+			},
+			"version": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"body"},
+				Description:   "The version of the pipeline defined by `processor` blocks, for external tools.",
+			},
+			"processor": ingestProcessorSchema(),
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -47,6 +68,7 @@ func resourceElasticsearchIngestPipelineCreate(d *schema.ResourceData, meta inte
 }
 
 func resourceElasticsearchIngestPipelineRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var result string
@@ -57,12 +79,12 @@ func resourceElasticsearchIngestPipelineRead(d *schema.ResourceData, meta interf
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		result, err = elastic7IngestGetPipeline(client, id)
+		result, err = elastic7IngestGetPipeline(ctx, client, id)
 	case *elastic6.Client:
-		result, err = elastic6IngestGetPipeline(client, id)
+		result, err = elastic6IngestGetPipeline(ctx, client, id)
 	default:
 		elastic5Client := client.(*elastic5.Client)
-		result, err = elastic5IngestGetPipeline(elastic5Client, id)
+		result, err = elastic5IngestGetPipeline(ctx, elastic5Client, id)
 	}
 	if err != nil {
 		return err
@@ -71,12 +93,111 @@ func resourceElasticsearchIngestPipelineRead(d *schema.ResourceData, meta interf
 	ds := &resourceDataSetter{d: d}
 	ds.set("name", d.Id())
 	ds.set("body", result)
+	if processors, ok := d.GetOk("processor"); ok {
+		var pipeline struct {
+			Description string                   `json:"description"`
+			Version     int                      `json:"version"`
+			Processors  []map[string]interface{} `json:"processors"`
+		}
+		if err := json.Unmarshal([]byte(result), &pipeline); err != nil {
+			return fmt.Errorf("error unmarshalling ingest pipeline %s: %+v: %s", d.Id(), err, result)
+		}
+		// processors configured as JSON are read back as JSON
+		configured := processors.([]interface{})
+		blocks := make([]interface{}, 0, len(pipeline.Processors))
+		for i, p := range pipeline.Processors {
+			asJson := false
+			if i < len(configured) {
+				if block, ok := configured[i].(map[string]interface{}); ok {
+					asJson = block["json"].(string) != ""
+				}
+			}
+			blocks = append(blocks, flattenIngestProcessor(p, asJson))
+		}
+		ds.set("description", pipeline.Description)
+		ds.set("version", pipeline.Version)
+		ds.set("processor", blocks)
+	}
 	return ds.err
 }
 
-func elastic7IngestGetPipeline(client *elastic7.Client, id string) (string, error) {
+// customizeDiffIngestPipelineProcessors plans the body of a pipeline defined
+// by processor blocks.
+func customizeDiffIngestPipelineProcessors(d *schema.ResourceDiff, meta interface{}) error {
+	processors := d.Get("processor").([]interface{})
+	if len(processors) == 0 || (!d.HasChange("processor") && !d.HasChange("description") && !d.HasChange("version")) {
+		return nil
+	}
+	for _, key := range []string{"processor", "description", "version"} {
+		if !ingestProcessorsKnown(d, key, d.Get(key)) {
+			return d.SetNewComputed("body")
+		}
+	}
+
+	body, err := ingestPipelineProcessorsBody(d.Get("description").(string), d.Get("version").(int), processors)
+	if err != nil {
+		return err
+	}
+	if old, _ := d.GetChange("body"); diffSuppressIngestPipeline("body", old.(string), body, nil) {
+		return nil
+	}
+	return d.SetNew("body", body)
+}
+
+// ingestProcessorsKnown returns whether all the values nested in v, the value
+// of key, are known.
+func ingestProcessorsKnown(d *schema.ResourceDiff, key string, v interface{}) bool {
+	if !d.NewValueKnown(key) {
+		return false
+	}
+	switch v := v.(type) {
+	case []interface{}:
+		for i, item := range v {
+			if !ingestProcessorsKnown(d, fmt.Sprintf("%s.%d", key, i), item) {
+				return false
+			}
+		}
+	case map[string]interface{}:
+		for k, item := range v {
+			if !ingestProcessorsKnown(d, key+"."+k, item) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ingestPipelineProcessorsBody returns the body of a pipeline defined by
+// processor blocks.
+func ingestPipelineProcessorsBody(description string, version int, blocks []interface{}) (string, error) {
+	processors := make([]interface{}, 0, len(blocks))
+	for i, b := range blocks {
+		block, _ := b.(map[string]interface{})
+		if block == nil {
+			return "", fmt.Errorf("processor %d is empty", i)
+		}
+		processor, err := expandIngestProcessor(block)
+		if err != nil {
+			return "", fmt.Errorf("invalid processor %d: %+v", i, err)
+		}
+		processors = append(processors, processor)
+	}
+	pipeline := map[string]interface{}{
+		"processors": processors,
+	}
+	if description != "" {
+		pipeline["description"] = description
+	}
+	if version != 0 {
+		pipeline["version"] = version
+	}
+	body, err := json.Marshal(pipeline)
+	return string(body), err
+}
+
+func elastic7IngestGetPipeline(ctx context.Context, client *elastic7.Client, id string) (string, error) {
 
-	res, err := client.IngestGetPipeline().Pretty(false).Do(context.TODO())
+	res, err := client.IngestGetPipeline().Pretty(false).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -91,8 +212,8 @@ func elastic7IngestGetPipeline(client *elastic7.Client, id string) (string, erro
 	return string(tj), nil
 }
 
-func elastic6IngestGetPipeline(client *elastic6.Client, id string) (string, error) {
-	res, err := client.IngestGetPipeline(id).Do(context.TODO())
+func elastic6IngestGetPipeline(ctx context.Context, client *elastic6.Client, id string) (string, error) {
+	res, err := client.IngestGetPipeline(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -105,8 +226,8 @@ func elastic6IngestGetPipeline(client *elastic6.Client, id string) (string, erro
 	return string(tj), nil
 }
 
-func elastic5IngestGetPipeline(client *elastic5.Client, id string) (string, error) {
-	res, err := client.IngestGetPipeline(id).Do(context.TODO())
+func elastic5IngestGetPipeline(ctx context.Context, client *elastic5.Client, id string) (string, error) {
+	res, err := client.IngestGetPipeline(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -125,6 +246,7 @@ func resourceElasticsearchIngestPipelineUpdate(d *schema.ResourceData, meta inte
 }
 
 func resourceElasticsearchIngestPipelineDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var err error
@@ -132,15 +254,20 @@ func resourceElasticsearchIngestPipelineDelete(d *schema.ResourceData, meta inte
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		_, err = client.IngestDeletePipeline(id).Do(context.TODO())
-	case *elastic6.Client:
-		_, err = client.IngestDeletePipeline(id).Do(context.TODO())
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		_, err = elastic5Client.IngestDeletePipeline(id).Do(context.TODO())
-	}
+	err = retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			_, err := client.IngestDeletePipeline(id).Do(ctx)
+			return err
+		case *elastic6.Client:
+			_, err := client.IngestDeletePipeline(id).Do(ctx)
+			return err
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			_, err := elastic5Client.IngestDeletePipeline(id).Do(ctx)
+			return err
+		}
+	})
 
 	if err != nil {
 		return err
@@ -150,23 +277,37 @@ func resourceElasticsearchIngestPipelineDelete(d *schema.ResourceData, meta inte
 }
 
 func resourceElasticsearchPutIngestPipeline(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	name := d.Get("name").(string)
 	body := d.Get("body").(string)
+	if processors, ok := d.GetOk("processor"); ok {
+		var err error
+		body, err = ingestPipelineProcessorsBody(d.Get("description").(string), d.Get("version").(int), processors.([]interface{}))
+		if err != nil {
+			return err
+		}
+		if err := d.Set("body", body); err != nil {
+			return err
+		}
+	}
 
 	var err error
 	esClient, err := getClient(meta.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		_, err = client.IngestPutPipeline(name).BodyString(body).Do(context.TODO())
-	case *elastic6.Client:
-		_, err = client.IngestPutPipeline(name).BodyString(body).Do(context.TODO())
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		_, err = elastic5Client.IngestPutPipeline(name).BodyString(body).Do(context.TODO())
-	}
-
-	return err
+	return retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			_, err := client.IngestPutPipeline(name).BodyString(body).Do(ctx)
+			return err
+		case *elastic6.Client:
+			_, err := client.IngestPutPipeline(name).BodyString(body).Do(ctx)
+			return err
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			_, err := elastic5Client.IngestPutPipeline(name).BodyString(body).Do(ctx)
+			return err
+		}
+	})
 }
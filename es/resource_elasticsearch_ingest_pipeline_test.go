@@ -2,7 +2,10 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	elastic7 "github.com/olivere/elastic/v7"
@@ -218,3 +221,63 @@ resource "elasticsearch_ingest_pipeline" "test" {
 EOF
 }
 `
+
+func TestResourceElasticsearchIngestPipelineProcessors(t *testing.T) {
+	var stored json.RawMessage
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_ingest/pipeline/logs" && r.Method == "PUT":
+			stored, _ = ioutil.ReadAll(r.Body)
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_ingest/pipeline" || r.URL.Path == "/_ingest/pipeline/logs":
+			b, _ := json.Marshal(map[string]json.RawMessage{"logs": stored})
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchIngestPipeline()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "logs",
+		"description": "Parses logs",
+		"processor": []interface{}{
+			map[string]interface{}{
+				"rename": []interface{}{
+					map[string]interface{}{"field": "host", "target_field": "host.name"},
+				},
+			},
+			map[string]interface{}{
+				"if":   "ctx.env == null",
+				"json": `{"set": {"field": "env", "value": "prod"}}`,
+			},
+		},
+	})
+	diff, err := r.Diff(nil, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := `{"description":"Parses logs","processors":[{"rename":{"field":"host","target_field":"host.name"}},{"set":{"field":"env","if":"ctx.env == null","value":"prod"}}]}`
+	if !suppressEquivalentJson("", expected, string(stored), nil) {
+		t.Errorf("expected the pipeline %s, got %s", expected, stored)
+	}
+	if state.ID != "logs" || state.Attributes["processor.1.json"] == "" {
+		t.Errorf("expected the processors to be read back, got %v", state.Attributes)
+	}
+
+	diff, err = r.Diff(state, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no changes, got %v", diff)
+	}
+}
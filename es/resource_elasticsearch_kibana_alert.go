@@ -15,7 +15,6 @@ import (
 	"github.com/phillbaker/terraform-provider-elasticsearch/kibana"
 )
 
-var minimalKibanaVersion, _ = version.NewVersion("7.7.0")
 var notifyWhenKibanaVersion, _ = version.NewVersion("7.11.0")
 
 func resourceElasticsearchKibanaAlert() *schema.Resource {
@@ -193,6 +192,7 @@ func resourceElasticsearchKibanaAlertCreate(d *schema.ResourceData, meta interfa
 }
 
 func resourceElasticsearchKibanaAlertRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	err := resourceElasticsearchKibanaAlertCheckVersion(meta)
 	if err != nil {
 		return err
@@ -210,7 +210,7 @@ func resourceElasticsearchKibanaAlertRead(d *schema.ResourceData, meta interface
 
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		alert, err = kibanaGetAlert(client, id, spaceID)
+		alert, err = kibanaGetAlert(ctx, client, id, spaceID)
 	default:
 		err = fmt.Errorf("Kibana Alert endpoint only available from Kibana >= 7.7, got version < 7.0.0")
 	}
@@ -253,6 +253,7 @@ func resourceElasticsearchKibanaAlertUpdate(d *schema.ResourceData, meta interfa
 }
 
 func resourceElasticsearchKibanaAlertDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	err := resourceElasticsearchKibanaAlertCheckVersion(meta)
 	if err != nil {
 		return err
@@ -268,7 +269,7 @@ func resourceElasticsearchKibanaAlertDelete(d *schema.ResourceData, meta interfa
 
 	switch client := kibanaClient.(type) {
 	case *elastic7.Client:
-		err = kibanaDeleteAlert(client, id, spaceID)
+		err = kibanaDeleteAlert(ctx, client, id, spaceID)
 	default:
 		err = fmt.Errorf("Kibana Alert endpoint only available from ElasticSearch >= 7.7, got version < 7.0.0")
 	}
@@ -281,6 +282,7 @@ func resourceElasticsearchKibanaAlertDelete(d *schema.ResourceData, meta interfa
 }
 
 func resourceElasticsearchPostKibanaAlert(d *schema.ResourceData, meta interface{}) (string, error) {
+	ctx := providerContext(meta)
 	spaceID := ""
 
 	kibanaClient, err := getKibanaClient(meta.(*ProviderConf))
@@ -323,7 +325,7 @@ func resourceElasticsearchPostKibanaAlert(d *schema.ResourceData, meta interface
 	var id string
 	switch client := kibanaClient.(type) {
 	case *elastic7.Client:
-		id, err = kibanaPostAlert(client, spaceID, alert)
+		id, err = kibanaPostAlert(ctx, client, spaceID, alert)
 	default:
 		err = fmt.Errorf("Kibana Alert endpoint only available from ElasticSearch >= 7.7, got version < 7.0.0")
 	}
@@ -405,33 +407,20 @@ func resourceElasticsearchPutKibanaAlert(d *schema.ResourceData, meta interface{
 }
 
 func resourceElasticsearchKibanaGetVersion(meta interface{}) (*version.Version, error) {
-	esClient, err := getClient(meta.(*ProviderConf))
-	if err != nil {
+	conf := meta.(*ProviderConf)
+	if _, err := getClient(conf); err != nil {
 		return nil, err
 	}
 
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7GetVersion(client)
-	default:
-		return nil, fmt.Errorf("Kibana Alert endpoint only available from ElasticSearch >= 7.7, got version < 7.0.0")
-	}
+	v, _ := clusterVersion(conf)
+	return version.NewVersion(v)
 }
 
 func resourceElasticsearchKibanaAlertCheckVersion(meta interface{}) error {
-	elasticVersion, err := resourceElasticsearchKibanaGetVersion(meta)
-	if err != nil {
-		return err
-	}
-
-	if elasticVersion.LessThan(minimalKibanaVersion) {
-		return fmt.Errorf("Kibana Alert endpoint only available from ElasticSearch >= 7.7, got version %s", elasticVersion.String())
-	}
-
-	return err
+	return capabilityKibanaAlerts.check(meta.(*ProviderConf))
 }
 
-func kibanaGetAlert(client *elastic7.Client, id, spaceID string) (kibana.Alert, error) {
+func kibanaGetAlert(ctx context.Context, client *elastic7.Client, id, spaceID string) (kibana.Alert, error) {
 	path, err := uritemplates.Expand("/api/alerts/alert/{id}", map[string]string{
 		"id": id,
 	})
@@ -441,7 +430,7 @@ func kibanaGetAlert(client *elastic7.Client, id, spaceID string) (kibana.Alert,
 
 	var body json.RawMessage
 	var res *elastic7.Response
-	res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+	res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 		Method: "GET",
 		Path:   path,
 	})
@@ -459,7 +448,7 @@ func kibanaGetAlert(client *elastic7.Client, id, spaceID string) (kibana.Alert,
 	return *alert, nil
 }
 
-func kibanaPostAlert(client *elastic7.Client, spaceID string, alert kibana.Alert) (string, error) {
+func kibanaPostAlert(ctx context.Context, client *elastic7.Client, spaceID string, alert kibana.Alert) (string, error) {
 	path, err := uritemplates.Expand("/api/alerts/alert", map[string]string{})
 	if err != nil {
 		return "", fmt.Errorf("error building URL path for alert: %+v", err)
@@ -472,7 +461,7 @@ func kibanaPostAlert(client *elastic7.Client, spaceID string, alert kibana.Alert
 	}
 
 	var res *elastic7.Response
-	res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+	res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 		Method: "POST",
 		Path:   path,
 		Body:   string(body[:]),
@@ -490,7 +479,7 @@ func kibanaPostAlert(client *elastic7.Client, spaceID string, alert kibana.Alert
 	return alert.ID, nil
 }
 
-func kibanaDeleteAlert(client *elastic7.Client, id, spaceID string) error {
+func kibanaDeleteAlert(ctx context.Context, client *elastic7.Client, id, spaceID string) error {
 	path, err := uritemplates.Expand("/api/alerts/alert/{id}", map[string]string{
 		"id": id,
 	})
@@ -498,7 +487,7 @@ func kibanaDeleteAlert(client *elastic7.Client, id, spaceID string) error {
 		return fmt.Errorf("error building URL path for alert: %+v", err)
 	}
 
-	_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+	_, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 		Method: "DELETE",
 		Path:   path,
 	})
@@ -71,9 +71,35 @@ func resourceElasticsearchKibanaObject() *schema.Resource {
 				Default:  ".kibana",
 			},
 		},
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchKibanaObjectImport,
+		},
 	}
 }
 
+// resourceElasticsearchKibanaObjectImport imports an object from an ID of the
+// form index/object_id, e.g. .kibana/index-pattern:cloudtrail. Read fills in
+// the _source of the placeholder body.
+func resourceElasticsearchKibanaObjectImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	values, err := parseCompositeId(d.Id(), "index", "object_id")
+	if err != nil {
+		return nil, err
+	}
+	index, id := values[0], values[1]
+
+	body, err := json.Marshal([]map[string]interface{}{{"_id": id, "_source": map[string]interface{}{}}})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(id)
+	ds := &resourceDataSetter{d: d}
+	ds.set("index", index)
+	ds.set("body", string(body))
+
+	return []*schema.ResourceData{d}, ds.err
+}
+
 const (
 	INDEX_CREATED int = iota
 	INDEX_EXISTS
@@ -83,6 +109,7 @@ const (
 const deprecatedDocType = "doc"
 
 func resourceElasticsearchKibanaObjectCreate(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	index := d.Get("index").(string)
 	mapping_index := d.Get("index").(string)
 
@@ -94,12 +121,12 @@ func resourceElasticsearchKibanaObjectCreate(d *schema.ResourceData, meta interf
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		success, err = elastic7CreateIndexIfNotExists(client, index, mapping_index)
+		success, err = elastic7CreateIndexIfNotExists(ctx, client, index, mapping_index)
 	case *elastic6.Client:
-		success, err = elastic6CreateIndexIfNotExists(client, index, mapping_index)
+		success, err = elastic6CreateIndexIfNotExists(ctx, client, index, mapping_index)
 	default:
 		elastic5Client := client.(*elastic5.Client)
-		success, err = elastic5CreateIndexIfNotExists(elastic5Client, index, mapping_index)
+		success, err = elastic5CreateIndexIfNotExists(ctx, elastic5Client, index, mapping_index)
 	}
 
 	if err != nil {
@@ -126,16 +153,16 @@ func resourceElasticsearchKibanaObjectCreate(d *schema.ResourceData, meta interf
 	return nil
 }
 
-func elastic7CreateIndexIfNotExists(client *elastic7.Client, index string, mappingIndex string) (int, error) {
+func elastic7CreateIndexIfNotExists(ctx context.Context, client *elastic7.Client, index string, mappingIndex string) (int, error) {
 	log.Printf("[INFO] elastic7CreateIndexIfNotExists %s", index)
 
 	// Use the IndexExists service to check if a specified index exists.
-	exists, err := client.IndexExists(index).Do(context.TODO())
+	exists, err := client.IndexExists(index).Do(ctx)
 	if err != nil {
 		return INDEX_CREATION_FAILED, err
 	}
 	if !exists {
-		createIndex, err := client.CreateIndex(mappingIndex).Body(`{"mappings":{}}`).Do(context.TODO())
+		createIndex, err := client.CreateIndex(mappingIndex).Body(`{"mappings":{}}`).Do(ctx)
 		if createIndex.Acknowledged {
 			return INDEX_CREATED, err
 		}
@@ -145,16 +172,16 @@ func elastic7CreateIndexIfNotExists(client *elastic7.Client, index string, mappi
 	return INDEX_EXISTS, nil
 }
 
-func elastic6CreateIndexIfNotExists(client *elastic6.Client, index string, mapping_index string) (int, error) {
+func elastic6CreateIndexIfNotExists(ctx context.Context, client *elastic6.Client, index string, mapping_index string) (int, error) {
 	log.Printf("[INFO] elastic6CreateIndexIfNotExists")
 
 	// Use the IndexExists service to check if a specified index exists.
-	exists, err := client.IndexExists(index).Do(context.TODO())
+	exists, err := client.IndexExists(index).Do(ctx)
 	if err != nil {
 		return INDEX_CREATION_FAILED, err
 	}
 	if !exists {
-		createIndex, err := client.CreateIndex(mapping_index).Body(`{"mappings":{}}`).Do(context.TODO())
+		createIndex, err := client.CreateIndex(mapping_index).Body(`{"mappings":{}}`).Do(ctx)
 		if createIndex.Acknowledged {
 			return INDEX_CREATED, err
 		} else {
@@ -165,7 +192,7 @@ func elastic6CreateIndexIfNotExists(client *elastic6.Client, index string, mappi
 	return INDEX_EXISTS, nil
 }
 
-func elastic5CreateIndexIfNotExists(client *elastic5.Client, index string, mapping_index string) (int, error) {
+func elastic5CreateIndexIfNotExists(ctx context.Context, client *elastic5.Client, index string, mapping_index string) (int, error) {
 	mapping := `{
     "mappings": {
       "search": {
@@ -182,12 +209,12 @@ func elastic5CreateIndexIfNotExists(client *elastic5.Client, index string, mappi
   }`
 
 	// Use the IndexExists service to check if a specified index exists.
-	exists, err := client.IndexExists(index).Do(context.TODO())
+	exists, err := client.IndexExists(index).Do(ctx)
 	if err != nil {
 		return INDEX_CREATION_FAILED, err
 	}
 	if !exists {
-		createIndex, err := client.CreateIndex(mapping_index).Body(mapping).Do(context.TODO())
+		createIndex, err := client.CreateIndex(mapping_index).Body(mapping).Do(ctx)
 		if createIndex.Acknowledged {
 			return INDEX_CREATED, err
 		} else {
@@ -199,6 +226,7 @@ func elastic5CreateIndexIfNotExists(client *elastic5.Client, index string, mappi
 }
 
 func resourceElasticsearchKibanaObjectRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	bodyString := d.Get("body").(string)
 	var body []interface{}
 	if err := json.Unmarshal([]byte(bodyString), &body); err != nil {
@@ -222,20 +250,20 @@ func resourceElasticsearchKibanaObjectRead(d *schema.ResourceData, meta interfac
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var result *elastic7.GetResult
-		result, err = elastic7GetObject(client, index, id)
+		result, err = elastic7GetObject(ctx, client, index, id)
 		if err == nil {
 			resultJSON, err = json.Marshal(result)
 		}
 	case *elastic6.Client:
 		var result *elastic6.GetResult
-		result, err = elastic6GetObject(client, objectType, index, id)
+		result, err = elastic6GetObject(ctx, client, objectType, index, id)
 		if err == nil {
 			resultJSON, err = json.Marshal(result)
 		}
 	default:
 		var result *elastic5.GetResult
 		elastic5Client := client.(*elastic5.Client)
-		result, err = elastic5GetObject(elastic5Client, objectType, index, id)
+		result, err = elastic5GetObject(ctx, elastic5Client, objectType, index, id)
 		if err == nil {
 			resultJSON, err = json.Marshal(result)
 		}
@@ -289,6 +317,7 @@ func resourceElasticsearchKibanaObjectUpdate(d *schema.ResourceData, meta interf
 }
 
 func resourceElasticsearchKibanaObjectDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	bodyString := d.Get("body").(string)
 	var body []interface{}
 	if err := json.Unmarshal([]byte(bodyString), &body); err != nil {
@@ -310,12 +339,12 @@ func resourceElasticsearchKibanaObjectDelete(d *schema.ResourceData, meta interf
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		err = elastic7DeleteIndex(client, index, id)
+		err = elastic7DeleteIndex(ctx, client, index, id)
 	case *elastic6.Client:
-		err = elastic6DeleteIndex(client, objectType, index, id)
+		err = elastic6DeleteIndex(ctx, client, objectType, index, id)
 	default:
 		elastic5Client := client.(*elastic5.Client)
-		err = elastic5DeleteIndex(elastic5Client, objectType, index, id)
+		err = elastic5DeleteIndex(ctx, elastic5Client, objectType, index, id)
 	}
 
 	if err != nil {
@@ -325,39 +354,40 @@ func resourceElasticsearchKibanaObjectDelete(d *schema.ResourceData, meta interf
 	return nil
 }
 
-func elastic7DeleteIndex(client *elastic7.Client, index string, id string) error {
+func elastic7DeleteIndex(ctx context.Context, client *elastic7.Client, index string, id string) error {
 	_, err := client.Delete().
 		Index(index).
 		Id(id).
-		Do(context.TODO())
+		Do(ctx)
 
 	// we'll get an error if it's not found
 	return err
 }
 
-func elastic6DeleteIndex(client *elastic6.Client, objectType string, index string, id string) error {
+func elastic6DeleteIndex(ctx context.Context, client *elastic6.Client, objectType string, index string, id string) error {
 	_, err := client.Delete().
 		Index(index).
 		Type(objectType).
 		Id(id).
-		Do(context.TODO())
+		Do(ctx)
 
 	// we'll get an error if it's not found: https://github.com/olivere/elastic/blob/v6.1.26/delete.go#L207-L210
 	return err
 }
 
-func elastic5DeleteIndex(client *elastic5.Client, objectType string, index string, id string) error {
+func elastic5DeleteIndex(ctx context.Context, client *elastic5.Client, objectType string, index string, id string) error {
 	_, err := client.Delete().
 		Index(index).
 		Type(objectType).
 		Id(id).
-		Do(context.TODO())
+		Do(ctx)
 
 	// we'll get an error if it's not found: https://github.com/olivere/elastic/blob/v5.0.70/delete.go#L201-L203
 	return err
 }
 
 func resourceElasticsearchPutKibanaObject(d *schema.ResourceData, meta interface{}) (string, error) {
+	ctx := providerContext(meta)
 	bodyString := d.Get("body").(string)
 	var body []interface{}
 	if err := json.Unmarshal([]byte(bodyString), &body); err != nil {
@@ -380,12 +410,12 @@ func resourceElasticsearchPutKibanaObject(d *schema.ResourceData, meta interface
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		err = elastic7PutIndex(client, index, id, data)
+		err = elastic7PutIndex(ctx, client, index, id, data)
 	case *elastic6.Client:
-		err = elastic6PutIndex(client, objectType, index, id, data)
+		err = elastic6PutIndex(ctx, client, objectType, index, id, data)
 	default:
 		elastic5Client := client.(*elastic5.Client)
-		err = elastic5PutIndex(elastic5Client, objectType, index, id, data)
+		err = elastic5PutIndex(ctx, elastic5Client, objectType, index, id, data)
 	}
 
 	if err != nil {
@@ -395,34 +425,34 @@ func resourceElasticsearchPutKibanaObject(d *schema.ResourceData, meta interface
 	return id, nil
 }
 
-func elastic7PutIndex(client *elastic7.Client, index string, id string, data interface{}) error {
+func elastic7PutIndex(ctx context.Context, client *elastic7.Client, index string, id string, data interface{}) error {
 	_, err := client.Index().
 		Index(index).
 		Id(id).
 		BodyJson(&data).
-		Do(context.TODO())
+		Do(ctx)
 
 	return err
 }
 
-func elastic6PutIndex(client *elastic6.Client, objectType string, index string, id string, data interface{}) error {
+func elastic6PutIndex(ctx context.Context, client *elastic6.Client, objectType string, index string, id string, data interface{}) error {
 	_, err := client.Index().
 		Index(index).
 		Type(objectType).
 		Id(id).
 		BodyJson(&data).
-		Do(context.TODO())
+		Do(ctx)
 
 	return err
 }
 
-func elastic5PutIndex(client *elastic5.Client, objectType string, index string, id string, data interface{}) error {
+func elastic5PutIndex(ctx context.Context, client *elastic5.Client, objectType string, index string, id string, data interface{}) error {
 	_, err := client.Index().
 		Index(index).
 		Type(objectType).
 		Id(id).
 		BodyJson(&data).
-		Do(context.TODO())
+		Do(ctx)
 
 	return err
 }
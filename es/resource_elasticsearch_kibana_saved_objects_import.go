@@ -0,0 +1,432 @@
+package es
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func resourceElasticsearchKibanaSavedObjectsImport() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceElasticsearchKibanaSavedObjectsImportCreate,
+		Read:          resourceElasticsearchKibanaSavedObjectsImportRead,
+		Update:        resourceElasticsearchKibanaSavedObjectsImportUpdate,
+		Delete:        resourceElasticsearchKibanaSavedObjectsImportDelete,
+		CustomizeDiff: customizeDiffKibanaSavedObjectsImport,
+		Schema: map[string]*schema.Schema{
+			"file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"file", "content"},
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Path to an NDJSON file of saved objects, e.g. exported from the Kibana UI, streamed from disk so that bundles of any size are never held in memory. Only the path and the hash of the content are stored in the state.",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"file", "content"},
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The NDJSON of the saved objects, e.g. rendered by `templatefile`. Only its hash is stored in the state.",
+				StateFunc:    hashSum,
+			},
+			"space_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The Kibana space to import the saved objects into. Defaults to the default space.",
+			},
+			"overwrite": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Overwrite the saved objects that already exist with the same IDs. Otherwise they fail the import.",
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of Kibana tags, e.g. of `elasticsearch_kibana_tag`, assigned to the dashboards, visualizations, lens, maps and searches of the bundle on import. Changing them imports the bundle again, which only unassigns the removed tags with `overwrite`.",
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Delete the imported saved objects when the resource is destroyed. Otherwise they are kept in Kibana.",
+			},
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 hash of the imported NDJSON, computed during plan, which imports it again when it changes.",
+			},
+			"objects": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The imported saved objects, as `type/id`, e.g. `dashboard/722b74f0-b882-11e8-a6d9-e546fe2bba5f`.",
+			},
+		},
+		Description: "Imports a bundle of Kibana or OpenSearch Dashboards saved objects, e.g. dashboards with their visualizations and index patterns, with the saved objects import API. The NDJSON is streamed to Kibana and only its hash is kept in the state, so that bundles of tens of MB don't bloat the state or the plans. A changed bundle is imported again; the objects are not read back.",
+	}
+}
+
+// customizeDiffKibanaSavedObjectsImport hashes the NDJSON during plan, so that
+// a file changed on disk is imported again.
+func customizeDiffKibanaSavedObjectsImport(d *schema.ResourceDiff, meta interface{}) error {
+	var hash string
+	if file, ok := d.GetOk("file"); ok {
+		if !d.NewValueKnown("file") {
+			return d.SetNewComputed("content_hash")
+		}
+		f, err := os.Open(file.(string))
+		if err != nil {
+			return fmt.Errorf("error opening the saved objects: %+v", err)
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return fmt.Errorf("error reading the saved objects: %+v", err)
+		}
+		hash = hex.EncodeToString(h.Sum(nil))
+	} else {
+		if !d.NewValueKnown("content") {
+			return d.SetNewComputed("content_hash")
+		}
+		// content is hashed by its StateFunc
+		hash = d.Get("content").(string)
+		if d.HasChange("content") {
+			hash = hashSum(hash)
+		}
+	}
+	if hash != d.Get("content_hash").(string) {
+		return d.SetNew("content_hash", hash)
+	}
+	return nil
+}
+
+func resourceElasticsearchKibanaSavedObjectsImportCreate(d *schema.ResourceData, meta interface{}) error {
+	hash, err := importKibanaSavedObjects(d, meta)
+	if err != nil {
+		return err
+	}
+	d.SetId(hash)
+	return nil
+}
+
+// resourceElasticsearchKibanaSavedObjectsImportRead keeps the state of the
+// import, whose objects may be changed in Kibana afterwards.
+func resourceElasticsearchKibanaSavedObjectsImportRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchKibanaSavedObjectsImportUpdate(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("content_hash") && !d.HasChange("overwrite") && !d.HasChange("tags") {
+		return nil
+	}
+	_, err := importKibanaSavedObjects(d, meta)
+	return err
+}
+
+func resourceElasticsearchKibanaSavedObjectsImportDelete(d *schema.ResourceData, meta interface{}) error {
+	if !d.Get("delete_on_destroy").(bool) {
+		d.SetId("")
+		return nil
+	}
+
+	kibanaClient, err := getKibanaClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	client, ok := kibanaClient.(*elastic7.Client)
+	if !ok {
+		return fmt.Errorf("saved objects imports are only available from Kibana >= 7.0")
+	}
+	for _, object := range d.Get("objects").([]interface{}) {
+		parts := strings.SplitN(object.(string), "/", 2)
+		objectPath, err := uritemplates.Expand("/api/saved_objects/{type}/{id}", map[string]string{
+			"type": parts[0],
+			"id":   parts[1],
+		})
+		if err != nil {
+			return fmt.Errorf("error building URL path for saved object: %+v", err)
+		}
+		path, err := kibanaSpacePath(d.Get("space_id").(string), objectPath)
+		if err != nil {
+			return err
+		}
+		_, err = client.PerformRequest(providerContext(meta), elastic7.PerformRequestOptions{
+			Method:  "DELETE",
+			Path:    path,
+			Headers: http.Header{"Osd-Xsrf": []string{"true"}},
+		})
+		if err != nil && !elastic7.IsNotFound(err) {
+			return fmt.Errorf("error deleting saved object %s: %+v", object, err)
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+// taggableKibanaTypes are the types of saved objects Kibana assigns tags to.
+var taggableKibanaTypes = map[string]bool{
+	"dashboard":     true,
+	"visualization": true,
+	"lens":          true,
+	"map":           true,
+	"search":        true,
+}
+
+// importKibanaSavedObjects streams the NDJSON to the saved objects import API
+// as a multipart upload, collecting the types and IDs of the objects and
+// assigning them the tags on the way, and returns its hash.
+func importKibanaSavedObjects(d *schema.ResourceData, meta interface{}) (string, error) {
+	openNDJSON := func() (io.ReadCloser, error) {
+		if file, ok := d.GetOk("file"); ok {
+			f, err := os.Open(file.(string))
+			if err != nil {
+				return nil, fmt.Errorf("error opening the saved objects: %+v", err)
+			}
+			return f, nil
+		}
+		return ioutil.NopCloser(strings.NewReader(d.Get("content").(string))), nil
+	}
+
+	path, err := kibanaSpacePath(d.Get("space_id").(string), "/api/saved_objects/_import")
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"overwrite": []string{strconv.FormatBool(d.Get("overwrite").(bool))},
+	}
+
+	var tags []string
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		tags = append(tags, tag.(string))
+	}
+	sort.Strings(tags)
+
+	hasher := sha256.New()
+	var objects []string
+	// each attempt of the upload reads the NDJSON again, with the boundary of
+	// the content type
+	form := multipart.NewWriter(nil)
+	var body *io.PipeReader
+	var done chan struct{}
+	stop := func() {
+		if body != nil {
+			// unblocks the upload if Kibana answered before reading all of it
+			_ = body.Close()
+			<-done
+		}
+	}
+	upload := func() (io.ReadCloser, error) {
+		stop()
+		ndjson, err := openNDJSON()
+		if err != nil {
+			return nil, err
+		}
+		hasher.Reset()
+		objects = []string{}
+
+		var w *io.PipeWriter
+		body, w = io.Pipe()
+		done = make(chan struct{})
+		go func(done chan struct{}) {
+			defer close(done)
+			defer ndjson.Close()
+			mw := multipart.NewWriter(w)
+			if err := mw.SetBoundary(form.Boundary()); err != nil {
+				_ = w.CloseWithError(err)
+				return
+			}
+			part, err := mw.CreateFormFile("file", "export.ndjson")
+			if err != nil {
+				_ = w.CloseWithError(err)
+				return
+			}
+			dec := json.NewDecoder(io.TeeReader(ndjson, hasher))
+			for {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err == io.EOF {
+					break
+				} else if err != nil {
+					_ = w.CloseWithError(fmt.Errorf("error parsing the saved objects: %+v", err))
+					return
+				}
+				var object struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				}
+				if err := json.Unmarshal(raw, &object); err != nil {
+					_ = w.CloseWithError(fmt.Errorf("error parsing the saved objects: %+v", err))
+					return
+				}
+				// the export summary ending the file has neither
+				if object.Type != "" && object.ID != "" {
+					objects = append(objects, object.Type+"/"+object.ID)
+				}
+				if len(tags) > 0 && taggableKibanaTypes[object.Type] {
+					if raw, err = assignKibanaTags(raw, tags); err != nil {
+						_ = w.CloseWithError(err)
+						return
+					}
+				}
+				if _, err := part.Write(append(raw, '\n')); err != nil {
+					_ = w.CloseWithError(err)
+					return
+				}
+			}
+			_ = w.CloseWithError(mw.Close())
+		}(done)
+		return body, nil
+	}
+
+	res, err := kibanaUpload(meta.(*ProviderConf), path, params, form.FormDataContentType(), upload)
+	stop()
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Type  string `json:"type"`
+			ID    string `json:"id"`
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return "", fmt.Errorf("error unmarshalling the import of saved objects: %+v: %s", err, res)
+	}
+	if !response.Success {
+		failures := []string{}
+		for _, e := range response.Errors {
+			reason := e.Error.Type
+			if e.Error.Message != "" {
+				reason = e.Error.Message
+			}
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", e.Type, e.ID, reason))
+		}
+		sort.Strings(failures)
+		return "", fmt.Errorf("error importing saved objects: %s", strings.Join(failures, "; "))
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	ds := &resourceDataSetter{d: d}
+	ds.set("content_hash", hash)
+	ds.set("objects", objects)
+	return hash, ds.err
+}
+
+// assignKibanaTags adds references to tags to the saved object raw, unless it
+// has them already.
+func assignKibanaTags(raw json.RawMessage, tags []string) (json.RawMessage, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("error parsing the saved objects: %+v", err)
+	}
+	var references []map[string]interface{}
+	if len(object["references"]) > 0 {
+		if err := json.Unmarshal(object["references"], &references); err != nil {
+			return nil, fmt.Errorf("error parsing the references of the saved objects: %+v", err)
+		}
+	}
+	assigned := map[string]bool{}
+	for _, reference := range references {
+		if reference["type"] == "tag" {
+			if id, ok := reference["id"].(string); ok {
+				assigned[id] = true
+			}
+		}
+	}
+	for _, tag := range tags {
+		if !assigned[tag] {
+			// the name Kibana gives the references to tags
+			references = append(references, map[string]interface{}{"type": "tag", "id": tag, "name": "tag-ref-" + tag})
+		}
+	}
+
+	var err error
+	if object["references"], err = json.Marshal(references); err != nil {
+		return nil, err
+	}
+	return json.Marshal(object)
+}
+
+// kibanaUpload sends the body returned by body to the Kibana API as it's read,
+// unlike the requests of the Kibana client, which buffers them. body is called
+// again for each retry, so that the retries don't buffer it either. The AWS
+// signer still reads the whole body to sign it, as does the trace logging.
+func kibanaUpload(conf *ProviderConf, path string, params url.Values, contentType string, body func() (io.ReadCloser, error)) (json.RawMessage, error) {
+	u, err := url.Parse(strings.TrimSuffix(conf.kibanaUrl, "/") + path)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the Kibana URL: %+v", err)
+	}
+	u.RawQuery = params.Encode()
+	first, err := body()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(providerContext(conf), "POST", u.String(), first)
+	if err != nil {
+		first.Close()
+		return nil, err
+	}
+	req.GetBody = body
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("osd-xsrf", "true")
+	if conf.parsedUrl.User.Username() != "" {
+		p, _ := conf.parsedUrl.User.Password()
+		req.SetBasicAuth(conf.parsedUrl.User.Username(), p)
+	}
+	if conf.username != "" && conf.password != "" {
+		req.SetBasicAuth(conf.username, conf.password)
+	}
+
+	res, err := kibanaHttpClient(conf).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	raw, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		log.Printf("[INFO] Kibana upload to %s failed: %s", path, raw)
+		return nil, &elastic7.Error{Status: res.StatusCode, Details: &elastic7.ErrorDetails{Reason: string(raw)}}
+	}
+	return raw, nil
+}
+
+// kibanaSpacePath prefixes path with the space, unless it's the default one.
+func kibanaSpacePath(spaceID, path string) (string, error) {
+	if spaceID == "" || spaceID == "default" {
+		return path, nil
+	}
+	prefix, err := uritemplates.Expand("/s/{space_id}", map[string]string{
+		"space_id": spaceID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for space: %+v", err)
+	}
+	return prefix + path, nil
+}
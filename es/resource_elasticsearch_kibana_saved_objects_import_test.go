@@ -0,0 +1,249 @@
+package es
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchKibanaSavedObjectsImport(t *testing.T) {
+	ndjson := `{"type": "index-pattern", "id": "logs", "attributes": {"title": "logs-*"}}
+{"type": "dashboard", "id": "overview", "attributes": {"title": "Overview"}}
+{"exportedCount": 2, "missingRefCount": 0, "missingReferences": []}
+`
+	var imported string
+	deleted := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/s/ops/api/saved_objects/_import" && r.Method == "POST":
+			if r.URL.Query().Get("overwrite") != "true" {
+				t.Errorf("expected the objects to be overwritten, got %s", r.URL.RawQuery)
+			}
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := ioutil.ReadAll(file)
+			imported = string(b)
+			_, _ = w.Write([]byte(`{"success": true, "successCount": 2}`))
+		case r.Method == "DELETE":
+			deleted = append(deleted, r.URL.Path)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"kibana_url":  server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	file := filepath.Join(t.TempDir(), "export.ndjson")
+	if err := ioutil.WriteFile(file, []byte(ndjson), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"file":     file,
+		"space_id": "ops",
+	})
+
+	r := resourceElasticsearchKibanaSavedObjectsImport()
+	diff, err := r.Diff(nil, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if imported != ndjson {
+		t.Errorf("unexpected import: %s", imported)
+	}
+	if state.Attributes["content_hash"] != hashSum(ndjson) || state.ID != hashSum(ndjson) {
+		t.Errorf("expected the hash of the file in the state, got %v", state.Attributes)
+	}
+
+	diff, err = r.Diff(state, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff for an unchanged file, got %v", diff)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(ndjson[:80]+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = r.Diff(state, config, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff.Empty() || diff.RequiresNew() {
+		t.Fatalf("expected a changed file to be imported again in place, got %v", diff)
+	}
+
+	if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	sort.Strings(deleted)
+	expected := []string{"/s/ops/api/saved_objects/dashboard/overview", "/s/ops/api/saved_objects/index-pattern/logs"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected %v to be deleted, got %v", expected, deleted)
+	}
+}
+
+func TestResourceElasticsearchKibanaSavedObjectsImportContent(t *testing.T) {
+	ndjson := `{"type": "index-pattern", "id": "logs", "attributes": {"title": "logs-*"}}`
+	r := resourceElasticsearchKibanaSavedObjectsImport()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"content": ndjson,
+	})
+	state := &terraform.InstanceState{
+		ID: hashSum(ndjson),
+		Attributes: map[string]string{
+			"id":                hashSum(ndjson),
+			"content":           hashSum(ndjson),
+			"content_hash":      hashSum(ndjson),
+			"overwrite":         "true",
+			"delete_on_destroy": "true",
+			"objects.#":         "1",
+			"objects.0":         "index-pattern/logs",
+		},
+	}
+	diff, err := r.Diff(state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no diff for unchanged content, got %v", diff)
+	}
+
+	diff, err = r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"content": ndjson + "\n",
+	}), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := diff.Attributes["content_hash"].New; actual != hashSum(ndjson+"\n") {
+		t.Errorf("expected the hash of the new content, got %s", actual)
+	}
+}
+
+func TestAssignKibanaTags(t *testing.T) {
+	raw := json.RawMessage(`{"type": "dashboard", "id": "overview", "references": [{"type": "tag", "id": "ops", "name": "tag-ref-ops"}, {"type": "index-pattern", "id": "logs", "name": "kibanaSavedObjectMeta.searchSourceJSON.index"}]}`)
+	tagged, err := assignKibanaTags(raw, []string{"ops", "payments"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var object struct {
+		ID         string              `json:"id"`
+		References []map[string]string `json:"references"`
+	}
+	if err := json.Unmarshal(tagged, &object); err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]string{
+		{"type": "tag", "id": "ops", "name": "tag-ref-ops"},
+		{"type": "index-pattern", "id": "logs", "name": "kibanaSavedObjectMeta.searchSourceJSON.index"},
+		{"type": "tag", "id": "payments", "name": "tag-ref-payments"},
+	}
+	if object.ID != "overview" || !reflect.DeepEqual(object.References, expected) {
+		t.Errorf("unexpected tagged object: %s", tagged)
+	}
+
+	tagged, err = assignKibanaTags(json.RawMessage(`{"type": "search", "id": "errors"}`), []string{"ops"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(tagged), `"references":[{"id":"ops","name":"tag-ref-ops","type":"tag"}]`) {
+		t.Errorf("expected the references to be added, got %s", tagged)
+	}
+}
+
+// blockingReader returns io.EOF once unblocked, failing if it's read before,
+// e.g. by a transport buffering the body it belongs to.
+type blockingReader <-chan struct{}
+
+func (r blockingReader) Read(p []byte) (int, error) {
+	select {
+	case <-r:
+		return 0, io.EOF
+	case <-time.After(5 * time.Second):
+		return 0, errors.New("the body was read before being sent")
+	}
+}
+
+func TestKibanaUploadStreamsBody(t *testing.T) {
+	received := make(chan struct{})
+	var once sync.Once
+	uploads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+			return
+		}
+		// the headers are sent before the end of the body
+		once.Do(func() { close(received) })
+		b, _ := ioutil.ReadAll(r.Body)
+		if string(b) != "first, second" {
+			t.Errorf("unexpected upload: %s", b)
+		}
+		uploads++
+		if uploads == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success": true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":               server.URL,
+		"kibana_url":        server.URL,
+		"sniff":             false,
+		"healthcheck":       false,
+		"retry_backoff_min": "1ms",
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	body := func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(io.MultiReader(strings.NewReader("first, "), blockingReader(received), strings.NewReader("second"))), nil
+	}
+	res, err := kibanaUpload(meta.(*ProviderConf), "/api/saved_objects/_import", url.Values{}, "text/plain", body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(res) != `{"success": true}` || uploads != 2 {
+		t.Errorf("expected the upload to be retried once, got %s after %d uploads", res, uploads)
+	}
+}
@@ -0,0 +1,183 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func resourceElasticsearchKibanaTag() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchKibanaTagCreate,
+		Read:   resourceElasticsearchKibanaTagRead,
+		Update: resourceElasticsearchKibanaTagUpdate,
+		Delete: resourceElasticsearchKibanaTagDelete,
+		Schema: map[string]*schema.Schema{
+			"tag_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The ID of the saved object of the tag, e.g. to assign it with the `tags` of `elasticsearch_kibana_saved_objects_import`. Defaults to an ID generated by Kibana.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the tag, e.g. `team:payments`.",
+			},
+			"color": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`), "must be a hex color, e.g. #54B399"),
+				Description:  "The color of the tag, as a hex color, e.g. `#54B399`.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the tag.",
+			},
+			"space_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The Kibana space of the tag. Defaults to the default space.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchKibanaTagImport,
+		},
+		Description: "Manages a Kibana tag, through the saved objects API at the `kibana_url` of the provider, to make the dashboards and visualizations deployed by Terraform discoverable by team or tag conventions.",
+	}
+}
+
+// resourceElasticsearchKibanaTagImport imports a tag of the default space from
+// its ID, or of another space from an ID of the form space_id/tag_id.
+func resourceElasticsearchKibanaTagImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if !strings.Contains(d.Id(), "/") {
+		return []*schema.ResourceData{d}, nil
+	}
+	return importStateCompositeId("tag_id", "space_id", "tag_id")(d, meta)
+}
+
+func resourceElasticsearchKibanaTagCreate(d *schema.ResourceData, meta interface{}) error {
+	body := map[string]interface{}{
+		"attributes": expandKibanaTagAttributes(d),
+	}
+	res, err := kibanaTagRequest(d, meta, "POST", d.Get("tag_id").(string), body)
+	if err != nil {
+		return err
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(res, &object); err != nil {
+		return fmt.Errorf("error unmarshalling tag: %+v: %s", err, res)
+	}
+
+	d.SetId(object.ID)
+	return resourceElasticsearchKibanaTagRead(d, meta)
+}
+
+func resourceElasticsearchKibanaTagRead(d *schema.ResourceData, meta interface{}) error {
+	res, err := kibanaTagRequest(d, meta, "GET", d.Id(), nil)
+	if elastic7.IsNotFound(err) {
+		log.Printf("[WARN] Tag (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var object struct {
+		Attributes struct {
+			Name        string `json:"name"`
+			Color       string `json:"color"`
+			Description string `json:"description"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(res, &object); err != nil {
+		return fmt.Errorf("error unmarshalling tag: %+v: %s", err, res)
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("tag_id", d.Id())
+	ds.set("name", object.Attributes.Name)
+	ds.set("color", object.Attributes.Color)
+	ds.set("description", object.Attributes.Description)
+	return ds.err
+}
+
+func resourceElasticsearchKibanaTagUpdate(d *schema.ResourceData, meta interface{}) error {
+	body := map[string]interface{}{
+		"attributes": expandKibanaTagAttributes(d),
+	}
+	if _, err := kibanaTagRequest(d, meta, "PUT", d.Id(), body); err != nil {
+		return err
+	}
+	return resourceElasticsearchKibanaTagRead(d, meta)
+}
+
+func resourceElasticsearchKibanaTagDelete(d *schema.ResourceData, meta interface{}) error {
+	_, err := kibanaTagRequest(d, meta, "DELETE", d.Id(), nil)
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func expandKibanaTagAttributes(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        d.Get("name").(string),
+		"color":       d.Get("color").(string),
+		"description": d.Get("description").(string),
+	}
+}
+
+// kibanaTagRequest sends a request for the tag id, or a new one with a
+// generated ID if id is empty, to the saved objects API of Kibana, in the
+// space of the resource.
+func kibanaTagRequest(d *schema.ResourceData, meta interface{}, method, id string, body interface{}) (json.RawMessage, error) {
+	template := "/api/saved_objects/tag/{id}"
+	if id == "" {
+		template = "/api/saved_objects/tag"
+	}
+	objectPath, err := uritemplates.Expand(template, map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building URL path for tag: %+v", err)
+	}
+	path, err := kibanaSpacePath(d.Get("space_id").(string), objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	kibanaClient, err := getKibanaClient(meta.(*ProviderConf))
+	if err != nil {
+		return nil, err
+	}
+	client, ok := kibanaClient.(*elastic7.Client)
+	if !ok {
+		return nil, fmt.Errorf("tags are only available from Kibana >= 7.10")
+	}
+	res, err := client.PerformRequest(providerContext(meta), elastic7.PerformRequestOptions{
+		Method: method,
+		Path:   path,
+		Body:   body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
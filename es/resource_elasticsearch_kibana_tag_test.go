@@ -0,0 +1,134 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchKibanaTag(t *testing.T) {
+	attributes := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version": {"number": "8.11.0"}}`))
+			return
+		}
+		if r.URL.Path != "/s/ops/api/saved_objects/tag/team-payments" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			return
+		}
+		switch r.Method {
+		case "POST", "PUT":
+			if r.Header.Get("kbn-xsrf") != "true" {
+				t.Errorf("expected the kbn-xsrf header")
+			}
+			var object struct {
+				Attributes map[string]interface{} `json:"attributes"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&object); err != nil {
+				t.Fatal(err)
+			}
+			attributes = object.Attributes
+			_, _ = w.Write([]byte(`{"id": "team-payments", "type": "tag"}`))
+		case "GET":
+			if len(attributes) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"statusCode": 404, "error": "Not Found"}`))
+				return
+			}
+			b, _ := json.Marshal(map[string]interface{}{"id": "team-payments", "type": "tag", "attributes": attributes})
+			_, _ = w.Write(b)
+		case "DELETE":
+			attributes = map[string]interface{}{}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"kibana_url":  server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	r := resourceElasticsearchKibanaTag()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tag_id":   "team-payments",
+		"name":     "team:payments",
+		"color":    "#54B399",
+		"space_id": "ops",
+	})
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "team-payments" || attributes["name"] != "team:payments" || attributes["description"] != "" {
+		t.Errorf("unexpected tag %s: %v", d.Id(), attributes)
+	}
+
+	_ = d.Set("description", "Owned by the payments team")
+	if err := r.Update(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if attributes["description"] != "Owned by the payments team" || attributes["color"] != "#54B399" {
+		t.Errorf("unexpected tag: %v", attributes)
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d.SetId("team-payments")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deleted tag to be removed from state")
+	}
+}
+
+func TestAccElasticsearchKibanaTag(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityKibanaTags.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccKibanaProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKibanaTagResource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_kibana_tag.test", "id", "terraform-test"),
+					resource.TestCheckResourceAttr("elasticsearch_kibana_tag.test", "color", "#54B399"),
+				),
+			},
+		},
+	})
+}
+
+var testAccKibanaTagResource = `
+resource "elasticsearch_kibana_tag" "test" {
+  tag_id = "terraform-test"
+  name   = "terraform-test"
+  color  = "#54B399"
+}
+`
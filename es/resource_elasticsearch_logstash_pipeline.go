@@ -0,0 +1,245 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+// logstashPipelineSettings maps the arguments of the resource to the
+// pipeline_settings of the API, which are the settings of pipelines.yml.
+var logstashPipelineSettings = map[string]string{
+	"pipeline_workers":        "pipeline.workers",
+	"pipeline_batch_size":     "pipeline.batch.size",
+	"pipeline_batch_delay":    "pipeline.batch.delay",
+	"queue_type":              "queue.type",
+	"queue_max_bytes":         "queue.max_bytes",
+	"queue_checkpoint_writes": "queue.checkpoint.writes",
+}
+
+type logstashPipeline struct {
+	Description      string                 `json:"description"`
+	LastModified     string                 `json:"last_modified"`
+	PipelineMetadata json.RawMessage        `json:"pipeline_metadata"`
+	Username         string                 `json:"username"`
+	Pipeline         string                 `json:"pipeline"`
+	PipelineSettings map[string]interface{} `json:"pipeline_settings"`
+}
+
+func resourceElasticsearchLogstashPipeline() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchLogstashPipelineCreate,
+		Read:   resourceElasticsearchLogstashPipelineRead,
+		Update: resourceElasticsearchLogstashPipelineUpdate,
+		Delete: resourceElasticsearchLogstashPipelineDelete,
+		Schema: map[string]*schema.Schema{
+			"pipeline_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the pipeline, as listed in `xpack.management.pipeline.id` of the Logstash nodes running it.",
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"pipeline": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The configuration of the pipeline, e.g. `input { beats { port => 5044 } } output { elasticsearch { } }`.",
+			},
+			"pipeline_metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          `{"type":"logstash_pipeline","version":1}`,
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON metadata of the pipeline.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "terraform",
+				Description: "The user recorded as the last one to change the pipeline.",
+			},
+			"pipeline_workers": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of workers running the filter and output stages. Defaults to the number of CPUs of the Logstash node.",
+			},
+			"pipeline_batch_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of events a worker collects before running the filters and outputs.",
+			},
+			"pipeline_batch_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How long, in milliseconds, a worker waits for each event before running an undersized batch.",
+			},
+			"queue_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"memory", "persisted"}, false),
+				Description:  "The type of the queue buffering the events, `memory` or `persisted`.",
+			},
+			"queue_max_bytes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The capacity of a persisted queue, e.g. `1gb`.",
+			},
+			"queue_checkpoint_writes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The number of events written to a persisted queue before forcing a checkpoint.",
+			},
+			"last_modified": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the pipeline was last changed.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages a pipeline of Logstash centralized pipeline management, which Logstash nodes configured with `xpack.management` load from Elasticsearch.",
+	}
+}
+
+func resourceElasticsearchLogstashPipelineCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutLogstashPipeline(d, meta); err != nil {
+		return err
+	}
+	d.SetId(d.Get("pipeline_id").(string))
+	return resourceElasticsearchLogstashPipelineRead(d, meta)
+}
+
+func resourceElasticsearchLogstashPipelineRead(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	path, err := logstashPipelinePath(id)
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	body, err := performRequest(providerContext(meta), esClient, "GET", path, nil, nil)
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	var pipelines map[string]logstashPipeline
+	if err == nil {
+		if err := json.Unmarshal(body, &pipelines); err != nil {
+			return fmt.Errorf("error unmarshalling Logstash pipeline: %+v: %s", err, body)
+		}
+	}
+	pipeline, ok := pipelines[id]
+	if !ok {
+		log.Printf("[WARN] Logstash pipeline (%s) not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("pipeline_id", id)
+	ds.set("description", pipeline.Description)
+	ds.set("pipeline", pipeline.Pipeline)
+	ds.set("pipeline_metadata", compactJson(pipeline.PipelineMetadata))
+	ds.set("username", pipeline.Username)
+	ds.set("last_modified", pipeline.LastModified)
+	for key, setting := range logstashPipelineSettings {
+		value := pipeline.PipelineSettings[setting]
+		if _, ok := d.Get(key).(int); ok {
+			// numbers are returned as floats, or as strings when set so
+			i, _ := strconv.Atoi(scalarString(value))
+			ds.set(key, i)
+		} else if value != nil {
+			ds.set(key, scalarString(value))
+		} else {
+			ds.set(key, "")
+		}
+	}
+	return ds.err
+}
+
+func resourceElasticsearchLogstashPipelineUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutLogstashPipeline(d, meta); err != nil {
+		return err
+	}
+	return resourceElasticsearchLogstashPipelineRead(d, meta)
+}
+
+func resourceElasticsearchLogstashPipelineDelete(d *schema.ResourceData, meta interface{}) error {
+	path, err := logstashPipelinePath(d.Id())
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, nil, nil)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceElasticsearchPutLogstashPipeline(d *schema.ResourceData, meta interface{}) error {
+	path, err := logstashPipelinePath(d.Get("pipeline_id").(string))
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]interface{}{}
+	for key, setting := range logstashPipelineSettings {
+		if value, ok := d.GetOk(key); ok {
+			settings[setting] = value
+		}
+	}
+	pipeline := logstashPipeline{
+		Description:      d.Get("description").(string),
+		LastModified:     time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+		PipelineMetadata: json.RawMessage(d.Get("pipeline_metadata").(string)),
+		Username:         d.Get("username").(string),
+		Pipeline:         d.Get("pipeline").(string),
+		PipelineSettings: settings,
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", path, nil, pipeline)
+		return err
+	})
+}
+
+func logstashPipelinePath(id string) (string, error) {
+	path, err := uritemplates.Expand("/_logstash/pipeline/{id}", map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for Logstash pipeline: %+v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,135 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func TestResourceElasticsearchLogstashPipelineCreate(t *testing.T) {
+	var stored []byte
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.17.0"}}`))
+		case r.URL.Path == "/_logstash/pipeline/beats" && r.Method == "PUT":
+			stored, _ = ioutil.ReadAll(r.Body)
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/_logstash/pipeline/beats" && r.Method == "GET":
+			// settings set from Kibana are stored as strings
+			var pipeline map[string]interface{}
+			_ = json.Unmarshal(stored, &pipeline)
+			pipeline["pipeline_settings"].(map[string]interface{})["pipeline.batch.size"] = "250"
+			body, _ := json.Marshal(map[string]interface{}{"beats": pipeline})
+			_, _ = w.Write(body)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchLogstashPipeline()
+	d := r.TestResourceData()
+	_ = d.Set("pipeline_id", "beats")
+	_ = d.Set("pipeline", "input { beats { port => 5044 } }")
+	_ = d.Set("pipeline_metadata", `{"type": "logstash_pipeline", "version": 1}`)
+	_ = d.Set("username", "terraform")
+	_ = d.Set("pipeline_workers", 2)
+	_ = d.Set("pipeline_batch_size", 250)
+	_ = d.Set("queue_type", "persisted")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var pipeline logstashPipeline
+	if err := json.Unmarshal(stored, &pipeline); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expectedSettings := map[string]interface{}{"pipeline.workers": float64(2), "pipeline.batch.size": float64(250), "queue.type": "persisted"}
+	if fmt.Sprint(pipeline.PipelineSettings) != fmt.Sprint(expectedSettings) {
+		t.Errorf("expected settings %v, got %v", expectedSettings, pipeline.PipelineSettings)
+	}
+	if pipeline.LastModified == "" || pipeline.Username != "terraform" {
+		t.Errorf("unexpected pipeline: %s", stored)
+	}
+
+	expected := map[string]interface{}{
+		"pipeline_workers":     2,
+		"pipeline_batch_size":  250,
+		"pipeline_batch_delay": 0,
+		"queue_type":           "persisted",
+		"queue_max_bytes":      "",
+		"last_modified":        pipeline.LastModified,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchLogstashPipeline(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityLogstashPipelines.check(testAccProvider.Meta().(*ProviderConf)); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchLogstashPipelineDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchLogstashPipeline,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_logstash_pipeline.test", "pipeline_workers", "2"),
+					resource.TestCheckResourceAttrSet("elasticsearch_logstash_pipeline.test", "last_modified"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_logstash_pipeline.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchLogstashPipelineDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_logstash_pipeline" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+		esClient, err := getClient(meta.(*ProviderConf))
+		if err != nil {
+			return err
+		}
+		_, err = performRequest(providerContext(meta), esClient, "GET", "/_logstash/pipeline/"+rs.Primary.ID, nil, nil)
+		if elastic7.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Logstash pipeline %q still exists", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+var testAccElasticsearchLogstashPipeline = `
+resource "elasticsearch_logstash_pipeline" "test" {
+  pipeline_id      = "terraform-test"
+  description      = "test pipeline"
+  pipeline         = "input { stdin {} } output { stdout {} }"
+  pipeline_workers = 2
+}
+`
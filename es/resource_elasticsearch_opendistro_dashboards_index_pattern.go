@@ -0,0 +1,183 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func resourceElasticsearchOpenDistroDashboardsIndexPattern() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchOpenDistroDashboardsIndexPatternCreate,
+		Read:   resourceElasticsearchOpenDistroDashboardsIndexPatternRead,
+		Update: resourceElasticsearchOpenDistroDashboardsIndexPatternUpdate,
+		Delete: resourceElasticsearchOpenDistroDashboardsIndexPatternDelete,
+		Schema: map[string]*schema.Schema{
+			"pattern_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The ID of the saved object of the index pattern, e.g. to reference it from visualizations. Defaults to an ID generated by OpenSearch Dashboards.",
+			},
+			"title": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The pattern of the indices, aliases or data streams to search, e.g. `logs-*`.",
+			},
+			"time_field_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The date field filtering the documents by the time picker, e.g. `@timestamp`. Leave it out for data without a time dimension.",
+			},
+			"tenant": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The tenant of the security plugin the index pattern belongs to, e.g. `global_tenant`, or `__user__` for the private tenant of the provider's user. Defaults to the default tenant of the user.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchOpenDistroDashboardsIndexPatternImport,
+		},
+		Description: "Manages an index pattern of OpenSearch Dashboards, through its saved objects API at the `kibana_url` of the provider, in the default tenant or another tenant of the security plugin. Kibana replaced index patterns with data views, whose API and objects OpenSearch Dashboards doesn't share.",
+	}
+}
+
+// resourceElasticsearchOpenDistroDashboardsIndexPatternImport imports an index
+// pattern of the default tenant from its ID, or of another tenant from an ID
+// of the form tenant/pattern_id.
+func resourceElasticsearchOpenDistroDashboardsIndexPatternImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if !strings.Contains(d.Id(), "/") {
+		return []*schema.ResourceData{d}, nil
+	}
+	return importStateCompositeId("pattern_id", "tenant", "pattern_id")(d, meta)
+}
+
+func resourceElasticsearchOpenDistroDashboardsIndexPatternCreate(d *schema.ResourceData, meta interface{}) error {
+	body := map[string]interface{}{
+		"attributes": expandDashboardsIndexPatternAttributes(d),
+	}
+	res, err := dashboardsSavedObjectRequest(d, meta, "POST", d.Get("pattern_id").(string), body)
+	if err != nil {
+		return err
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(res, &object); err != nil {
+		return fmt.Errorf("error unmarshalling index pattern: %+v: %s", err, res)
+	}
+
+	d.SetId(object.ID)
+	return resourceElasticsearchOpenDistroDashboardsIndexPatternRead(d, meta)
+}
+
+func resourceElasticsearchOpenDistroDashboardsIndexPatternRead(d *schema.ResourceData, meta interface{}) error {
+	res, err := dashboardsSavedObjectRequest(d, meta, "GET", d.Id(), nil)
+	if elastic7.IsNotFound(err) {
+		log.Printf("[WARN] Index pattern (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var object struct {
+		Attributes struct {
+			Title         string `json:"title"`
+			TimeFieldName string `json:"timeFieldName"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(res, &object); err != nil {
+		return fmt.Errorf("error unmarshalling index pattern: %+v: %s", err, res)
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("pattern_id", d.Id())
+	ds.set("title", object.Attributes.Title)
+	ds.set("time_field_name", object.Attributes.TimeFieldName)
+	return ds.err
+}
+
+func resourceElasticsearchOpenDistroDashboardsIndexPatternUpdate(d *schema.ResourceData, meta interface{}) error {
+	body := map[string]interface{}{
+		"attributes": expandDashboardsIndexPatternAttributes(d),
+	}
+	if _, err := dashboardsSavedObjectRequest(d, meta, "PUT", d.Id(), body); err != nil {
+		return err
+	}
+	return resourceElasticsearchOpenDistroDashboardsIndexPatternRead(d, meta)
+}
+
+func resourceElasticsearchOpenDistroDashboardsIndexPatternDelete(d *schema.ResourceData, meta interface{}) error {
+	_, err := dashboardsSavedObjectRequest(d, meta, "DELETE", d.Id(), nil)
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func expandDashboardsIndexPatternAttributes(d *schema.ResourceData) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"title": d.Get("title").(string),
+	}
+	// the attribute is left out, rather than empty, for patterns without time
+	if timeField := d.Get("time_field_name").(string); timeField != "" {
+		attributes["timeFieldName"] = timeField
+	}
+	return attributes
+}
+
+// dashboardsSavedObjectRequest sends a request for the index pattern id, or a
+// new one with a generated ID if id is empty, to the saved objects API of
+// OpenSearch Dashboards, in the tenant of the resource.
+func dashboardsSavedObjectRequest(d *schema.ResourceData, meta interface{}, method, id string, body interface{}) (json.RawMessage, error) {
+	template := "/api/saved_objects/index-pattern/{id}"
+	if id == "" {
+		template = "/api/saved_objects/index-pattern"
+	}
+	path, err := uritemplates.Expand(template, map[string]string{
+		"id": id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building URL path for index pattern: %+v", err)
+	}
+
+	// OpenSearch Dashboards rejects writes without its own xsrf header
+	headers := http.Header{}
+	headers.Set("osd-xsrf", "true")
+	if tenant := d.Get("tenant").(string); tenant != "" {
+		headers.Set("securitytenant", tenant)
+	}
+
+	kibanaClient, err := getKibanaClient(meta.(*ProviderConf))
+	if err != nil {
+		return nil, err
+	}
+	client, ok := kibanaClient.(*elastic7.Client)
+	if !ok {
+		return nil, fmt.Errorf("index patterns are only available from OpenSearch Dashboards")
+	}
+	res, err := client.PerformRequest(providerContext(meta), elastic7.PerformRequestOptions{
+		Method:  method,
+		Path:    path,
+		Headers: headers,
+		Body:    body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
@@ -0,0 +1,150 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchOpenDistroDashboardsIndexPattern(t *testing.T) {
+	attributes := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+			return
+		}
+		if r.URL.Path != "/api/saved_objects/index-pattern/logs" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			return
+		}
+		if r.Header.Get("securitytenant") != "analysts" {
+			t.Errorf("expected the analysts tenant, got %q", r.Header.Get("securitytenant"))
+		}
+		switch r.Method {
+		case "POST", "PUT":
+			if r.Header.Get("osd-xsrf") != "true" {
+				t.Errorf("expected the osd-xsrf header")
+			}
+			var object struct {
+				Attributes map[string]interface{} `json:"attributes"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&object); err != nil {
+				t.Fatal(err)
+			}
+			attributes = object.Attributes
+			_, _ = w.Write([]byte(`{"id": "logs", "type": "index-pattern"}`))
+		case "GET":
+			if len(attributes) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"statusCode": 404, "error": "Not Found"}`))
+				return
+			}
+			b, _ := json.Marshal(map[string]interface{}{"id": "logs", "type": "index-pattern", "attributes": attributes})
+			_, _ = w.Write(b)
+		case "DELETE":
+			attributes = map[string]interface{}{}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	raw := map[string]interface{}{
+		"url":         server.URL,
+		"kibana_url":  server.URL,
+		"sniff":       false,
+		"healthcheck": false,
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	conf := meta.(*ProviderConf)
+
+	r := resourceElasticsearchOpenDistroDashboardsIndexPattern()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"pattern_id":      "logs",
+		"title":           "logs-*",
+		"time_field_name": "@timestamp",
+		"tenant":          "analysts",
+	})
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "logs" || attributes["title"] != "logs-*" || attributes["timeFieldName"] != "@timestamp" {
+		t.Errorf("unexpected index pattern %s: %v", d.Id(), attributes)
+	}
+
+	_ = d.Set("time_field_name", "")
+	if err := r.Update(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := attributes["timeFieldName"]; ok {
+		t.Errorf("expected the time field to be left out, got %v", attributes)
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d.SetId("logs")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deleted index pattern to be removed from state")
+	}
+}
+
+func TestResourceElasticsearchOpenDistroDashboardsIndexPatternElasticsearch(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+	})
+	p := Provider().(*schema.Provider)
+	r := p.ResourcesMap["elasticsearch_opendistro_dashboards_index_pattern"]
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{"title": "logs-*"}), conf)
+	if err == nil || !strings.Contains(err.Error(), "OpenSearch Dashboards index patterns are not available on Elasticsearch") {
+		t.Errorf("expected a capability error, got %v", err)
+	}
+}
+
+func TestAccElasticsearchOpenDistroDashboardsIndexPattern(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityDashboardsIndexPatterns.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccKibanaProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOpenDistroDashboardsIndexPatternResource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_opendistro_dashboards_index_pattern.test", "id", "terraform-test"),
+					resource.TestCheckResourceAttr("elasticsearch_opendistro_dashboards_index_pattern.test", "time_field_name", "@timestamp"),
+				),
+			},
+		},
+	})
+}
+
+var testAccOpenDistroDashboardsIndexPatternResource = `
+resource "elasticsearch_opendistro_dashboards_index_pattern" "test" {
+  pattern_id      = "terraform-test"
+  title           = "terraform-test-*"
+  time_field_name = "@timestamp"
+}
+`
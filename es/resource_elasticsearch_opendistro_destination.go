@@ -111,6 +111,7 @@ func resourceElasticsearchOpenDistroDestinationUpdate(d *schema.ResourceData, m
 }
 
 func resourceElasticsearchOpenDistroDestinationDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	var err error
 
 	path, err := uritemplates.Expand("/_opendistro/_alerting/destinations/{id}", map[string]string{
@@ -126,14 +127,20 @@ func resourceElasticsearchOpenDistroDestinationDelete(d *schema.ResourceData, m
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method: "DELETE",
-			Path:   path,
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
 		})
 	case *elastic6.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
-			Method: "DELETE",
-			Path:   path,
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic6.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
 		})
 	default:
 		err = errors.New("destination resource not implemented prior to Elastic v6")
@@ -142,7 +149,7 @@ func resourceElasticsearchOpenDistroDestinationDelete(d *schema.ResourceData, m
 	return err
 }
 
-func resourceElasticsearchOpenDistroGetDestination(destinationID string, esClient interface{}) (Destination, error) {
+func resourceElasticsearchOpenDistroGetDestination(ctx context.Context, destinationID string, esClient interface{}) (Destination, error) {
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		path, err := uritemplates.Expand("/_opendistro/_alerting/destinations/{id}", map[string]string{
@@ -152,7 +159,7 @@ func resourceElasticsearchOpenDistroGetDestination(destinationID string, esClien
 			return Destination{}, fmt.Errorf("error building URL path for destination: %+v", err)
 		}
 
-		httpResponse, err := client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		httpResponse, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -178,6 +185,7 @@ func resourceElasticsearchOpenDistroGetDestination(destinationID string, esClien
 }
 
 func resourceElasticsearchOpenDistroQueryOrGetDestination(destinationID string, m interface{}) (Destination, error) {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return Destination{}, err
@@ -189,11 +197,11 @@ func resourceElasticsearchOpenDistroQueryOrGetDestination(destinationID string,
 		// See https://github.com/opendistro-for-elasticsearch/alerting/issues/56,
 		// no API endpoint for retrieving destination prior to ODFE 1.11.0. So do
 		// a request, if it 404s, fall back to trying to query the index.
-		destination, err := resourceElasticsearchOpenDistroGetDestination(destinationID, client)
+		destination, err := resourceElasticsearchOpenDistroGetDestination(ctx, destinationID, client)
 		if err == nil {
 			return destination, err
 		} else {
-			result, err := elastic7GetObject(client, DESTINATION_INDEX, destinationID)
+			result, err := elastic7GetObject(ctx, client, DESTINATION_INDEX, destinationID)
 
 			if err != nil {
 				return Destination{}, err
@@ -204,7 +212,7 @@ func resourceElasticsearchOpenDistroQueryOrGetDestination(destinationID string,
 			return dr.Destination, nil
 		}
 	case *elastic6.Client:
-		result, err := elastic6GetObject(client, DESTINATION_TYPE, DESTINATION_INDEX, destinationID)
+		result, err := elastic6GetObject(ctx, client, DESTINATION_TYPE, DESTINATION_INDEX, destinationID)
 		if err != nil {
 			return Destination{}, err
 		}
@@ -218,6 +226,7 @@ func resourceElasticsearchOpenDistroQueryOrGetDestination(destinationID string,
 }
 
 func resourceElasticsearchOpenDistroPostDestination(d *schema.ResourceData, m interface{}) (*destinationResponse, error) {
+	ctx := providerContext(m)
 	destinationJSON := d.Get("body").(string)
 
 	var err error
@@ -233,7 +242,7 @@ func resourceElasticsearchOpenDistroPostDestination(d *schema.ResourceData, m in
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "POST",
 			Path:   path,
 			Body:   destinationJSON,
@@ -241,7 +250,7 @@ func resourceElasticsearchOpenDistroPostDestination(d *schema.ResourceData, m in
 		body = res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "POST",
 			Path:   path,
 			Body:   destinationJSON,
@@ -263,6 +272,7 @@ func resourceElasticsearchOpenDistroPostDestination(d *schema.ResourceData, m in
 }
 
 func resourceElasticsearchOpenDistroPutDestination(d *schema.ResourceData, m interface{}) (*destinationResponse, error) {
+	ctx := providerContext(m)
 	destinationJSON := d.Get("body").(string)
 
 	var err error
@@ -283,7 +293,7 @@ func resourceElasticsearchOpenDistroPutDestination(d *schema.ResourceData, m int
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "PUT",
 			Path:   path,
 			Body:   destinationJSON,
@@ -291,7 +301,7 @@ func resourceElasticsearchOpenDistroPutDestination(d *schema.ResourceData, m int
 		body = res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "PUT",
 			Path:   path,
 			Body:   destinationJSON,
@@ -1,15 +1,12 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
@@ -80,6 +77,7 @@ func resourceElasticsearchOpenDistroISMPolicyRead(d *schema.ResourceData, m inte
 		return err
 	}
 
+	stripPolicy(policyResponse.Policy)
 	bodyString, err := json.Marshal(policyResponse.Policy)
 	if err != nil {
 		return err
@@ -113,6 +111,7 @@ func resourceElasticsearchOpenDistroISMPolicyUpdate(d *schema.ResourceData, m in
 }
 
 func resourceElasticsearchOpenDistroISMPolicyDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	path, err := uritemplates.Expand("/_opendistro/_ism/policies/{policy_id}", map[string]string{
 		"policy_id": d.Id(),
 	})
@@ -126,20 +125,19 @@ func resourceElasticsearchOpenDistroISMPolicyDelete(d *schema.ResourceData, m in
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "DELETE",
-			Path:             path,
-			RetryStatusCodes: []int{http.StatusConflict},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
 		})
 
 		if err != nil {
 			return fmt.Errorf("error deleting policy: %+v : %+v", path, err)
 		}
 	case *elastic6.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		_, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "DELETE",
 			Path:   path,
 		})
@@ -155,6 +153,7 @@ func resourceElasticsearchOpenDistroISMPolicyDelete(d *schema.ResourceData, m in
 }
 
 func resourceElasticsearchGetOpenDistroISMPolicy(policyID string, m interface{}) (GetPolicyResponse, error) {
+	ctx := providerContext(m)
 	var err error
 	response := new(GetPolicyResponse)
 
@@ -174,7 +173,7 @@ func resourceElasticsearchGetOpenDistroISMPolicy(policyID string, m interface{})
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -185,7 +184,7 @@ func resourceElasticsearchGetOpenDistroISMPolicy(policyID string, m interface{})
 		body = &res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -212,6 +211,7 @@ func resourceElasticsearchGetOpenDistroISMPolicy(policyID string, m interface{})
 }
 
 func resourceElasticsearchPutOpenDistroISMPolicy(d *schema.ResourceData, m interface{}) (*PutPolicyResponse, error) {
+	ctx := providerContext(m)
 	response := new(PutPolicyResponse)
 	policyJSON := d.Get("body").(string)
 	seq := d.Get("seq_no").(int)
@@ -238,15 +238,15 @@ func resourceElasticsearchPutOpenDistroISMPolicy(d *schema.ResourceData, m inter
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "PUT",
-			Path:             path,
-			Params:           params,
-			Body:             string(policyJSON),
-			RetryStatusCodes: []int{http.StatusConflict},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
+		err = retryOnTransientErrors(m, func() error {
+			var err error
+			res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "PUT",
+				Path:   path,
+				Params: params,
+				Body:   string(policyJSON),
+			})
+			return err
 		})
 		if err != nil {
 			return response, fmt.Errorf("error putting policy: %+v : %+v : %+v", path, policyJSON, err)
@@ -254,7 +254,7 @@ func resourceElasticsearchPutOpenDistroISMPolicy(d *schema.ResourceData, m inter
 		body = &res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "PUT",
 			Path:   path,
 			Params: params,
@@ -1,7 +1,6 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -59,7 +58,7 @@ func resourceElasticsearchOpenDistroISMPolicyMapping() *schema.Resource {
 			},
 		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStateCompositeId("indexes", "indexes", "policy_id"),
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
@@ -179,6 +178,7 @@ func resourceElasticsearchOpenDistroISMPolicyMappingDelete(d *schema.ResourceDat
 }
 
 func resourceElasticsearchPostOpendistroPolicyMapping(d *schema.ResourceData, m interface{}, action string) (*PolicyMappingResponse, error) {
+	ctx := providerContext(m)
 	response := new(PolicyMappingResponse)
 	requestBody := ""
 
@@ -226,7 +226,7 @@ func resourceElasticsearchPostOpendistroPolicyMapping(d *schema.ResourceData, m
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "POST",
 			Path:   path,
 			Body:   requestBody,
@@ -251,6 +251,7 @@ func resourceElasticsearchPostOpendistroPolicyMapping(d *schema.ResourceData, m
 }
 
 func resourceElasticsearchGetOpendistroPolicyMapping(indexPattern string, m interface{}) (map[string]interface{}, error) {
+	ctx := providerContext(m)
 	response := new(map[string]interface{})
 	path, err := uritemplates.Expand("/_opendistro/_ism/explain/{index_pattern}", map[string]string{
 		"index_pattern": indexPattern,
@@ -267,7 +268,7 @@ func resourceElasticsearchGetOpendistroPolicyMapping(indexPattern string, m inte
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
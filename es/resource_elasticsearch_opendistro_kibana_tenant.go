@@ -1,7 +1,6 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,7 +8,6 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/olivere/elastic/uritemplates"
@@ -112,6 +110,7 @@ func resourceElasticsearchOpenDistroKibanaTenantUpdate(d *schema.ResourceData, m
 }
 
 func resourceElasticsearchOpenDistroKibanaTenantDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	path, err := uritemplates.Expand("/_opendistro/_security/api/tenants/{name}", map[string]string{
 		"name": d.Get("tenant_name").(string),
 	})
@@ -125,14 +124,13 @@ func resourceElasticsearchOpenDistroKibanaTenantDelete(d *schema.ResourceData, m
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "DELETE",
-			Path:             path,
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
+		}, http.StatusInternalServerError)
 	default:
 		err = errors.New("Creating tenants requires elastic v7 client")
 	}
@@ -141,6 +139,7 @@ func resourceElasticsearchOpenDistroKibanaTenantDelete(d *schema.ResourceData, m
 }
 
 func resourceElasticsearchGetOpenDistroKibanaTenant(tenantID string, m interface{}) (TenantBody, error) {
+	ctx := providerContext(m)
 	var err error
 	tenant := new(TenantBody)
 
@@ -160,7 +159,7 @@ func resourceElasticsearchGetOpenDistroKibanaTenant(tenantID string, m interface
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -184,6 +183,7 @@ func resourceElasticsearchGetOpenDistroKibanaTenant(tenantID string, m interface
 }
 
 func resourceElasticsearchPutOpenDistroKibanaTenant(d *schema.ResourceData, m interface{}) (*TenantResponse, error) {
+	ctx := providerContext(m)
 	response := new(TenantResponse)
 
 	tenantsDefinition := TenantBody{
@@ -210,15 +210,15 @@ func resourceElasticsearchPutOpenDistroKibanaTenant(d *schema.ResourceData, m in
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "PUT",
-			Path:             path,
-			Body:             string(tenantJSON),
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		err = retryOnTransientErrors(m, func() error {
+			var err error
+			res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "PUT",
+				Path:   path,
+				Body:   string(tenantJSON),
+			})
+			return err
+		}, http.StatusInternalServerError)
 		body = res.Body
 	default:
 		err = errors.New("Creating tenants requires elastic v7 client")
@@ -1,7 +1,6 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -111,6 +110,7 @@ func resourceElasticsearchOpenDistroMonitorUpdate(d *schema.ResourceData, m inte
 }
 
 func resourceElasticsearchOpenDistroMonitorDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	var err error
 
 	path, err := uritemplates.Expand("/_opendistro/_alerting/monitors/{id}", map[string]string{
@@ -126,14 +126,20 @@ func resourceElasticsearchOpenDistroMonitorDelete(d *schema.ResourceData, m inte
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method: "DELETE",
-			Path:   path,
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
 		})
 	case *elastic6.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
-			Method: "DELETE",
-			Path:   path,
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic6.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
 		})
 	default:
 		err = errors.New("monitor resource not implemented prior to Elastic v6")
@@ -143,6 +149,7 @@ func resourceElasticsearchOpenDistroMonitorDelete(d *schema.ResourceData, m inte
 }
 
 func resourceElasticsearchOpenDistroGetMonitor(monitorID string, m interface{}) (*monitorResponse, error) {
+	ctx := providerContext(m)
 	var err error
 	response := new(monitorResponse)
 
@@ -161,14 +168,14 @@ func resourceElasticsearchOpenDistroGetMonitor(monitorID string, m interface{})
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
 		body = res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -189,6 +196,7 @@ func resourceElasticsearchOpenDistroGetMonitor(monitorID string, m interface{})
 }
 
 func resourceElasticsearchOpenDistroPostMonitor(d *schema.ResourceData, m interface{}) (*monitorResponse, error) {
+	ctx := providerContext(m)
 	monitorJSON := d.Get("body").(string)
 
 	var err error
@@ -204,7 +212,7 @@ func resourceElasticsearchOpenDistroPostMonitor(d *schema.ResourceData, m interf
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "POST",
 			Path:   path,
 			Body:   monitorJSON,
@@ -212,7 +220,7 @@ func resourceElasticsearchOpenDistroPostMonitor(d *schema.ResourceData, m interf
 		body = res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "POST",
 			Path:   path,
 			Body:   monitorJSON,
@@ -234,6 +242,7 @@ func resourceElasticsearchOpenDistroPostMonitor(d *schema.ResourceData, m interf
 }
 
 func resourceElasticsearchOpenDistroPutMonitor(d *schema.ResourceData, m interface{}) (*monitorResponse, error) {
+	ctx := providerContext(m)
 	monitorJSON := d.Get("body").(string)
 
 	var err error
@@ -254,7 +263,7 @@ func resourceElasticsearchOpenDistroPutMonitor(d *schema.ResourceData, m interfa
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "PUT",
 			Path:   path,
 			Body:   monitorJSON,
@@ -262,7 +271,7 @@ func resourceElasticsearchOpenDistroPutMonitor(d *schema.ResourceData, m interfa
 		body = res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "PUT",
 			Path:   path,
 			Body:   monitorJSON,
@@ -1,13 +1,11 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/olivere/elastic/uritemplates"
@@ -173,6 +171,7 @@ func resourceElasticsearchOpenDistroRoleUpdate(d *schema.ResourceData, m interfa
 }
 
 func resourceElasticsearchOpenDistroRoleDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	path, err := uritemplates.Expand("/_opendistro/_security/api/roles/{name}", map[string]string{
 		"name": d.Get("role_name").(string),
 	})
@@ -186,14 +185,13 @@ func resourceElasticsearchOpenDistroRoleDelete(d *schema.ResourceData, m interfa
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "DELETE",
-			Path:             path,
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
+		}, http.StatusInternalServerError)
 	default:
 		err = errors.New("role resource not implemented prior to Elastic v7")
 	}
@@ -202,6 +200,7 @@ func resourceElasticsearchOpenDistroRoleDelete(d *schema.ResourceData, m interfa
 }
 
 func resourceElasticsearchGetOpenDistroRole(roleID string, m interface{}) (RoleBody, error) {
+	ctx := providerContext(m)
 	var err error
 	role := new(RoleBody)
 
@@ -221,7 +220,7 @@ func resourceElasticsearchGetOpenDistroRole(roleID string, m interface{}) (RoleB
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -245,11 +244,12 @@ func resourceElasticsearchGetOpenDistroRole(roleID string, m interface{}) (RoleB
 }
 
 func resourceElasticsearchPutOpenDistroRole(d *schema.ResourceData, m interface{}) (*RoleResponse, error) {
+	ctx := providerContext(m)
 	response := new(RoleResponse)
 
 	indexPermissions, err := expandIndexPermissionsSet(d.Get("index_permissions").(*schema.Set).List())
 	if err != nil {
-		fmt.Print("Error in index get : ", err)
+		return response, fmt.Errorf("error expanding index_permissions: %+v", err)
 	}
 	var indexPermissionsBody []IndexPermissions
 	for _, idx := range indexPermissions {
@@ -265,7 +265,7 @@ func resourceElasticsearchPutOpenDistroRole(d *schema.ResourceData, m interface{
 
 	tenantPermissions, err := expandTenantPermissionsSet(d.Get("tenant_permissions").(*schema.Set).List())
 	if err != nil {
-		fmt.Print("Error in tenant get : ", err)
+		return response, fmt.Errorf("error expanding tenant_permissions: %+v", err)
 	}
 	var tenantPermissionsBody []TenantPermissions
 	for _, tenant := range tenantPermissions {
@@ -303,20 +303,20 @@ func resourceElasticsearchPutOpenDistroRole(d *schema.ResourceData, m interface{
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method: "PUT",
-			Path:   path,
-			Body:   string(roleJSON),
-			// see https://github.com/opendistro-for-
-			// elasticsearch/security/issues/1095, this should return a 409, but
-			// retry on the 500 as well. We can't parse the message to only retry on
-			// the conlict exception becaues the elastic client doesn't directly
-			// expose the error response body
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		// see https://github.com/opendistro-for-
+		// elasticsearch/security/issues/1095, this should return a 409, but
+		// retry on the 500 as well. We can't parse the message to only retry on
+		// the conlict exception becaues the elastic client doesn't directly
+		// expose the error response body
+		err = retryOnTransientErrors(m, func() error {
+			var err error
+			res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "PUT",
+				Path:   path,
+				Body:   string(roleJSON),
+			})
+			return err
+		}, http.StatusInternalServerError)
 		body = res.Body
 	default:
 		err = errors.New("role resource not implemented prior to Elastic v7")
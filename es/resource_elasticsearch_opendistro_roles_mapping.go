@@ -1,13 +1,11 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/olivere/elastic/uritemplates"
@@ -112,6 +110,7 @@ func resourceElasticsearchOpenDistroRolesMappingUpdate(d *schema.ResourceData, m
 }
 
 func resourceElasticsearchOpenDistroRolesMappingDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	path, err := uritemplates.Expand("/_opendistro/_security/api/rolesmapping/{name}", map[string]string{
 		"name": d.Get("role_name").(string),
 	})
@@ -125,14 +124,13 @@ func resourceElasticsearchOpenDistroRolesMappingDelete(d *schema.ResourceData, m
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "DELETE",
-			Path:             path,
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
+		}, http.StatusInternalServerError)
 	default:
 		err = errors.New("role mapping resource not implemented prior to Elastic v7")
 	}
@@ -141,6 +139,7 @@ func resourceElasticsearchOpenDistroRolesMappingDelete(d *schema.ResourceData, m
 }
 
 func resourceElasticsearchGetOpenDistroRolesMapping(roleID string, m interface{}) (RolesMapping, error) {
+	ctx := providerContext(m)
 	var err error
 	var roleMapping = new(RolesMapping)
 
@@ -159,7 +158,7 @@ func resourceElasticsearchGetOpenDistroRolesMapping(roleID string, m interface{}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -183,6 +182,7 @@ func resourceElasticsearchGetOpenDistroRolesMapping(roleID string, m interface{}
 }
 
 func resourceElasticsearchPutOpenDistroRolesMapping(d *schema.ResourceData, m interface{}) (*RoleMappingResponse, error) {
+	ctx := providerContext(m)
 	var err error
 	response := new(RoleMappingResponse)
 
@@ -215,20 +215,20 @@ func resourceElasticsearchPutOpenDistroRolesMapping(d *schema.ResourceData, m in
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method: "PUT",
-			Path:   path,
-			Body:   string(roleJSON),
-			// see https://github.com/opendistro-for-
-			// elasticsearch/security/issues/1095, this should return a 409, but
-			// retry on the 500 as well. We can't parse the message to only retry on
-			// the conlict exception becaues the elastic client doesn't directly
-			// expose the error response body
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		// see https://github.com/opendistro-for-
+		// elasticsearch/security/issues/1095, this should return a 409, but
+		// retry on the 500 as well. We can't parse the message to only retry on
+		// the conlict exception becaues the elastic client doesn't directly
+		// expose the error response body
+		err = retryOnTransientErrors(m, func() error {
+			var err error
+			res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "PUT",
+				Path:   path,
+				Body:   string(roleJSON),
+			})
+			return err
+		}, http.StatusInternalServerError)
 		body = res.Body
 	default:
 		err = errors.New("role mapping resource not implemented prior to Elastic v7")
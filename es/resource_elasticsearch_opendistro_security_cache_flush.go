@@ -0,0 +1,53 @@
+package es
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceElasticsearchOpenDistroSecurityCacheFlush() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchOpenDistroSecurityCacheFlushCreate,
+		Read:   resourceElasticsearchOpenDistroSecurityCacheFlushRead,
+		Delete: resourceElasticsearchOpenDistroSecurityCacheFlushDelete,
+		Schema: map[string]*schema.Schema{
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that flush the cache again when they change, e.g. the password of a rotated user or the ID of a changed role mapping.",
+			},
+		},
+		Description: "Flushes the cache of the security plugin once, when the resource is created or its triggers change, so that the users, roles and credentials changed outside of the security REST API, e.g. in an LDAP directory or by securityadmin, take effect right away. Destroying the resource does nothing.",
+	}
+}
+
+func resourceElasticsearchOpenDistroSecurityCacheFlushCreate(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", "/_opendistro/_security/api/cache", nil, nil)
+		return err
+	}, http.StatusInternalServerError)
+	if err != nil {
+		return err
+	}
+
+	d.SetId("security_cache")
+	return nil
+}
+
+// resourceElasticsearchOpenDistroSecurityCacheFlushRead keeps the state of the
+// flush, an action without anything to read back.
+func resourceElasticsearchOpenDistroSecurityCacheFlushRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchOpenDistroSecurityCacheFlushDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
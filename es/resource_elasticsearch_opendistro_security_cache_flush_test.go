@@ -0,0 +1,32 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResourceElasticsearchOpenDistroSecurityCacheFlush(t *testing.T) {
+	flushed := 0
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+		case r.URL.Path == "/_opendistro/_security/api/cache" && r.Method == "DELETE":
+			flushed++
+			_, _ = w.Write([]byte(`{"status": "OK", "message": "Cache flushed successfully."}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchOpenDistroSecurityCacheFlush()
+	d := r.TestResourceData()
+	_ = d.Set("triggers", map[string]interface{}{"password_version": "2"})
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if flushed != 1 || d.Id() != "security_cache" {
+		t.Errorf("expected the cache to be flushed once, got %d flushes and ID %s", flushed, d.Id())
+	}
+}
@@ -0,0 +1,118 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	tenancyConfigPath = "/_plugins/_security/api/tenancy/config"
+	globalTenant      = "global_tenant"
+)
+
+// tenancyConfig is the multi-tenancy configuration of OpenSearch Dashboards,
+// stored by the security plugin.
+type tenancyConfig struct {
+	MultitenancyEnabled  bool   `json:"multitenancy_enabled"`
+	PrivateTenantEnabled bool   `json:"private_tenant_enabled"`
+	DefaultTenant        string `json:"default_tenant"`
+}
+
+func resourceElasticsearchOpenDistroTenancyConfig() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchOpenDistroTenancyConfigUpdate,
+		Read:   resourceElasticsearchOpenDistroTenancyConfigRead,
+		Update: resourceElasticsearchOpenDistroTenancyConfigUpdate,
+		Delete: resourceElasticsearchOpenDistroTenancyConfigDelete,
+		Schema: map[string]*schema.Schema{
+			"multitenancy_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the users of OpenSearch Dashboards can switch between tenants. Otherwise everybody uses the global tenant.",
+			},
+			"private_tenant_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether each user gets a private tenant.",
+			},
+			"default_tenant": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      globalTenant,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The tenant users get when they log in: `global_tenant`, `__user__` for their private tenant, or the name of a custom tenant, e.g. one of an `elasticsearch_opendistro_kibana_tenant`.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages the multi-tenancy configuration of OpenSearch Dashboards, otherwise only changeable in Dashboards or with securityadmin. Destroying the resource enables multi-tenancy and private tenants again, with the global tenant as the default. There is only one per cluster, its ID is `tenancy`. Requires OpenSearch >= 2.7.",
+	}
+}
+
+func resourceElasticsearchOpenDistroTenancyConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := tenancyConfig{
+		MultitenancyEnabled:  d.Get("multitenancy_enabled").(bool),
+		PrivateTenantEnabled: d.Get("private_tenant_enabled").(bool),
+		DefaultTenant:        d.Get("default_tenant").(string),
+	}
+	if err := putTenancyConfig(d, meta, config); err != nil {
+		return err
+	}
+	d.SetId("tenancy")
+	return resourceElasticsearchOpenDistroTenancyConfigRead(d, meta)
+}
+
+func resourceElasticsearchOpenDistroTenancyConfigRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	res, err := performRequest(providerContext(meta), esClient, "GET", tenancyConfigPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	var config tenancyConfig
+	if err := json.Unmarshal(res, &config); err != nil {
+		return fmt.Errorf("error unmarshalling tenancy config: %+v: %s", err, res)
+	}
+	// the default tenant isn't returned until it's configured
+	if config.DefaultTenant == "" {
+		config.DefaultTenant = globalTenant
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("multitenancy_enabled", config.MultitenancyEnabled)
+	ds.set("private_tenant_enabled", config.PrivateTenantEnabled)
+	ds.set("default_tenant", config.DefaultTenant)
+	return ds.err
+}
+
+func resourceElasticsearchOpenDistroTenancyConfigDelete(d *schema.ResourceData, meta interface{}) error {
+	config := tenancyConfig{
+		MultitenancyEnabled:  true,
+		PrivateTenantEnabled: true,
+		DefaultTenant:        globalTenant,
+	}
+	if err := putTenancyConfig(d, meta, config); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func putTenancyConfig(d *schema.ResourceData, meta interface{}, config tenancyConfig) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", tenancyConfigPath, nil, config)
+		return err
+	})
+}
@@ -0,0 +1,109 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchOpenDistroTenancyConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"multitenancy_enabled":   true,
+		"private_tenant_enabled": true,
+	}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"distribution": "opensearch", "number": "2.11.0"}, "tagline": "The OpenSearch Project: https://opensearch.org/"}`))
+		case r.URL.Path == "/_plugins/_security/api/tenancy/config" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+				t.Fatal(err)
+			}
+			b, _ := json.Marshal(config)
+			_, _ = w.Write(b)
+		case r.URL.Path == "/_plugins/_security/api/tenancy/config":
+			b, _ := json.Marshal(config)
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchOpenDistroTenancyConfig()
+	d := r.TestResourceData()
+	d.SetId("tenancy")
+	if err := r.Read(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("default_tenant") != "global_tenant" {
+		t.Errorf("expected the global tenant to be the default, got %v", d.Get("default_tenant"))
+	}
+
+	_ = d.Set("private_tenant_enabled", false)
+	_ = d.Set("default_tenant", "analysts")
+	if err := r.Update(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if config["private_tenant_enabled"] != false || config["default_tenant"] != "analysts" || config["multitenancy_enabled"] != true {
+		t.Errorf("unexpected tenancy config: %v", config)
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if config["private_tenant_enabled"] != true || config["default_tenant"] != "global_tenant" {
+		t.Errorf("expected the tenancy config to be reset, got %v", config)
+	}
+}
+
+func TestResourceElasticsearchOpenDistroTenancyConfigElasticsearch(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+	})
+	p := Provider().(*schema.Provider)
+	r := p.ResourcesMap["elasticsearch_opendistro_tenancy_config"]
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{}), conf)
+	if err == nil || !strings.Contains(err.Error(), "multi-tenancy configurations are not available on Elasticsearch") {
+		t.Errorf("expected a capability error, got %v", err)
+	}
+}
+
+func TestAccElasticsearchOpenDistroTenancyConfig(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityTenancyConfig.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccOpendistroProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOpenDistroTenancyConfigResource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_opendistro_tenancy_config.test", "id", "tenancy"),
+					resource.TestCheckResourceAttr("elasticsearch_opendistro_tenancy_config.test", "private_tenant_enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+var testAccOpenDistroTenancyConfigResource = `
+resource "elasticsearch_opendistro_tenancy_config" "test" {
+  private_tenant_enabled = false
+}
+`
@@ -1,13 +1,11 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/olivere/elastic/uritemplates"
@@ -101,6 +99,7 @@ func resourceElasticsearchOpenDistroUserUpdate(d *schema.ResourceData, m interfa
 }
 
 func resourceElasticsearchOpenDistroUserDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	var err error
 
 	path, err := uritemplates.Expand("/_opendistro/_security/api/internalusers/{name}", map[string]string{
@@ -116,14 +115,13 @@ func resourceElasticsearchOpenDistroUserDelete(d *schema.ResourceData, m interfa
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method:           "DELETE",
-			Path:             path,
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		err = retryOnTransientErrors(m, func() error {
+			_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "DELETE",
+				Path:   path,
+			})
+			return err
+		}, http.StatusInternalServerError)
 	default:
 		err = errors.New("Role resource not implemented prior to Elastic v7")
 	}
@@ -132,6 +130,7 @@ func resourceElasticsearchOpenDistroUserDelete(d *schema.ResourceData, m interfa
 }
 
 func resourceElasticsearchGetOpenDistroUser(userID string, m interface{}) (UserBody, error) {
+	ctx := providerContext(m)
 	var err error
 	user := new(UserBody)
 
@@ -151,7 +150,7 @@ func resourceElasticsearchGetOpenDistroUser(userID string, m interface{}) (UserB
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   path,
 		})
@@ -175,6 +174,7 @@ func resourceElasticsearchGetOpenDistroUser(userID string, m interface{}) (UserB
 }
 
 func resourceElasticsearchPutOpenDistroUser(d *schema.ResourceData, m interface{}) (*UserResponse, error) {
+	ctx := providerContext(m)
 	response := new(UserResponse)
 
 	userDefinition := UserBody{
@@ -211,20 +211,20 @@ func resourceElasticsearchPutOpenDistroUser(d *schema.ResourceData, m interface{
 	case *elastic7.Client:
 		var res *elastic7.Response
 		log.Printf("[INFO] put opendistro user: %+v", userDefinition)
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
-			Method: "PUT",
-			Path:   path,
-			Body:   string(userJSON),
-			// see https://github.com/opendistro-for-
-			// elasticsearch/security/issues/1095, this should return a 409, but
-			// retry on the 500 as well. We can't parse the message to only retry on
-			// the conlict exception becaues the elastic client doesn't directly
-			// expose the error response body
-			RetryStatusCodes: []int{http.StatusConflict, http.StatusInternalServerError},
-			Retrier: elastic7.NewBackoffRetrier(
-				elastic7.NewExponentialBackoff(100*time.Millisecond, 30*time.Second),
-			),
-		})
+		// see https://github.com/opendistro-for-
+		// elasticsearch/security/issues/1095, this should return a 409, but
+		// retry on the 500 as well. We can't parse the message to only retry on
+		// the conlict exception becaues the elastic client doesn't directly
+		// expose the error response body
+		err = retryOnTransientErrors(m, func() error {
+			var err error
+			res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+				Method: "PUT",
+				Path:   path,
+				Body:   string(userJSON),
+			})
+			return err
+		}, http.StatusInternalServerError)
 		if err != nil {
 			e, ok := err.(*elastic7.Error)
 			if !ok {
@@ -0,0 +1,118 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+func resourceElasticsearchReloadSecureSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchReloadSecureSettingsCreate,
+		Read:   resourceElasticsearchReloadSecureSettingsRead,
+		Delete: resourceElasticsearchReloadSecureSettingsDelete,
+		Schema: map[string]*schema.Schema{
+			"node_filter": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "_all",
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only reload the secure settings of the nodes matching this filter, e.g. `data:true` or comma separated node names or IDs.",
+			},
+			"keystore_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "The password of the keystores of the nodes, if they're password protected. Requires Elasticsearch 7.7.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that reload the secure settings again when they change, e.g. the version of a rotated secret written to the keystores.",
+			},
+			"reloaded_nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the nodes whose secure settings were reloaded, sorted.",
+			},
+		},
+		Description: "Reloads the reloadable secure settings of the keystores of the nodes once, when the resource is created or any of its arguments changes, e.g. after rotating the credentials of a snapshot repository client, failing if any node failed to reload them. Destroying the resource does nothing.",
+	}
+}
+
+func resourceElasticsearchReloadSecureSettingsCreate(d *schema.ResourceData, meta interface{}) error {
+	filter := d.Get("node_filter").(string)
+	path, err := uritemplates.Expand("/_nodes/{nodes}/reload_secure_settings", map[string]string{
+		"nodes": filter,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for reload secure settings: %+v", err)
+	}
+	var body interface{}
+	if password, ok := d.GetOk("keystore_password"); ok {
+		body = map[string]interface{}{
+			"secure_settings_password": password,
+		}
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	res, err := performRequest(providerContext(meta), esClient, "POST", path, nil, body)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Nodes map[string]struct {
+			Name            string `json:"name"`
+			ReloadException *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"reload_exception"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return fmt.Errorf("error unmarshalling reload secure settings: %+v: %s", err, res)
+	}
+
+	reloaded, failures := []string{}, []string{}
+	for _, node := range response.Nodes {
+		if e := node.ReloadException; e != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s: %s", node.Name, e.Type, e.Reason))
+			continue
+		}
+		reloaded = append(reloaded, node.Name)
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("error reloading the secure settings of %d nodes: %s", len(failures), strings.Join(failures, "; "))
+	}
+	sort.Strings(reloaded)
+
+	d.SetId(filter)
+	ds := &resourceDataSetter{d: d}
+	ds.set("reloaded_nodes", reloaded)
+	return ds.err
+}
+
+// resourceElasticsearchReloadSecureSettingsRead keeps the state of the reload,
+// an action without anything to read back.
+func resourceElasticsearchReloadSecureSettingsRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchReloadSecureSettingsDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,57 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceElasticsearchReloadSecureSettings(t *testing.T) {
+	failing := false
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_nodes/data:true/reload_secure_settings":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["secure_settings_password"] != "keystore" {
+				t.Errorf("expected the keystore password, got %v", body)
+			}
+			if failing {
+				_, _ = w.Write([]byte(`{"_nodes": {"total": 2, "successful": 2, "failed": 0}, "nodes": {
+					"a1": {"name": "node-1"},
+					"b2": {"name": "node-2", "reload_exception": {"type": "security_exception", "reason": "Provided keystore password was incorrect"}}
+				}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"_nodes": {"total": 2, "successful": 2, "failed": 0}, "nodes": {
+				"b2": {"name": "node-2"},
+				"a1": {"name": "node-1"}
+			}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchReloadSecureSettings()
+	d := r.TestResourceData()
+	_ = d.Set("node_filter", "data:true")
+	_ = d.Set("keystore_password", "keystore")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "data:true" || d.Get("reloaded_nodes.#") != 2 || d.Get("reloaded_nodes.0") != "node-1" {
+		t.Errorf("unexpected state: %s %v", d.Id(), d.State())
+	}
+
+	failing = true
+	d = r.TestResourceData()
+	_ = d.Set("node_filter", "data:true")
+	_ = d.Set("keystore_password", "keystore")
+	if err := r.Create(d, conf); err == nil || !strings.Contains(err.Error(), "node-2: security_exception: Provided keystore password was incorrect") {
+		t.Errorf("expected a reload error, got %v", err)
+	}
+}
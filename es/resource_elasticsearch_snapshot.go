@@ -0,0 +1,322 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func resourceElasticsearchSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchSnapshotCreate,
+		Read:   resourceElasticsearchSnapshotRead,
+		Update: resourceElasticsearchSnapshotUpdate,
+		Delete: resourceElasticsearchSnapshotDelete,
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the snapshot repository to take the snapshot into.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the snapshot, unique within the repository.",
+			},
+			"indices": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The indices, data streams and index patterns to snapshot. Defaults to all of them.",
+			},
+			"ignore_unavailable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Skip the missing or closed indices instead of failing the snapshot.",
+			},
+			"include_global_state": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Include the cluster state, e.g. the templates and persistent settings, in the snapshot.",
+			},
+			"partial": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Snapshot the available shards of indices with unavailable primary shards, instead of failing the snapshot.",
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Wait, up to the create timeout, for the snapshot to complete before the resource is created, so that the resources depending on it only change once the data is backed up.",
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Delete the snapshot when the resource is destroyed, otherwise only remove it from the state.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The UUID of the snapshot.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the snapshot, e.g. `IN_PROGRESS`, `SUCCESS` or `PARTIAL`.",
+			},
+			"snapshot_indices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The indices included in the snapshot.",
+			},
+			"start_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the snapshot started.",
+			},
+			"end_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the snapshot completed, empty while in progress.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchSnapshotImport,
+		},
+		Description: "Takes a snapshot of indices into a repository, e.g. to back them up before risky changes made later in the same apply by the resources depending on it.",
+	}
+}
+
+func resourceElasticsearchSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	repository := d.Get("repository").(string)
+	name := d.Get("name").(string)
+	path, err := snapshotPath(repository, name)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"ignore_unavailable":   d.Get("ignore_unavailable").(bool),
+		"include_global_state": d.Get("include_global_state").(bool),
+		"partial":              d.Get("partial").(bool),
+	}
+	if indices := expandStringList(d.Get("indices").([]interface{})); len(indices) > 0 {
+		body["indices"] = indices
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	// the snapshot is polled below rather than with wait_for_completion, which
+	// would hold the request open for as long as the snapshot takes
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "PUT", path, nil, body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(snapshotId(repository, name))
+
+	if d.Get("wait_for_completion").(bool) {
+		err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+			snapshot, err := getSnapshot(d, meta, repository, name)
+			if err != nil {
+				return resource.NonRetryableError(err)
+			}
+			if snapshot == nil {
+				return resource.NonRetryableError(fmt.Errorf("snapshot %s was deleted while in progress", d.Id()))
+			}
+			if !snapshotCompleted(snapshot.State) {
+				log.Printf("[DEBUG] Snapshot %s is %s", d.Id(), snapshot.State)
+				return resource.RetryableError(fmt.Errorf("snapshot %s is still %s", d.Id(), snapshot.State))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceElasticsearchSnapshotRead(d, meta)
+}
+
+func resourceElasticsearchSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	repository, name, err := parseSnapshotId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := getSnapshot(d, meta, repository, name)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		log.Printf("[WARN] Snapshot (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	switch snapshot.State {
+	case "FAILED", "INCOMPATIBLE":
+		return fmt.Errorf("snapshot %s is %s: %s", d.Id(), snapshot.State, snapshot.Reason)
+	case "PARTIAL":
+		for _, f := range snapshot.Failures {
+			log.Printf("[WARN] Snapshot %s is missing shards of index %s: %s", d.Id(), f.Index, f.Reason)
+		}
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("repository", repository)
+	ds.set("name", name)
+	ds.set("uuid", snapshot.UUID)
+	ds.set("state", snapshot.State)
+	ds.set("snapshot_indices", snapshot.Indices)
+	ds.set("start_time", snapshot.StartTime)
+	ds.set("end_time", snapshot.EndTime)
+	return ds.err
+}
+
+// resourceElasticsearchSnapshotUpdate only stores the arguments that don't
+// change the snapshot itself, e.g. delete_on_destroy.
+func resourceElasticsearchSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchSnapshotRead(d, meta)
+}
+
+func resourceElasticsearchSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[INFO] Leaving snapshot (%s) in the repository, delete_on_destroy is false", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	repository, name, err := parseSnapshotId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, err := snapshotPath(repository, name)
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	// deleting a snapshot in progress aborts it
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, nil, nil)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) && !elastic6.IsNotFound(err) && !elastic5.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceElasticsearchSnapshotImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseSnapshotId(d.Id()); err != nil {
+		return nil, err
+	}
+	ds := &resourceDataSetter{d: d}
+	ds.set("ignore_unavailable", false)
+	ds.set("include_global_state", true)
+	ds.set("partial", false)
+	ds.set("wait_for_completion", true)
+	ds.set("delete_on_destroy", true)
+	return []*schema.ResourceData{d}, ds.err
+}
+
+// getSnapshot returns the description of a snapshot, in any of the response
+// formats of the versions of the cluster, or nil if it doesn't exist.
+func getSnapshot(d *schema.ResourceData, meta interface{}, repository, name string) (*snapshotInfo, error) {
+	path, err := snapshotPath(repository, name)
+	if err != nil {
+		return nil, err
+	}
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return nil, err
+	}
+	res, err := performRequest(providerContext(meta), esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) || elastic6.IsNotFound(err) || elastic5.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots struct {
+		Snapshots []snapshotInfo `json:"snapshots"`
+		// Elasticsearch 7.6 to 7.12 group the snapshots by repository
+		Responses []struct {
+			Snapshots []snapshotInfo `json:"snapshots"`
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(res, &snapshots); err != nil {
+		return nil, fmt.Errorf("error unmarshalling snapshot %s: %+v: %s", d.Id(), err, res)
+	}
+	for _, r := range snapshots.Responses {
+		snapshots.Snapshots = append(snapshots.Snapshots, r.Snapshots...)
+	}
+	for _, s := range snapshots.Snapshots {
+		if s.Snapshot == name {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+// snapshotCompleted returns whether a snapshot in state stopped running,
+// successfully or not.
+func snapshotCompleted(state string) bool {
+	switch state {
+	case "SUCCESS", "PARTIAL", "FAILED", "INCOMPATIBLE":
+		return true
+	}
+	return false
+}
+
+func snapshotId(repository, name string) string {
+	return repository + "/" + name
+}
+
+func parseSnapshotId(id string) (string, string, error) {
+	parts, err := parseCompositeId(id, "repository", "name")
+	if err != nil {
+		return "", "", err
+	}
+	return parts[0], parts[1], nil
+}
+
+func snapshotPath(repository, name string) (string, error) {
+	path, err := uritemplates.Expand("/_snapshot/{repository}/{name}", map[string]string{
+		"repository": repository,
+		"name":       name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for snapshot: %+v", err)
+	}
+	return path, nil
+}
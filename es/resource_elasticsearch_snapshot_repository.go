@@ -2,8 +2,12 @@ package es
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
 	elastic7 "github.com/olivere/elastic/v7"
 	elastic5 "gopkg.in/olivere/elastic.v5"
 	elastic6 "gopkg.in/olivere/elastic.v6"
@@ -29,23 +33,77 @@ func resourceElasticsearchSnapshotRepository() *schema.Resource {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Verify that every master and data node can access the repository when it's created or updated, failing with the errors of the nodes that can't.",
+			},
+			"verified_nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the nodes that verified the repository when it was last created or updated, empty if `verify` is false.",
+			},
+			"deletion_protection": deletionProtectionSchema(),
 		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceElasticsearchSnapshotRepositoryImport,
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceElasticsearchSnapshotRepositoryV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceElasticsearchSnapshotRepositoryStateUpgradeV0,
+				Version: 0,
+			},
+		},
+	}
+}
+
+func resourceElasticsearchSnapshotRepositoryV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":     {Type: schema.TypeString, Required: true},
+			"type":     {Type: schema.TypeString, Required: true},
+			"settings": {Type: schema.TypeMap, Optional: true},
 		},
 	}
 }
 
+// resourceElasticsearchSnapshotRepositoryStateUpgradeV0 stores every setting
+// as a string, the way the API returns them, and drops null settings left by
+// older states, which otherwise fail to decode into the map of strings.
+func resourceElasticsearchSnapshotRepositoryStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	settings, _ := rawState["settings"].(map[string]interface{})
+	upgraded := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		if v == nil {
+			continue
+		}
+		upgraded[k] = scalarString(v)
+	}
+	rawState["settings"] = upgraded
+
+	return rawState, nil
+}
+
+func resourceElasticsearchSnapshotRepositoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	err := d.Set("verify", true)
+	return []*schema.ResourceData{d}, err
+}
+
 func resourceElasticsearchSnapshotRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
-	err := resourceElasticsearchSnapshotRepositoryUpdate(d, meta)
-	if err != nil {
+	if err := resourceElasticsearchPutSnapshotRepository(d, meta); err != nil {
 		return err
 	}
+	// a repository failing verification is still registered
 	d.SetId(d.Get("name").(string))
-	return nil
+	return resourceElasticsearchVerifySnapshotRepository(d, meta)
 }
 
 func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var repositoryType string
@@ -57,12 +115,12 @@ func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta in
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		repositoryType, settings, err = elastic7SnapshotGetRepository(client, id)
+		repositoryType, settings, err = elastic7SnapshotGetRepository(ctx, client, id)
 	case *elastic6.Client:
-		repositoryType, settings, err = elastic6SnapshotGetRepository(client, id)
+		repositoryType, settings, err = elastic6SnapshotGetRepository(ctx, client, id)
 	default:
 		elastic5Client := client.(*elastic5.Client)
-		repositoryType, settings, err = elastic5SnapshotGetRepository(elastic5Client, id)
+		repositoryType, settings, err = elastic5SnapshotGetRepository(ctx, elastic5Client, id)
 	}
 
 	if err != nil {
@@ -76,8 +134,8 @@ func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta in
 	return ds.err
 }
 
-func elastic7SnapshotGetRepository(client *elastic7.Client, id string) (string, map[string]interface{}, error) {
-	repos, err := client.SnapshotGetRepository(id).Do(context.TODO())
+func elastic7SnapshotGetRepository(ctx context.Context, client *elastic7.Client, id string) (string, map[string]interface{}, error) {
+	repos, err := client.SnapshotGetRepository(id).Do(ctx)
 	if err != nil {
 		return "", make(map[string]interface{}), err
 	}
@@ -85,8 +143,8 @@ func elastic7SnapshotGetRepository(client *elastic7.Client, id string) (string,
 	return repos[id].Type, repos[id].Settings, nil
 }
 
-func elastic6SnapshotGetRepository(client *elastic6.Client, id string) (string, map[string]interface{}, error) {
-	repos, err := client.SnapshotGetRepository(id).Do(context.TODO())
+func elastic6SnapshotGetRepository(ctx context.Context, client *elastic6.Client, id string) (string, map[string]interface{}, error) {
+	repos, err := client.SnapshotGetRepository(id).Do(ctx)
 	if err != nil {
 		return "", make(map[string]interface{}), err
 	}
@@ -94,8 +152,8 @@ func elastic6SnapshotGetRepository(client *elastic6.Client, id string) (string,
 	return repos[id].Type, repos[id].Settings, nil
 }
 
-func elastic5SnapshotGetRepository(client *elastic5.Client, id string) (string, map[string]interface{}, error) {
-	repos, err := client.SnapshotGetRepository(id).Do(context.TODO())
+func elastic5SnapshotGetRepository(ctx context.Context, client *elastic5.Client, id string) (string, map[string]interface{}, error) {
+	repos, err := client.SnapshotGetRepository(id).Do(ctx)
 	if err != nil {
 		return "", make(map[string]interface{}), err
 	}
@@ -104,6 +162,66 @@ func elastic5SnapshotGetRepository(client *elastic5.Client, id string) (string,
 }
 
 func resourceElasticsearchSnapshotRepositoryUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceElasticsearchPutSnapshotRepository(d, meta); err != nil {
+		return err
+	}
+	return resourceElasticsearchVerifySnapshotRepository(d, meta)
+}
+
+// resourceElasticsearchVerifySnapshotRepository verifies the repository if
+// enabled, recording the nodes that verified it.
+func resourceElasticsearchVerifySnapshotRepository(d *schema.ResourceData, meta interface{}) error {
+	nodes := []string{}
+	if d.Get("verify").(bool) {
+		esClient, err := getClient(meta.(*ProviderConf))
+		if err != nil {
+			return err
+		}
+		nodes, err = verifySnapshotRepository(providerContext(meta), esClient, d.Get("name").(string))
+		if err != nil {
+			return err
+		}
+	}
+	return d.Set("verified_nodes", nodes)
+}
+
+// verifySnapshotRepository checks that every master and data node can access
+// the repository, returning the names of the nodes.
+func verifySnapshotRepository(ctx context.Context, esClient interface{}, name string) ([]string, error) {
+	path, err := uritemplates.Expand("/_snapshot/{name}/_verify", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building URL path for snapshot repository verification: %+v", err)
+	}
+
+	res, err := performRequest(ctx, esClient, "POST", path, nil, nil)
+	if err != nil {
+		// the reason of the error lists the nodes that failed and why
+		return nil, fmt.Errorf("snapshot repository %s failed verification: %+v", name, err)
+	}
+	var verification struct {
+		Nodes map[string]struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(res, &verification); err != nil {
+		return nil, fmt.Errorf("error unmarshalling snapshot repository verification: %+v: %s", err, res)
+	}
+
+	nodes := make([]string, 0, len(verification.Nodes))
+	for id, node := range verification.Nodes {
+		if node.Name == "" {
+			node.Name = id
+		}
+		nodes = append(nodes, node.Name)
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+func resourceElasticsearchPutSnapshotRepository(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	repositoryType := d.Get("type").(string)
 	name := d.Get("name").(string)
 
@@ -118,50 +236,56 @@ func resourceElasticsearchSnapshotRepositoryUpdate(d *schema.ResourceData, meta
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7SnapshotCreateRepository(client, name, repositoryType, settings)
-	case *elastic6.Client:
-		err = elastic6SnapshotCreateRepository(client, name, repositoryType, settings)
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		err = elastic5SnapshotCreateRepository(elastic5Client, name, repositoryType, settings)
-	}
-
-	return err
+	return retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7SnapshotCreateRepository(ctx, client, name, repositoryType, settings)
+		case *elastic6.Client:
+			return elastic6SnapshotCreateRepository(ctx, client, name, repositoryType, settings)
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			return elastic5SnapshotCreateRepository(ctx, elastic5Client, name, repositoryType, settings)
+		}
+	})
 }
 
-func elastic7SnapshotCreateRepository(client *elastic7.Client, name string, repositoryType string, settings map[string]interface{}) error {
+func elastic7SnapshotCreateRepository(ctx context.Context, client *elastic7.Client, name string, repositoryType string, settings map[string]interface{}) error {
 	repo := elastic7.SnapshotRepositoryMetaData{
 		Type:     repositoryType,
 		Settings: settings,
 	}
 
-	_, err := client.SnapshotCreateRepository(name).BodyJson(&repo).Do(context.TODO())
+	// verified separately to report the errors of the nodes
+	_, err := client.SnapshotCreateRepository(name).Verify(false).BodyJson(&repo).Do(ctx)
 	return err
 }
 
-func elastic6SnapshotCreateRepository(client *elastic6.Client, name string, repositoryType string, settings map[string]interface{}) error {
+func elastic6SnapshotCreateRepository(ctx context.Context, client *elastic6.Client, name string, repositoryType string, settings map[string]interface{}) error {
 	repo := elastic6.SnapshotRepositoryMetaData{
 		Type:     repositoryType,
 		Settings: settings,
 	}
 
-	_, err := client.SnapshotCreateRepository(name).BodyJson(&repo).Do(context.TODO())
+	_, err := client.SnapshotCreateRepository(name).Verify(false).BodyJson(&repo).Do(ctx)
 	return err
 }
 
-func elastic5SnapshotCreateRepository(client *elastic5.Client, name string, repositoryType string, settings map[string]interface{}) error {
+func elastic5SnapshotCreateRepository(ctx context.Context, client *elastic5.Client, name string, repositoryType string, settings map[string]interface{}) error {
 	repo := elastic5.SnapshotRepositoryMetaData{
 		Type:     repositoryType,
 		Settings: settings,
 	}
 
-	_, err := client.SnapshotCreateRepository(name).BodyJson(&repo).Do(context.TODO())
+	_, err := client.SnapshotCreateRepository(name).Verify(false).BodyJson(&repo).Do(ctx)
 	return err
 }
 
 func resourceElasticsearchSnapshotRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d); err != nil {
+		return err
+	}
+
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var err error
@@ -169,15 +293,17 @@ func resourceElasticsearchSnapshotRepositoryDelete(d *schema.ResourceData, meta
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7SnapshotDeleteRepository(client, id)
-	case *elastic6.Client:
-		err = elastic6SnapshotDeleteRepository(client, id)
-	default:
-		elastic5Client := client.(*elastic5.Client)
-		err = elastic5SnapshotDeleteRepository(elastic5Client, id)
-	}
+	err = retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7SnapshotDeleteRepository(ctx, client, id)
+		case *elastic6.Client:
+			return elastic6SnapshotDeleteRepository(ctx, client, id)
+		default:
+			elastic5Client := client.(*elastic5.Client)
+			return elastic5SnapshotDeleteRepository(ctx, elastic5Client, id)
+		}
+	})
 
 	if err != nil {
 		return err
@@ -186,17 +312,17 @@ func resourceElasticsearchSnapshotRepositoryDelete(d *schema.ResourceData, meta
 	return nil
 }
 
-func elastic7SnapshotDeleteRepository(client *elastic7.Client, id string) error {
-	_, err := client.SnapshotDeleteRepository(id).Do(context.TODO())
+func elastic7SnapshotDeleteRepository(ctx context.Context, client *elastic7.Client, id string) error {
+	_, err := client.SnapshotDeleteRepository(id).Do(ctx)
 	return err
 }
 
-func elastic6SnapshotDeleteRepository(client *elastic6.Client, id string) error {
-	_, err := client.SnapshotDeleteRepository(id).Do(context.TODO())
+func elastic6SnapshotDeleteRepository(ctx context.Context, client *elastic6.Client, id string) error {
+	_, err := client.SnapshotDeleteRepository(id).Do(ctx)
 	return err
 }
 
-func elastic5SnapshotDeleteRepository(client *elastic5.Client, id string) error {
-	_, err := client.SnapshotDeleteRepository(id).Do(context.TODO())
+func elastic5SnapshotDeleteRepository(ctx context.Context, client *elastic5.Client, id string) error {
+	_, err := client.SnapshotDeleteRepository(id).Do(ctx)
 	return err
 }
@@ -0,0 +1,93 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+func resourceElasticsearchSnapshotRepositoryCleanup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchSnapshotRepositoryCleanupCreate,
+		Read:   resourceElasticsearchSnapshotRepositoryCleanupRead,
+		Delete: resourceElasticsearchSnapshotRepositoryCleanupDelete,
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the snapshot repository to clean up.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that clean up the repository again when they change, e.g. the ID of a `time_rotating` resource to clean it up on a schedule.",
+			},
+			"deleted_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of bytes freed by the cleanup.",
+			},
+			"deleted_blobs": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of files removed by the cleanup.",
+			},
+		},
+		Description: "Cleans up a snapshot repository once, when the resource is created or any of its arguments changes, deleting the data that no snapshot references anymore, e.g. left behind by deleted snapshots.",
+	}
+}
+
+func resourceElasticsearchSnapshotRepositoryCleanupCreate(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("repository").(string)
+	path, err := uritemplates.Expand("/_snapshot/{name}/_cleanup", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return fmt.Errorf("error building URL path for snapshot repository cleanup: %+v", err)
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	var res json.RawMessage
+	err = retryOnTransientErrors(meta, func() error {
+		var err error
+		res, err = performRequest(providerContext(meta), esClient, "POST", path, nil, nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	var cleanup struct {
+		Results struct {
+			DeletedBytes int64 `json:"deleted_bytes"`
+			DeletedBlobs int64 `json:"deleted_blobs"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(res, &cleanup); err != nil {
+		return fmt.Errorf("error unmarshalling snapshot repository cleanup: %+v: %s", err, res)
+	}
+
+	d.SetId(name)
+	ds := &resourceDataSetter{d: d}
+	ds.set("deleted_bytes", cleanup.Results.DeletedBytes)
+	ds.set("deleted_blobs", cleanup.Results.DeletedBlobs)
+	return ds.err
+}
+
+// resourceElasticsearchSnapshotRepositoryCleanupRead keeps the state of the
+// cleanup, an action without anything to read back.
+func resourceElasticsearchSnapshotRepositoryCleanupRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchSnapshotRepositoryCleanupDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
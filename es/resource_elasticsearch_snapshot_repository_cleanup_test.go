@@ -0,0 +1,73 @@
+package es
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchSnapshotRepositoryCleanupCreate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/backups/_cleanup":
+			if r.Method != "POST" {
+				t.Errorf("unexpected method: %s", r.Method)
+			}
+			_, _ = w.Write([]byte(`{"results": {"deleted_bytes": 20971520, "deleted_blobs": 5}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchSnapshotRepositoryCleanup()
+	d := r.TestResourceData()
+	_ = d.Set("repository", "backups")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "backups" {
+		t.Errorf("unexpected id: %s", d.Id())
+	}
+	if actual := d.Get("deleted_bytes"); actual != 20971520 {
+		t.Errorf("expected 20971520 deleted bytes, got %v", actual)
+	}
+	if actual := d.Get("deleted_blobs"); actual != 5 {
+		t.Errorf("expected 5 deleted blobs, got %v", actual)
+	}
+}
+
+func TestAccElasticsearchSnapshotRepositoryCleanup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSnapshotRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchSnapshotRepositoryCleanup,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("elasticsearch_snapshot_repository_cleanup.test", "deleted_bytes"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchSnapshotRepositoryCleanup = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name = "terraform-test-cleanup"
+  type = "fs"
+
+  settings = {
+    location = "/tmp/elasticsearch"
+  }
+}
+
+resource "elasticsearch_snapshot_repository_cleanup" "test" {
+  repository = elasticsearch_snapshot_repository.test.name
+}
+`
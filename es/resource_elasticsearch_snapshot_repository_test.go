@@ -3,6 +3,9 @@ package es
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
 	elastic7 "github.com/olivere/elastic/v7"
@@ -23,6 +26,7 @@ func TestAccElasticsearchSnapshotRepository(t *testing.T) {
 				Config: testAccElasticsearchSnapshotRepository,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchSnapshotRepositoryExists("elasticsearch_snapshot_repository.test"),
+					resource.TestCheckResourceAttrSet("elasticsearch_snapshot_repository.test", "verified_nodes.0"),
 				),
 			},
 		},
@@ -39,9 +43,10 @@ func TestAccElasticsearchSnapshotRepository_importBasic(t *testing.T) {
 				Config: testAccElasticsearchSnapshotRepository,
 			},
 			{
-				ResourceName:      "elasticsearch_snapshot_repository.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "elasticsearch_snapshot_repository.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"verified_nodes"},
 			},
 		},
 	})
@@ -125,3 +130,125 @@ resource "elasticsearch_snapshot_repository" "test" {
   }
 }
 `
+
+func TestResourceElasticsearchSnapshotRepositoryStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"name": "backups",
+		"type": "fs",
+		"settings": map[string]interface{}{
+			"location":          "/tmp/backups",
+			"compress":          true,
+			"max_restore_bytes": float64(1000000),
+			"chunk_size":        nil,
+		},
+	}
+	expected := map[string]interface{}{
+		"name": "backups",
+		"type": "fs",
+		"settings": map[string]interface{}{
+			"location":          "/tmp/backups",
+			"compress":          "true",
+			"max_restore_bytes": "1000000",
+		},
+	}
+
+	actual, err := resourceElasticsearchSnapshotRepositoryStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceElasticsearchSnapshotRepositoryCreateVerify(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/backups":
+			if r.URL.Query().Get("verify") != "false" {
+				t.Errorf("expected the repository to be verified separately: %s", r.URL)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_snapshot/backups/_verify":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": {"type": "repository_verification_exception", "reason": "[backups] [[node-2, 'RemoteTransportException[[data-2][10.0.0.2:9300]]; AccessDeniedException[/mnt/backups]']]"}, "status": 500}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchSnapshotRepository()
+	d := r.TestResourceData()
+	_ = d.Set("name", "backups")
+	_ = d.Set("type", "fs")
+	_ = d.Set("verify", true)
+	err := r.Create(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "snapshot repository backups failed verification") || !strings.Contains(err.Error(), "data-2") {
+		t.Errorf("expected the verification to fail with the errors of the nodes, got %v", err)
+	}
+	if d.Id() != "backups" {
+		t.Errorf("expected the repository failing verification to be kept in the state")
+	}
+}
+
+func TestResourceElasticsearchSnapshotRepositoryVerifyCreate(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_snapshot/backups/_verify":
+			_, _ = w.Write([]byte(`{"nodes": {"node-2": {"name": "data-2"}, "node-1": {"name": "data-1"}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchSnapshotRepositoryVerify()
+	d := r.TestResourceData()
+	_ = d.Set("repository", "backups")
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if nodes := d.Get("nodes").([]interface{}); !reflect.DeepEqual(nodes, []interface{}{"data-1", "data-2"}) {
+		t.Errorf("unexpected nodes: %v", nodes)
+	}
+}
+
+func TestAccElasticsearchSnapshotRepositoryVerify(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSnapshotRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchSnapshotRepositoryVerify,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("elasticsearch_snapshot_repository_verify.test", "nodes.0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchSnapshotRepositoryVerify = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name   = "terraform-test-verify"
+  type   = "fs"
+  verify = false
+
+  settings = {
+    location = "/tmp/elasticsearch"
+  }
+}
+
+resource "elasticsearch_snapshot_repository_verify" "test" {
+  repository = elasticsearch_snapshot_repository.test.name
+  triggers = {
+    location = elasticsearch_snapshot_repository.test.settings.location
+  }
+}
+`
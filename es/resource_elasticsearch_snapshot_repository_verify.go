@@ -0,0 +1,61 @@
+package es
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceElasticsearchSnapshotRepositoryVerify() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchSnapshotRepositoryVerifyCreate,
+		Read:   resourceElasticsearchSnapshotRepositoryVerifyRead,
+		Delete: resourceElasticsearchSnapshotRepositoryVerifyDelete,
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the snapshot repository to verify.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that verify the repository again when they change, e.g. the version of rotated credentials.",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the nodes that verified the repository.",
+			},
+		},
+		Description: "Verifies once, when the resource is created or any of its arguments changes, that every master and data node can access a snapshot repository, e.g. after rotating the credentials of its storage, failing with the errors of the nodes that can't.",
+	}
+}
+
+func resourceElasticsearchSnapshotRepositoryVerifyCreate(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("repository").(string)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	nodes, err := verifySnapshotRepository(providerContext(meta), esClient, name)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(name)
+	return d.Set("nodes", nodes)
+}
+
+// resourceElasticsearchSnapshotRepositoryVerifyRead keeps the state of the
+// verification, an action without anything to read back.
+func resourceElasticsearchSnapshotRepositoryVerifyRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchSnapshotRepositoryVerifyDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
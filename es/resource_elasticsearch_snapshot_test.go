@@ -0,0 +1,124 @@
+package es
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchSnapshotCreate(t *testing.T) {
+	var polls int
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_snapshot/backups/before-upgrade" && r.Method == "PUT":
+			if r.URL.Query().Get("wait_for_completion") != "" {
+				t.Errorf("unexpected wait_for_completion: %s", r.URL)
+			}
+			_, _ = w.Write([]byte(`{"accepted": true}`))
+		case r.URL.Path == "/_snapshot/backups/before-upgrade" && r.Method == "GET":
+			polls++
+			state, endTime := "IN_PROGRESS", ""
+			if polls > 1 {
+				state, endTime = "SUCCESS", "2021-03-01T10:05:00.000Z"
+			}
+			_, _ = fmt.Fprintf(w, `{"snapshots": [{"snapshot": "before-upgrade", "uuid": "dKb54xw67gvdRctLCxSket", "state": %q, "indices": ["logs-1"], "start_time": "2021-03-01T10:00:00.000Z", "end_time": %q}]}`, state, endTime)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchSnapshot()
+	d := r.TestResourceData()
+	_ = d.Set("repository", "backups")
+	_ = d.Set("name", "before-upgrade")
+	_ = d.Set("indices", []string{"logs-*"})
+	_ = d.Set("wait_for_completion", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "backups/before-upgrade" {
+		t.Errorf("unexpected id: %s", d.Id())
+	}
+	if actual := d.Get("state"); actual != "SUCCESS" {
+		t.Errorf("expected the snapshot to complete, got %v", actual)
+	}
+	if actual := d.Get("snapshot_indices.0"); actual != "logs-1" {
+		t.Errorf("unexpected snapshot_indices: %v", d.Get("snapshot_indices"))
+	}
+}
+
+func TestAccElasticsearchSnapshot(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchSnapshot,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_snapshot.test", "state", "SUCCESS"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_snapshot.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"indices"},
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchSnapshotDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_snapshot" {
+			continue
+		}
+
+		meta := testAccProvider.Meta()
+		d := resourceElasticsearchSnapshot().Data(rs.Primary)
+		repository, name, err := parseSnapshotId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		snapshot, err := getSnapshot(d, meta, repository, name)
+		if err != nil {
+			return err
+		}
+		if snapshot != nil {
+			return fmt.Errorf("Snapshot %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+var testAccElasticsearchSnapshot = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name = "terraform-test-snapshot"
+  type = "fs"
+
+  settings = {
+    location = "/tmp/elasticsearch"
+  }
+}
+
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-snapshot"
+  number_of_shards   = 1
+  number_of_replicas = 0
+}
+
+resource "elasticsearch_snapshot" "test" {
+  repository = elasticsearch_snapshot_repository.test.name
+  name       = "terraform-test"
+  indices    = [elasticsearch_index.test.name]
+}
+`
@@ -0,0 +1,164 @@
+package es
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+var slicesRegexp = regexp.MustCompile(`^([1-9][0-9]*|auto)$`)
+
+func resourceElasticsearchUpdateByQuery() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchUpdateByQueryCreate,
+		Read:   resourceElasticsearchByQueryRead,
+		Delete: resourceElasticsearchByQueryDelete,
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The index, data stream or alias to update, or comma separated list of them, with wildcards.",
+			},
+			"query": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON query selecting the documents to update. Defaults to every document.",
+			},
+			"script": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJson,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON script updating each document, e.g. `{\"source\": \"ctx._source.count++\", \"lang\": \"painless\"}`. Without a script, the documents are reindexed in place, e.g. to pick up new mappings.",
+			},
+			"pipeline": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The ingest pipeline to run the documents through.",
+			},
+			"conflicts":           byQueryConflictsSchema(),
+			"slices":              byQuerySlicesSchema(),
+			"wait_for_completion": byQueryWaitForCompletionSchema(),
+			"triggers":            byQueryTriggersSchema(),
+			"task_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the task running the update.",
+			},
+			"total": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents processed, 0 if `wait_for_completion` is false.",
+			},
+			"updated": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents updated, 0 if `wait_for_completion` is false.",
+			},
+			"version_conflicts": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of documents that changed while they were updated, 0 if `wait_for_completion` is false.",
+			},
+		},
+		Description: "Runs `_update_by_query` once, when the resource is created or any of its arguments changes, e.g. to backfill documents after a mapping or pipeline change in the same apply. Destroying the resource doesn't revert the update.",
+	}
+}
+
+func resourceElasticsearchUpdateByQueryCreate(d *schema.ResourceData, meta interface{}) error {
+	body := map[string]interface{}{}
+	if query, ok := d.GetOk("query"); ok {
+		body["query"] = json.RawMessage(query.(string))
+	}
+	if script, ok := d.GetOk("script"); ok {
+		body["script"] = json.RawMessage(script.(string))
+	}
+	params := url.Values{
+		"conflicts": []string{d.Get("conflicts").(string)},
+		"slices":    []string{d.Get("slices").(string)},
+	}
+	if pipeline, ok := d.GetOk("pipeline"); ok {
+		params.Set("pipeline", pipeline.(string))
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	taskId, result, err := runByQuery(providerContext(meta), esClient, "_update_by_query", d.Get("index").(string), params, body, d.Get("wait_for_completion").(bool), d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(taskId)
+	ds := &resourceDataSetter{d: d}
+	ds.set("task_id", taskId)
+	if result != nil {
+		ds.set("total", result.Total)
+		ds.set("updated", result.Updated)
+		ds.set("version_conflicts", result.VersionConflicts)
+	}
+	return ds.err
+}
+
+// resourceElasticsearchByQueryRead keeps the state of the by query
+// resources, which are actions without anything to read back.
+func resourceElasticsearchByQueryRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceElasticsearchByQueryDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+func byQueryConflictsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		Default:      "abort",
+		ValidateFunc: validation.StringInSlice([]string{"abort", "proceed"}, false),
+		Description:  "Whether to `abort` or `proceed` when a document changes while it's processed.",
+	}
+}
+
+func byQuerySlicesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		Default:      "1",
+		ValidateFunc: validation.StringMatch(slicesRegexp, "must be a positive number or auto"),
+		Description:  "The number of slices processed in parallel, or `auto` for one per shard.",
+	}
+}
+
+func byQueryWaitForCompletionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		ForceNew:    true,
+		Default:     true,
+		Description: "Wait for the task to complete, up to the create timeout, failing if any document failed. Otherwise only start the task. A task still running at the timeout, or when the apply is interrupted, keeps running, and the next apply waits for it instead of starting another.",
+	}
+}
+
+func byQueryTriggersSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		ForceNew:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Arbitrary values that run the task again when they change, e.g. the ID of the pipeline or mapping that requires it.",
+	}
+}
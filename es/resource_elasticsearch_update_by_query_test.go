@@ -0,0 +1,139 @@
+package es
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestResourceElasticsearchUpdateByQueryCreate(t *testing.T) {
+	polls := 0
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/logs-*/_update_by_query":
+			q := r.URL.Query()
+			if q.Get("wait_for_completion") != "false" || q.Get("conflicts") != "proceed" || q.Get("slices") != "auto" || q.Get("pipeline") != "enrich" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			var body map[string]interface{}
+			raw, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(raw, &body); err != nil || body["query"] == nil || body["script"] == nil {
+				t.Errorf("unexpected body: %s", raw)
+			}
+			_, _ = w.Write([]byte(`{"task": "node-1:42"}`))
+		case "/_tasks/node-1:42":
+			polls++
+			if polls == 1 {
+				_, _ = w.Write([]byte(`{"completed": false, "task": {"status": {"total": 10, "updated": 4}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"completed": true, "response": {"total": 10, "updated": 9, "version_conflicts": 1, "failures": []}}`))
+		case "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchUpdateByQuery()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs-*")
+	_ = d.Set("query", `{"term": {"env": "prod"}}`)
+	_ = d.Set("script", `{"source": "ctx._source.tier = 'hot'"}`)
+	_ = d.Set("pipeline", "enrich")
+	_ = d.Set("conflicts", "proceed")
+	_ = d.Set("slices", "auto")
+	_ = d.Set("wait_for_completion", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if polls != 2 {
+		t.Errorf("expected the task to be polled twice, got %d", polls)
+	}
+	expected := map[string]interface{}{
+		"task_id":           "node-1:42",
+		"total":             10,
+		"updated":           9,
+		"version_conflicts": 1,
+	}
+	for key, value := range expected {
+		if actual := d.Get(key); actual != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, actual)
+		}
+	}
+}
+
+func TestResourceElasticsearchUpdateByQueryCreateFailures(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/logs/_update_by_query":
+			_, _ = w.Write([]byte(`{"task": "node-1:43"}`))
+		case "/_tasks/node-1:43":
+			_, _ = w.Write([]byte(`{"completed": true, "response": {"total": 2, "updated": 1, "failures": [{"id": "2", "cause": {"type": "mapper_parsing_exception"}}]}}`))
+		case "/_tasks":
+			_, _ = w.Write([]byte(`{"nodes": {}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchUpdateByQuery()
+	d := r.TestResourceData()
+	_ = d.Set("index", "logs")
+	_ = d.Set("conflicts", "abort")
+	_ = d.Set("slices", "1")
+	_ = d.Set("wait_for_completion", true)
+	err := r.Create(d, conf)
+	if err == nil || !strings.Contains(err.Error(), "_update_by_query failed on 1 documents") {
+		t.Errorf("expected a failure, got %v", err)
+	}
+}
+
+func TestAccElasticsearchUpdateByQuery(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchUpdateByQuery,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_update_by_query.test", "updated", "1"),
+					resource.TestCheckResourceAttrSet("elasticsearch_update_by_query.test", "task_id"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchUpdateByQuery = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-update-by-query"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  force_destroy      = true
+}
+
+resource "elasticsearch_bulk_documents" "test" {
+  index     = elasticsearch_index.test.name
+  documents = jsonencode({ tier = "warm" })
+}
+
+resource "elasticsearch_update_by_query" "test" {
+  index  = elasticsearch_bulk_documents.test.index
+  query  = jsonencode({ term = { tier = "warm" } })
+  script = jsonencode({ source = "ctx._source.tier = 'hot'" })
+}
+`
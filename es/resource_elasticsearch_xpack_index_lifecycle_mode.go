@@ -0,0 +1,145 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceElasticsearchXpackIndexLifecycleMode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchXpackIndexLifecycleModeCreate,
+		Read:   resourceElasticsearchXpackIndexLifecycleModeRead,
+		Update: resourceElasticsearchXpackIndexLifecycleModeUpdate,
+		Delete: resourceElasticsearchXpackIndexLifecycleModeDelete,
+		Schema: lifecycleModeSchema("index lifecycle management"),
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchLifecycleModeImport,
+		},
+		Description: "Starts or stops index lifecycle management, e.g. to pause lifecycle actions during a cluster migration. Destroying the resource starts it again. There is only one per cluster, its ID is `ilm`.",
+	}
+}
+
+func resourceElasticsearchXpackIndexLifecycleModeCreate(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchPutLifecycleMode(d, meta, "_ilm", schema.TimeoutCreate)
+}
+
+func resourceElasticsearchXpackIndexLifecycleModeUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchPutLifecycleMode(d, meta, "_ilm", schema.TimeoutUpdate)
+}
+
+func resourceElasticsearchXpackIndexLifecycleModeRead(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchLifecycleModeRead(d, meta, "_ilm")
+}
+
+func resourceElasticsearchXpackIndexLifecycleModeDelete(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchLifecycleModeDelete(d, meta, "_ilm")
+}
+
+func lifecycleModeSchema(name string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"enabled": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: fmt.Sprintf("Whether %s is running, or stopped.", name),
+		},
+		"wait_for_stopped": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "When stopping, wait, up to the create or update timeout, for the running operations to complete, instead of returning while it is still `STOPPING`.",
+		},
+		"operation_mode": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The operation mode, `RUNNING`, `STOPPING` or `STOPPED`.",
+		},
+	}
+}
+
+// lifecycleOperationMode returns the operation mode of the _ilm or _slm api.
+func lifecycleOperationMode(meta interface{}, api string) (string, error) {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return "", err
+	}
+	res, err := performRequest(providerContext(meta), esClient, "GET", "/"+api+"/status", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var status struct {
+		OperationMode string `json:"operation_mode"`
+	}
+	if err := json.Unmarshal(res, &status); err != nil {
+		return "", fmt.Errorf("error unmarshalling %s status: %+v: %s", api, err, res)
+	}
+	return status.OperationMode, nil
+}
+
+// setLifecycleMode starts or stops the _ilm or _slm api, waiting, if wait is
+// set, until it is stopped.
+func setLifecycleMode(d *schema.ResourceData, meta interface{}, api string, enabled bool, wait bool, timeout string) error {
+	action := "stop"
+	if enabled {
+		action = "start"
+	}
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "POST", "/"+api+"/"+action, nil, nil)
+		return err
+	})
+	if err != nil || enabled || !wait {
+		return err
+	}
+
+	return resource.Retry(d.Timeout(timeout), func() *resource.RetryError {
+		mode, err := lifecycleOperationMode(meta, api)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if mode != "STOPPED" {
+			log.Printf("[DEBUG] %s is %s", api, mode)
+			return resource.RetryableError(fmt.Errorf("%s is still %s", api, mode))
+		}
+		return nil
+	})
+}
+
+func resourceElasticsearchLifecycleModeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	err := d.Set("wait_for_stopped", true)
+	return []*schema.ResourceData{d}, err
+}
+
+func resourceElasticsearchPutLifecycleMode(d *schema.ResourceData, meta interface{}, api string, timeout string) error {
+	if err := setLifecycleMode(d, meta, api, d.Get("enabled").(bool), d.Get("wait_for_stopped").(bool), timeout); err != nil {
+		return err
+	}
+	d.SetId(api[1:])
+	return resourceElasticsearchLifecycleModeRead(d, meta, api)
+}
+
+func resourceElasticsearchLifecycleModeRead(d *schema.ResourceData, meta interface{}, api string) error {
+	mode, err := lifecycleOperationMode(meta, api)
+	if err != nil {
+		return err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("enabled", mode == "RUNNING")
+	ds.set("operation_mode", mode)
+	return ds.err
+}
+
+func resourceElasticsearchLifecycleModeDelete(d *schema.ResourceData, meta interface{}, api string) error {
+	if err := setLifecycleMode(d, meta, api, true, false, schema.TimeoutDelete); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,113 @@
+package es
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+)
+
+func TestResourceElasticsearchXpackIndexLifecycleModeCreate(t *testing.T) {
+	var stopped bool
+	var polls int
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_ilm/stop":
+			stopped = true
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_ilm/start":
+			stopped = false
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_ilm/status":
+			polls++
+			switch {
+			case !stopped:
+				_, _ = w.Write([]byte(`{"operation_mode": "RUNNING"}`))
+			case polls == 1:
+				_, _ = w.Write([]byte(`{"operation_mode": "STOPPING"}`))
+			default:
+				_, _ = w.Write([]byte(`{"operation_mode": "STOPPED"}`))
+			}
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackIndexLifecycleMode()
+	d := r.TestResourceData()
+	_ = d.Set("enabled", false)
+	_ = d.Set("wait_for_stopped", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "ilm" || d.Get("operation_mode") != "STOPPED" || d.Get("enabled") != false {
+		t.Errorf("expected ilm to be stopped, got %s %v", d.Id(), d.Get("operation_mode"))
+	}
+	if polls < 2 {
+		t.Errorf("expected to wait for ilm to stop, got %d polls", polls)
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if stopped {
+		t.Errorf("expected ilm to be started on destroy")
+	}
+}
+
+func TestAccElasticsearchXpackIndexLifecycleMode(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	esClient, err := getClient(provider.Meta().(*ProviderConf))
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	_, es5 := esClient.(*elastic5.Client)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if es5 {
+				t.Skip("Index lifecycles only supported on ES >= 6")
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchXpackIndexLifecycleMode(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_index_lifecycle_mode.test", "operation_mode", "STOPPED"),
+				),
+			},
+			{
+				Config: testAccElasticsearchXpackIndexLifecycleMode(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_index_lifecycle_mode.test", "operation_mode", "RUNNING"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_xpack_index_lifecycle_mode.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccElasticsearchXpackIndexLifecycleMode(enabled bool) string {
+	return fmt.Sprintf(`
+resource "elasticsearch_xpack_index_lifecycle_mode" "test" {
+  enabled = %t
+}
+`, enabled)
+}
@@ -27,11 +27,12 @@ var xPackIndexLifecyclePolicySchema = map[string]*schema.Schema{
 
 func resourceElasticsearchDeprecatedIndexLifecyclePolicy() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchXpackIndexLifecyclePolicyCreate,
-		Read:   resourceElasticsearchXpackIndexLifecyclePolicyRead,
-		Update: resourceElasticsearchXpackIndexLifecyclePolicyUpdate,
-		Delete: resourceElasticsearchXpackIndexLifecyclePolicyDelete,
-		Schema: xPackIndexLifecyclePolicySchema,
+		Create:        resourceElasticsearchXpackIndexLifecyclePolicyCreate,
+		Read:          resourceElasticsearchXpackIndexLifecyclePolicyRead,
+		Update:        resourceElasticsearchXpackIndexLifecyclePolicyUpdate,
+		Delete:        resourceElasticsearchXpackIndexLifecyclePolicyDelete,
+		CustomizeDiff: customizeDiffValidateBody("body", indexLifecyclePolicyBodySchema),
+		Schema:        xPackIndexLifecyclePolicySchema,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -62,6 +63,7 @@ func resourceElasticsearchXpackIndexLifecyclePolicyCreate(d *schema.ResourceData
 }
 
 func resourceElasticsearchXpackIndexLifecyclePolicyRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var result string
@@ -72,9 +74,9 @@ func resourceElasticsearchXpackIndexLifecyclePolicyRead(d *schema.ResourceData,
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		result, err = elastic7IndexGetLifecyclePolicy(client, id)
+		result, err = elastic7IndexGetLifecyclePolicy(ctx, client, id)
 	case *elastic6.Client:
-		result, err = elastic6IndexGetLifecyclePolicy(client, id)
+		result, err = elastic6IndexGetLifecyclePolicy(ctx, client, id)
 	default:
 		err = errors.New("Index Lifecycle Management is only supported by the elastic library >= v6!")
 	}
@@ -84,12 +86,12 @@ func resourceElasticsearchXpackIndexLifecyclePolicyRead(d *schema.ResourceData,
 
 	ds := &resourceDataSetter{d: d}
 	ds.set("name", d.Id())
-	ds.set("body", result)
+	ds.set("body", strippedJson(result, stripIndexLifecyclePolicy))
 	return ds.err
 }
 
-func elastic7IndexGetLifecyclePolicy(client *elastic7.Client, id string) (string, error) {
-	res, err := client.XPackIlmGetLifecycle().Policy(id).Do(context.TODO())
+func elastic7IndexGetLifecyclePolicy(ctx context.Context, client *elastic7.Client, id string) (string, error) {
+	res, err := client.XPackIlmGetLifecycle().Policy(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -102,8 +104,8 @@ func elastic7IndexGetLifecyclePolicy(client *elastic7.Client, id string) (string
 	return string(tj), nil
 }
 
-func elastic6IndexGetLifecyclePolicy(client *elastic6.Client, id string) (string, error) {
-	res, err := client.XPackIlmGetLifecycle().Policy(id).Do(context.TODO())
+func elastic6IndexGetLifecyclePolicy(ctx context.Context, client *elastic6.Client, id string) (string, error) {
+	res, err := client.XPackIlmGetLifecycle().Policy(id).Do(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -121,6 +123,7 @@ func resourceElasticsearchXpackIndexLifecyclePolicyUpdate(d *schema.ResourceData
 }
 
 func resourceElasticsearchXpackIndexLifecyclePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var err error
@@ -128,14 +131,16 @@ func resourceElasticsearchXpackIndexLifecyclePolicyDelete(d *schema.ResourceData
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7IndexDeleteLifecyclePolicy(client, id)
-	case *elastic6.Client:
-		err = elastic6IndexDeleteLifecyclePolicy(client, id)
-	default:
-		err = errors.New("Index Lifecycle Management is only supported by the elastic library >= v6!")
-	}
+	err = retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7IndexDeleteLifecyclePolicy(ctx, client, id)
+		case *elastic6.Client:
+			return elastic6IndexDeleteLifecyclePolicy(ctx, client, id)
+		default:
+			return errors.New("Index Lifecycle Management is only supported by the elastic library >= v6!")
+		}
+	})
 
 	if err != nil {
 		return err
@@ -144,17 +149,18 @@ func resourceElasticsearchXpackIndexLifecyclePolicyDelete(d *schema.ResourceData
 	return nil
 }
 
-func elastic7IndexDeleteLifecyclePolicy(client *elastic7.Client, id string) error {
-	_, err := client.XPackIlmDeleteLifecycle().Policy(id).Do(context.TODO())
+func elastic7IndexDeleteLifecyclePolicy(ctx context.Context, client *elastic7.Client, id string) error {
+	_, err := client.XPackIlmDeleteLifecycle().Policy(id).Do(ctx)
 	return err
 }
 
-func elastic6IndexDeleteLifecyclePolicy(client *elastic6.Client, id string) error {
-	_, err := client.XPackIlmDeleteLifecycle().Policy(id).Do(context.TODO())
+func elastic6IndexDeleteLifecyclePolicy(ctx context.Context, client *elastic6.Client, id string) error {
+	_, err := client.XPackIlmDeleteLifecycle().Policy(id).Do(ctx)
 	return err
 }
 
 func resourceElasticsearchPutIndexLifecyclePolicy(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	name := d.Get("name").(string)
 	body := d.Get("body").(string)
 
@@ -163,24 +169,24 @@ func resourceElasticsearchPutIndexLifecyclePolicy(d *schema.ResourceData, meta i
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7IndexPutLifecyclePolicy(client, name, body)
-	case *elastic6.Client:
-		err = elastic6IndexPutLifecyclePolicy(client, name, body)
-	default:
-		err = errors.New("resourceElasticsearchPutIndexLifecyclePolicy Index Lifecycle Management is only supported by the elastic library >= v6!")
-	}
-
-	return err
+	return retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7IndexPutLifecyclePolicy(ctx, client, name, body)
+		case *elastic6.Client:
+			return elastic6IndexPutLifecyclePolicy(ctx, client, name, body)
+		default:
+			return errors.New("resourceElasticsearchPutIndexLifecyclePolicy Index Lifecycle Management is only supported by the elastic library >= v6!")
+		}
+	})
 }
 
-func elastic7IndexPutLifecyclePolicy(client *elastic7.Client, name string, body string) error {
-	_, err := client.XPackIlmPutLifecycle().Policy(name).BodyString(body).Do(context.TODO())
+func elastic7IndexPutLifecyclePolicy(ctx context.Context, client *elastic7.Client, name string, body string) error {
+	_, err := client.XPackIlmPutLifecycle().Policy(name).BodyString(body).Do(ctx)
 	return err
 }
 
-func elastic6IndexPutLifecyclePolicy(client *elastic6.Client, name string, body string) error {
-	_, err := client.XPackIlmPutLifecycle().Policy(name).BodyString(body).Do(context.TODO())
+func elastic6IndexPutLifecyclePolicy(ctx context.Context, client *elastic6.Client, name string, body string) error {
+	_, err := client.XPackIlmPutLifecycle().Policy(name).BodyString(body).Do(ctx)
 	return err
 }
@@ -1,7 +1,6 @@
 package es
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -78,6 +77,7 @@ func resourceElasticsearchLicenseRead(d *schema.ResourceData, meta interface{})
 }
 
 func resourceElasticsearchLicenseDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	var err error
 
 	esClient, err := getClient(meta.(*ProviderConf))
@@ -86,12 +86,12 @@ func resourceElasticsearchLicenseDelete(d *schema.ResourceData, meta interface{}
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		_, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "DELETE",
 			Path:   "/_license",
 		})
 	case *elastic6.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		_, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "DELETE",
 			Path:   "/_xpack/license",
 		})
@@ -107,6 +107,7 @@ func resourceElasticsearchLicenseDelete(d *schema.ResourceData, meta interface{}
 }
 
 func resourceElasticsearchGetXpackLicense(meta interface{}) (License, error) {
+	ctx := providerContext(meta)
 	license := new(License)
 
 	var body json.RawMessage
@@ -119,18 +120,22 @@ func resourceElasticsearchGetXpackLicense(meta interface{}) (License, error) {
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "GET",
 			Path:   "/_license",
 		})
-		body = res.Body
+		if err == nil {
+			body = res.Body
+		}
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "GET",
 			Path:   "/_xpack/license",
 		})
-		body = res.Body
+		if err == nil {
+			body = res.Body
+		}
 	default:
 		return *license, errors.New("License is only supported by the elasticsearch >= v6!")
 	}
@@ -169,6 +174,7 @@ func resourceElasticsearchCreateXpackLicense(d *schema.ResourceData, meta interf
 }
 
 func resourceElasticsearchPutEnterpriseLicense(l string, meta interface{}) (License, error) {
+	ctx := providerContext(meta)
 	request := fmt.Sprintf(`{"licenses": [%s]}`, l)
 
 	var emptyLicense License
@@ -181,7 +187,7 @@ func resourceElasticsearchPutEnterpriseLicense(l string, meta interface{}) (Lice
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		var res *elastic7.Response
-		res, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "PUT",
 			Path:   "/_license?acknowledge=true",
 			Body:   request,
@@ -189,7 +195,7 @@ func resourceElasticsearchPutEnterpriseLicense(l string, meta interface{}) (Lice
 		body = res.Body
 	case *elastic6.Client:
 		var res *elastic6.Response
-		res, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		res, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "PUT",
 			Path:   "/_xpack/license?acknowledge=true",
 			Body:   request,
@@ -212,6 +218,7 @@ func resourceElasticsearchPutEnterpriseLicense(l string, meta interface{}) (Lice
 }
 
 func resourceElasticsearchPostBasicLicense(meta interface{}) (License, error) {
+	ctx := providerContext(meta)
 	var l License
 	var err error
 	esClient, err := getClient(meta.(*ProviderConf))
@@ -220,12 +227,12 @@ func resourceElasticsearchPostBasicLicense(meta interface{}) (License, error) {
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+		_, err = client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 			Method: "POST",
 			Path:   "/_license/start_basic?acknowledge=true",
 		})
 	case *elastic6.Client:
-		_, err = client.PerformRequest(context.TODO(), elastic6.PerformRequestOptions{
+		_, err = client.PerformRequest(ctx, elastic6.PerformRequestOptions{
 			Method: "POST",
 			Path:   "/_xpack/license/start_basic?acknowledge=true",
 		})
@@ -0,0 +1,89 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceElasticsearchXpackMlUpgradeMode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchXpackMlUpgradeModeCreate,
+		Read:   resourceElasticsearchXpackMlUpgradeModeRead,
+		Update: resourceElasticsearchXpackMlUpgradeModeUpdate,
+		Delete: resourceElasticsearchXpackMlUpgradeModeDelete,
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether machine learning is in upgrade mode, with every job and datafeed paused, e.g. while nodes are replaced.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Sets the upgrade mode of machine learning, pausing all jobs and datafeeds before a cluster upgrade and resuming them afterwards. Destroying the resource disables the upgrade mode. There is only one per cluster, its ID is `ml`.",
+	}
+}
+
+func resourceElasticsearchXpackMlUpgradeModeCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := setMlUpgradeMode(d, meta, d.Get("enabled").(bool), schema.TimeoutCreate); err != nil {
+		return err
+	}
+	d.SetId("ml")
+	return resourceElasticsearchXpackMlUpgradeModeRead(d, meta)
+}
+
+func resourceElasticsearchXpackMlUpgradeModeRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	res, err := performRequest(providerContext(meta), esClient, "GET", "/_ml/info", nil, nil)
+	if err != nil {
+		return err
+	}
+	var info struct {
+		UpgradeMode bool `json:"upgrade_mode"`
+	}
+	if err := json.Unmarshal(res, &info); err != nil {
+		return fmt.Errorf("error unmarshalling machine learning info: %+v: %s", err, res)
+	}
+
+	return d.Set("enabled", info.UpgradeMode)
+}
+
+func resourceElasticsearchXpackMlUpgradeModeUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := setMlUpgradeMode(d, meta, d.Get("enabled").(bool), schema.TimeoutUpdate); err != nil {
+		return err
+	}
+	return resourceElasticsearchXpackMlUpgradeModeRead(d, meta)
+}
+
+func resourceElasticsearchXpackMlUpgradeModeDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := setMlUpgradeMode(d, meta, false, schema.TimeoutDelete); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// setMlUpgradeMode waits, up to the operation timeout, for the jobs and
+// datafeeds to be paused or resumed.
+func setMlUpgradeMode(d *schema.ResourceData, meta interface{}, enabled bool, timeout string) error {
+	params := url.Values{
+		"enabled": []string{strconv.FormatBool(enabled)},
+		"timeout": []string{fmt.Sprintf("%ds", int(d.Timeout(timeout).Seconds()))},
+	}
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "POST", "/_ml/set_upgrade_mode", params, nil)
+		return err
+	})
+}
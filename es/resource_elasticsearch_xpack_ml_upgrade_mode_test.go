@@ -0,0 +1,94 @@
+package es
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchXpackMlUpgradeModeCreate(t *testing.T) {
+	upgradeMode := false
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_ml/set_upgrade_mode":
+			if r.URL.Query().Get("timeout") != "1200s" {
+				t.Errorf("expected the operation timeout to be used: %s", r.URL)
+			}
+			upgradeMode = r.URL.Query().Get("enabled") == "true"
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_ml/info":
+			_, _ = fmt.Fprintf(w, `{"defaults": {}, "upgrade_mode": %t}`, upgradeMode)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackMlUpgradeMode()
+	resourceWithTimeouts(r)
+	d := r.TestResourceData()
+	_ = d.Set("enabled", true)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "ml" || !upgradeMode || d.Get("enabled") != true {
+		t.Errorf("expected the upgrade mode to be enabled")
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if upgradeMode {
+		t.Errorf("expected the upgrade mode to be disabled on destroy")
+	}
+}
+
+func TestAccElasticsearchXpackMlUpgradeMode(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	conf := provider.Meta().(*ProviderConf)
+	if _, err := getClient(conf); err != nil {
+		t.Skipf("err: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityMlUpgradeMode.check(conf); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchXpackMlUpgradeMode(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_ml_upgrade_mode.test", "enabled", "true"),
+				),
+			},
+			{
+				Config: testAccElasticsearchXpackMlUpgradeMode(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_ml_upgrade_mode.test", "enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccElasticsearchXpackMlUpgradeMode(enabled bool) string {
+	return fmt.Sprintf(`
+resource "elasticsearch_xpack_ml_upgrade_mode" "test" {
+  enabled = %t
+}
+`, enabled)
+}
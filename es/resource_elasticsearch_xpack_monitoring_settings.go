@@ -0,0 +1,219 @@
+package es
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const monitoringExportersSetting = "xpack.monitoring.exporters."
+
+// monitoringSettings are the cluster settings of the scalar arguments.
+var monitoringSettings = map[string]string{
+	"collection_enabled":  "xpack.monitoring.collection.enabled",
+	"collection_interval": "xpack.monitoring.collection.interval",
+	"history_duration":    "xpack.monitoring.history.duration",
+}
+
+func resourceElasticsearchXpackMonitoringSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchXpackMonitoringSettingsUpdate,
+		Read:   resourceElasticsearchXpackMonitoringSettingsRead,
+		Update: resourceElasticsearchXpackMonitoringSettingsUpdate,
+		Delete: resourceElasticsearchXpackMonitoringSettingsDelete,
+		Schema: map[string]*schema.Schema{
+			"collection_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the cluster collects monitoring data, `xpack.monitoring.collection.enabled`.",
+			},
+			"collection_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "How often the monitoring data is collected, e.g. `30s`, `xpack.monitoring.collection.interval`. Defaults to the default of the cluster, `10s`.",
+			},
+			"history_duration": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "How long the indices of the local exporters keep monitoring data, e.g. `3d`, `xpack.monitoring.history.duration`. Defaults to the default of the cluster, `7d`.",
+			},
+			"exporter": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The exporters sending the monitoring data, `xpack.monitoring.exporters.*`. Defaults to the default local exporter of the cluster.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"local", "http"}, false),
+							Description:  "`local`, storing the data in the cluster, or `http`, sending it to a monitoring cluster.",
+						},
+						"host": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The URLs of the nodes of the monitoring cluster of an `http` exporter.",
+						},
+						"settings": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The other settings of the exporter, relative to it, e.g. `auth.username` or `index.name.time_format`. Secure settings like `auth.secure_password` belong in the keystore of the nodes.",
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages the cluster settings collecting and exporting monitoring data. Destroying the resource resets them to their defaults. There is only one per cluster, its ID is `monitoring`.",
+	}
+}
+
+func resourceElasticsearchXpackMonitoringSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
+	settings := map[string]interface{}{
+		monitoringSettings["collection_enabled"]: d.Get("collection_enabled"),
+	}
+	for _, key := range []string{"collection_interval", "history_duration"} {
+		settings[monitoringSettings[key]] = nil
+		if v, ok := d.GetOk(key); ok {
+			settings[monitoringSettings[key]] = v
+		}
+	}
+	// reset the settings of the exporters that are removed or changed
+	old, _ := d.GetChange("exporter")
+	for setting := range expandMonitoringExporters(old.(*schema.Set).List()) {
+		settings[setting] = nil
+	}
+	for setting, v := range expandMonitoringExporters(d.Get("exporter").(*schema.Set).List()) {
+		settings[setting] = v
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	if err := putPersistentClusterSettings(providerContext(meta), esClient, settings); err != nil {
+		return err
+	}
+	d.SetId("monitoring")
+	return resourceElasticsearchXpackMonitoringSettingsRead(d, meta)
+}
+
+func resourceElasticsearchXpackMonitoringSettingsRead(d *schema.ResourceData, meta interface{}) error {
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	settings, err := persistentClusterSettings(providerContext(meta), esClient)
+	if err != nil {
+		return err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("collection_enabled", clusterSettingString(settings[monitoringSettings["collection_enabled"]]) == "true")
+	for _, key := range []string{"collection_interval", "history_duration"} {
+		v := ""
+		if setting, ok := settings[monitoringSettings[key]]; ok {
+			v = clusterSettingString(setting)
+		}
+		ds.set(key, v)
+	}
+	ds.set("exporter", flattenMonitoringExporters(settings))
+	return ds.err
+}
+
+func resourceElasticsearchXpackMonitoringSettingsDelete(d *schema.ResourceData, meta interface{}) error {
+	settings := map[string]interface{}{}
+	for _, setting := range monitoringSettings {
+		settings[setting] = nil
+	}
+	for setting := range expandMonitoringExporters(d.Get("exporter").(*schema.Set).List()) {
+		settings[setting] = nil
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	if err := putPersistentClusterSettings(providerContext(meta), esClient, settings); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// expandMonitoringExporters returns the flat cluster settings of exporters.
+func expandMonitoringExporters(exporters []interface{}) map[string]interface{} {
+	settings := map[string]interface{}{}
+	for _, e := range exporters {
+		exporter := e.(map[string]interface{})
+		// removed elements of the set are left empty during apply
+		if exporter["name"].(string) == "" {
+			continue
+		}
+		prefix := monitoringExportersSetting + exporter["name"].(string) + "."
+		settings[prefix+"type"] = exporter["type"]
+		if hosts := exporter["host"].([]interface{}); len(hosts) > 0 {
+			settings[prefix+"host"] = hosts
+		}
+		for k, v := range exporter["settings"].(map[string]interface{}) {
+			settings[prefix+k] = v
+		}
+	}
+	return settings
+}
+
+// flattenMonitoringExporters returns the exporters in the flat cluster
+// settings, sorted by name.
+func flattenMonitoringExporters(settings map[string]interface{}) []map[string]interface{} {
+	byName := map[string]map[string]interface{}{}
+	for setting, v := range settings {
+		if !strings.HasPrefix(setting, monitoringExportersSetting) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(setting, monitoringExportersSetting), ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		exporter, ok := byName[parts[0]]
+		if !ok {
+			exporter = map[string]interface{}{
+				"name":     parts[0],
+				"host":     []interface{}{},
+				"settings": map[string]interface{}{},
+			}
+			byName[parts[0]] = exporter
+		}
+		switch parts[1] {
+		case "type":
+			exporter["type"] = clusterSettingString(v)
+		case "host":
+			if hosts, ok := v.([]interface{}); ok {
+				exporter["host"] = hosts
+			} else {
+				exporter["host"] = []interface{}{v}
+			}
+		default:
+			exporter["settings"].(map[string]interface{})[parts[1]] = clusterSettingString(v)
+		}
+	}
+
+	exporters := make([]map[string]interface{}, 0, len(byName))
+	for _, exporter := range byName {
+		exporters = append(exporters, exporter)
+	}
+	sort.Slice(exporters, func(i, j int) bool { return exporters[i]["name"].(string) < exporters[j]["name"].(string) })
+	return exporters
+}
@@ -0,0 +1,146 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchXpackMonitoringSettings(t *testing.T) {
+	persistent := map[string]interface{}{
+		"cluster.routing.allocation.enable": "all",
+	}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_cluster/settings" && r.Method == "PUT":
+			var body struct {
+				Persistent map[string]interface{} `json:"persistent"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range body.Persistent {
+				if v == nil {
+					delete(persistent, k)
+				} else {
+					persistent[k] = v
+				}
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_cluster/settings":
+			if r.URL.Query().Get("flat_settings") != "true" {
+				t.Errorf("expected flat settings, got %s", r.URL)
+			}
+			b, _ := json.Marshal(map[string]interface{}{"persistent": persistent, "transient": map[string]interface{}{}})
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackMonitoringSettings()
+	d := r.TestResourceData()
+	_ = d.Set("collection_enabled", true)
+	_ = d.Set("history_duration", "3d")
+	_ = d.Set("exporter", []interface{}{
+		map[string]interface{}{
+			"name":     "monitoring",
+			"type":     "http",
+			"host":     []interface{}{"https://monitoring.example.com:9200"},
+			"settings": map[string]interface{}{"auth.username": "remote_monitor"},
+		},
+	})
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"cluster.routing.allocation.enable":                   "all",
+		"xpack.monitoring.collection.enabled":                 true,
+		"xpack.monitoring.history.duration":                   "3d",
+		"xpack.monitoring.exporters.monitoring.type":          "http",
+		"xpack.monitoring.exporters.monitoring.host":          []interface{}{"https://monitoring.example.com:9200"},
+		"xpack.monitoring.exporters.monitoring.auth.username": "remote_monitor",
+	}
+	assertSettings := func() {
+		t.Helper()
+		b, _ := json.Marshal(persistent)
+		e, _ := json.Marshal(expected)
+		if string(b) != string(e) {
+			t.Errorf("expected the settings %s, got %s", e, b)
+		}
+	}
+	assertSettings()
+	if d.Id() != "monitoring" || d.Get("exporter.#") != 1 || d.Get("collection_interval") != "" {
+		t.Errorf("expected the settings to be read back, got %s %v", d.Id(), d.State())
+	}
+
+	state := d.State()
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"collection_enabled":  true,
+		"collection_interval": "30s",
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(state, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected = map[string]interface{}{
+		"cluster.routing.allocation.enable":    "all",
+		"xpack.monitoring.collection.enabled":  true,
+		"xpack.monitoring.collection.interval": "30s",
+	}
+	assertSettings()
+
+	d = r.TestResourceData()
+	d.SetId("monitoring")
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected = map[string]interface{}{
+		"cluster.routing.allocation.enable": "all",
+	}
+	assertSettings()
+}
+
+func TestAccElasticsearchXpackMonitoringSettings(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	if err := provider.Configure(&terraform.ResourceConfig{}); err != nil {
+		t.Skipf("err: %s", err)
+	}
+	capabilityErr := capabilityXpackMonitoring.check(provider.Meta().(*ProviderConf))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if capabilityErr != nil {
+				t.Skip(capabilityErr)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchXpackMonitoringSettings,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_monitoring_settings.test", "collection_enabled", "true"),
+					resource.TestCheckResourceAttr("elasticsearch_xpack_monitoring_settings.test", "history_duration", "3d"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchXpackMonitoringSettings = `
+resource "elasticsearch_xpack_monitoring_settings" "test" {
+  collection_enabled = true
+  history_duration   = "3d"
+}
+`
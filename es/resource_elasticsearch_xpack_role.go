@@ -6,8 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
 	elastic7 "github.com/olivere/elastic/v7"
 	elastic5 "gopkg.in/olivere/elastic.v5"
 	elastic6 "gopkg.in/olivere/elastic.v6"
@@ -20,11 +26,21 @@ func resourceElasticsearchXpackRole() *schema.Resource {
 		Update: resourceElasticsearchXpackRoleUpdate,
 		Delete: resourceElasticsearchXpackRoleDelete,
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffCheckRoleIndexPatterns,
+			customizeDiffCheckRoleDescription,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"role_name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description of the intent of the role. Requires Elasticsearch >= 8.15.",
+			},
 			"indices": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -112,6 +128,30 @@ func resourceElasticsearchXpackRole() *schema.Resource {
 				Type:             schema.TypeString,
 				Optional:         true,
 				DiffSuppressFunc: suppressEquivalentJson,
+				ConflictsWith:    []string{"global_privileges"},
+			},
+			"global_privileges": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"global"},
+				Description:   "The global privileges of the role, as a block rather than the JSON of `global`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"manage_applications": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The applications, with wildcards, whose application privileges the owners of the role can manage.",
+						},
+						"write_profile_applications": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The applications, with wildcards, under whose names the owners of the role can write the data of user profiles. Requires Elasticsearch >= 8.5.",
+						},
+					},
+				},
 			},
 			"run_as": {
 				Type:     schema.TypeSet,
@@ -126,13 +166,204 @@ func resourceElasticsearchXpackRole() *schema.Resource {
 				Default:          "{}",
 				DiffSuppressFunc: suppressEquivalentJson,
 			},
+			"check_index_patterns": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "off",
+				ValidateFunc: validation.StringInSlice([]string{"off", "warn", "error"}, false),
+				Description:  "Check during plan that the `names` of `indices` match an existing index, alias or data stream, or the patterns of an index template, to catch typos granting nothing. One of `off`, `warn`, logging a warning, or `error`, failing the plan. Requires Elasticsearch >= 7.9.",
+			},
 		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceElasticsearchXpackRoleImport,
 		},
 	}
 }
 
+func resourceElasticsearchXpackRoleImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	err := d.Set("check_index_patterns", "off")
+	return []*schema.ResourceData{d}, err
+}
+
+// customizeDiffCheckRoleIndexPatterns checks, if enabled, that the index
+// patterns of the role match something when they change.
+func customizeDiffCheckRoleIndexPatterns(d *schema.ResourceDiff, meta interface{}) error {
+	mode := d.Get("check_index_patterns").(string)
+	if mode == "off" || !d.NewValueKnown("indices") || (d.Id() != "" && !d.HasChange("indices") && !d.HasChange("check_index_patterns")) {
+		return nil
+	}
+
+	var patterns []string
+	for _, v := range d.Get("indices").(*schema.Set).List() {
+		for _, name := range v.(map[string]interface{})["names"].(*schema.Set).List() {
+			patterns = append(patterns, name.(string))
+		}
+	}
+	sort.Strings(patterns)
+
+	conf := meta.(*ProviderConf)
+	esClient, err := getClient(conf)
+	if err != nil {
+		log.Printf("[WARN] Skipping the check of the index patterns, the cluster can't be reached: %+v", err)
+		return nil
+	}
+	if err := capabilityResolveIndex.check(conf); err != nil {
+		log.Printf("[WARN] Skipping the check of the index patterns: %+v", err)
+		return nil
+	}
+
+	unmatched, err := unmatchedIndexPatterns(providerContext(meta), esClient, patterns)
+	if err != nil {
+		return fmt.Errorf("error checking the index patterns of role %s: %+v", d.Get("role_name"), err)
+	}
+	if len(unmatched) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("the index patterns %s of role %s don't match any index, alias, data stream or index template", strings.Join(unmatched, ", "), d.Get("role_name"))
+	if mode == "error" {
+		return errors.New(msg)
+	}
+	log.Printf("[WARN] %s", msg)
+	return nil
+}
+
+// customizeDiffCheckRoleDescription fails the plan of a description on
+// clusters that don't store it, instead of the apply.
+func customizeDiffCheckRoleDescription(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("description").(string) == "" || !d.HasChange("description") {
+		return nil
+	}
+	conf := meta.(*ProviderConf)
+	if _, err := getClient(conf); err != nil {
+		log.Printf("[WARN] Skipping the version check of the description, the cluster can't be reached: %+v", err)
+		return nil
+	}
+	return capabilityXpackRoleDescriptions.check(conf)
+}
+
+// unmatchedIndexPatterns returns the patterns that match neither an index,
+// alias or data stream nor the patterns of an index template. Regular
+// expressions, templated names and names of remote clusters aren't checked.
+func unmatchedIndexPatterns(ctx context.Context, esClient interface{}, patterns []string) ([]string, error) {
+	var templatePatterns []string
+	var unmatched []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "{{") || strings.Contains(pattern, ":") || indexPatternPrefix(pattern) == "" {
+			continue
+		}
+
+		path, err := uritemplates.Expand("/_resolve/index/{pattern}", map[string]string{
+			"pattern": pattern,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error building URL path for index resolution: %+v", err)
+		}
+		res, err := performRequest(ctx, esClient, "GET", path, url.Values{"expand_wildcards": []string{"all"}}, nil)
+		if err != nil && !elastic7.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			var resolved struct {
+				Indices     []json.RawMessage `json:"indices"`
+				Aliases     []json.RawMessage `json:"aliases"`
+				DataStreams []json.RawMessage `json:"data_streams"`
+			}
+			if err := json.Unmarshal(res, &resolved); err != nil {
+				return nil, fmt.Errorf("error unmarshalling resolved index %s: %+v: %s", pattern, err, res)
+			}
+			if len(resolved.Indices)+len(resolved.Aliases)+len(resolved.DataStreams) > 0 {
+				continue
+			}
+		}
+
+		if templatePatterns == nil {
+			templatePatterns, err = indexTemplatePatterns(ctx, esClient)
+			if err != nil {
+				return nil, err
+			}
+		}
+		matched := false
+		for _, templatePattern := range templatePatterns {
+			if indexPatternsOverlap(pattern, templatePattern) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched, nil
+}
+
+// indexTemplatePatterns returns the index patterns of the composable and
+// legacy index templates.
+func indexTemplatePatterns(ctx context.Context, esClient interface{}) ([]string, error) {
+	patterns := []string{}
+
+	res, err := performRequest(ctx, esClient, "GET", "/_index_template", nil, nil)
+	if err != nil && !elastic7.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		var composable struct {
+			IndexTemplates []struct {
+				IndexTemplate struct {
+					IndexPatterns []string `json:"index_patterns"`
+				} `json:"index_template"`
+			} `json:"index_templates"`
+		}
+		if err := json.Unmarshal(res, &composable); err != nil {
+			return nil, fmt.Errorf("error unmarshalling index templates: %+v: %s", err, res)
+		}
+		for _, t := range composable.IndexTemplates {
+			patterns = append(patterns, t.IndexTemplate.IndexPatterns...)
+		}
+	}
+
+	res, err = performRequest(ctx, esClient, "GET", "/_template", nil, nil)
+	if err != nil && !elastic7.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		var legacy map[string]struct {
+			IndexPatterns []string `json:"index_patterns"`
+		}
+		if err := json.Unmarshal(res, &legacy); err != nil {
+			return nil, fmt.Errorf("error unmarshalling legacy index templates: %+v: %s", err, res)
+		}
+		for _, t := range legacy {
+			patterns = append(patterns, t.IndexPatterns...)
+		}
+	}
+	return patterns, nil
+}
+
+// indexPatternPrefix returns the part of pattern before its first wildcard.
+func indexPatternPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// indexPatternsOverlap returns whether some index name could match both
+// patterns, comparing the parts before their first wildcard.
+func indexPatternsOverlap(a, b string) bool {
+	prefixA, prefixB := indexPatternPrefix(a), indexPatternPrefix(b)
+	wildcardA, wildcardB := prefixA != a, prefixB != b
+	switch {
+	case !wildcardA && !wildcardB:
+		return a == b
+	case !wildcardA:
+		return strings.HasPrefix(a, prefixB)
+	case !wildcardB:
+		return strings.HasPrefix(b, prefixA)
+	default:
+		return strings.HasPrefix(prefixA, prefixB) || strings.HasPrefix(prefixB, prefixA)
+	}
+}
+
 func resourceElasticsearchXpackRoleCreate(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("role_name").(string)
 
@@ -204,9 +435,27 @@ func resourceElasticsearchXpackRoleRead(d *schema.ResourceData, m interface{}) e
 		ds.set("applications", applications)
 	}
 
-	ds.set("global", role.Global)
+	// the global privileges are read back into the block unless configured
+	// as JSON, or if the block can't express them
+	globalPrivileges, ok := flattenRoleGlobalPrivileges(role.Global)
+	if ok && d.Get("global").(string) == "" {
+		ds.set("global", "")
+		ds.set("global_privileges", globalPrivileges)
+	} else {
+		ds.set("global", role.Global)
+		ds.set("global_privileges", nil)
+	}
 	ds.set("run_as", role.RunAs)
 	ds.set("metadata", role.Metadata)
+
+	// the clients don't know the description, added in 8.15
+	if capabilityXpackRoleDescriptions.check(m.(*ProviderConf)) == nil {
+		description, err := xpackRoleDescription(d, m, d.Id())
+		if err != nil {
+			return err
+		}
+		ds.set("description", description)
+	}
 	return ds.err
 }
 
@@ -280,15 +529,19 @@ func buildPutRoleBody(d *schema.ResourceData, m interface{}) (string, error) {
 	}
 
 	runAs := expandStringList(d.Get("run_as").(*schema.Set).List())
-	global := d.Get("global").(string)
+	global := optionalInterfaceJson(d.Get("global").(string))
+	if v, ok := d.GetOk("global_privileges"); ok {
+		global = expandRoleGlobalPrivileges(v.([]interface{}))
+	}
 	metadata := d.Get("metadata").(string)
 
 	role := PutRoleBody{
+		Description:  d.Get("description").(string),
 		Cluster:      clusterPrivileges,
 		Applications: applicationsBody,
 		Indices:      indicesBody,
 		RunAs:        runAs,
-		Global:       optionalInterfaceJson(global),
+		Global:       global,
 		Metadata:     optionalInterfaceJson(metadata),
 	}
 
@@ -300,33 +553,92 @@ func buildPutRoleBody(d *schema.ResourceData, m interface{}) (string, error) {
 	return string(body[:]), err
 }
 
+// expandRoleGlobalPrivileges returns the global section of a role for the
+// global_privileges block.
+func expandRoleGlobalPrivileges(v []interface{}) interface{} {
+	global := map[string]interface{}{}
+	if len(v) == 0 || v[0] == nil {
+		return global
+	}
+	block := v[0].(map[string]interface{})
+	if apps := expandStringList(block["manage_applications"].(*schema.Set).List()); len(apps) > 0 {
+		global["application"] = map[string]interface{}{
+			"manage": map[string]interface{}{"applications": apps},
+		}
+	}
+	if apps := expandStringList(block["write_profile_applications"].(*schema.Set).List()); len(apps) > 0 {
+		global["profile"] = map[string]interface{}{
+			"write": map[string]interface{}{"applications": apps},
+		}
+	}
+	return global
+}
+
+// flattenRoleGlobalPrivileges returns the global_privileges block for the
+// JSON of the global section of a role, and false if the block can't express
+// it.
+func flattenRoleGlobalPrivileges(global string) ([]map[string]interface{}, bool) {
+	if global == "" {
+		return nil, true
+	}
+	var privileges map[string]map[string]struct {
+		Applications []string `json:"applications"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(global))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&privileges); err != nil {
+		return nil, false
+	}
+	block := map[string]interface{}{}
+	for section, actions := range privileges {
+		for action, v := range actions {
+			switch section + "." + action {
+			case "application.manage":
+				block["manage_applications"] = v.Applications
+			case "profile.write":
+				block["write_profile_applications"] = v.Applications
+			default:
+				return nil, false
+			}
+		}
+	}
+	if len(block) == 0 {
+		return nil, true
+	}
+	return []map[string]interface{}{block}, true
+}
+
 func xpackPutRole(d *schema.ResourceData, m interface{}, name string, body string) error {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7PutRole(client, name, body)
-	case *elastic6.Client:
-		return elastic6PutRole(client, name, body)
-	case *elastic5.Client:
-		return elastic5PutRole(client, name, body)
-	default:
-		return errors.New("unhandled client type")
-	}
+	return retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7PutRole(ctx, client, name, body)
+		case *elastic6.Client:
+			return elastic6PutRole(ctx, client, name, body)
+		case *elastic5.Client:
+			return elastic5PutRole(client, name, body)
+		default:
+			return errors.New("unhandled client type")
+		}
+	})
 }
 
 func xpackGetRole(d *schema.ResourceData, m interface{}, name string) (XPackSecurityRole, error) {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return XPackSecurityRole{}, err
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		return elastic7GetRole(client, name)
+		return elastic7GetRole(ctx, client, name)
 	case *elastic6.Client:
-		return elastic6GetRole(client, name)
+		return elastic6GetRole(ctx, client, name)
 	case *elastic5.Client:
 		return elastic5GetRole(client, name)
 	default:
@@ -334,35 +646,63 @@ func xpackGetRole(d *schema.ResourceData, m interface{}, name string) (XPackSecu
 	}
 }
 
+// xpackRoleDescription returns the description of the role name.
+func xpackRoleDescription(d *schema.ResourceData, m interface{}, name string) (string, error) {
+	esClient, err := getClient(m.(*ProviderConf))
+	if err != nil {
+		return "", err
+	}
+	path, err := uritemplates.Expand("/_security/role/{name}", map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for role: %+v", err)
+	}
+	res, err := performRequest(providerContext(m), esClient, "GET", path, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var roles map[string]struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(res, &roles); err != nil {
+		return "", fmt.Errorf("error unmarshalling role %s: %+v: %s", name, err, res)
+	}
+	return roles[name].Description, nil
+}
+
 func xpackDeleteRole(d *schema.ResourceData, m interface{}, name string) error {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7DeleteRole(client, name)
-	case *elastic6.Client:
-		return elastic6DeleteRole(client, name)
-	case *elastic5.Client:
-		return elastic5DeleteRole(client, name)
-	default:
-		return errors.New("unhandled client type")
-	}
+	return retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7DeleteRole(ctx, client, name)
+		case *elastic6.Client:
+			return elastic6DeleteRole(ctx, client, name)
+		case *elastic5.Client:
+			return elastic5DeleteRole(client, name)
+		default:
+			return errors.New("unhandled client type")
+		}
+	})
 }
 
 func elastic5PutRole(client *elastic5.Client, name string, body string) error {
 	return errors.New("unsupported in elasticv5 client")
 }
 
-func elastic6PutRole(client *elastic6.Client, name string, body string) error {
-	_, err := client.XPackSecurityPutRole(name).Body(body).Do(context.Background())
+func elastic6PutRole(ctx context.Context, client *elastic6.Client, name string, body string) error {
+	_, err := client.XPackSecurityPutRole(name).Body(body).Do(ctx)
 	log.Printf("[INFO] put error: %+v", err)
 	return err
 }
 
-func elastic7PutRole(client *elastic7.Client, name string, body string) error {
-	_, err := client.XPackSecurityPutRole(name).Body(body).Do(context.Background())
+func elastic7PutRole(ctx context.Context, client *elastic7.Client, name string, body string) error {
+	_, err := client.XPackSecurityPutRole(name).Body(body).Do(ctx)
 	log.Printf("[INFO] put error: %+v", err)
 	return err
 }
@@ -372,8 +712,8 @@ func elastic5GetRole(client *elastic5.Client, name string) (XPackSecurityRole, e
 	return XPackSecurityRole{}, err
 }
 
-func elastic6GetRole(client *elastic6.Client, name string) (XPackSecurityRole, error) {
-	res, err := client.XPackSecurityGetRole(name).Do(context.Background())
+func elastic6GetRole(ctx context.Context, client *elastic6.Client, name string) (XPackSecurityRole, error) {
+	res, err := client.XPackSecurityGetRole(name).Do(ctx)
 	if err != nil {
 		return XPackSecurityRole{}, err
 	}
@@ -416,8 +756,8 @@ func elastic6GetRole(client *elastic6.Client, name string) (XPackSecurityRole, e
 	return role, err
 }
 
-func elastic7GetRole(client *elastic7.Client, name string) (XPackSecurityRole, error) {
-	res, err := client.XPackSecurityGetRole(name).Do(context.Background())
+func elastic7GetRole(ctx context.Context, client *elastic7.Client, name string) (XPackSecurityRole, error) {
+	res, err := client.XPackSecurityGetRole(name).Do(ctx)
 	if err != nil {
 		return XPackSecurityRole{}, err
 	}
@@ -466,17 +806,18 @@ func elastic5DeleteRole(client *elastic5.Client, name string) error {
 	return err
 }
 
-func elastic6DeleteRole(client *elastic6.Client, name string) error {
-	_, err := client.XPackSecurityDeleteRole(name).Do(context.Background())
+func elastic6DeleteRole(ctx context.Context, client *elastic6.Client, name string) error {
+	_, err := client.XPackSecurityDeleteRole(name).Do(ctx)
 	return err
 }
 
-func elastic7DeleteRole(client *elastic7.Client, name string) error {
-	_, err := client.XPackSecurityDeleteRole(name).Do(context.Background())
+func elastic7DeleteRole(ctx context.Context, client *elastic7.Client, name string) error {
+	_, err := client.XPackSecurityDeleteRole(name).Do(ctx)
 	return err
 }
 
 type PutRoleBody struct {
+	Description  string                         `json:"description,omitempty"`
 	Cluster      []string                       `json:"cluster"`
 	Applications []PutRoleApplicationPrivileges `json:"applications,omitempty"`
 	Indices      []PutRoleIndicesPermissions    `json:"indices,omitempty"`
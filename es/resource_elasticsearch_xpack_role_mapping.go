@@ -80,19 +80,18 @@ func resourceElasticsearchXpackRoleMappingRead(d *schema.ResourceData, m interfa
 
 	roleMapping, err := xpackGetRoleMapping(d, m, d.Id())
 	if err != nil {
-		fmt.Println("Error during read")
 		if elasticErr, ok := err.(*elastic7.Error); ok && elastic7.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] Role mapping %s not found. Removing from state\n", d.Id())
+			log.Printf("[WARN] Role mapping %s not found. Removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic6.Error); ok && elastic6.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] Role mapping %s not found. Removing from state\n", d.Id())
+			log.Printf("[WARN] Role mapping %s not found. Removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic5.Error); ok && elastic5.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] Role mapping %s not found. Removing from state\n", d.Id())
+			log.Printf("[WARN] Role mapping %s not found. Removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
@@ -126,19 +125,18 @@ func resourceElasticsearchXpackRoleMappingDelete(d *schema.ResourceData, m inter
 
 	err := xpackDeleteRoleMapping(d, m, d.Id())
 	if err != nil {
-		fmt.Println("Error during destroy")
 		if elasticErr, ok := err.(*elastic7.Error); ok && elastic7.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] Role mapping %s not found. Resource removed from state\n", d.Id())
+			log.Printf("[WARN] Role mapping %s not found. Resource removed from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic6.Error); ok && elastic6.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] Role mapping %s not found. Resource removed from state\n", d.Id())
+			log.Printf("[WARN] Role mapping %s not found. Resource removed from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic5.Error); ok && elastic5.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] Role mapping %s not found. Resource removed from state\n", d.Id())
+			log.Printf("[WARN] Role mapping %s not found. Resource removed from state", d.Id())
 			d.SetId("")
 			return nil
 		}
@@ -162,38 +160,42 @@ func buildPutRoleMappingBody(d *schema.ResourceData, m interface{}) (string, err
 
 	body, err := json.Marshal(roleMapping)
 	if err != nil {
-		err = fmt.Errorf("Body Error : %s", body)
+		err = fmt.Errorf("error marshalling request body: %+v", err)
 	}
 	return string(body[:]), err
 }
 
 func xpackPutRoleMapping(d *schema.ResourceData, m interface{}, name string, body string) error {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7PutRoleMapping(client, name, body)
-	case *elastic6.Client:
-		return elastic6PutRoleMapping(client, name, body)
-	case *elastic5.Client:
-		return elastic5PutRoleMapping(client, name, body)
-	default:
-		return errors.New("unhandled client type")
-	}
+	return retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7PutRoleMapping(ctx, client, name, body)
+		case *elastic6.Client:
+			return elastic6PutRoleMapping(ctx, client, name, body)
+		case *elastic5.Client:
+			return elastic5PutRoleMapping(client, name, body)
+		default:
+			return errors.New("unhandled client type")
+		}
+	})
 }
 
 func xpackGetRoleMapping(d *schema.ResourceData, m interface{}, name string) (XPackSecurityRoleMapping, error) {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return XPackSecurityRoleMapping{}, err
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		return elastic7GetRoleMapping(client, name)
+		return elastic7GetRoleMapping(ctx, client, name)
 	case *elastic6.Client:
-		return elastic6GetRoleMapping(client, name)
+		return elastic6GetRoleMapping(ctx, client, name)
 	case *elastic5.Client:
 		return elastic5GetRoleMapping(client, name)
 	default:
@@ -202,34 +204,37 @@ func xpackGetRoleMapping(d *schema.ResourceData, m interface{}, name string) (XP
 }
 
 func xpackDeleteRoleMapping(d *schema.ResourceData, m interface{}, name string) error {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7DeleteRoleMapping(client, name)
-	case *elastic6.Client:
-		return elastic6DeleteRoleMapping(client, name)
-	case *elastic5.Client:
-		return elastic5DeleteRoleMapping(client, name)
-	default:
-		return errors.New("unhandled client type")
-	}
+	return retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7DeleteRoleMapping(ctx, client, name)
+		case *elastic6.Client:
+			return elastic6DeleteRoleMapping(ctx, client, name)
+		case *elastic5.Client:
+			return elastic5DeleteRoleMapping(client, name)
+		default:
+			return errors.New("unhandled client type")
+		}
+	})
 }
 
 func elastic5PutRoleMapping(client *elastic5.Client, name string, body string) error {
 	return errors.New("unsupported in elasticv5 client")
 }
 
-func elastic6PutRoleMapping(client *elastic6.Client, name string, body string) error {
-	resp, err := client.XPackSecurityPutRoleMapping(name).Body(body).Do(context.Background())
+func elastic6PutRoleMapping(ctx context.Context, client *elastic6.Client, name string, body string) error {
+	resp, err := client.XPackSecurityPutRoleMapping(name).Body(body).Do(ctx)
 	log.Printf("[INFO] put error: %+v, %+v", resp, err)
 	return err
 }
 
-func elastic7PutRoleMapping(client *elastic7.Client, name string, body string) error {
-	resp, err := client.XPackSecurityPutRoleMapping(name).Body(body).Do(context.Background())
+func elastic7PutRoleMapping(ctx context.Context, client *elastic7.Client, name string, body string) error {
+	resp, err := client.XPackSecurityPutRoleMapping(name).Body(body).Do(ctx)
 	log.Printf("[INFO] put error: %+v, %+v", resp, err)
 	return err
 }
@@ -239,8 +244,8 @@ func elastic5GetRoleMapping(client *elastic5.Client, name string) (XPackSecurity
 	return XPackSecurityRoleMapping{}, err
 }
 
-func elastic6GetRoleMapping(client *elastic6.Client, name string) (XPackSecurityRoleMapping, error) {
-	res, err := client.XPackSecurityGetRoleMapping(name).Do(context.Background())
+func elastic6GetRoleMapping(ctx context.Context, client *elastic6.Client, name string) (XPackSecurityRoleMapping, error) {
+	res, err := client.XPackSecurityGetRoleMapping(name).Do(ctx)
 	if err != nil {
 		return XPackSecurityRoleMapping{}, err
 	}
@@ -263,8 +268,8 @@ func elastic6GetRoleMapping(client *elastic6.Client, name string) (XPackSecurity
 	return roleMapping, err
 }
 
-func elastic7GetRoleMapping(client *elastic7.Client, name string) (XPackSecurityRoleMapping, error) {
-	res, err := client.XPackSecurityGetRoleMapping(name).Do(context.Background())
+func elastic7GetRoleMapping(ctx context.Context, client *elastic7.Client, name string) (XPackSecurityRoleMapping, error) {
+	res, err := client.XPackSecurityGetRoleMapping(name).Do(ctx)
 	if err != nil {
 		return XPackSecurityRoleMapping{}, err
 	}
@@ -292,13 +297,13 @@ func elastic5DeleteRoleMapping(client *elastic5.Client, name string) error {
 	return err
 }
 
-func elastic6DeleteRoleMapping(client *elastic6.Client, name string) error {
-	_, err := client.XPackSecurityDeleteRoleMapping(name).Do(context.Background())
+func elastic6DeleteRoleMapping(ctx context.Context, client *elastic6.Client, name string) error {
+	_, err := client.XPackSecurityDeleteRoleMapping(name).Do(ctx)
 	return err
 }
 
-func elastic7DeleteRoleMapping(client *elastic7.Client, name string) error {
-	_, err := client.XPackSecurityDeleteRoleMapping(name).Do(context.Background())
+func elastic7DeleteRoleMapping(ctx context.Context, client *elastic7.Client, name string) error {
+	_, err := client.XPackSecurityDeleteRoleMapping(name).Do(ctx)
 	return err
 }
 
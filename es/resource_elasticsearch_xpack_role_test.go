@@ -2,7 +2,10 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	elastic7 "github.com/olivere/elastic/v7"
@@ -94,6 +97,191 @@ func TestAccElasticsearchXpackRole(t *testing.T) {
 	})
 }
 
+func TestResourceElasticsearchXpackRoleCheckIndexPatterns(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_resolve/index/logs-prod-*":
+			_, _ = w.Write([]byte(`{"indices": [], "aliases": [], "data_streams": [{"name": "logs-prod-default"}]}`))
+		case "/_resolve/index/metrics-*", "/_resolve/index/logs-pord-*":
+			_, _ = w.Write([]byte(`{"indices": [], "aliases": [], "data_streams": []}`))
+		case "/_resolve/index/audit":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"type": "index_not_found_exception", "reason": "no such index [audit]"}, "status": 404}`))
+		case "/_index_template":
+			_, _ = w.Write([]byte(`{"index_templates": [{"name": "metrics", "index_template": {"index_patterns": ["metrics-*-*"]}}]}`))
+		case "/_template":
+			_, _ = w.Write([]byte(`{"legacy": {"index_patterns": ["legacy-*"]}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackRole()
+	diff := func(mode string) error {
+		_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"role_name":            "logs",
+			"check_index_patterns": mode,
+			"indices": []interface{}{
+				map[string]interface{}{
+					"names":      []interface{}{"logs-prod-*", "logs-pord-*", "metrics-*", "audit", "/logs-.*/"},
+					"privileges": []interface{}{"read"},
+				},
+			},
+		}), conf)
+		return err
+	}
+
+	if err := diff("warn"); err != nil {
+		t.Errorf("expected only a warning, got %s", err)
+	}
+	err := diff("error")
+	if err == nil || !strings.Contains(err.Error(), "the index patterns audit, logs-pord-* of role logs") {
+		t.Errorf("expected the unmatched patterns to fail the plan, got %v", err)
+	}
+}
+
+func TestResourceElasticsearchXpackRoleGlobalPrivileges(t *testing.T) {
+	var global string
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "8.5.0"}}`))
+		case r.URL.Path == "/_security/role/admin" && r.Method == "PUT":
+			var body struct {
+				Global json.RawMessage `json:"global"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			global = string(body.Global)
+			_, _ = w.Write([]byte(`{"role": {"created": true}}`))
+		case r.URL.Path == "/_security/role/admin":
+			_, _ = w.Write([]byte(`{"admin": {"cluster": ["manage_security"], "global": ` + global + `}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackRole()
+	d := r.TestResourceData()
+	d.Set("role_name", "admin")
+	d.Set("cluster", []interface{}{"manage_security"})
+	d.Set("global_privileges", []interface{}{
+		map[string]interface{}{
+			"manage_applications":        []interface{}{"kibana-*"},
+			"write_profile_applications": []interface{}{"kibana"},
+		},
+	})
+	if err := r.Create(d, conf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"application":{"manage":{"applications":["kibana-*"]}},"profile":{"write":{"applications":["kibana"]}}}`
+	if global != expected {
+		t.Errorf("expected the global privileges %s, got %s", expected, global)
+	}
+	if d.Get("global").(string) != "" {
+		t.Errorf("expected the global privileges to be read back into the block, got %s", d.Get("global"))
+	}
+	if apps := d.Get("global_privileges.0.manage_applications").(*schema.Set).List(); len(apps) != 1 || apps[0] != "kibana-*" {
+		t.Errorf("expected the applications to manage to be read back, got %v", apps)
+	}
+	if apps := d.Get("global_privileges.0.write_profile_applications").(*schema.Set).List(); len(apps) != 1 || apps[0] != "kibana" {
+		t.Errorf("expected the profile applications to be read back, got %v", apps)
+	}
+}
+
+func TestResourceElasticsearchXpackRoleDescription(t *testing.T) {
+	clusterVersion := "8.15.0"
+	var description string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "` + clusterVersion + `"}}`))
+		case r.URL.Path == "/_security/role/ops" && r.Method == "PUT":
+			var body struct {
+				Description string `json:"description"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			description = body.Description
+			_, _ = w.Write([]byte(`{"role": {"created": true}}`))
+		case r.URL.Path == "/_security/role/ops":
+			_, _ = w.Write([]byte(`{"ops": {"cluster": ["monitor"], "description": "` + description + `"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}
+
+	r := resourceElasticsearchXpackRole()
+	d := r.TestResourceData()
+	d.Set("role_name", "ops")
+	d.Set("cluster", []interface{}{"monitor"})
+	d.Set("description", "Read-only access for the on-call team")
+	if err := r.Create(d, testProviderConf(t, handler)); err != nil {
+		t.Fatal(err)
+	}
+	if description != "Read-only access for the on-call team" {
+		t.Errorf("expected the description to be sent, got %q", description)
+	}
+	if d.Get("description").(string) != description {
+		t.Errorf("expected the description to be read back, got %q", d.Get("description"))
+	}
+
+	clusterVersion = "8.14.0"
+	_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"role_name":   "ops",
+		"description": "Read-only access for the on-call team",
+	}), testProviderConf(t, handler))
+	if err == nil || !strings.Contains(err.Error(), "require Elasticsearch >= 8.15.0") {
+		t.Errorf("expected the description to fail the plan on 8.14, got %v", err)
+	}
+}
+
+func TestFlattenRoleGlobalPrivileges(t *testing.T) {
+	tests := []struct {
+		global   string
+		expected bool
+	}{
+		{``, true},
+		{`{}`, true},
+		{`{"application": {"manage": {"applications": ["app"]}}}`, true},
+		{`{"application": {"manage": {"applications": ["app"], "other": true}}}`, false},
+		{`{"application": {"read": {"applications": ["app"]}}}`, false},
+	}
+	for _, tt := range tests {
+		if _, ok := flattenRoleGlobalPrivileges(tt.global); ok != tt.expected {
+			t.Errorf("expected %s to fit the block: %t, got %t", tt.global, tt.expected, ok)
+		}
+	}
+}
+
+func TestIndexPatternsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"logs-*", "logs-prod-*", true},
+		{"logs-prod-*", "logs-*", true},
+		{"logs-pord-*", "logs-prod-*", false},
+		{"logs", "logs", true},
+		{"logs", "logs-*", false},
+		{"logs-1", "logs-*", true},
+		{"metrics-*", "logs-*", false},
+	}
+	for _, tt := range tests {
+		if actual := indexPatternsOverlap(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("expected %s and %s to overlap: %t, got %t", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+
 func testAccCheckRoleDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "elasticsearch_xpack_role" {
@@ -0,0 +1,35 @@
+package es
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceElasticsearchXpackSnapshotLifecycleMode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchXpackSnapshotLifecycleModeCreate,
+		Read:   resourceElasticsearchXpackSnapshotLifecycleModeRead,
+		Update: resourceElasticsearchXpackSnapshotLifecycleModeUpdate,
+		Delete: resourceElasticsearchXpackSnapshotLifecycleModeDelete,
+		Schema: lifecycleModeSchema("snapshot lifecycle management"),
+		Importer: &schema.ResourceImporter{
+			State: resourceElasticsearchLifecycleModeImport,
+		},
+		Description: "Starts or stops snapshot lifecycle management, e.g. to suspend scheduled snapshots during a restore exercise. Destroying the resource starts it again. There is only one per cluster, its ID is `slm`.",
+	}
+}
+
+func resourceElasticsearchXpackSnapshotLifecycleModeCreate(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchPutLifecycleMode(d, meta, "_slm", schema.TimeoutCreate)
+}
+
+func resourceElasticsearchXpackSnapshotLifecycleModeUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchPutLifecycleMode(d, meta, "_slm", schema.TimeoutUpdate)
+}
+
+func resourceElasticsearchXpackSnapshotLifecycleModeRead(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchLifecycleModeRead(d, meta, "_slm")
+}
+
+func resourceElasticsearchXpackSnapshotLifecycleModeDelete(d *schema.ResourceData, meta interface{}) error {
+	return resourceElasticsearchLifecycleModeDelete(d, meta, "_slm")
+}
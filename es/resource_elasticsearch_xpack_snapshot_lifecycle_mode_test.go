@@ -0,0 +1,87 @@
+package es
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchXpackSnapshotLifecycleModeCreate(t *testing.T) {
+	var polls int
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_slm/stop":
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_slm/status":
+			polls++
+			_, _ = w.Write([]byte(`{"operation_mode": "STOPPING"}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackSnapshotLifecycleMode()
+	d := r.TestResourceData()
+	_ = d.Set("enabled", false)
+	_ = d.Set("wait_for_stopped", false)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if polls != 1 {
+		t.Errorf("expected the status to only be read once without waiting, got %d", polls)
+	}
+	if d.Id() != "slm" || d.Get("operation_mode") != "STOPPING" || d.Get("enabled") != false {
+		t.Errorf("expected slm to be stopping, got %s %v", d.Id(), d.Get("operation_mode"))
+	}
+}
+
+func TestAccElasticsearchXpackSnapshotLifecycleMode(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	conf := provider.Meta().(*ProviderConf)
+	if _, err := getClient(conf); err != nil {
+		t.Skipf("err: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilitySnapshotLifecycleMode.check(conf); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchXpackSnapshotLifecycleMode(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_snapshot_lifecycle_mode.test", "operation_mode", "STOPPED"),
+				),
+			},
+			{
+				Config: testAccElasticsearchXpackSnapshotLifecycleMode(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_snapshot_lifecycle_mode.test", "operation_mode", "RUNNING"),
+				),
+			},
+		},
+	})
+}
+
+func testAccElasticsearchXpackSnapshotLifecycleMode(enabled bool) string {
+	return fmt.Sprintf(`
+resource "elasticsearch_xpack_snapshot_lifecycle_mode" "test" {
+  enabled = %t
+}
+`, enabled)
+}
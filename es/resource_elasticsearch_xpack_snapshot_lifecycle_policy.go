@@ -49,6 +49,7 @@ func resourceElasticsearchXpackSnapshotLifecyclePolicyCreate(d *schema.ResourceD
 }
 
 func resourceElasticsearchXpackSnapshotLifecyclePolicyRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var result string
@@ -59,7 +60,7 @@ func resourceElasticsearchXpackSnapshotLifecyclePolicyRead(d *schema.ResourceDat
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		result, err = elastic7SnapshotGetLifecyclePolicy(client, id)
+		result, err = elastic7SnapshotGetLifecyclePolicy(ctx, client, id)
 	default:
 		err = errors.New("Snapshot Lifecycle Management is only supported by the elastic library >= v7!")
 	}
@@ -73,8 +74,8 @@ func resourceElasticsearchXpackSnapshotLifecyclePolicyRead(d *schema.ResourceDat
 	return ds.err
 }
 
-func elastic7SnapshotGetLifecyclePolicy(client *elastic7.Client, id string) (string, error) {
-	res, err := client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+func elastic7SnapshotGetLifecyclePolicy(ctx context.Context, client *elastic7.Client, id string) (string, error) {
+	res, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 		Method: http.MethodGet,
 		Path:   "/_slm/policy/" + id,
 	})
@@ -118,6 +119,7 @@ func resourceElasticsearchXpackSnapshotLifecyclePolicyUpdate(d *schema.ResourceD
 }
 
 func resourceElasticsearchXpackSnapshotLifecyclePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	id := d.Id()
 
 	var err error
@@ -125,12 +127,14 @@ func resourceElasticsearchXpackSnapshotLifecyclePolicyDelete(d *schema.ResourceD
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7SnapshotDeleteLifecyclePolicy(client, id)
-	default:
-		err = errors.New("Snapshot Lifecycle Management is only supported by the elastic library >= v7!")
-	}
+	err = retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7SnapshotDeleteLifecyclePolicy(ctx, client, id)
+		default:
+			return errors.New("Snapshot Lifecycle Management is only supported by the elastic library >= v7!")
+		}
+	})
 
 	if err != nil {
 		return err
@@ -139,8 +143,8 @@ func resourceElasticsearchXpackSnapshotLifecyclePolicyDelete(d *schema.ResourceD
 	return nil
 }
 
-func elastic7SnapshotDeleteLifecyclePolicy(client *elastic7.Client, id string) error {
-	_, err := client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+func elastic7SnapshotDeleteLifecyclePolicy(ctx context.Context, client *elastic7.Client, id string) error {
+	_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 		Method: http.MethodDelete,
 		Path:   "/_slm/policy/" + id,
 	})
@@ -148,6 +152,7 @@ func elastic7SnapshotDeleteLifecyclePolicy(client *elastic7.Client, id string) e
 }
 
 func resourceElasticsearchPutSnapshotLifecyclePolicy(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
 	name := d.Get("name").(string)
 	body := d.Get("body").(string)
 
@@ -156,18 +161,18 @@ func resourceElasticsearchPutSnapshotLifecyclePolicy(d *schema.ResourceData, met
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		err = elastic7SnapshotPutLifecyclePolicy(client, name, body)
-	default:
-		err = errors.New("resourceElasticsearchPutSnapshotLifecyclePolicy Snapshot Lifecycle Management is only supported by the elastic library >= v7!")
-	}
-
-	return err
+	return retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7SnapshotPutLifecyclePolicy(ctx, client, name, body)
+		default:
+			return errors.New("resourceElasticsearchPutSnapshotLifecyclePolicy Snapshot Lifecycle Management is only supported by the elastic library >= v7!")
+		}
+	})
 }
 
-func elastic7SnapshotPutLifecyclePolicy(client *elastic7.Client, name string, body string) error {
-	_, err := client.PerformRequest(context.TODO(), elastic7.PerformRequestOptions{
+func elastic7SnapshotPutLifecyclePolicy(ctx context.Context, client *elastic7.Client, name string, body string) error {
+	_, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
 		Method: http.MethodPut,
 		Path:   "/_slm/policy/" + name,
 		Body:   body,
@@ -0,0 +1,336 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/olivere/elastic/uritemplates"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+var diffSuppressTransform = diffSuppressJson(normalizeTransform)
+
+// transformFunctions are the keys of the body of a transform that the update
+// API rejects, changing them replaces the transform.
+var transformFunctions = []string{"pivot", "latest"}
+
+type transformStatsResponse struct {
+	Transforms []transformStats `json:"transforms"`
+}
+
+type transformStats struct {
+	State         string `json:"state"`
+	Reason        string `json:"reason"`
+	Checkpointing struct {
+		Last struct {
+			Checkpoint int `json:"checkpoint"`
+		} `json:"last"`
+	} `json:"checkpointing"`
+}
+
+func resourceElasticsearchXpackTransform() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceElasticsearchXpackTransformCreate,
+		Read:          resourceElasticsearchXpackTransformRead,
+		Update:        resourceElasticsearchXpackTransformUpdate,
+		Delete:        resourceElasticsearchXpackTransformDelete,
+		CustomizeDiff: customizeDiffXpackTransform,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The ID of the transform.",
+			},
+			"body": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: diffSuppressTransform,
+				ValidateFunc:     validation.StringIsJSON,
+				Description:      "The JSON definition of the transform, with its `source`, `dest` and `pivot` or `latest`, see the [docs](https://www.elastic.co/guide/en/elasticsearch/reference/current/put-transform.html#put-transform-request-body). Changing the `pivot` or `latest` replaces the transform.",
+			},
+			"start": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the transform is started. A batch transform stops by itself once it has processed its source, it's only started again when this argument changes. Setting it to `false` stops the transform once it has finished its current checkpoint.",
+			},
+			"wait_for_checkpoint": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether starting the transform waits, up to the create or update timeout, for it to complete a checkpoint, so that its destination index is populated before the resources depending on the transform, e.g. aliases or dashboards, are created. A failed transform fails the apply.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the transform, e.g. `started`, `indexing`, `stopped` or `failed`.",
+			},
+			"checkpoint": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The last checkpoint completed by the transform, 0 if it hasn't completed any.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Manages a transform, pivoting or taking the latest documents of source indices into a destination index, and whether it's started.",
+	}
+}
+
+// customizeDiffXpackTransform replaces the transform when its function
+// changes, which the update API rejects.
+func customizeDiffXpackTransform(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("body") || !d.NewValueKnown("body") {
+		return nil
+	}
+	old, new := d.GetChange("body")
+	var oldBody, newBody map[string]interface{}
+	if json.Unmarshal([]byte(old.(string)), &oldBody) != nil || json.Unmarshal([]byte(new.(string)), &newBody) != nil {
+		return nil
+	}
+	for _, key := range transformFunctions {
+		if !reflect.DeepEqual(oldBody[key], newBody[key]) {
+			return d.ForceNew("body")
+		}
+	}
+	return nil
+}
+
+func resourceElasticsearchXpackTransformCreate(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+	path, err := transformPath(name, "")
+	if err != nil {
+		return err
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("body").(string)), &body); err != nil {
+		return fmt.Errorf("error unmarshalling transform body: %+v", err)
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+	if _, err := performRequest(ctx, esClient, "PUT", path, nil, body); err != nil {
+		return err
+	}
+	d.SetId(name)
+
+	if d.Get("start").(bool) {
+		if err := startTransform(ctx, esClient, name, d.Get("wait_for_checkpoint").(bool), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchXpackTransformRead(d, meta)
+}
+
+func resourceElasticsearchXpackTransformRead(d *schema.ResourceData, meta interface{}) error {
+	id := d.Id()
+	path, err := transformPath(id, "")
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if elastic7.IsNotFound(err) {
+		log.Printf("[WARN] Transform (%s) not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Transforms []map[string]interface{} `json:"transforms"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return fmt.Errorf("error unmarshalling transform: %+v: %s", err, res)
+	}
+	if len(response.Transforms) == 0 {
+		log.Printf("[WARN] Transform (%s) not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+	transform := response.Transforms[0]
+	normalizeTransform(transform)
+	body, err := json.Marshal(transform)
+	if err != nil {
+		return err
+	}
+
+	stats, err := getTransformStats(ctx, esClient, id)
+	if err != nil {
+		return err
+	}
+
+	ds := &resourceDataSetter{d: d}
+	ds.set("name", id)
+	ds.set("body", string(body))
+	ds.set("state", stats.State)
+	ds.set("checkpoint", stats.Checkpointing.Last.Checkpoint)
+	return ds.err
+}
+
+func resourceElasticsearchXpackTransformUpdate(d *schema.ResourceData, meta interface{}) error {
+	name := d.Id()
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	ctx := providerContext(meta)
+
+	if d.HasChange("body") {
+		path, err := transformPath(name, "_update")
+		if err != nil {
+			return err
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(d.Get("body").(string)), &body); err != nil {
+			return fmt.Errorf("error unmarshalling transform body: %+v", err)
+		}
+		deleteKeys(body, transformFunctions...)
+		if _, err := performRequest(ctx, esClient, "POST", path, nil, body); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("start") {
+		if d.Get("start").(bool) {
+			err = startTransform(ctx, esClient, name, d.Get("wait_for_checkpoint").(bool), d.Timeout(schema.TimeoutUpdate))
+		} else {
+			err = stopTransform(ctx, esClient, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return resourceElasticsearchXpackTransformRead(d, meta)
+}
+
+func resourceElasticsearchXpackTransformDelete(d *schema.ResourceData, meta interface{}) error {
+	path, err := transformPath(d.Id(), "")
+	if err != nil {
+		return err
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	// force stops the transform first
+	params := url.Values{
+		"force": []string{"true"},
+	}
+	err = retryOnTransientErrors(meta, func() error {
+		_, err := performRequest(providerContext(meta), esClient, "DELETE", path, params, nil)
+		return err
+	})
+	if err != nil && !elastic7.IsNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// startTransform starts a transform, then, if wait is set, waits for it to
+// complete a checkpoint after those it had already completed.
+func startTransform(ctx context.Context, esClient interface{}, name string, wait bool, timeout time.Duration) error {
+	stats, err := getTransformStats(ctx, esClient, name)
+	if err != nil {
+		return err
+	}
+	previous := stats.Checkpointing.Last.Checkpoint
+
+	path, err := transformPath(name, "_start")
+	if err != nil {
+		return err
+	}
+	if _, err := performRequest(ctx, esClient, "POST", path, nil, nil); err != nil {
+		return err
+	}
+	if !wait {
+		return nil
+	}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		stats, err := getTransformStats(ctx, esClient, name)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if stats.State == "failed" {
+			return resource.NonRetryableError(fmt.Errorf("transform %s failed: %s", name, stats.Reason))
+		}
+		if stats.Checkpointing.Last.Checkpoint <= previous {
+			log.Printf("[INFO] Transform %s is %s, waiting for checkpoint %d", name, stats.State, previous+1)
+			return resource.RetryableError(fmt.Errorf("transform %s hasn't completed checkpoint %d, its state is %s", name, previous+1, stats.State))
+		}
+		return nil
+	})
+}
+
+// stopTransform stops a transform once it has finished its current
+// checkpoint, waiting for it to be stopped.
+func stopTransform(ctx context.Context, esClient interface{}, name string) error {
+	path, err := transformPath(name, "_stop")
+	if err != nil {
+		return err
+	}
+	params := url.Values{
+		"wait_for_checkpoint": []string{"true"},
+		"wait_for_completion": []string{"true"},
+	}
+	_, err = performRequest(ctx, esClient, "POST", path, params, nil)
+	return err
+}
+
+func getTransformStats(ctx context.Context, esClient interface{}, name string) (transformStats, error) {
+	path, err := transformPath(name, "_stats")
+	if err != nil {
+		return transformStats{}, err
+	}
+	res, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+	if err != nil {
+		return transformStats{}, err
+	}
+	var response transformStatsResponse
+	if err := json.Unmarshal(res, &response); err != nil {
+		return transformStats{}, fmt.Errorf("error unmarshalling transform stats: %+v: %s", err, res)
+	}
+	if len(response.Transforms) == 0 {
+		return transformStats{}, fmt.Errorf("no stats for transform %s", name)
+	}
+	return response.Transforms[0], nil
+}
+
+// transformPath returns the path of a transform, or of one of its APIs if
+// api isn't empty.
+func transformPath(name, api string) (string, error) {
+	template := "/_transform/{name}"
+	if api != "" {
+		template += "/" + api
+	}
+	path, err := uritemplates.Expand(template, map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error building URL path for transform: %+v", err)
+	}
+	return path, nil
+}
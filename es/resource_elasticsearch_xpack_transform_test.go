@@ -0,0 +1,199 @@
+package es
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchXpackTransform(t *testing.T) {
+	var stored map[string]interface{}
+	state, checkpoint, polls := "stopped", 0, 0
+	var updates []map[string]interface{}
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case r.URL.Path == "/_transform/ecommerce" && r.Method == "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&stored); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_transform/ecommerce":
+			transform := map[string]interface{}{"id": "ecommerce", "version": "7.10.2", "create_time": 1, "settings": map[string]interface{}{}}
+			for k, v := range stored {
+				transform[k] = v
+			}
+			b, _ := json.Marshal(map[string]interface{}{"count": 1, "transforms": []interface{}{transform}})
+			_, _ = w.Write(b)
+		case r.URL.Path == "/_transform/ecommerce/_update":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			updates = append(updates, body)
+			for k, v := range body {
+				stored[k] = v
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/_transform/ecommerce/_start":
+			state = "indexing"
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_transform/ecommerce/_stop":
+			if r.URL.Query().Get("wait_for_completion") != "true" {
+				t.Errorf("expected the stop to be waited for, got %s", r.URL)
+			}
+			state = "stopped"
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case r.URL.Path == "/_transform/ecommerce/_stats":
+			// the first checkpoint completes after a few polls
+			if state == "indexing" {
+				if polls++; polls == 3 {
+					state, checkpoint = "started", checkpoint+1
+				}
+			}
+			b, _ := json.Marshal(map[string]interface{}{"transforms": []interface{}{map[string]interface{}{
+				"state":         state,
+				"checkpointing": map[string]interface{}{"last": map[string]interface{}{"checkpoint": checkpoint}},
+			}}})
+			_, _ = w.Write(b)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+
+	body := `{
+		"source": {"index": "ecommerce"},
+		"dest": {"index": "ecommerce-customers"},
+		"pivot": {
+			"group_by": {"customer_id": {"terms": {"field": "customer_id"}}},
+			"aggregations": {"orders": {"value_count": {"field": "order_id"}}}
+		}
+	}`
+	r := resourceElasticsearchXpackTransform()
+	diff, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                "ecommerce",
+		"body":                body,
+		"start":               true,
+		"wait_for_checkpoint": true,
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	s, err := r.Apply(nil, diff, conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.Attributes["checkpoint"] != "1" || s.Attributes["state"] != "started" {
+		t.Errorf("expected the apply to wait for the first checkpoint, got %v", s.Attributes)
+	}
+	if !equivalentJson(s.Attributes["body"], body) {
+		t.Errorf("expected the server managed keys to be stripped, got %s", s.Attributes["body"])
+	}
+
+	// the function can't be updated
+	diff, err = r.Diff(s, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":  "ecommerce",
+		"body":  strings.Replace(body, "customer_id", "customer", 2),
+		"start": true,
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Errorf("expected a change of the pivot to replace the transform")
+	}
+
+	diff, err = r.Diff(s, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":  "ecommerce",
+		"body":  strings.Replace(body, `"source"`, `"description": "Orders by customer", "source"`, 1),
+		"start": false,
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("expected the description to be updated in place")
+	}
+	if s, err = r.Apply(s, diff, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(updates) != 1 || updates[0]["description"] != "Orders by customer" || updates[0]["pivot"] != nil {
+		t.Errorf("expected the update to leave out the pivot, got %v", updates)
+	}
+	if s.Attributes["state"] != "stopped" {
+		t.Errorf("expected the transform to be stopped, got %v", s.Attributes)
+	}
+}
+
+func TestResourceElasticsearchXpackTransformFailed(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_transform/ecommerce/_stats":
+			_, _ = w.Write([]byte(`{"transforms": [{"state": "failed", "reason": "no such index [ecommerce]", "checkpointing": {"last": {}}}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		}
+	})
+
+	r := resourceElasticsearchXpackTransform()
+	diff, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                "ecommerce",
+		"body":                `{"source": {"index": "ecommerce"}, "dest": {"index": "latest"}, "latest": {"unique_key": ["id"], "sort": "@timestamp"}}`,
+		"start":               true,
+		"wait_for_checkpoint": true,
+	}), conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, err = r.Apply(nil, diff, conf)
+	if err == nil || !strings.Contains(err.Error(), "transform ecommerce failed: no such index [ecommerce]") {
+		t.Errorf("expected the failure of the transform to fail the apply, got %v", err)
+	}
+}
+
+func TestAccElasticsearchXpackTransform(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchXpackTransform,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_transform.test", "checkpoint", "1"),
+				),
+			},
+		},
+	})
+}
+
+var testAccElasticsearchXpackTransform = `
+resource "elasticsearch_index" "test" {
+  name               = "terraform-test-transform-source"
+  number_of_shards   = 1
+  number_of_replicas = 0
+  mappings           = jsonencode({ properties = { customer = { type = "keyword" } } })
+}
+
+resource "elasticsearch_xpack_transform" "test" {
+  name = "terraform-test-transform"
+  body = jsonencode({
+    source = { index = elasticsearch_index.test.name }
+    dest   = { index = "terraform-test-transform-dest" }
+    pivot = {
+      group_by     = { customer = { terms = { field = "customer" } } }
+      aggregations = { count = { value_count = { field = "customer" } } }
+    }
+  })
+  start               = true
+  wait_for_checkpoint = true
+}
+`
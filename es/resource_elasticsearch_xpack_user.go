@@ -6,20 +6,30 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	elastic7 "github.com/olivere/elastic/v7"
 	elastic5 "gopkg.in/olivere/elastic.v5"
 	elastic6 "gopkg.in/olivere/elastic.v6"
 )
 
+// importedUserCredentials is recorded as the password and password_hash of
+// imported users, which can't be read back, so that the first apply after an
+// import records the configured credentials without changing them.
+const importedUserCredentials = "imported"
+
 func resourceElasticsearchXpackUser() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides an Elasticsearch XPack user resource. See the upstream [docs](https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api.html) for more details.",
-		Create:      resourceElasticsearchXpackUserCreate,
-		Read:        resourceElasticsearchXpackUserRead,
-		Update:      resourceElasticsearchXpackUserUpdate,
-		Delete:      resourceElasticsearchXpackUserDelete,
+		Description:   "Provides an Elasticsearch XPack user resource. See the upstream [docs](https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api.html) for more details.",
+		Create:        resourceElasticsearchXpackUserCreate,
+		Read:          resourceElasticsearchXpackUserRead,
+		Update:        resourceElasticsearchXpackUserUpdate,
+		Delete:        resourceElasticsearchXpackUserDelete,
+		CustomizeDiff: customizeDiffCheckUserRoles,
 
 		Schema: map[string]*schema.Schema{
 			"username": {
@@ -52,7 +62,7 @@ func resourceElasticsearchXpackUser() *schema.Resource {
 				Required:    false,
 				Optional:    true,
 				StateFunc:   hashSum,
-				Description: "The user’s password. Passwords must be at least 6 characters long. Mutually exclusive with `password_hash`, one of which must be provided at creation.",
+				Description: "The user’s password. Passwords must be at least 6 characters long. Mutually exclusive with `password_hash`, one of which must be provided at creation. The first apply after an import records it without changing the password of the user.",
 			},
 			"password_hash": {
 				Type:        schema.TypeString,
@@ -60,7 +70,7 @@ func resourceElasticsearchXpackUser() *schema.Resource {
 				Sensitive:   true,
 				Optional:    true,
 				StateFunc:   hashSum,
-				Description: "A hash of the user’s password. This must be produced using the same hashing algorithm as has been configured for password storage. Mutually exclusive with `password`, one of which must be provided at creation.",
+				Description: "A hash of the user’s password. This must be produced using the same hashing algorithm as has been configured for password storage. Mutually exclusive with `password`, one of which must be provided at creation. The first apply after an import records it without changing the password of the user.",
 			},
 			"roles": {
 				Type:     schema.TypeSet,
@@ -78,13 +88,64 @@ func resourceElasticsearchXpackUser() *schema.Resource {
 				DiffSuppressFunc: suppressEquivalentJson,
 				Description:      "Arbitrary metadata that you want to associate with the user",
 			},
+			"validate_roles": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "off",
+				ValidateFunc: validation.StringInSlice([]string{"off", "warn", "error"}, false),
+				Description:  "Check during plan that the `roles` exist, as native or built-in roles, to catch typos like `superusr` granting nothing. One of `off`, `warn`, logging a warning, or `error`, failing the plan. Roles created by the same apply must be referenced from their resource, e.g. `elasticsearch_xpack_role.reader.role_name`, rather than by name, to be skipped. Roles defined in `roles.yml` or by other role providers aren't known to the API, use `warn` if the user has some.",
+			},
 		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceElasticsearchXpackUserImport,
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceElasticsearchXpackUserV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceElasticsearchXpackUserStateUpgradeV0,
+				Version: 0,
+			},
 		},
 	}
 }
 
+func resourceElasticsearchXpackUserV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"username":      {Type: schema.TypeString, Required: true},
+			"fullname":      {Type: schema.TypeString, Optional: true},
+			"email":         {Type: schema.TypeString, Optional: true},
+			"enabled":       {Type: schema.TypeBool, Optional: true},
+			"password":      {Type: schema.TypeString, Optional: true, Sensitive: true},
+			"password_hash": {Type: schema.TypeString, Optional: true, Sensitive: true},
+			"roles":         {Type: schema.TypeSet, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"metadata":      {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceElasticsearchXpackUserStateUpgradeV0 fills in the metadata of users
+// created before it defaulted to an empty object, and normalizes it, so that
+// the upgrade doesn't show a diff.
+func resourceElasticsearchXpackUserStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	metadata, _ := rawState["metadata"].(string)
+	if metadata == "" {
+		metadata = "{}"
+	}
+	normalized, err := structure.NormalizeJsonString(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error upgrading user metadata %q: %+v", metadata, err)
+	}
+	rawState["metadata"] = normalized
+
+	if _, ok := rawState["enabled"].(bool); !ok {
+		rawState["enabled"] = true
+	}
+
+	return rawState, nil
+}
+
 func resourceElasticsearchXpackUserCreate(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("username").(string)
 
@@ -104,19 +165,18 @@ func resourceElasticsearchXpackUserRead(d *schema.ResourceData, m interface{}) e
 
 	user, err := xpackGetUser(d, m, d.Id())
 	if err != nil {
-		fmt.Println("Error during read")
 		if elasticErr, ok := err.(*elastic7.Error); ok && elastic7.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] User %s not found. Removing from state\n", d.Id())
+			log.Printf("[WARN] User %s not found. Removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic6.Error); ok && elastic6.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] User %s not found. Removing from state\n", d.Id())
+			log.Printf("[WARN] User %s not found. Removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic5.Error); ok && elastic5.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] User %s not found. Removing from state\n", d.Id())
+			log.Printf("[WARN] User %s not found. Removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
@@ -133,6 +193,55 @@ func resourceElasticsearchXpackUserRead(d *schema.ResourceData, m interface{}) e
 	return ds.err
 }
 
+func resourceElasticsearchXpackUserImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	ds := &resourceDataSetter{d: d}
+	ds.set("password", importedUserCredentials)
+	ds.set("password_hash", importedUserCredentials)
+	ds.set("validate_roles", "off")
+	return []*schema.ResourceData{d}, ds.err
+}
+
+// customizeDiffCheckUserRoles checks, if enabled, that the roles of the user
+// exist when they change. Roles referenced from the attributes of resources
+// created by the same apply aren't known yet, and aren't checked.
+func customizeDiffCheckUserRoles(d *schema.ResourceDiff, meta interface{}) error {
+	mode := d.Get("validate_roles").(string)
+	if mode == "off" || !d.NewValueKnown("roles") || (d.Id() != "" && !d.HasChange("roles") && !d.HasChange("validate_roles")) {
+		return nil
+	}
+
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		log.Printf("[WARN] Skipping the check of the roles, the cluster can't be reached: %+v", err)
+		return nil
+	}
+	existing, err := objectListers["elasticsearch_xpack_role"].list(providerContext(meta), esClient, "elasticsearch_xpack_role")
+	if err != nil && mode == "warn" {
+		log.Printf("[WARN] Skipping the check of the roles of user %s, they can't be listed: %+v", d.Get("username"), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error checking the roles of user %s: %+v", d.Get("username"), err)
+	}
+
+	var missing []string
+	for _, role := range d.Get("roles").(*schema.Set).List() {
+		if _, ok := existing[role.(string)]; !ok {
+			missing = append(missing, role.(string))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	msg := fmt.Sprintf("the roles %s of user %s don't exist", strings.Join(missing, ", "), d.Get("username"))
+	if mode == "error" {
+		return errors.New(msg)
+	}
+	log.Printf("[WARN] %s", msg)
+	return nil
+}
+
 func resourceElasticsearchXpackUserUpdate(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("username").(string)
 
@@ -151,19 +260,18 @@ func resourceElasticsearchXpackUserDelete(d *schema.ResourceData, m interface{})
 
 	err := xpackDeleteUser(d, m, d.Id())
 	if err != nil {
-		fmt.Println("Error during destroy")
 		if elasticErr, ok := err.(*elastic7.Error); ok && elastic7.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] User %s not found. Resource removed from state\n", d.Id())
+			log.Printf("[WARN] User %s not found. Resource removed from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic6.Error); ok && elastic6.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] User %s not found. Resource removed from state\n", d.Id())
+			log.Printf("[WARN] User %s not found. Resource removed from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		if elasticErr, ok := err.(*elastic5.Error); ok && elastic5.IsNotFound(elasticErr) {
-			fmt.Printf("[WARN] User %s not found. Resource removed from state\n", d.Id())
+			log.Printf("[WARN] User %s not found. Resource removed from state", d.Id())
 			d.SetId("")
 			return nil
 		}
@@ -191,49 +299,59 @@ func buildPutUserBody(d *schema.ResourceData, m interface{}) (string, error) {
 		Metadata: optionalInterfaceJson(metadata),
 	}
 
-	if d.HasChange("password") {
+	if credentialsChanged(d, "password") {
 		user.Password = password
 	}
-	if d.HasChange("password_hash") {
+	if credentialsChanged(d, "password_hash") {
 		user.PasswordHash = passwordHash
 	}
 
 	body, err := json.Marshal(user)
 	if err != nil {
-		fmt.Printf("Body : %s", body)
-		err = fmt.Errorf("Body Error : %s", body)
+		err = fmt.Errorf("error marshalling request body: %+v", err)
 	}
 	log.Printf("[INFO] put body: %+v", user)
 	return string(body[:]), err
 }
 
+// credentialsChanged returns whether the password or password_hash key of
+// the user changed, other than from the value recorded by an import.
+func credentialsChanged(d *schema.ResourceData, key string) bool {
+	old, _ := d.GetChange(key)
+	return d.HasChange(key) && old.(string) != importedUserCredentials
+}
+
 func xpackPutUser(d *schema.ResourceData, m interface{}, name string, body string) error {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7PutUser(client, name, body)
-	case *elastic6.Client:
-		return elastic6PutUser(client, name, body)
-	case *elastic5.Client:
-		return elastic5PutUser(client, name, body)
-	default:
-		return errors.New("unhandled client type")
-	}
+	return retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7PutUser(ctx, client, name, body)
+		case *elastic6.Client:
+			return elastic6PutUser(ctx, client, name, body)
+		case *elastic5.Client:
+			return elastic5PutUser(client, name, body)
+		default:
+			return errors.New("unhandled client type")
+		}
+	})
 }
 
 func xpackGetUser(d *schema.ResourceData, m interface{}, name string) (XPackSecurityUser, error) {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return XPackSecurityUser{}, err
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		return elastic7GetUser(client, name)
+		return elastic7GetUser(ctx, client, name)
 	case *elastic6.Client:
-		return elastic6GetUser(client, name)
+		return elastic6GetUser(ctx, client, name)
 	case *elastic5.Client:
 		return elastic5GetUser(client, name)
 	default:
@@ -242,34 +360,37 @@ func xpackGetUser(d *schema.ResourceData, m interface{}, name string) (XPackSecu
 }
 
 func xpackDeleteUser(d *schema.ResourceData, m interface{}, name string) error {
+	ctx := providerContext(m)
 	esClient, err := getClient(m.(*ProviderConf))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		return elastic7DeleteUser(client, name)
-	case *elastic6.Client:
-		return elastic6DeleteUser(client, name)
-	case *elastic5.Client:
-		return elastic5DeleteUser(client, name)
-	default:
-		return errors.New("unhandled client type")
-	}
+	return retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			return elastic7DeleteUser(ctx, client, name)
+		case *elastic6.Client:
+			return elastic6DeleteUser(ctx, client, name)
+		case *elastic5.Client:
+			return elastic5DeleteUser(client, name)
+		default:
+			return errors.New("unhandled client type")
+		}
+	})
 }
 
 func elastic5PutUser(client *elastic5.Client, name string, body string) error {
 	return errors.New("unsupported in elasticv5 client")
 }
 
-func elastic6PutUser(client *elastic6.Client, name string, body string) error {
-	_, err := client.XPackSecurityPutUser(name).Body(body).Do(context.Background())
+func elastic6PutUser(ctx context.Context, client *elastic6.Client, name string, body string) error {
+	_, err := client.XPackSecurityPutUser(name).Body(body).Do(ctx)
 	log.Printf("[INFO] put error: %+v", err)
 	return err
 }
 
-func elastic7PutUser(client *elastic7.Client, name string, body string) error {
-	_, err := client.XPackSecurityPutUser(name).Body(body).Do(context.Background())
+func elastic7PutUser(ctx context.Context, client *elastic7.Client, name string, body string) error {
+	_, err := client.XPackSecurityPutUser(name).Body(body).Do(ctx)
 	log.Printf("[INFO] put error: %+v", err)
 	return err
 }
@@ -279,8 +400,8 @@ func elastic5GetUser(client *elastic5.Client, name string) (XPackSecurityUser, e
 	return XPackSecurityUser{}, err
 }
 
-func elastic6GetUser(client *elastic6.Client, name string) (XPackSecurityUser, error) {
-	res, err := client.XPackSecurityGetUser(name).Do(context.Background())
+func elastic6GetUser(ctx context.Context, client *elastic6.Client, name string) (XPackSecurityUser, error) {
+	res, err := client.XPackSecurityGetUser(name).Do(ctx)
 	if err != nil {
 		return XPackSecurityUser{}, err
 	}
@@ -299,8 +420,8 @@ func elastic6GetUser(client *elastic6.Client, name string) (XPackSecurityUser, e
 	return user, err
 }
 
-func elastic7GetUser(client *elastic7.Client, name string) (XPackSecurityUser, error) {
-	res, err := client.XPackSecurityGetUser(name).Do(context.Background())
+func elastic7GetUser(ctx context.Context, client *elastic7.Client, name string) (XPackSecurityUser, error) {
+	res, err := client.XPackSecurityGetUser(name).Do(ctx)
 	if err != nil {
 		return XPackSecurityUser{}, err
 	}
@@ -324,13 +445,13 @@ func elastic5DeleteUser(client *elastic5.Client, name string) error {
 	return err
 }
 
-func elastic6DeleteUser(client *elastic6.Client, name string) error {
-	_, err := client.XPackSecurityDeleteUser(name).Do(context.Background())
+func elastic6DeleteUser(ctx context.Context, client *elastic6.Client, name string) error {
+	_, err := client.XPackSecurityDeleteUser(name).Do(ctx)
 	return err
 }
 
-func elastic7DeleteUser(client *elastic7.Client, name string) error {
-	_, err := client.XPackSecurityDeleteUser(name).Do(context.Background())
+func elastic7DeleteUser(ctx context.Context, client *elastic7.Client, name string) error {
+	_, err := client.XPackSecurityDeleteUser(name).Do(ctx)
 	return err
 }
 
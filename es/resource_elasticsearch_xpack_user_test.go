@@ -2,7 +2,10 @@ package es
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	elastic7 "github.com/olivere/elastic/v7"
@@ -299,8 +302,167 @@ func TestAccUserResource_importBasic(t *testing.T) {
 				ResourceName:            "elasticsearch_xpack_user.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"password"}, // because ES doesn't return this field
+				ImportStateVerifyIgnore: []string{"password", "password_hash"}, // because ES doesn't return these fields
 			},
 		},
 	})
 }
+
+func TestResourceElasticsearchXpackUserStateUpgradeV0(t *testing.T) {
+	tests := []struct {
+		metadata interface{}
+		expected string
+	}{
+		{nil, "{}"},
+		{"", "{}"},
+		{`{ "team": "search" }`, `{"team":"search"}`},
+	}
+
+	for _, tt := range tests {
+		v0 := map[string]interface{}{
+			"username": "johndoe",
+			"metadata": tt.metadata,
+			"enabled":  false,
+		}
+
+		actual, err := resourceElasticsearchXpackUserStateUpgradeV0(v0, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if actual["metadata"] != tt.expected {
+			t.Errorf("expected metadata %s, got %v", tt.expected, actual["metadata"])
+		}
+		if actual["enabled"] != false {
+			t.Errorf("expected enabled to be kept, got %v", actual["enabled"])
+		}
+	}
+
+	if _, err := resourceElasticsearchXpackUserStateUpgradeV0(map[string]interface{}{"metadata": "{"}, nil); err == nil {
+		t.Error("expected an error for invalid metadata")
+	}
+}
+
+func TestBuildPutUserBodyAfterImport(t *testing.T) {
+	r := resourceElasticsearchXpackUser()
+	tests := []struct {
+		old      string
+		expected string
+	}{
+		{importedUserCredentials, ""},
+		{hashSum("old-password"), "new-password"},
+	}
+
+	for _, tt := range tests {
+		state := &terraform.InstanceState{
+			ID: "johndoe",
+			Attributes: map[string]string{
+				"username": "johndoe",
+				"password": tt.old,
+				"roles.#":  "0",
+				"metadata": "{}",
+				"enabled":  "true",
+			},
+		}
+		diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"username": "johndoe",
+			"password": "new-password",
+			"roles":    []interface{}{},
+		}), nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		d, err := schema.InternalMap(r.Schema).Data(state, diff)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		body, err := buildPutUserBody(d, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var user XPackSecurityUser
+		if err := json.Unmarshal([]byte(body), &user); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if user.Password != tt.expected {
+			t.Errorf("expected the password %q after %q, got %q", tt.expected, tt.old, user.Password)
+		}
+	}
+}
+
+func TestResourceElasticsearchXpackUserValidateRoles(t *testing.T) {
+	requests := 0
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_security/role":
+			requests++
+			_, _ = w.Write([]byte(`{
+				"superuser": {"cluster": ["all"], "metadata": {"_reserved": true}},
+				"logs_reader": {"cluster": [], "indices": [{"names": ["logs-*"], "privileges": ["read"]}]}
+			}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackUser()
+	diff := func(mode string, roles ...interface{}) error {
+		_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"username":       "jdoe",
+			"password":       "secret",
+			"roles":          roles,
+			"validate_roles": mode,
+		}), conf)
+		return err
+	}
+
+	if err := diff("error", "superuser", "logs_reader"); err != nil {
+		t.Errorf("expected the roles to exist, got %s", err)
+	}
+	if err := diff("warn", "superusr"); err != nil {
+		t.Errorf("expected only a warning, got %s", err)
+	}
+	err := diff("error", "superusr", "logs_reader", "logs_writer")
+	if err == nil || !strings.Contains(err.Error(), "the roles logs_writer, superusr of user jdoe don't exist") {
+		t.Errorf("expected the missing roles to fail the plan, got %v", err)
+	}
+
+	requests = 0
+	if err := diff("off", "superusr"); err != nil || requests != 0 {
+		t.Errorf("expected no check, got %v after %d requests", err, requests)
+	}
+
+}
+
+func TestResourceElasticsearchXpackUserValidateRolesOffline(t *testing.T) {
+	raw := map[string]interface{}{
+		// nothing listens on the discard port
+		"url":                   "http://127.0.0.1:9",
+		"skip_version_ping":     true,
+		"elasticsearch_version": "7.10.2",
+	}
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := resourceElasticsearchXpackUser()
+	diff := func(mode string) error {
+		_, err := r.Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"username":       "jdoe",
+			"password":       "secret",
+			"roles":          []interface{}{"superuser"},
+			"validate_roles": mode,
+		}), meta)
+		return err
+	}
+	if err := diff("warn"); err != nil {
+		t.Errorf("expected only a warning when the roles can't be listed, got %s", err)
+	}
+	if err := diff("error"); err == nil {
+		t.Errorf("expected the plan to fail when the roles can't be listed")
+	}
+}
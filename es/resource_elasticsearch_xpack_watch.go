@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
@@ -25,11 +27,12 @@ var xPackWatchSchema = map[string]*schema.Schema{
 		Type:             schema.TypeString,
 		Required:         true,
 		ValidateFunc:     validation.StringIsJSON,
-		DiffSuppressFunc: suppressEquivalentJson,
+		DiffSuppressFunc: diffSuppressWatch,
 		StateFunc: func(v interface{}) string {
 			json, _ := structure.NormalizeJsonString(v)
 			return json
 		},
+		Description: "The JSON body of the watch. Secrets of the actions, like the basic auth passwords of webhooks, which the API returns redacted, are kept as configured.",
 	},
 	"active": {
 		Type:        schema.TypeBool,
@@ -38,32 +41,76 @@ var xPackWatchSchema = map[string]*schema.Schema{
 		Default:     true,
 		Description: "Boolean to activate the xpack watcher, defaults `true`",
 	},
+	"request_timeout": requestTimeoutSchema(),
+}
+
+var xPackWatchStateUpgraders = []schema.StateUpgrader{
+	{
+		Type:    resourceElasticsearchWatchV0().CoreConfigSchema().ImpliedType(),
+		Upgrade: resourceElasticsearchWatchStateUpgradeV0,
+		Version: 0,
+	},
+}
+
+func resourceElasticsearchWatchV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"watch_id": {Type: schema.TypeString, Required: true},
+			"body":     {Type: schema.TypeString, Required: true},
+			"active":   {Type: schema.TypeBool, Optional: true},
+		},
+	}
+}
+
+// resourceElasticsearchWatchStateUpgradeV0 normalizes bodies stored as
+// returned by the API and activates watches created before `active` existed,
+// which were always active.
+func resourceElasticsearchWatchStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if body, ok := rawState["body"].(string); ok && body != "" {
+		normalized, err := structure.NormalizeJsonString(body)
+		if err != nil {
+			return nil, fmt.Errorf("error upgrading watch body: %+v", err)
+		}
+		rawState["body"] = normalized
+	}
+
+	if _, ok := rawState["active"].(bool); !ok {
+		rawState["active"] = true
+	}
+
+	return rawState, nil
 }
 
 func resourceElasticsearchDeprecatedWatch() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchWatchCreate,
-		Read:   resourceElasticsearchWatchRead,
-		Update: resourceElasticsearchWatchUpdate,
-		Delete: resourceElasticsearchWatchDelete,
-		Schema: xPackWatchSchema,
+		Create:        resourceElasticsearchWatchCreate,
+		Read:          resourceElasticsearchWatchRead,
+		Update:        resourceElasticsearchWatchUpdate,
+		Delete:        resourceElasticsearchWatchDelete,
+		CustomizeDiff: customizeDiffValidateBody("body", watchBodySchema),
+		Schema:        xPackWatchSchema,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		SchemaVersion:      1,
+		StateUpgraders:     xPackWatchStateUpgraders,
 		DeprecationMessage: "elasticsearch_watch is deprecated, please use elasticsearch_xpack_watch resource instead.",
 	}
 }
 
 func resourceElasticsearchXpackWatch() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceElasticsearchWatchCreate,
-		Read:   resourceElasticsearchWatchRead,
-		Update: resourceElasticsearchWatchUpdate,
-		Delete: resourceElasticsearchWatchDelete,
-		Schema: xPackWatchSchema,
+		Create:        resourceElasticsearchWatchCreate,
+		Read:          resourceElasticsearchWatchRead,
+		Update:        resourceElasticsearchWatchUpdate,
+		Delete:        resourceElasticsearchWatchDelete,
+		CustomizeDiff: customizeDiffValidateBody("body", watchBodySchema),
+		Schema:        xPackWatchSchema,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		SchemaVersion:  1,
+		StateUpgraders: xPackWatchStateUpgraders,
 	}
 }
 
@@ -71,7 +118,7 @@ func resourceElasticsearchWatchCreate(d *schema.ResourceData, m interface{}) err
 	// Determine whether the watch already exists, otherwise the API will
 	// override an existing watch with the name.
 	watchID := d.Get("watch_id").(string)
-	_, err := resourceElasticsearchGetWatch(watchID, m)
+	_, err := resourceElasticsearchGetWatch(watchID, resourceConf(d, m))
 
 	if err == nil {
 		log.Printf("[INFO] watch exists: %+v", err)
@@ -94,7 +141,7 @@ func resourceElasticsearchWatchCreate(d *schema.ResourceData, m interface{}) err
 }
 
 func resourceElasticsearchWatchRead(d *schema.ResourceData, m interface{}) error {
-	res, err := resourceElasticsearchGetWatch(d.Id(), m)
+	res, err := resourceElasticsearchGetWatch(d.Id(), resourceConf(d, m))
 
 	if elastic6.IsNotFound(err) || elastic7.IsNotFound(err) {
 		log.Printf("[WARN] Watch (%s) not found, removing from state", d.Id())
@@ -109,7 +156,7 @@ func resourceElasticsearchWatchRead(d *schema.ResourceData, m interface{}) error
 	var watch []byte
 	status := false
 
-	esClient, err := getClient(m.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, m))
 	if err != nil {
 		return err
 	}
@@ -124,18 +171,93 @@ func resourceElasticsearchWatchRead(d *schema.ResourceData, m interface{}) error
 		status = watchResponse.Status.State.Active
 	}
 
+	if err != nil {
+		return err
+	}
+	body, err := unredactWatch(string(watch), d.Get("body").(string))
 	if err != nil {
 		return err
 	}
 
 	ds := &resourceDataSetter{d: d}
-	ds.set("body", string(watch))
+	ds.set("body", body)
 	ds.set("watch_id", d.Id())
 	ds.set("active", status)
 
 	return ds.err
 }
 
+// watchRedacted replaces the secrets of watches returned by the API, e.g. the
+// passwords of webhook actions.
+const watchRedacted = "::es_redacted::"
+
+// diffSuppressWatch compares watch bodies, a redacted value in the state
+// being equal to any configured value: after an import, the secrets aren't
+// known until they change in the configuration.
+func diffSuppressWatch(k, old, new string, d *schema.ResourceData) bool {
+	var oo, no interface{}
+	if err := json.Unmarshal([]byte(old), &oo); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &no); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(mergeRedacted(oo, no), no)
+}
+
+// unredactWatch restores in the watch body read from the API the redacted
+// values from the previous body, so that the state keeps the configured
+// secrets and a change of them shows in the plan.
+func unredactWatch(body, previous string) (string, error) {
+	if previous == "" || !strings.Contains(body, watchRedacted) {
+		return body, nil
+	}
+	var b, p interface{}
+	if err := json.Unmarshal([]byte(body), &b); err != nil {
+		return "", fmt.Errorf("error unmarshalling watch body: %+v", err)
+	}
+	if err := json.Unmarshal([]byte(previous), &p); err != nil {
+		// the previous body may not be valid JSON before the first apply
+		return body, nil
+	}
+	merged, err := json.Marshal(mergeRedacted(b, p))
+	if err != nil {
+		return "", err
+	}
+	return string(merged), nil
+}
+
+// mergeRedacted returns v with its redacted values replaced by the values at
+// the same path of other, if any.
+func mergeRedacted(v, other interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if o, ok := other.(string); ok && v == watchRedacted {
+			return o
+		}
+	case map[string]interface{}:
+		o, _ := other.(map[string]interface{})
+		merged := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			merged[key] = mergeRedacted(value, o[key])
+		}
+		return merged
+	case []interface{}:
+		o, _ := other.([]interface{})
+		merged := make([]interface{}, len(v))
+		for i, value := range v {
+			var ov interface{}
+			if i < len(o) {
+				ov = o[i]
+			}
+			merged[i] = mergeRedacted(value, ov)
+		}
+		return merged
+	}
+	return v
+}
+
 func resourceElasticsearchWatchUpdate(d *schema.ResourceData, m interface{}) error {
 	_, err := resourceElasticsearchPutWatch(d, m)
 
@@ -147,24 +269,30 @@ func resourceElasticsearchWatchUpdate(d *schema.ResourceData, m interface{}) err
 }
 
 func resourceElasticsearchWatchDelete(d *schema.ResourceData, m interface{}) error {
+	ctx := providerContext(m)
 	var err error
-	esClient, err := getClient(m.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, m))
 	if err != nil {
 		return err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		_, err = client.XPackWatchDelete(d.Id()).Do(context.TODO())
-	case *elastic6.Client:
-		_, err = client.XPackWatchDelete(d.Id()).Do(context.TODO())
-	default:
-		err = errors.New("watch resource not implemented prior to Elastic v6")
-	}
+	err = retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			_, err := client.XPackWatchDelete(d.Id()).Do(ctx)
+			return err
+		case *elastic6.Client:
+			_, err := client.XPackWatchDelete(d.Id()).Do(ctx)
+			return err
+		default:
+			return errors.New("watch resource not implemented prior to Elastic v6")
+		}
+	})
 
 	return err
 }
 
 func resourceElasticsearchGetWatch(watchID string, m interface{}) (interface{}, error) {
+	ctx := providerContext(m)
 	var res interface{}
 	var err error
 	esClient, err := getClient(m.(*ProviderConf))
@@ -173,9 +301,9 @@ func resourceElasticsearchGetWatch(watchID string, m interface{}) (interface{},
 	}
 	switch client := esClient.(type) {
 	case *elastic7.Client:
-		res, err = client.XPackWatchGet(watchID).Do(context.TODO())
+		res, err = client.XPackWatchGet(watchID).Do(ctx)
 	case *elastic6.Client:
-		res, err = client.XPackWatchGet(watchID).Do(context.TODO())
+		res, err = client.XPackWatchGet(watchID).Do(ctx)
 	default:
 		err = errors.New("watch resource not implemented prior to Elastic v6")
 	}
@@ -184,33 +312,38 @@ func resourceElasticsearchGetWatch(watchID string, m interface{}) (interface{},
 }
 
 func resourceElasticsearchPutWatch(d *schema.ResourceData, m interface{}) (string, error) {
+	ctx := providerContext(m)
 	watchID := d.Get("watch_id").(string)
 	watchJSON := d.Get("body").(string)
 	isActive := d.Get("active").(bool)
 
 	var err error
-	esClient, err := getClient(m.(*ProviderConf))
+	esClient, err := getClient(resourceConf(d, m))
 	if err != nil {
 		return "", err
 	}
-	switch client := esClient.(type) {
-	case *elastic7.Client:
-		_, err = client.XPackWatchPut(watchID).
-			Body(watchJSON).
-			Do(context.TODO())
-	case *elastic6.Client:
-		_, err = client.XPackWatchPut(watchID).
-			Body(watchJSON).
-			Do(context.TODO())
-	default:
-		err = errors.New("watch resource not implemented prior to Elastic v6")
-	}
+	err = retryOnTransientErrors(m, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			_, err := client.XPackWatchPut(watchID).
+				Body(watchJSON).
+				Do(ctx)
+			return err
+		case *elastic6.Client:
+			_, err := client.XPackWatchPut(watchID).
+				Body(watchJSON).
+				Do(ctx)
+			return err
+		default:
+			return errors.New("watch resource not implemented prior to Elastic v6")
+		}
+	})
 
 	if err != nil {
 		return "", err
 	}
 
-	_, err = activateWatcher(esClient, watchID, isActive)
+	_, err = activateWatcher(ctx, esClient, watchID, isActive)
 
 	if err != nil {
 		return "", err
@@ -220,20 +353,20 @@ func resourceElasticsearchPutWatch(d *schema.ResourceData, m interface{}) (strin
 }
 
 // turn on or off the watcher
-func activateWatcher(esClient interface{}, watchID string, isActive bool) (string, error) {
+func activateWatcher(ctx context.Context, esClient interface{}, watchID string, isActive bool) (string, error) {
 	var err error
 	switch client := esClient.(type) {
 	case *elastic7.Client:
 		if isActive {
-			_, err = client.XPackWatchActivate(watchID).Do(context.TODO())
+			_, err = client.XPackWatchActivate(watchID).Do(ctx)
 		} else {
-			_, err = client.XPackWatchDeactivate(watchID).Do(context.TODO())
+			_, err = client.XPackWatchDeactivate(watchID).Do(ctx)
 		}
 	case *elastic6.Client:
 		if isActive {
-			_, err = client.XPackWatchActivate(watchID).Do(context.TODO())
+			_, err = client.XPackWatchActivate(watchID).Do(ctx)
 		} else {
-			_, err = client.XPackWatchDeactivate(watchID).Do(context.TODO())
+			_, err = client.XPackWatchDeactivate(watchID).Do(ctx)
 		}
 	default:
 		err = errors.New("watch resource not implemented prior to Elastic v6")
@@ -3,6 +3,9 @@ package es
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
 	elastic7 "github.com/olivere/elastic/v7"
@@ -50,6 +53,13 @@ func TestAccElasticsearchWatch(t *testing.T) {
 					testCheckElasticsearchWatchDeactivated("elasticsearch_xpack_watch.test_watch"),
 				),
 			},
+			{
+				Config: testAccElasticsearchWatch + testAccElasticsearchWatchDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_xpack_watch.test", "active", "false"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_xpack_watch.test", "body"),
+				),
+			},
 		},
 	})
 }
@@ -198,3 +208,77 @@ resource "elasticsearch_xpack_watch" "test_watch" {
 EOF
 }
 `
+
+func TestResourceElasticsearchWatchStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"watch_id": "my_watch",
+		"body":     `{ "trigger": {"schedule": {"interval": "1m"}} }`,
+	}
+	expected := map[string]interface{}{
+		"watch_id": "my_watch",
+		"body":     `{"trigger":{"schedule":{"interval":"1m"}}}`,
+		"active":   true,
+	}
+
+	actual, err := resourceElasticsearchWatchStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+var testAccElasticsearchWatchDataSource = `
+data "elasticsearch_xpack_watch" "test" {
+  watch_id = elasticsearch_xpack_watch.test_watch.watch_id
+}
+`
+
+func TestResourceElasticsearchWatchRedacted(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_watcher/watch/notify":
+			_, _ = w.Write([]byte(`{
+				"found": true,
+				"_id": "notify",
+				"status": {"state": {"active": true}},
+				"watch": {
+					"trigger": {"schedule": {"interval": "1m"}},
+					"actions": {"hook": {"webhook": {"host": "example.com", "auth": {"basic": {"username": "elastic", "password": "::es_redacted::"}}}}}
+				}
+			}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	configured := `{"trigger":{"schedule":{"interval":"1m"}},"actions":{"hook":{"webhook":{"host":"example.com","auth":{"basic":{"username":"elastic","password":"changeme"}}}}}}`
+	r := resourceElasticsearchXpackWatch()
+	d := r.TestResourceData()
+	d.SetId("notify")
+	if err := d.Set("body", configured); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := resourceElasticsearchWatchRead(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if body := d.Get("body").(string); !strings.Contains(body, `"password":"changeme"`) || strings.Contains(body, watchRedacted) {
+		t.Errorf("expected the configured password to be kept, got %s", d.Get("body"))
+	}
+
+	imported := `{"actions":{"hook":{"webhook":{"auth":{"basic":{"password":"::es_redacted::"}}}}}}`
+	if !diffSuppressWatch("body", imported, `{"actions":{"hook":{"webhook":{"auth":{"basic":{"password":"changeme"}}}}}}`, nil) {
+		t.Error("expected a redacted password to match any configured password")
+	}
+	if diffSuppressWatch("body", `{"actions":{"hook":{"webhook":{"auth":{"basic":{"password":"changeme"}}}}}}`, `{"actions":{"hook":{"webhook":{"auth":{"basic":{"password":"rotated"}}}}}}`, nil) {
+		t.Error("expected a changed password to show in the diff")
+	}
+	if diffSuppressWatch("body", imported, `{"actions":{"hook":{"webhook":{"auth":{"basic":{"password":"changeme"}}},"host":"example.com"}}}`, nil) {
+		t.Error("expected other changes to show in the diff")
+	}
+}
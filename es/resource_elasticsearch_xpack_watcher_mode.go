@@ -0,0 +1,130 @@
+package es
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+func resourceElasticsearchXpackWatcherMode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchXpackWatcherModeUpdate,
+		Read:   resourceElasticsearchXpackWatcherModeRead,
+		Update: resourceElasticsearchXpackWatcherModeUpdate,
+		Delete: resourceElasticsearchXpackWatcherModeDelete,
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the Watcher service is started, or stopped, pausing every watch without deactivating them.",
+			},
+			"watcher_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the Watcher service, `started`, `starting`, `stopping` or `stopped`. When the nodes disagree, the state of the first node that isn't `started` or `stopped` like the others.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Description: "Starts or stops the Watcher service, e.g. to pause all watches during a maintenance window. Destroying the resource starts it again. There is only one per cluster, its ID is `watcher`.",
+	}
+}
+
+func resourceElasticsearchXpackWatcherModeUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := setWatcherMode(meta, d.Get("enabled").(bool)); err != nil {
+		return err
+	}
+	d.SetId("watcher")
+	return resourceElasticsearchXpackWatcherModeRead(d, meta)
+}
+
+func resourceElasticsearchXpackWatcherModeRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := providerContext(meta)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+
+	var states []string
+	switch client := esClient.(type) {
+	case *elastic7.Client:
+		var res *elastic7.XPackWatcherStatsResponse
+		res, err = client.XPackWatchStats().Do(ctx)
+		if err == nil {
+			for _, s := range res.Stats {
+				states = append(states, s.WatcherState)
+			}
+		}
+	case *elastic6.Client:
+		var res *elastic6.XPackWatcherStatsResponse
+		res, err = client.XPackWatchStats().Do(ctx)
+		if err == nil {
+			for _, s := range res.Stats {
+				states = append(states, s.WatcherState)
+			}
+		}
+	default:
+		err = errors.New("watcher mode resource not implemented prior to Elastic v6")
+	}
+	if err != nil {
+		return err
+	}
+
+	state := watcherState(states)
+	ds := &resourceDataSetter{d: d}
+	ds.set("enabled", state == "started" || state == "starting")
+	ds.set("watcher_state", state)
+	return ds.err
+}
+
+func resourceElasticsearchXpackWatcherModeDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := setWatcherMode(meta, true); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func setWatcherMode(meta interface{}, enabled bool) error {
+	ctx := providerContext(meta)
+	esClient, err := getClient(meta.(*ProviderConf))
+	if err != nil {
+		return err
+	}
+	return retryOnTransientErrors(meta, func() error {
+		switch client := esClient.(type) {
+		case *elastic7.Client:
+			if enabled {
+				_, err = client.XPackWatchStart().Do(ctx)
+			} else {
+				_, err = client.XPackWatchStop().Do(ctx)
+			}
+		case *elastic6.Client:
+			if enabled {
+				_, err = client.XPackWatchStart().Do(ctx)
+			} else {
+				_, err = client.XPackWatchStop().Do(ctx)
+			}
+		default:
+			err = errors.New("watcher mode resource not implemented prior to Elastic v6")
+		}
+		return err
+	})
+}
+
+// watcherState returns the state the nodes agree on, or else the first
+// transitional or diverging one.
+func watcherState(states []string) string {
+	if len(states) == 0 {
+		return "stopped"
+	}
+	for _, state := range states {
+		if state != states[0] || (state != "started" && state != "stopped") {
+			return state
+		}
+	}
+	return states[0]
+}
@@ -0,0 +1,111 @@
+package es
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceElasticsearchXpackWatcherModeCreate(t *testing.T) {
+	state := "started"
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_watcher/_stop":
+			state = "stopped"
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_watcher/_start":
+			state = "started"
+			_, _ = w.Write([]byte(`{"acknowledged": true}`))
+		case "/_watcher/stats":
+			_, _ = fmt.Fprintf(w, `{"stats": [{"node_id": "node-1", "watcher_state": %q}, {"node_id": "node-2", "watcher_state": %q}]}`, state, state)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+
+	r := resourceElasticsearchXpackWatcherMode()
+	d := r.TestResourceData()
+	_ = d.Set("enabled", false)
+	if err := r.Create(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "watcher" || d.Get("watcher_state") != "stopped" || d.Get("enabled") != false {
+		t.Errorf("expected watcher to be stopped, got %s %v", d.Id(), d.Get("watcher_state"))
+	}
+
+	if err := r.Delete(d, conf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state != "started" {
+		t.Errorf("expected watcher to be started on destroy")
+	}
+}
+
+func TestWatcherState(t *testing.T) {
+	tests := []struct {
+		states   []string
+		expected string
+	}{
+		{[]string{"started", "started"}, "started"},
+		{[]string{"stopped"}, "stopped"},
+		{[]string{"started", "stopping"}, "stopping"},
+		{[]string{"started", "stopped"}, "stopped"},
+		{nil, "stopped"},
+	}
+	for _, tt := range tests {
+		if actual := watcherState(tt.states); actual != tt.expected {
+			t.Errorf("expected %s for %v, got %s", tt.expected, tt.states, actual)
+		}
+	}
+}
+
+func TestAccElasticsearchXpackWatcherMode(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	err := provider.Configure(&terraform.ResourceConfig{})
+	if err != nil {
+		t.Skipf("err: %s", err)
+	}
+	conf := provider.Meta().(*ProviderConf)
+	if _, err := getClient(conf); err != nil {
+		t.Skipf("err: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if err := capabilityWatcher.check(conf); err != nil {
+				t.Skip(err)
+			}
+		},
+		Providers: testAccXPackProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccElasticsearchXpackWatcherMode(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_watcher_mode.test", "enabled", "false"),
+				),
+			},
+			{
+				Config: testAccElasticsearchXpackWatcherMode(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_xpack_watcher_mode.test", "enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccElasticsearchXpackWatcherMode(enabled bool) string {
+	return fmt.Sprintf(`
+resource "elasticsearch_xpack_watcher_mode" "test" {
+  enabled = %t
+}
+`, enabled)
+}
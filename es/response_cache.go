@@ -0,0 +1,170 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// responseCache holds the responses to the GET requests of data sources, for
+// the lifetime of a provider instance, i.e. a single plan or apply, so that a
+// data source evaluated by many modules reads the cluster once.
+type responseCache struct {
+	mu        sync.Mutex
+	responses map[string]*cachedResponse
+}
+
+// cachedResponse is a response, or the request on its way to get it, which
+// identical requests sent meanwhile wait for.
+type cachedResponse struct {
+	done   chan struct{}
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{responses: map[string]*cachedResponse{}}
+}
+
+type cachedReadsKey struct{}
+
+// withCachedReads returns a context whose GET requests are answered from the
+// response cache, if the provider has one.
+func withCachedReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cachedReadsKey{}, true)
+}
+
+type withResponseCache struct {
+	rt    http.RoundTripper
+	cache *responseCache
+}
+
+// WithResponseCache wraps rt so that the successful GET requests sent with a
+// context from withCachedReads are only sent once, identical requests getting
+// a copy of the first response. Any other write, e.g. of a resource, clears
+// the cache, so that reads never miss the changes of the same run. A nil
+// cache doesn't cache anything.
+func WithResponseCache(rt http.RoundTripper, cache *responseCache) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if cache == nil {
+		return rt
+	}
+
+	return withResponseCache{rt: rt, cache: cache}
+}
+
+func (c withResponseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	cached, _ := req.Context().Value(cachedReadsKey{}).(bool)
+	if !cached {
+		if req.Method != "GET" && req.Method != "HEAD" {
+			c.cache.clear()
+		}
+		return c.rt.RoundTrip(req)
+	}
+	if req.Method != "GET" {
+		return c.rt.RoundTrip(req)
+	}
+
+	key := responseCacheKey(req)
+	c.cache.mu.Lock()
+	entry, ok := c.cache.responses[key]
+	if !ok {
+		entry = &cachedResponse{done: make(chan struct{})}
+		c.cache.responses[key] = entry
+	}
+	c.cache.mu.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if entry.err == nil {
+			log.Printf("[DEBUG] Using the cached response to GET %s", req.URL.Path)
+			return entry.response(req), nil
+		}
+		// the first request failed, try again without the cache
+		return c.rt.RoundTrip(req)
+	}
+
+	res, err := c.rt.RoundTrip(req)
+	if err == nil {
+		entry.body, err = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		entry.status, entry.header = res.StatusCode, res.Header
+	}
+	if err == nil && (res.StatusCode < 200 || res.StatusCode >= 300) {
+		// only successful responses are kept, errors are read again
+		entry.err = errUncachedResponse
+	} else {
+		entry.err = err
+	}
+	if entry.err != nil {
+		c.cache.remove(key, entry)
+	}
+	close(entry.done)
+	if err != nil {
+		return nil, err
+	}
+	return entry.response(req), nil
+}
+
+// errUncachedResponse marks the responses that aren't cached.
+var errUncachedResponse = errors.New("response not cached")
+
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(r.status),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.responses) > 0 {
+		c.responses = map[string]*cachedResponse{}
+	}
+}
+
+func (c *responseCache) remove(key string, entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.responses[key] == entry {
+		delete(c.responses, key)
+	}
+}
+
+// responseCacheKey identifies a request by its URL and headers, e.g. the
+// tenant of the security plugin, which may change the response.
+func responseCacheKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(req.URL.String())
+	for _, name := range names {
+		b.WriteString("\n" + name + ": " + strings.Join(req.Header[name], ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package es
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithResponseCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithResponseCache(nil, newResponseCache())}
+	cached := withCachedReads(context.Background())
+	send := func(ctx context.Context, method, path string) string {
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		res, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return string(body)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if body := send(cached, "GET", "/_cluster/info"); body != "/_cluster/info" {
+				t.Errorf("unexpected response: %s", body)
+			}
+		}()
+	}
+	wg.Wait()
+	send(cached, "GET", "/missing")
+	send(cached, "GET", "/missing")
+	send(context.Background(), "GET", "/_nodes")
+	send(context.Background(), "GET", "/_nodes")
+
+	expected := map[string]int{"GET /_cluster/info": 1, "GET /missing": 2, "GET /_nodes": 2}
+	for request, count := range expected {
+		if requests[request] != count {
+			t.Errorf("expected %s to be sent %d times, got %d", request, count, requests[request])
+		}
+	}
+
+	// writes clear the cache, so that reads see them
+	send(context.Background(), "PUT", "/_ingest/pipeline/test")
+	send(cached, "GET", "/_cluster/info")
+	if requests["GET /_cluster/info"] != 2 {
+		t.Errorf("expected the cache to be cleared by a write, got %d requests", requests["GET /_cluster/info"])
+	}
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://localhost:9200/_plugins/_security/api/roles", nil)
+	key := responseCacheKey(req)
+	req.Header.Set("securitytenant", "analysts")
+	if tenantKey := responseCacheKey(req); tenantKey == key || !strings.Contains(tenantKey, "analysts") {
+		t.Errorf("expected the headers to be part of the key, got %q", tenantKey)
+	}
+}
@@ -0,0 +1,73 @@
+package es
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	elastic7 "github.com/olivere/elastic/v7"
+	elastic5 "gopkg.in/olivere/elastic.v5"
+	elastic6 "gopkg.in/olivere/elastic.v6"
+)
+
+// transientStatusCodes are returned by requests that are likely to succeed
+// when sent again, e.g. on seq_no conflicts while updating the security or
+// ISM indices, or while a proxy fails over. 429 and 503 are already retried
+// for every request by the http client.
+var transientStatusCodes = []int{
+	http.StatusConflict,
+	http.StatusBadGateway,
+	http.StatusGatewayTimeout,
+}
+
+// retryOnTransientErrors runs the idempotent operation f until it succeeds
+// or fails with an error that isn't transient, waiting a jittered
+// exponential backoff between attempts. statusCodes are retried in addition
+// to the transientStatusCodes. It gives up after the provider's max_retries
+// or when the operation's context is done, returning the last error along
+// with the number of attempts.
+func retryOnTransientErrors(meta interface{}, f func() error, statusCodes ...int) error {
+	conf := meta.(*ProviderConf)
+	ctx := providerContext(meta)
+	backoff := conf.retryBackoffMin
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || !isTransientError(err, statusCodes) {
+			return err
+		}
+		if attempt > conf.maxRetries {
+			return fmt.Errorf("giving up after %d attempts: %+v", attempt, err)
+		}
+
+		wait := backoff
+		if wait > 0 {
+			// full jitter, so that concurrent operations don't retry in lockstep
+			wait = time.Duration(rand.Int63n(int64(wait))) + 1
+		}
+		log.Printf("[WARN] Attempt %d failed, retrying in %s: %+v", attempt, wait, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("giving up after %d attempts: %+v", attempt, err)
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > conf.retryBackoffMax {
+			backoff = conf.retryBackoffMax
+		}
+	}
+}
+
+func isTransientError(err error, statusCodes []int) bool {
+	for _, codes := range [][]int{transientStatusCodes, statusCodes} {
+		for _, code := range codes {
+			if elastic7.IsStatusCode(err, code) || elastic6.IsStatusCode(err, code) || elastic5.IsStatusCode(err, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package es
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+func TestRetryOnTransientErrors(t *testing.T) {
+	conf := &ProviderConf{maxRetries: 3, retryBackoffMin: time.Millisecond, retryBackoffMax: time.Millisecond}
+
+	var attempts int
+	err := retryOnTransientErrors(conf, func() error {
+		attempts++
+		if attempts < 3 {
+			return &elastic7.Error{Status: http.StatusConflict}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	err = retryOnTransientErrors(conf, func() error {
+		attempts++
+		return &elastic7.Error{Status: http.StatusInternalServerError}
+	}, http.StatusInternalServerError)
+	if err == nil || !strings.Contains(err.Error(), "after 4 attempts") {
+		t.Errorf("expected to give up after 4 attempts, got %v", err)
+	}
+
+	attempts = 0
+	notFound := &elastic7.Error{Status: http.StatusNotFound}
+	err = retryOnTransientErrors(conf, func() error {
+		attempts++
+		return notFound
+	})
+	if err != notFound || attempts != 1 {
+		t.Errorf("expected other errors to be returned as is, got %v after %d attempts", err, attempts)
+	}
+
+	if err := retryOnTransientErrors(conf, func() error { return errors.New("boom") }); err == nil {
+		t.Errorf("expected an error")
+	}
+}
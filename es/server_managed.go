@@ -0,0 +1,120 @@
+package es
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// The APIs return objects with keys that the cluster populates itself, e.g.
+// ids, timestamps, creation versions and statistics, which are never part of
+// a configuration. They are stripped, using the lists below, both from the
+// bodies stored in the state when reading and from the bodies compared by the
+// diff suppress functions, so that they neither leak into the state nor show
+// up as diffs. The index resource reads an allowlist of settings instead, see
+// settingsKeys.
+var (
+	// serverManagedIndexSettings are set on every index, and end up in
+	// templates when settings are copied from an existing index.
+	serverManagedIndexSettings = []string{
+		"index.uuid",
+		"index.creation_date",
+		"index.creation_date_string",
+		"index.provided_name",
+		"index.version.created",
+		"index.version.created_string",
+		"index.version.upgraded",
+		"index.version.upgraded_string",
+		"index.resize.source.name",
+		"index.resize.source.uuid",
+		"index.routing.allocation.initial_recovery._id",
+	}
+	serverManagedIndexLifecyclePolicyKeys    = []string{"version", "modified_date", "in_use_by"}
+	serverManagedSnapshotLifecyclePolicyKeys = []string{"version", "modified_date", "modified_date_millis", "stats", "next_execution", "next_execution_millis", "last_success", "last_failure", "in_progress"}
+	serverManagedPolicyKeys                  = []string{"last_updated_time", "policy_id", "schema_version"}
+	serverManagedDestinationKeys             = []string{"id", "last_update_time", "schema_version"}
+	serverManagedMonitorKeys                 = []string{"id", "last_update_time", "enabled_time", "schema_version", "user"}
+	serverManagedTransformKeys               = []string{"id", "version", "create_time", "authorization"}
+)
+
+// stripServerManagedSettings removes the server managed index settings,
+// whether they're nested or flat, with or without the index prefix.
+func stripServerManagedSettings(settings map[string]interface{}) {
+	for _, key := range serverManagedIndexSettings {
+		deletePath(settings, key)
+		deletePath(settings, strings.TrimPrefix(key, "index."))
+	}
+}
+
+// deletePath deletes a dotted key from m, whichever of its parts are nested
+// objects, e.g. "index.version.created" from {"index": {"version.created":
+// ...}}.
+func deletePath(m map[string]interface{}, path string) {
+	delete(m, path)
+	for i := strings.Index(path, "."); i >= 0; i = nextDot(path, i) {
+		if nested, ok := m[path[:i]].(map[string]interface{}); ok {
+			deletePath(nested, path[i+1:])
+			if len(nested) == 0 {
+				delete(m, path[:i])
+			}
+		}
+	}
+}
+
+func nextDot(s string, i int) int {
+	j := strings.Index(s[i+1:], ".")
+	if j < 0 {
+		return -1
+	}
+	return i + 1 + j
+}
+
+func stripIndexTemplate(tpl map[string]interface{}) {
+	if settings, ok := tpl["settings"].(map[string]interface{}); ok {
+		stripServerManagedSettings(settings)
+	}
+}
+
+// stripComposableIndexTemplate strips composable index templates and
+// component templates, which both nest their settings in template.
+func stripComposableIndexTemplate(tpl map[string]interface{}) {
+	if innerTpl, ok := tpl["template"].(map[string]interface{}); ok {
+		stripIndexTemplate(innerTpl)
+	}
+}
+
+func stripIndexLifecyclePolicy(pol map[string]interface{}) {
+	deleteKeys(pol, serverManagedIndexLifecyclePolicyKeys...)
+}
+
+// stripPolicy removes the server managed keys of an ISM policy, as returned
+// by the API.
+func stripPolicy(tpl map[string]interface{}) {
+	deleteKeys(tpl, serverManagedPolicyKeys...)
+	if ism_template, ok := tpl["ism_template"]; ok {
+		switch templates := ism_template.(type) {
+		case map[string]interface{}:
+			delete(templates, "last_updated_time")
+		case []interface{}:
+			for _, t := range templates {
+				if template, ok := t.(map[string]interface{}); ok {
+					delete(template, "last_updated_time")
+				}
+			}
+		}
+	}
+}
+
+// strippedJson returns body without the keys removed by strip, or body
+// unchanged if it isn't a JSON object.
+func strippedJson(body string, strip jsonNormalizer) string {
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		return body
+	}
+	strip(m)
+	stripped, err := json.Marshal(m)
+	if err != nil {
+		return body
+	}
+	return string(stripped)
+}
@@ -0,0 +1,51 @@
+package es
+
+import (
+	"testing"
+)
+
+func TestStrippedJson(t *testing.T) {
+	cases := []struct {
+		name     string
+		strip    jsonNormalizer
+		body     string
+		expected string
+	}{
+		{
+			"nested index settings",
+			stripIndexTemplate,
+			`{"index_patterns":["a-*"],"settings":{"index":{"number_of_shards":"1","uuid":"x1","version":{"created":"7100099"},"creation_date":"1600000000000"}}}`,
+			`{"index_patterns":["a-*"],"settings":{"index":{"number_of_shards":"1"}}}`,
+		},
+		{
+			"flat index settings",
+			stripComposableIndexTemplate,
+			`{"template":{"settings":{"index.provided_name":"a-1","index.version.created":"7100099","number_of_replicas":"0"}}}`,
+			`{"template":{"settings":{"number_of_replicas":"0"}}}`,
+		},
+		{
+			"index lifecycle policy",
+			stripIndexLifecyclePolicy,
+			`{"version":3,"modified_date":"2021-01-01T00:00:00.000Z","policy":{"phases":{}}}`,
+			`{"policy":{"phases":{}}}`,
+		},
+		{
+			"ism policy",
+			stripPolicy,
+			`{"policy_id":"p","last_updated_time":1,"schema_version":1,"ism_template":[{"index_patterns":["a-*"],"last_updated_time":1}]}`,
+			`{"ism_template":[{"index_patterns":["a-*"]}]}`,
+		},
+		{
+			"invalid json",
+			stripIndexTemplate,
+			`{"settings":`,
+			`{"settings":`,
+		},
+	}
+
+	for _, c := range cases {
+		if actual := strippedJson(c.body, c.strip); actual != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package es
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/olivere/elastic/uritemplates"
+)
+
+// taskActions are the actions of the tasks started by each API, to find the
+// task of an interrupted apply and resume waiting for it.
+var taskActions = map[string]string{
+	"_reindex":         "indices:data/write/reindex",
+	"_update_by_query": "indices:data/write/update/byquery",
+	"_delete_by_query": "indices:data/write/delete/byquery",
+	"_forcemerge":      "indices:admin/forcemerge",
+}
+
+// taskOpaqueIdPrefix prefixes the X-Opaque-Id header of the tasks started by
+// the provider, followed by a hash of the request.
+const taskOpaqueIdPrefix = "terraform-provider-elasticsearch/"
+
+type taskResponse struct {
+	Completed bool `json:"completed"`
+	Task      struct {
+		RunningTimeInNanos int64      `json:"running_time_in_nanos"`
+		Status             taskStatus `json:"status"`
+	} `json:"task"`
+	Response json.RawMessage `json:"response"`
+	Error    *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// taskStatus is the progress of a _reindex, _update_by_query or
+// _delete_by_query task, empty for other tasks.
+type taskStatus struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+	Batches int `json:"batches"`
+}
+
+// byQueryResponse is the result of _update_by_query, _delete_by_query and
+// _reindex.
+type byQueryResponse struct {
+	Total            int               `json:"total"`
+	Created          int               `json:"created"`
+	Updated          int               `json:"updated"`
+	Deleted          int               `json:"deleted"`
+	VersionConflicts int               `json:"version_conflicts"`
+	Failures         []json.RawMessage `json:"failures"`
+}
+
+// waitForTask polls a task started with wait_for_completion=false, backing
+// off up to 10s between requests and logging its progress, until it
+// completes, returning its response. If the timeout expires the task keeps
+// running, and the next apply resumes waiting for it.
+func waitForTask(ctx context.Context, esClient interface{}, taskId string, timeout time.Duration) (json.RawMessage, error) {
+	path, err := uritemplates.Expand("/_tasks/{task_id}", map[string]string{
+		"task_id": taskId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building URL path for task: %+v", err)
+	}
+
+	var task taskResponse
+	running := false
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		running = false
+		body, err := performRequest(ctx, esClient, "GET", path, nil, nil)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		task = taskResponse{}
+		if err := json.Unmarshal(body, &task); err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error unmarshalling task: %+v: %s", err, body))
+		}
+		if !task.Completed {
+			running = true
+			log.Printf("[INFO] Task %s is still running%s", taskId, taskProgress(task))
+			return resource.RetryableError(fmt.Errorf("task %s is still running", taskId))
+		}
+		return nil
+	})
+	if err != nil && running {
+		return nil, fmt.Errorf("timeout while waiting for task %s%s, which keeps running: the next apply resumes waiting for it", taskId, taskProgress(task))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if task.Error != nil {
+		return nil, fmt.Errorf("task %s failed: %s: %s", taskId, task.Error.Type, task.Error.Reason)
+	}
+	return task.Response, nil
+}
+
+// taskProgress describes the progress of a running task, or is empty if the
+// task doesn't report any.
+func taskProgress(task taskResponse) string {
+	var progress string
+	if task.Task.RunningTimeInNanos > 0 {
+		progress = fmt.Sprintf(" after %s", time.Duration(task.Task.RunningTimeInNanos).Round(time.Second))
+	}
+	status := task.Task.Status
+	if status.Total > 0 {
+		done := status.Created + status.Updated + status.Deleted
+		progress += fmt.Sprintf(", %d of %d documents done in %d batches", done, status.Total, status.Batches)
+	}
+	return progress
+}
+
+// startTask runs the API at path with wait_for_completion=false and returns
+// the ID of the task it started. If a task of a previous, interrupted apply
+// with the same request is still running, it returns its ID instead, so that
+// the caller resumes waiting for it.
+func startTask(ctx context.Context, esClient interface{}, api, path string, params url.Values, body interface{}) (string, error) {
+	params.Set("wait_for_completion", "false")
+
+	request, err := json.Marshal([]interface{}{path, params.Encode(), body})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling %s request: %+v", api, err)
+	}
+	opaqueId := fmt.Sprintf("%s%x", taskOpaqueIdPrefix, sha256.Sum256(request))
+	if taskId, err := findRunningTask(ctx, esClient, api, opaqueId); err != nil {
+		log.Printf("[WARN] Error looking for a running %s task to resume: %+v", api, err)
+	} else if taskId != "" {
+		log.Printf("[INFO] Resuming waiting for the running %s task %s", api, taskId)
+		return taskId, nil
+	}
+
+	res, err := performRequestWithHeaders(ctx, esClient, "POST", path, params, body, http.Header{
+		"X-Opaque-Id": []string{opaqueId},
+	})
+	if err != nil {
+		return "", err
+	}
+	var started struct {
+		Task string `json:"task"`
+	}
+	if err := json.Unmarshal(res, &started); err != nil || started.Task == "" {
+		return "", fmt.Errorf("error unmarshalling %s task: %+v: %s", api, err, res)
+	}
+	return started.Task, nil
+}
+
+// findRunningTask returns the ID of the running task of api started with the
+// X-Opaque-Id opaqueId, or an empty string if there's none.
+func findRunningTask(ctx context.Context, esClient interface{}, api, opaqueId string) (string, error) {
+	action, ok := taskActions[api]
+	if !ok {
+		return "", nil
+	}
+	res, err := performRequest(ctx, esClient, "GET", "/_tasks", url.Values{
+		"actions": []string{action},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Nodes map[string]struct {
+			Tasks map[string]struct {
+				ParentTaskId string            `json:"parent_task_id"`
+				Headers      map[string]string `json:"headers"`
+			} `json:"tasks"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(res, &response); err != nil {
+		return "", fmt.Errorf("error unmarshalling tasks: %+v: %s", err, res)
+	}
+	for _, node := range response.Nodes {
+		for taskId, task := range node.Tasks {
+			// the slices of a task are its children, with the same headers
+			if task.ParentTaskId == "" && task.Headers["X-Opaque-Id"] == opaqueId {
+				return taskId, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// runByQuery runs _update_by_query or _delete_by_query on index. Unless wait
+// is false, it waits for the task to complete and returns its result, failing
+// if any document failed.
+func runByQuery(ctx context.Context, esClient interface{}, api, index string, params url.Values, body interface{}, wait bool, timeout time.Duration) (string, *byQueryResponse, error) {
+	path, err := uritemplates.Expand("/{index}/{api}", map[string]string{
+		"index": index,
+		"api":   api,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("error building URL path for %s: %+v", api, err)
+	}
+
+	taskId, err := startTask(ctx, esClient, api, path, params, body)
+	if err != nil || !wait {
+		return taskId, nil, err
+	}
+
+	res, err := waitForTask(ctx, esClient, taskId, timeout)
+	if err != nil {
+		return taskId, nil, err
+	}
+	var result byQueryResponse
+	if err := json.Unmarshal(res, &result); err != nil {
+		return taskId, nil, fmt.Errorf("error unmarshalling %s result: %+v: %s", api, err, res)
+	}
+	if len(result.Failures) > 0 {
+		return taskId, &result, fmt.Errorf("%s failed on %d documents, e.g. %s", api, len(result.Failures), compactJson(result.Failures[0]))
+	}
+	return taskId, &result, nil
+}
@@ -0,0 +1,85 @@
+package es
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartTaskResume(t *testing.T) {
+	var opaqueId string
+	started := 0
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/logs/_delete_by_query":
+			started++
+			opaqueId = r.Header.Get("X-Opaque-Id")
+			_, _ = w.Write([]byte(`{"task": "node-1:7"}`))
+		case "/_tasks":
+			if actual := r.URL.Query().Get("actions"); actual != "indices:data/write/delete/byquery" {
+				t.Errorf("unexpected actions: %s", actual)
+			}
+			if opaqueId == "" {
+				_, _ = w.Write([]byte(`{"nodes": {}}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"nodes": {"node-1": {"tasks": {
+				"node-1:7": {"action": "indices:data/write/delete/byquery", "headers": {"X-Opaque-Id": %q}},
+				"node-1:8": {"action": "indices:data/write/delete/byquery", "parent_task_id": "node-1:7", "headers": {"X-Opaque-Id": %q}}
+			}}}}`, opaqueId, opaqueId)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+	esClient, err := getClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	body := map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}}
+
+	taskId, err := startTask(providerContext(conf), esClient, "_delete_by_query", "/logs/_delete_by_query", url.Values{}, body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.HasPrefix(opaqueId, taskOpaqueIdPrefix) {
+		t.Errorf("expected the task to be started with an X-Opaque-Id, got %q", opaqueId)
+	}
+
+	resumedTaskId, err := startTask(providerContext(conf), esClient, "_delete_by_query", "/logs/_delete_by_query", url.Values{}, body)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if started != 1 || resumedTaskId != taskId {
+		t.Errorf("expected the running task %s to be resumed, got %s after starting %d tasks", taskId, resumedTaskId, started)
+	}
+}
+
+func TestWaitForTaskTimeout(t *testing.T) {
+	conf := testProviderConf(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version": {"number": "7.10.2"}}`))
+		case "/_tasks/node-1:7":
+			_, _ = w.Write([]byte(`{"completed": false, "task": {"running_time_in_nanos": 125000000000, "status": {"total": 1000, "deleted": 250, "batches": 3}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+	})
+	esClient, err := getClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = waitForTask(providerContext(conf), esClient, "node-1:7", time.Second)
+	expected := "timeout while waiting for task node-1:7 after 2m5s, 250 of 1000 documents done in 3 batches, which keeps running"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,101 @@
+package es
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// traceLogEnv enables the logging of every request and response at TRACE
+// level when set to "trace", independently of TF_LOG for the other providers.
+const traceLogEnv = "TF_LOG_PROVIDER_ELASTICSEARCH"
+
+// traceBodyLimit is the number of bytes of each body that are logged.
+const traceBodyLimit = 4096
+
+// secretJsonValue matches the string values of keys that hold secrets, e.g.
+// passwords of users or credentials of snapshot repositories and watch
+// actions, in JSON or NDJSON bodies, even if they aren't valid.
+var secretJsonValue = regexp.MustCompile(`(?i)("(?:[^"]*(?:password|passwd|secret|api_key|apikey|access_key|private_key|authorization)[^"]*|[^"]*token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+func traceLogging() bool {
+	return strings.EqualFold(os.Getenv(traceLogEnv), "trace")
+}
+
+type withTraceLogging struct {
+	rt http.RoundTripper
+}
+
+// WithTraceLogging wraps rt to log the method, path, status and duration of
+// each request, along with the beginning of both bodies with secrets
+// redacted, if enabled with TF_LOG_PROVIDER_ELASTICSEARCH=trace. Headers
+// aren't logged since they carry the credentials.
+func WithTraceLogging(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if !traceLogging() {
+		return rt
+	}
+
+	return withTraceLogging{rt: rt}
+}
+
+func (t withTraceLogging) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	log.Printf("[TRACE] Request %s %s: %s", req.Method, req.URL.RequestURI(), traceBody(reqBody, req.Header))
+
+	start := time.Now()
+	res, err := t.rt.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("[TRACE] Request %s %s failed after %s: %+v", req.Method, req.URL.RequestURI(), duration, err)
+		return res, err
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if err != nil {
+		return res, err
+	}
+	log.Printf("[TRACE] Response %s %s: %d in %s: %s", req.Method, req.URL.RequestURI(), res.StatusCode, duration, traceBody(resBody, res.Header))
+
+	return res, nil
+}
+
+// traceBody returns the loggable version of body: redacted, truncated and
+// only if it's text.
+func traceBody(body []byte, header http.Header) string {
+	if len(body) == 0 {
+		return "(no body)"
+	}
+	if header.Get("Content-Encoding") != "" {
+		return "(encoded body)"
+	}
+
+	// redact before truncating, so that no secret is cut in half
+	s := redactSecrets(string(body))
+	if len(s) > traceBodyLimit {
+		s = s[:traceBodyLimit] + "... (truncated)"
+	}
+	return s
+}
+
+func redactSecrets(s string) string {
+	return secretJsonValue.ReplaceAllString(s, `$1"***"`)
+}
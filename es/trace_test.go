@@ -0,0 +1,72 @@
+package es
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	cases := map[string]string{
+		`{"password":"secret1","username":"johndoe"}`:                `{"password":"***","username":"johndoe"}`,
+		`{"password_hash" : "$2a$10$abc", "full_name": "John"}`:      `{"password_hash" : "***", "full_name": "John"}`,
+		`{"settings":{"secret_access_key":"ab\"cd","bucket":"b"}}`:   `{"settings":{"secret_access_key":"***","bucket":"b"}}`,
+		`{"auth":{"basic":{"Access_Token":"xyz"}}}`:                  `{"auth":{"basic":{"Access_Token":"***"}}}`,
+		`{"analysis":{"tokenizer":"standard"}}`:                      `{"analysis":{"tokenizer":"standard"}}`,
+		"{\"index\":{}}\n{\"api_key\":\"k\"}\n":                      "{\"index\":{}}\n{\"api_key\":\"***\"}\n",
+		`{"headers":{"Authorization":"Basic dXNlcjpwYXNz"}, "x": 1}`: `{"headers":{"Authorization":"***"}, "x": 1}`,
+	}
+	for body, expected := range cases {
+		if actual := redactSecrets(body); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+}
+
+func TestWithTraceLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, _ := ioutil.ReadAll(r.Body); string(body) != `{"password":"changeme"}` {
+			t.Errorf("unexpected body: %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"created":true}`))
+	}))
+	defer server.Close()
+
+	if WithTraceLogging(http.DefaultTransport) != http.DefaultTransport {
+		t.Errorf("expected no tracing without %s", traceLogEnv)
+	}
+
+	os.Setenv(traceLogEnv, "trace")
+	defer os.Unsetenv(traceLogEnv)
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	client := &http.Client{Transport: WithTraceLogging(nil)}
+	res, err := client.Post(server.URL+"/_security/user/johndoe?refresh=true", "application/json", strings.NewReader(`{"password":"changeme"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if body, _ := ioutil.ReadAll(res.Body); string(body) != `{"created":true}` {
+		t.Errorf("unexpected response body: %s", body)
+	}
+
+	for _, expected := range []string{
+		`[TRACE] Request POST /_security/user/johndoe?refresh=true: {"password":"***"}`,
+		`[TRACE] Response POST /_security/user/johndoe?refresh=true: 201 in `,
+		`: {"created":true}`,
+	} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Errorf("expected the logs to contain %q, got:\n%s", expected, logs.String())
+		}
+	}
+	if strings.Contains(logs.String(), "changeme") {
+		t.Errorf("expected the password to be redacted, got:\n%s", logs.String())
+	}
+}
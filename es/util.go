@@ -6,14 +6,14 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"log"
-	"reflect"
+	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	elastic7 "github.com/olivere/elastic/v7"
@@ -25,11 +25,11 @@ var (
 	errObjNotFound = fmt.Errorf("object not found")
 )
 
-func elastic7GetObject(client *elastic7.Client, index string, id string) (*elastic7.GetResult, error) {
+func elastic7GetObject(ctx context.Context, client *elastic7.Client, index string, id string) (*elastic7.GetResult, error) {
 	result, err := client.Get().
 		Index(index).
 		Id(id).
-		Do(context.TODO())
+		Do(ctx)
 
 	if err != nil {
 		return nil, err
@@ -41,12 +41,12 @@ func elastic7GetObject(client *elastic7.Client, index string, id string) (*elast
 	return result, nil
 }
 
-func elastic6GetObject(client *elastic6.Client, objectType string, index string, id string) (*elastic6.GetResult, error) {
+func elastic6GetObject(ctx context.Context, client *elastic6.Client, objectType string, index string, id string) (*elastic6.GetResult, error) {
 	result, err := client.Get().
 		Index(index).
 		Type(objectType).
 		Id(id).
-		Do(context.TODO())
+		Do(ctx)
 
 	if err != nil {
 		return nil, err
@@ -58,12 +58,12 @@ func elastic6GetObject(client *elastic6.Client, objectType string, index string,
 	return result, nil
 }
 
-func elastic5GetObject(client *elastic5.Client, objectType string, index string, id string) (*elastic5.GetResult, error) {
+func elastic5GetObject(ctx context.Context, client *elastic5.Client, objectType string, index string, id string) (*elastic5.GetResult, error) {
 	result, err := client.Get().
 		Index(index).
 		Type(objectType).
 		Id(id).
-		Do(context.TODO())
+		Do(ctx)
 
 	if err != nil {
 		return nil, err
@@ -75,10 +75,70 @@ func elastic5GetObject(client *elastic5.Client, objectType string, index string,
 	return result, nil
 }
 
+// performRequest sends a request to an API that the clients don't wrap, with
+// any client version, and returns the body of the response.
+func performRequest(ctx context.Context, esClient interface{}, method string, path string, params url.Values, body interface{}) (json.RawMessage, error) {
+	return performRequestWithHeaders(ctx, esClient, method, path, params, body, nil)
+}
+
+// performRequestWithHeaders is performRequest, adding headers to the request.
+func performRequestWithHeaders(ctx context.Context, esClient interface{}, method string, path string, params url.Values, body interface{}, headers http.Header) (json.RawMessage, error) {
+	switch client := esClient.(type) {
+	case *elastic7.Client:
+		res, err := client.PerformRequest(ctx, elastic7.PerformRequestOptions{
+			Method:  method,
+			Path:    path,
+			Params:  params,
+			Body:    body,
+			Headers: headers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Body, nil
+	case *elastic6.Client:
+		res, err := client.PerformRequest(ctx, elastic6.PerformRequestOptions{
+			Method:  method,
+			Path:    path,
+			Params:  params,
+			Body:    body,
+			Headers: headers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Body, nil
+	default:
+		res, err := esClient.(*elastic5.Client).PerformRequestWithOptions(ctx, elastic5.PerformRequestOptions{
+			Method:      method,
+			Path:        path,
+			Params:      params,
+			Body:        body,
+			ContentType: "application/json",
+			Headers:     headers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Body, nil
+	}
+}
+
+// compactJson returns raw without insignificant whitespace, or an empty string
+// if it's missing.
+func compactJson(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
 func normalizeDestination(tpl map[string]interface{}) {
-	delete(tpl, "id")
-	delete(tpl, "last_update_time")
-	delete(tpl, "schema_version")
+	deleteKeys(tpl, serverManagedDestinationKeys...)
 }
 
 func normalizeMonitor(tpl map[string]interface{}) {
@@ -86,11 +146,7 @@ func normalizeMonitor(tpl map[string]interface{}) {
 		normalizeMonitorTriggers(triggers)
 	}
 
-	delete(tpl, "id")
-	delete(tpl, "last_update_time")
-	delete(tpl, "enabled_time")
-	delete(tpl, "schema_version")
-	delete(tpl, "user")
+	deleteKeys(tpl, serverManagedMonitorKeys...)
 }
 
 func normalizeMonitorTriggers(triggers []interface{}) {
@@ -112,38 +168,26 @@ func normalizeMonitorTriggerActions(actions []interface{}) {
 	}
 }
 
+// normalizePolicy normalizes an ISM policy, either as returned by the API or
+// wrapped in {"policy": ...} as in the body.
 func normalizePolicy(tpl map[string]interface{}) {
-	delete(tpl, "last_updated_time")
-	delete(tpl, "policy_id")
-	delete(tpl, "schema_version")
-	if ism_template, ok := tpl["ism_template"]; ok {
-		if ism_template == nil {
-			delete(tpl, "ism_template")
-		}
-
-		switch templates := ism_template.(type) {
-		case map[string]interface{}:
-			delete(templates, "last_updated_time")
-		case []interface{}:
-			for _, t := range templates {
-				if template, ok := t.(map[string]interface{}); ok {
-					delete(template, "last_updated_time")
-				}
-			}
-		default:
-			log.Printf("[INFO] normalizePolicy unknown type: %T", ism_template)
-		}
+	if policy, ok := tpl["policy"].(map[string]interface{}); ok {
+		normalizePolicy(policy)
 	}
+	stripPolicy(tpl)
 	// ignore if set to null in response (ie not specified)
-	if error_notification, ok := tpl["error_notification"]; ok {
-		if error_notification == nil {
-			delete(tpl, "error_notification")
+	for _, key := range []string{"ism_template", "error_notification"} {
+		if v, ok := tpl[key]; ok && v == nil {
+			delete(tpl, key)
 		}
 	}
 }
 
 func normalizeIndexTemplate(tpl map[string]interface{}) {
 	delete(tpl, "version")
+	if mappings, ok := tpl["mappings"].(map[string]interface{}); ok {
+		normalizeIndexMappings(mappings)
+	}
 	if settings, ok := tpl["settings"]; ok {
 		if settingsMap, ok := settings.(map[string]interface{}); ok {
 			tpl["settings"] = normalizedIndexSettings(settingsMap)
@@ -157,6 +201,14 @@ For legacy index templates (ES < 7.8) or /_template endpoint on ES >= 7.8 see no
 */
 func normalizeComposableIndexTemplate(tpl map[string]interface{}) {
 	delete(tpl, "version")
+	// the defaults of data_stream are returned, depending on the version
+	if dataStream, ok := tpl["data_stream"].(map[string]interface{}); ok {
+		for _, key := range []string{"hidden", "allow_custom_routing"} {
+			if dataStream[key] == false {
+				delete(dataStream, key)
+			}
+		}
+	}
 	if innerTpl, ok := tpl["template"]; ok {
 		if innerTplMap, ok := innerTpl.(map[string]interface{}); ok {
 			if settings, ok := innerTplMap["settings"]; ok {
@@ -184,19 +236,19 @@ func normalizeComponentTemplate(tpl map[string]interface{}) {
 func normalizedIndexSettings(settings map[string]interface{}) map[string]interface{} {
 	f := flattenMap(settings)
 	for k, v := range f {
-		f[k] = fmt.Sprintf("%v", v)
+		f[k] = scalarString(v)
 		if !strings.HasPrefix(k, "index.") {
-			f["index."+k] = fmt.Sprintf("%v", v)
+			f["index."+k] = scalarString(v)
 			delete(f, k)
 		}
 	}
+	deleteKeys(f, serverManagedIndexSettings...)
 
 	return f
 }
 
 func normalizeIndexLifecyclePolicy(pol map[string]interface{}) {
-	delete(pol, "version")
-	delete(pol, "modified_date")
+	stripIndexLifecyclePolicy(pol)
 	if policy, ok := pol["policy"]; ok {
 		if policyMap, ok := policy.(map[string]interface{}); ok {
 			pol["policy"] = normalizedIndexLifecyclePolicy(policyMap)
@@ -204,13 +256,17 @@ func normalizeIndexLifecyclePolicy(pol map[string]interface{}) {
 	}
 }
 
+// normalizeTransform removes the server managed keys of a transform, and its
+// settings when the API returns them empty.
+func normalizeTransform(transform map[string]interface{}) {
+	deleteKeys(transform, serverManagedTransformKeys...)
+	if settings, ok := transform["settings"].(map[string]interface{}); ok && len(settings) == 0 {
+		delete(transform, "settings")
+	}
+}
+
 func normalizeSnapshotLifecyclePolicy(pol map[string]interface{}) {
-	delete(pol, "version")
-	delete(pol, "modified_date")
-	delete(pol, "modified_date_millis")
-	delete(pol, "stats")
-	delete(pol, "next_execution")
-	delete(pol, "next_execution_millis")
+	deleteKeys(pol, serverManagedSnapshotLifecyclePolicyKeys...)
 	if policy, ok := pol["policy"]; ok {
 		if policyMap, ok := policy.(map[string]interface{}); ok {
 			pol["policy"] = normalizedIndexLifecyclePolicy(policyMap)
@@ -221,12 +277,30 @@ func normalizeSnapshotLifecyclePolicy(pol map[string]interface{}) {
 func normalizedIndexLifecyclePolicy(policy map[string]interface{}) map[string]interface{} {
 	f := flattenMap(policy)
 	for k, v := range f {
-		f[k] = fmt.Sprintf("%v", v)
+		f[k] = scalarString(v)
 	}
 
 	return f
 }
 
+// deleteKeys removes server side metadata, e.g. timestamps and ids, from an
+// object returned by the API.
+func deleteKeys(m map[string]interface{}, keys ...string) {
+	for _, k := range keys {
+		delete(m, k)
+	}
+}
+
+// scalarString formats a decoded JSON value the way the APIs return it when
+// they store all values as strings, e.g. 1000000 as "1000000" rather than
+// "1e+06".
+func scalarString(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 func flattenMap(m map[string]interface{}) map[string]interface{} {
 	f := make(map[string]interface{})
 	for k, v := range m {
@@ -389,6 +463,43 @@ func expandIndicesPermissionSet(resourcesArray []interface{}) ([]PutRoleIndicesP
 	return vperm, nil
 }
 
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if v.(string) == "" {
+		return
+	}
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a valid duration, e.g. 30s or 5m: %s", k, err))
+	}
+	return
+}
+
+func requestTimeoutSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateDuration,
+		Description:  "Timeout for API requests made for this resource, e.g. 5m. Overrides the provider `timeout`.",
+	}
+}
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Fail to delete the resource, e.g. on `terraform destroy` or when it must be replaced, until this is set to false and applied.",
+	}
+}
+
+// checkDeletionProtection returns an error if the resource being deleted has
+// deletion_protection enabled in its state.
+func checkDeletionProtection(d *schema.ResourceData) error {
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("deletion_protection is enabled, set it to false and apply before deleting")
+	}
+	return nil
+}
+
 func optionalInterfaceJson(input string) interface{} {
 	if input == "" || input == "{}" {
 		return nil
@@ -623,15 +734,6 @@ func tenantPermissionsHash(v interface{}) int {
 	return hashcode.String(buf.String())
 }
 
-func elastic7GetVersion(client *elastic7.Client) (*version.Version, error) {
-	urls := reflect.ValueOf(client).Elem().FieldByName("urls")
-	versionString, err := client.ElasticsearchVersion(urls.Index(0).String())
-	if err != nil {
-		return nil, err
-	}
-	return version.NewVersion(versionString)
-}
-
 func toCamelCase(underScored string, startUpperCased bool) (camelCased string) {
 	isToUpper := false
 
@@ -0,0 +1,9 @@
+data "elasticsearch_alias" "app" {
+  name = "app"
+}
+
+locals {
+  # the index that isn't serving writes gets the next deployment
+  live_color = trimprefix(data.elasticsearch_alias.app.write_index, "app-")
+  next_color = local.live_color == "blue" ? "green" : "blue"
+}
@@ -0,0 +1,7 @@
+data "elasticsearch_cat_allocation" "cluster" {}
+
+locals {
+  # replicate to every data node while the disks have room
+  max_disk_percent = max([for node in data.elasticsearch_cat_allocation.cluster.nodes : node.disk_percent]...)
+  replicas         = local.max_disk_percent < 70 ? length(data.elasticsearch_cat_allocation.cluster.nodes) - 1 : 1
+}
@@ -0,0 +1,7 @@
+data "elasticsearch_cat_shards" "app" {
+  index = "app-*"
+}
+
+output "unassigned_shards" {
+  value = [for shard in data.elasticsearch_cat_shards.app.shards : "${shard.index}/${shard.shard}" if shard.state == "UNASSIGNED"]
+}
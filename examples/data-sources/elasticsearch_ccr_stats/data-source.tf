@@ -0,0 +1,10 @@
+data "elasticsearch_ccr_stats" "logs" {
+  index = elasticsearch_ccr_follower.logs.index
+
+  lifecycle {
+    postcondition {
+      condition     = alltrue([for i in self.indices : i.global_checkpoint_lag < 1000 && length(i.fatal_exceptions) == 0])
+      error_message = "The replication of the follower index is lagging or failed."
+    }
+  }
+}
@@ -0,0 +1,5 @@
+data "elasticsearch_cluster_info" "current" {}
+
+locals {
+  opensearch = data.elasticsearch_cluster_info.current.distribution == "opensearch"
+}
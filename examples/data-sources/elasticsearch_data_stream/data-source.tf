@@ -0,0 +1,7 @@
+data "elasticsearch_data_stream" "app" {
+  name = "logs-app-default"
+}
+
+output "app_write_index" {
+  value = data.elasticsearch_data_stream.app.write_index
+}
@@ -0,0 +1,8 @@
+check "upgrade" {
+  data "elasticsearch_deprecations" "all" {}
+
+  assert {
+    condition     = !data.elasticsearch_deprecations.all.critical
+    error_message = "Critical deprecations block the upgrade: ${join("; ", [for d in data.elasticsearch_deprecations.all.deprecations : "${d.resource} ${d.message}" if d.level == "critical"])}"
+  }
+}
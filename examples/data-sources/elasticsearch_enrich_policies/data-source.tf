@@ -0,0 +1,17 @@
+# fails if the policy doesn't exist
+data "elasticsearch_enrich_policies" "users" {
+  names = ["users-by-email"]
+}
+
+resource "elasticsearch_ingest_pipeline" "enrich_users" {
+  name = "enrich-users"
+  body = jsonencode({
+    processors = [{
+      enrich = {
+        policy_name  = data.elasticsearch_enrich_policies.users.policies[0].name
+        field        = "email"
+        target_field = "user"
+      }
+    }]
+  })
+}
@@ -0,0 +1,8 @@
+data "elasticsearch_health_report" "cluster" {}
+
+check "cluster_health" {
+  assert {
+    condition     = data.elasticsearch_health_report.cluster.status == "green"
+    error_message = join("\n", [for i in data.elasticsearch_health_report.cluster.indicators : "${i.name}: ${i.symptom}" if i.status != "green"])
+  }
+}
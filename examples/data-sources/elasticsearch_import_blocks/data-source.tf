@@ -0,0 +1,14 @@
+# terraform output -raw import_blocks > imports.tf
+# terraform plan -generate-config-out=generated.tf
+data "elasticsearch_import_blocks" "cluster" {
+  resource_types = [
+    "elasticsearch_xpack_user",
+    "elasticsearch_xpack_role",
+    "elasticsearch_composable_index_template",
+    "elasticsearch_ingest_pipeline",
+  ]
+}
+
+output "import_blocks" {
+  value = data.elasticsearch_import_blocks.cluster.hcl
+}
@@ -0,0 +1,8 @@
+data "elasticsearch_indices" "app" {
+  pattern          = "app-*"
+  expand_wildcards = ["open"]
+}
+
+output "app_documents" {
+  value = sum([for index in data.elasticsearch_indices.app.indices : index.docs_count])
+}
@@ -0,0 +1,14 @@
+data "elasticsearch_ingest_pipeline" "nginx" {
+  name = "logs-nginx.access-1.2.0"
+}
+
+resource "elasticsearch_ingest_pipeline" "custom" {
+  name = "logs-nginx.access-custom"
+  body = jsonencode({
+    description = "Enrich Nginx access logs after the integration pipeline"
+    processors = [
+      { pipeline = { name = data.elasticsearch_ingest_pipeline.nginx.name } },
+      { set = { field = "team", value = "web" } },
+    ]
+  })
+}
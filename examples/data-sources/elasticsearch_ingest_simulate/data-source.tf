@@ -0,0 +1,20 @@
+resource "elasticsearch_ingest_pipeline" "logs" {
+  name = "logs"
+  body = jsonencode({
+    processors = [
+      { lowercase = { field = "level" } },
+    ]
+  })
+}
+
+data "elasticsearch_ingest_simulate" "logs" {
+  pipeline_name = elasticsearch_ingest_pipeline.logs.name
+  documents     = [jsonencode({ message = "started", level = "INFO" })]
+
+  lifecycle {
+    postcondition {
+      condition     = jsondecode(self.results[0].source).level == "info"
+      error_message = "The logs pipeline doesn't lowercase the level."
+    }
+  }
+}
@@ -0,0 +1,14 @@
+# copies a dashboard and its visualizations and index patterns from staging
+data "elasticsearch_kibana_saved_objects_export" "overview" {
+  provider = elasticsearch.staging
+
+  objects {
+    type = "dashboard"
+    id   = "overview"
+  }
+  include_references_deep = true
+}
+
+resource "elasticsearch_kibana_saved_objects_import" "overview" {
+  content = data.elasticsearch_kibana_saved_objects_export.overview.ndjson
+}
@@ -0,0 +1,10 @@
+data "elasticsearch_kibana_spaces" "all" {}
+
+# import the shared dashboards into every space, including the spaces created
+# in Kibana
+resource "elasticsearch_kibana_saved_objects_import" "dashboards" {
+  for_each = toset(data.elasticsearch_kibana_spaces.all.ids)
+
+  space_id = each.key
+  file     = "${path.module}/dashboards.ndjson"
+}
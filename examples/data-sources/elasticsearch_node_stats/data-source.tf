@@ -0,0 +1,9 @@
+data "elasticsearch_node_stats" "data" {
+  node_filter = "data:true"
+}
+
+# one replica per data node beyond the first, up to 2
+resource "elasticsearch_index" "app" {
+  name               = "app"
+  number_of_replicas = min(length(data.elasticsearch_node_stats.data.nodes) - 1, 2)
+}
@@ -0,0 +1,12 @@
+data "elasticsearch_objects" "users" {
+  resource_type = "elasticsearch_xpack_user"
+  name_pattern  = "app-*"
+}
+
+# Terraform >= 1.5, the users still need a configuration, e.g. generated with
+# terraform plan -generate-config-out
+import {
+  for_each = data.elasticsearch_objects.users.import_ids
+  to       = elasticsearch_xpack_user.app[each.key]
+  id       = each.value
+}
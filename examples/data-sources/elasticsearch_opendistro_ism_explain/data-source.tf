@@ -0,0 +1,7 @@
+data "elasticsearch_opendistro_ism_explain" "logs" {
+  index = "logs-*"
+}
+
+output "failed_log_indices" {
+  value = data.elasticsearch_opendistro_ism_explain.logs.failed_indices
+}
@@ -0,0 +1,8 @@
+# the monitors of the payments team, managed in another workspace
+data "elasticsearch_opendistro_monitors" "payments" {
+  name = "payments-*"
+}
+
+output "disabled_payments_monitors" {
+  value = [for m in data.elasticsearch_opendistro_monitors.payments.monitors : m.name if !m.enabled]
+}
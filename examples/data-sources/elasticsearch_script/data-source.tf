@@ -0,0 +1,7 @@
+data "elasticsearch_script" "score" {
+  script_id = "calculate-score"
+}
+
+output "score_script_hash" {
+  value = data.elasticsearch_script.score.source_sha256
+}
@@ -0,0 +1,9 @@
+data "elasticsearch_search" "environment" {
+  index = "environment-registry"
+  query = jsonencode({ term = { "name.keyword" = "production" } })
+  size  = 1
+}
+
+locals {
+  production = jsondecode(data.elasticsearch_search.environment.hits[0].source)
+}
@@ -0,0 +1,12 @@
+data "elasticsearch_snapshot_repository" "dr" {
+  name = "dr-backups"
+}
+
+resource "elasticsearch_xpack_snapshot_lifecycle_policy" "nightly" {
+  name = "nightly-snapshots"
+  body = jsonencode({
+    schedule   = "0 30 1 * * ?"
+    name       = "<nightly-snap-{now/d}>"
+    repository = data.elasticsearch_snapshot_repository.dr.name
+  })
+}
@@ -0,0 +1,17 @@
+# wait for the nightly snapshots before changing the indices
+data "elasticsearch_snapshot_status" "nightly" {
+  repository          = "backups"
+  wait_for_completion = true
+
+  timeouts {
+    read = "1h"
+  }
+}
+
+resource "elasticsearch_index" "logs" {
+  name               = "logs"
+  number_of_shards   = 1
+  number_of_replicas = 1
+
+  depends_on = [data.elasticsearch_snapshot_status.nightly]
+}
@@ -0,0 +1,9 @@
+data "elasticsearch_snapshots" "nightly" {
+  repository   = "dr-backups"
+  name_pattern = "nightly-*"
+  state        = "SUCCESS"
+}
+
+output "latest_snapshot" {
+  value = data.elasticsearch_snapshots.nightly.latest
+}
@@ -0,0 +1,10 @@
+data "elasticsearch_xpack_builtin_privileges" "all" {}
+
+# only grants the privileges the version of the cluster knows
+resource "elasticsearch_xpack_role" "monitoring" {
+  role_name = "monitoring"
+  cluster = setintersection(
+    ["monitor", "monitor_enrich", "read_slm"],
+    data.elasticsearch_xpack_builtin_privileges.all.cluster,
+  )
+}
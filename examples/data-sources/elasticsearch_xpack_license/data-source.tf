@@ -0,0 +1,4 @@
+# fails the plan on clusters without a platinum, enterprise or trial license
+data "elasticsearch_xpack_license" "platinum" {
+  minimum_type = "platinum"
+}
@@ -0,0 +1,8 @@
+# fails if the service account doesn't exist
+data "elasticsearch_xpack_service_accounts" "fleet" {
+  names = ["elastic/fleet-server"]
+}
+
+output "fleet_server_cluster_privileges" {
+  value = data.elasticsearch_xpack_service_accounts.fleet.service_accounts[0].cluster
+}
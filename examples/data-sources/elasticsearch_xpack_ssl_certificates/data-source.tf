@@ -0,0 +1,10 @@
+check "certificates" {
+  data "elasticsearch_xpack_ssl_certificates" "expiring" {
+    expires_within_days = 30
+  }
+
+  assert {
+    condition     = length(data.elasticsearch_xpack_ssl_certificates.expiring.certificates) == 0
+    error_message = "Certificates expire within 30 days: ${join(", ", data.elasticsearch_xpack_ssl_certificates.expiring.certificates[*].subject_dn)}"
+  }
+}
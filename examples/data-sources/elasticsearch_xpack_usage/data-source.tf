@@ -0,0 +1,17 @@
+data "elasticsearch_xpack_usage" "cluster" {}
+
+resource "elasticsearch_xpack_index_lifecycle_policy" "logs" {
+  count = data.elasticsearch_xpack_usage.cluster.available["ilm"] ? 1 : 0
+
+  name = "logs"
+  body = jsonencode({
+    policy = {
+      phases = {
+        delete = {
+          min_age = "30d"
+          actions = { delete = {} }
+        }
+      }
+    }
+  })
+}
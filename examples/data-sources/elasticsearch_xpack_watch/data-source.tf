@@ -0,0 +1,8 @@
+# a watch managed by another team
+data "elasticsearch_xpack_watch" "cluster_health" {
+  watch_id = "cluster_health"
+}
+
+output "cluster_health_watch_failures" {
+  value = [for a in data.elasticsearch_xpack_watch.cluster_health.actions : a.last_execution_reason if !a.last_execution_successful && a.last_execution != ""]
+}
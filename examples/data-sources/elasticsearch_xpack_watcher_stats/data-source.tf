@@ -0,0 +1,8 @@
+check "watcher" {
+  data "elasticsearch_xpack_watcher_stats" "watcher" {}
+
+  assert {
+    condition     = data.elasticsearch_xpack_watcher_stats.watcher.watcher_state == "started" && data.elasticsearch_xpack_watcher_stats.watcher.queue_size < 100
+    error_message = "Watcher is ${data.elasticsearch_xpack_watcher_stats.watcher.watcher_state} with ${data.elasticsearch_xpack_watcher_stats.watcher.queue_size} queued executions."
+  }
+}
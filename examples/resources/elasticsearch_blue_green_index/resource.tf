@@ -0,0 +1,20 @@
+# changing the mappings creates products-<timestamp>, reindexes the current
+# index into it and swaps the products alias, without downtime
+resource "elasticsearch_blue_green_index" "products" {
+  alias        = "products"
+  index_prefix = "products-"
+
+  settings = jsonencode({
+    number_of_shards   = 3
+    number_of_replicas = 1
+  })
+  mappings = jsonencode({
+    properties = {
+      sku  = { type = "keyword" }
+      name = { type = "text" }
+    }
+  })
+
+  delete_previous_index = true
+  deletion_protection   = true
+}
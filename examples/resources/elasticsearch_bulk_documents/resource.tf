@@ -0,0 +1,14 @@
+resource "elasticsearch_index" "countries" {
+  name               = "countries"
+  number_of_shards   = 1
+  number_of_replicas = 1
+}
+
+# countries.ndjson holds a document per line, e.g.
+# {"code": "fr", "name": "France", "region": "Europe"}
+resource "elasticsearch_bulk_documents" "countries" {
+  index     = elasticsearch_index.countries.name
+  documents = file("${path.module}/countries.ndjson")
+  id_field  = "code"
+  mode      = "replace"
+}
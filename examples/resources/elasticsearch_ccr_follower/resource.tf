@@ -0,0 +1,8 @@
+resource "elasticsearch_ccr_follower" "logs" {
+  index          = "logs-replica"
+  remote_cluster = "eu-west"
+  leader_index   = "logs"
+
+  # set to true during maintenance of the remote cluster
+  paused = false
+}
@@ -0,0 +1,20 @@
+# check the cluster is green and done rebalancing whenever the template
+# changes, before replacing it
+resource "elasticsearch_cluster_health_check" "before_template" {
+  minimum_status        = "green"
+  max_relocating_shards = 0
+  triggers = {
+    template = sha1(local.logs_template)
+  }
+
+  timeouts {
+    create = "5m"
+  }
+}
+
+resource "elasticsearch_index_template" "logs" {
+  name = "logs"
+  body = local.logs_template
+
+  depends_on = [elasticsearch_cluster_health_check.before_template]
+}
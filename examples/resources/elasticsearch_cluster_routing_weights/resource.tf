@@ -0,0 +1,10 @@
+# fail over from zone-c: drain its search traffic, then decommission its nodes
+resource "elasticsearch_cluster_routing_weights" "zone" {
+  attribute = "zone"
+  weights = {
+    "zone-a" = 1
+    "zone-b" = 1
+    "zone-c" = 0
+  }
+  decommissioned_value = "zone-c"
+}
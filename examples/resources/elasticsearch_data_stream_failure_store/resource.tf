@@ -0,0 +1,6 @@
+# keep the documents the app fails to ingest for a week
+resource "elasticsearch_data_stream_failure_store" "app" {
+  data_stream = "logs-app-default"
+  enabled     = true
+  retention   = "7d"
+}
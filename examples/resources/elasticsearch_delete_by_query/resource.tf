@@ -0,0 +1,13 @@
+variable "offboarded_tenants" {
+  type    = set(string)
+  default = ["acme"]
+}
+
+resource "elasticsearch_delete_by_query" "offboarded" {
+  for_each = var.offboarded_tenants
+
+  index     = "tenants-*"
+  query     = jsonencode({ term = { tenant = each.key } })
+  conflicts = "proceed"
+  slices    = "auto"
+}
@@ -0,0 +1,9 @@
+resource "elasticsearch_document" "feature_flags" {
+  index       = "app-config"
+  document_id = "feature-flags"
+  refresh     = "wait_for"
+  body = jsonencode({
+    new_checkout = true
+    dark_mode    = false
+  })
+}
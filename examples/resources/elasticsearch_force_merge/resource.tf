@@ -0,0 +1,10 @@
+# merge the reindexed index into a single segment per shard, once the
+# reindexing completed
+resource "elasticsearch_force_merge" "logs_2020" {
+  index            = "logs-2020"
+  max_num_segments = 1
+
+  triggers = {
+    reindex = elasticsearch_update_by_query.logs_2020.id
+  }
+}
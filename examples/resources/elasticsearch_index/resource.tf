@@ -18,3 +18,11 @@ resource "elasticsearch_index" "test" {
 }
 EOF
 }
+
+# Bootstrap the first index rolled over by a lifecycle policy
+resource "elasticsearch_index" "logs" {
+  name                     = "logs-000001"
+  lifecycle_policy         = elasticsearch_xpack_index_lifecycle_policy.logs.name
+  rollover_alias           = "logs"
+  bootstrap_rollover_alias = true
+}
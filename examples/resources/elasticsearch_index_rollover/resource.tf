@@ -0,0 +1,9 @@
+# roll the logs data stream over whenever its template changes, so that the
+# new settings apply to the next write index right away
+resource "elasticsearch_index_rollover" "logs" {
+  target = "logs-app-default"
+
+  triggers = {
+    template = elasticsearch_composable_index_template.logs.body
+  }
+}
@@ -0,0 +1,5 @@
+# archive last year's index, keeping its data on disk
+resource "elasticsearch_index_state" "logs_2019" {
+  index  = "logs-2019"
+  status = "closed"
+}
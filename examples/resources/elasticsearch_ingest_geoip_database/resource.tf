@@ -0,0 +1,17 @@
+resource "elasticsearch_ingest_geoip_database" "city" {
+  database_id        = "city"
+  name               = "GeoIP2-City"
+  maxmind_account_id = "1234567"
+}
+
+resource "elasticsearch_ingest_pipeline" "geoip" {
+  name = "geoip"
+  body = jsonencode({
+    processors = [{
+      geoip = {
+        field         = "client.ip"
+        database_file = "${elasticsearch_ingest_geoip_database.city.name}.mmdb"
+      }
+    }]
+  })
+}
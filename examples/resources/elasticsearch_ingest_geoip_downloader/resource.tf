@@ -0,0 +1,4 @@
+# the cluster has no internet access and ships its own databases
+resource "elasticsearch_ingest_geoip_downloader" "downloader" {
+  enabled = false
+}
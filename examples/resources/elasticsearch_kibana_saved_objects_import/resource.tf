@@ -0,0 +1,5 @@
+resource "elasticsearch_kibana_saved_objects_import" "dashboards" {
+  file     = "${path.module}/dashboards/export.ndjson"
+  space_id = "ops"
+  tags     = [elasticsearch_kibana_tag.payments.tag_id]
+}
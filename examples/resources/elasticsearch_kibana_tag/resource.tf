@@ -0,0 +1,7 @@
+resource "elasticsearch_kibana_tag" "payments" {
+  tag_id      = "team-payments"
+  name        = "team:payments"
+  color       = "#54B399"
+  description = "Owned by the payments team"
+  space_id    = "ops"
+}
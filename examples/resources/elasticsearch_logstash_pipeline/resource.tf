@@ -0,0 +1,10 @@
+resource "elasticsearch_logstash_pipeline" "beats" {
+  pipeline_id = "beats"
+  description = "Beats to Elasticsearch"
+  pipeline    = file("${path.module}/beats.conf")
+
+  pipeline_workers    = 4
+  pipeline_batch_size = 250
+  queue_type          = "persisted"
+  queue_max_bytes     = "4gb"
+}
@@ -0,0 +1,6 @@
+resource "elasticsearch_opendistro_dashboards_index_pattern" "logs" {
+  pattern_id      = "logs"
+  title           = "logs-*"
+  time_field_name = "@timestamp"
+  tenant          = "global_tenant"
+}
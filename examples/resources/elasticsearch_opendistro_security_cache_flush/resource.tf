@@ -0,0 +1,11 @@
+resource "elasticsearch_opendistro_roles_mapping" "readers" {
+  role_name     = "readall"
+  backend_roles = var.reader_groups
+}
+
+# pick up the groups of the directory right away
+resource "elasticsearch_opendistro_security_cache_flush" "readers" {
+  triggers = {
+    backend_roles = join(",", elasticsearch_opendistro_roles_mapping.readers.backend_roles)
+  }
+}
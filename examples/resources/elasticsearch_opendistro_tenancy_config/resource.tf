@@ -0,0 +1,10 @@
+resource "elasticsearch_opendistro_kibana_tenant" "analysts" {
+  tenant_name = "analysts"
+  description = "Shared dashboards of the analysts"
+}
+
+# land everybody in the shared tenant, without private tenants
+resource "elasticsearch_opendistro_tenancy_config" "tenancy" {
+  private_tenant_enabled = false
+  default_tenant         = elasticsearch_opendistro_kibana_tenant.analysts.tenant_name
+}
@@ -0,0 +1,6 @@
+# reload the S3 client credentials rotated into the keystores
+resource "elasticsearch_reload_secure_settings" "s3" {
+  triggers = {
+    access_key_id = var.s3_access_key_id
+  }
+}
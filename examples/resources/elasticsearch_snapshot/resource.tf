@@ -0,0 +1,15 @@
+# back up the logs indices before changing their template
+resource "elasticsearch_snapshot" "before_upgrade" {
+  repository = "es-index-backups"
+  name       = "before-logs-v2"
+  indices    = ["logs-*"]
+
+  delete_on_destroy = false
+}
+
+resource "elasticsearch_composable_index_template" "logs" {
+  name = "logs"
+  body = file("${path.module}/logs-v2.json")
+
+  depends_on = [elasticsearch_snapshot.before_upgrade]
+}
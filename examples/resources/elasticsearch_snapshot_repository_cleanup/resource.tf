@@ -0,0 +1,15 @@
+resource "time_rotating" "weekly" {
+  rotation_days = 7
+}
+
+# purge the data left behind by deleted snapshots once a week
+resource "elasticsearch_snapshot_repository_cleanup" "backups" {
+  repository = "es-index-backups"
+  triggers = {
+    week = time_rotating.weekly.id
+  }
+}
+
+output "freed_bytes" {
+  value = elasticsearch_snapshot_repository_cleanup.backups.deleted_bytes
+}
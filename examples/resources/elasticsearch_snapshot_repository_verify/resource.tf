@@ -0,0 +1,16 @@
+resource "elasticsearch_snapshot_repository" "backups" {
+  name = "es-index-backups"
+  type = "s3"
+  settings = {
+    bucket = "es-index-backups"
+    region = "us-east-1"
+  }
+}
+
+# verify the repository again whenever the S3 credentials are rotated
+resource "elasticsearch_snapshot_repository_verify" "backups" {
+  repository = elasticsearch_snapshot_repository.backups.name
+  triggers = {
+    credentials = var.s3_credentials_version
+  }
+}
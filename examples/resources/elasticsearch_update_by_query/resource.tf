@@ -0,0 +1,23 @@
+resource "elasticsearch_ingest_pipeline" "tier" {
+  name = "tier"
+  body = jsonencode({
+    processors = [{ set = { field = "tier", value = "hot" } }]
+  })
+}
+
+# backfill the existing documents whenever the pipeline changes
+resource "elasticsearch_update_by_query" "tier" {
+  index     = "logs-*"
+  query     = jsonencode({ bool = { must_not = { exists = { field = "tier" } } } })
+  pipeline  = elasticsearch_ingest_pipeline.tier.name
+  conflicts = "proceed"
+  slices    = "auto"
+
+  triggers = {
+    pipeline = elasticsearch_ingest_pipeline.tier.body
+  }
+
+  timeouts {
+    create = "1h"
+  }
+}
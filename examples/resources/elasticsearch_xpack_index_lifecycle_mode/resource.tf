@@ -0,0 +1,5 @@
+# pause lifecycle actions during a migration, set back to true or destroy to
+# resume them
+resource "elasticsearch_xpack_index_lifecycle_mode" "ilm" {
+  enabled = false
+}
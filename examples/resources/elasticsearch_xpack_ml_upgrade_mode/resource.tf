@@ -0,0 +1,9 @@
+variable "upgrading" {
+  type    = bool
+  default = false
+}
+
+# pause machine learning jobs and datafeeds while the nodes are replaced
+resource "elasticsearch_xpack_ml_upgrade_mode" "ml" {
+  enabled = var.upgrading
+}
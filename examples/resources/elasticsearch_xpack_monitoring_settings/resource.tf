@@ -0,0 +1,14 @@
+# ship monitoring data to a dedicated monitoring cluster
+resource "elasticsearch_xpack_monitoring_settings" "monitoring" {
+  collection_enabled  = true
+  collection_interval = "30s"
+
+  exporter {
+    name = "monitoring"
+    type = "http"
+    host = ["https://monitoring.example.com:9200"]
+    settings = {
+      "auth.username" = "remote_monitoring_user"
+    }
+  }
+}
@@ -0,0 +1,9 @@
+variable "restore_exercise" {
+  type    = bool
+  default = false
+}
+
+# suspend scheduled snapshots while a restore exercise runs
+resource "elasticsearch_xpack_snapshot_lifecycle_mode" "slm" {
+  enabled = !var.restore_exercise
+}
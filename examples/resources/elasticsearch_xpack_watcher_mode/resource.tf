@@ -0,0 +1,9 @@
+variable "maintenance" {
+  type    = bool
+  default = false
+}
+
+# pause every watch during the maintenance window
+resource "elasticsearch_xpack_watcher_mode" "watcher" {
+  enabled = !var.maintenance
+}
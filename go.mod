@@ -7,6 +7,7 @@ require (
 	github.com/deoxxa/aws_signing_client v0.0.0-20161109131055-c20ee106809e
 	github.com/hashicorp/go-version v1.2.1
 	github.com/hashicorp/terraform-plugin-sdk v1.13.1
+	github.com/jcmturner/gokrb5/v8 v8.4.2
 	github.com/olivere/elastic v6.2.26+incompatible
 	github.com/olivere/elastic/v7 v7.0.25
 	google.golang.org/api v0.29.0 // indirect
@@ -6,6 +6,10 @@ import (
 	"github.com/phillbaker/terraform-provider-elasticsearch/es"
 )
 
+// The provider is served by SDK v1, whose gRPC server implements the SDK's
+// internal copy of protocol 5 rather than tfprotov5, so terraform-plugin-mux
+// can't serve it alongside terraform-plugin-framework resources. Moving to the
+// framework resource by resource requires upgrading to SDK v2 first.
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: es.Provider,